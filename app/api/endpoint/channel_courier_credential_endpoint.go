@@ -0,0 +1,71 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/global"
+	"go-klikdokter/helper/message"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type ChannelCourierCredentialEndpoint struct {
+	Get    endpoint.Endpoint
+	Save   endpoint.Endpoint
+	Delete endpoint.Endpoint
+}
+
+func MakeChannelCourierCredentialEndpoint(s service.ChannelCourierCredentialService) ChannelCourierCredentialEndpoint {
+	return ChannelCourierCredentialEndpoint{
+		Get:    makeGetChannelCourierCredential(s),
+		Save:   makeSaveChannelCourierCredential(s),
+		Delete: makeDeleteChannelCourierCredential(s),
+	}
+}
+
+func makeGetChannelCourierCredential(s service.ChannelCourierCredentialService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		result, msg := s.GetChannelCourierCredential(fmt.Sprint(rqst))
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeSaveChannelCourierCredential(s service.ChannelCourierCredentialService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		jwtInfo, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.SaveChannelCourierCredential)
+		req.JWTInfo = *jwtInfo
+		result, msg := s.SaveChannelCourierCredential(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeDeleteChannelCourierCredential(s service.ChannelCourierCredentialService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		msg = s.DeleteChannelCourierCredential(fmt.Sprint(rqst))
+		return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+	}
+}
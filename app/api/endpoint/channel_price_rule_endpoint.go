@@ -0,0 +1,89 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/global"
+	"go-klikdokter/helper/message"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type ChannelPriceRuleEndpoint struct {
+	List   endpoint.Endpoint
+	Save   endpoint.Endpoint
+	Update endpoint.Endpoint
+	Delete endpoint.Endpoint
+}
+
+func MakeChannelPriceRuleEndpoint(s service.ChannelPriceRuleService) ChannelPriceRuleEndpoint {
+	return ChannelPriceRuleEndpoint{
+		List:   makeListChannelPriceRule(s),
+		Save:   makeSaveChannelPriceRule(s),
+		Update: makeUpdateChannelPriceRule(s),
+		Delete: makeDeleteChannelPriceRule(s),
+	}
+}
+
+func makeListChannelPriceRule(s service.ChannelPriceRuleService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		result, msg := s.ListChannelPriceRule(fmt.Sprint(rqst))
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeSaveChannelPriceRule(s service.ChannelPriceRuleService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		jwtInfo, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.SaveChannelPriceRule)
+		req.JWTInfo = *jwtInfo
+		result, msg := s.CreateChannelPriceRule(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeUpdateChannelPriceRule(s service.ChannelPriceRuleService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		jwtInfo, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.UpdateChannelPriceRule)
+		req.JWTInfo = *jwtInfo
+		result, msg := s.UpdateChannelPriceRule(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeDeleteChannelPriceRule(s service.ChannelPriceRuleService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		msg = s.DeleteChannelPriceRule(fmt.Sprint(rqst))
+		return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+	}
+}
@@ -0,0 +1,55 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/global"
+	"go-klikdokter/helper/message"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type ChannelRecommendationWeightEndpoint struct {
+	Get  endpoint.Endpoint
+	Save endpoint.Endpoint
+}
+
+func MakeChannelRecommendationWeightEndpoint(s service.ChannelRecommendationWeightService) ChannelRecommendationWeightEndpoint {
+	return ChannelRecommendationWeightEndpoint{
+		Get:  makeGetChannelRecommendationWeight(s),
+		Save: makeSaveChannelRecommendationWeight(s),
+	}
+}
+
+func makeGetChannelRecommendationWeight(s service.ChannelRecommendationWeightService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		result, msg := s.GetChannelRecommendationWeight(fmt.Sprint(rqst))
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeSaveChannelRecommendationWeight(s service.ChannelRecommendationWeightService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		jwtInfo, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.SaveChannelRecommendationWeight)
+		req.JWTInfo = *jwtInfo
+		result, msg := s.SaveChannelRecommendationWeight(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
@@ -0,0 +1,72 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/global"
+	"go-klikdokter/helper/message"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type CourierHolidayEndpoint struct {
+	List   endpoint.Endpoint
+	Save   endpoint.Endpoint
+	Delete endpoint.Endpoint
+}
+
+func MakeCourierHolidayEndpoint(s service.CourierHolidayService) CourierHolidayEndpoint {
+	return CourierHolidayEndpoint{
+		List:   makeListCourierHoliday(s),
+		Save:   makeSaveCourierHoliday(s),
+		Delete: makeDeleteCourierHoliday(s),
+	}
+}
+
+func makeListCourierHoliday(s service.CourierHolidayService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.ListCourierHoliday)
+		result, msg := s.ListCourierHoliday(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeSaveCourierHoliday(s service.CourierHolidayService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		jwtInfo, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.SaveCourierHoliday)
+		req.JWTInfo = *jwtInfo
+		result, msg := s.CreateCourierHoliday(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeDeleteCourierHoliday(s service.CourierHolidayService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		msg = s.DeleteCourierHoliday(fmt.Sprint(rqst))
+		return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+	}
+}
@@ -0,0 +1,37 @@
+package endpoint
+
+import (
+	"context"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/global"
+	"go-klikdokter/helper/message"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type OrderShippingOutboxEndpoint struct {
+	List endpoint.Endpoint
+}
+
+func MakeOrderShippingOutboxEndpoint(s service.OrderShippingOutboxService) OrderShippingOutboxEndpoint {
+	return OrderShippingOutboxEndpoint{
+		List: makeGetOrderShippingOutboxList(s),
+	}
+}
+
+func makeGetOrderShippingOutboxList(s service.OrderShippingOutboxService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.GetOrderShippingOutboxList)
+		result, pagination, msg := s.GetOrderShippingOutboxList(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, pagination), nil
+	}
+}
@@ -0,0 +1,55 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/global"
+	"go-klikdokter/helper/message"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type RateCardEndpoint struct {
+	List endpoint.Endpoint
+	Save endpoint.Endpoint
+}
+
+func MakeRateCardEndpoint(s service.RateCardService) RateCardEndpoint {
+	return RateCardEndpoint{
+		List: makeListRateCard(s),
+		Save: makeSaveRateCard(s),
+	}
+}
+
+func makeListRateCard(s service.RateCardService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		result, msg := s.ListRateCard(fmt.Sprint(rqst))
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeSaveRateCard(s service.RateCardService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		jwtInfo, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.SaveRateCard)
+		req.JWTInfo = *jwtInfo
+		result, msg := s.CreateRateCard(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
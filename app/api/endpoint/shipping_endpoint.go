@@ -15,9 +15,9 @@ import (
 type ShippingEndpoint struct {
 	GetShippingRate               endpoint.Endpoint
 	GetShippingRateByShippingType endpoint.Endpoint
+	GetBatchShippingRate          endpoint.Endpoint
 	CreateDelivery                endpoint.Endpoint
 	GetOrderShippingTracking      endpoint.Endpoint
-	UpdateStatusShipper           endpoint.Endpoint
 	GetOrderShippingList          endpoint.Endpoint
 	GetOrderShippingDetail        endpoint.Endpoint
 	CancelPickUp                  endpoint.Endpoint
@@ -25,17 +25,17 @@ type ShippingEndpoint struct {
 	GetOrderShippingLabel         endpoint.Endpoint
 	RepickupOrder                 endpoint.Endpoint
 	GetShippingTracking           endpoint.Endpoint
-	UpdateStatusGrab              endpoint.Endpoint
 	DownloadOrderShipping         endpoint.Endpoint
+	UpdateStatusOrderShipping     endpoint.Endpoint
 }
 
 func MakeShippingEndpoint(s service.ShippingService) ShippingEndpoint {
 	return ShippingEndpoint{
 		GetShippingRate:               makeGetShippingRate(s),
 		GetShippingRateByShippingType: makeGetShippingRateByShippingType(s),
+		GetBatchShippingRate:          makeGetBatchShippingRate(s),
 		CreateDelivery:                makeCreateDelivery(s),
 		GetOrderShippingTracking:      makeGetOrderShippingTracking(s),
-		UpdateStatusShipper:           makeUpdateStatusShipper(s),
 		GetOrderShippingList:          makeGetOrderShippingList(s),
 		GetOrderShippingDetail:        makeGetOrderShippingDetail(s),
 		CancelPickUp:                  makeCancelPickup(s),
@@ -43,8 +43,8 @@ func MakeShippingEndpoint(s service.ShippingService) ShippingEndpoint {
 		GetOrderShippingLabel:         makeGetOrderShippingLabel(s),
 		RepickupOrder:                 makeRepickupOrder(s),
 		GetShippingTracking:           makeGetShippingTracking(s),
-		UpdateStatusGrab:              makeUpdateStatusGrab(s),
 		DownloadOrderShipping:         makeDownloadOrderShipping(s),
+		UpdateStatusOrderShipping:     makeUpdateStatusOrderShipping(s),
 	}
 }
 
@@ -58,7 +58,7 @@ func makeGetShippingRate(s service.ShippingService) endpoint.Endpoint {
 		}
 
 		req := rqst.(request.GetShippingRateRequest)
-		result, msg := s.GetShippingRate(req)
+		result, msg := s.GetShippingRate(ctx, req)
 		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
 	}
 }
@@ -73,7 +73,22 @@ func makeGetShippingRateByShippingType(s service.ShippingService) endpoint.Endpo
 		}
 
 		req := rqst.(request.GetShippingRateRequest)
-		result, msg := s.GetShippingRateByShippingType(req)
+		result, msg := s.GetShippingRateByShippingType(ctx, req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeGetBatchShippingRate(s service.ShippingService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.BatchShippingRateRequest)
+		result, msg := s.GetBatchShippingRate(ctx, &req)
 		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
 	}
 }
@@ -89,7 +104,7 @@ func makeCreateDelivery(s service.ShippingService) endpoint.Endpoint {
 		*/
 		req := rqst.(request.CreateDelivery)
 		// req.JWTInfo = *jwtInfo
-		result, msg := s.CreateDelivery(&req)
+		result, msg := s.CreateDelivery(ctx, &req)
 		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
 	}
 }
@@ -103,19 +118,10 @@ func makeGetOrderShippingTracking(s service.ShippingService) endpoint.Endpoint {
 		}
 
 		req := rqst.(request.GetOrderShippingTracking)
-		result, msg := s.OrderShippingTracking(&req)
+		result, msg := s.OrderShippingTracking(ctx, &req)
 		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
 	}
 }
-func makeUpdateStatusShipper(s service.ShippingService) endpoint.Endpoint {
-	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
-
-		req := rqst.(request.WebhookUpdateStatusShipper)
-		_, msg := s.UpdateStatusShipper(&req)
-		return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
-	}
-}
-
 func makeGetOrderShippingList(s service.ShippingService) endpoint.Endpoint {
 	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
 
@@ -172,7 +178,7 @@ func makeCancelPickup(s service.ShippingService) endpoint.Endpoint {
 		*/
 		req := rqst.(request.CancelPickup)
 
-		msg := s.CancelPickup(&req)
+		msg := s.CancelPickup(ctx, &req)
 		return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
 	}
 }
@@ -188,7 +194,7 @@ func makeCancelOrder(s service.ShippingService) endpoint.Endpoint {
 		*/
 		req := rqst.(request.CancelOrder)
 		// req.Body.JWTInfo = *jwtInfo
-		msg := s.CancelOrder(&req)
+		msg := s.CancelOrder(ctx, &req)
 		return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
 	}
 }
@@ -212,7 +218,7 @@ func makeRepickupOrder(s service.ShippingService) endpoint.Endpoint {
 	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
 
 		req := rqst.(request.RepickupOrderRequest)
-		result, msg := s.RepickupOrder(&req)
+		result, msg := s.RepickupOrder(ctx, &req)
 		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
 	}
 }
@@ -221,16 +227,26 @@ func makeGetShippingTracking(s service.ShippingService) endpoint.Endpoint {
 	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
 
 		req := rqst.(request.GetOrderShippingTracking)
-		result, msg := s.ShippingTracking(&req)
+		result, msg := s.ShippingTracking(ctx, &req)
 		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
 	}
 }
 
-func makeUpdateStatusGrab(s service.ShippingService) endpoint.Endpoint {
+func makeUpdateStatusOrderShipping(s service.ShippingService) endpoint.Endpoint {
 	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
 
-		req := rqst.(request.WebhookUpdateStatusGrabRequest)
-		msg := s.UpdateStatusGrab(&req)
+		// Retrieve JWT Info
+		jwtInfo, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.UpdateStatusOrderShipping)
+		if len(req.Body.Username) == 0 {
+			req.Body.Username = jwtInfo.ActorName
+		}
+
+		msg = s.UpdateStatusOrderShipping(&req)
 		return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
 	}
 }
@@ -0,0 +1,89 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/global"
+	"go-klikdokter/helper/message"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type ShippingPromotionEndpoint struct {
+	List   endpoint.Endpoint
+	Save   endpoint.Endpoint
+	Update endpoint.Endpoint
+	Delete endpoint.Endpoint
+}
+
+func MakeShippingPromotionEndpoint(s service.ShippingPromotionService) ShippingPromotionEndpoint {
+	return ShippingPromotionEndpoint{
+		List:   makeListShippingPromotion(s),
+		Save:   makeSaveShippingPromotion(s),
+		Update: makeUpdateShippingPromotion(s),
+		Delete: makeDeleteShippingPromotion(s),
+	}
+}
+
+func makeListShippingPromotion(s service.ShippingPromotionService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		result, msg := s.ListShippingPromotion(fmt.Sprint(rqst))
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeSaveShippingPromotion(s service.ShippingPromotionService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		jwtInfo, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.SaveShippingPromotion)
+		req.JWTInfo = *jwtInfo
+		result, msg := s.CreateShippingPromotion(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeUpdateShippingPromotion(s service.ShippingPromotionService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		jwtInfo, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.UpdateShippingPromotion)
+		req.JWTInfo = *jwtInfo
+		result, msg := s.UpdateShippingPromotion(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeDeleteShippingPromotion(s service.ShippingPromotionService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		msg = s.DeleteShippingPromotion(fmt.Sprint(rqst))
+		return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+	}
+}
@@ -0,0 +1,35 @@
+package endpoint
+
+import (
+	"context"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/global"
+	"go-klikdokter/helper/message"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type ShippingProviderEndpoint struct {
+	CircuitBreakerList endpoint.Endpoint
+}
+
+func MakeShippingProviderEndpoint(s service.ShippingProviderService) ShippingProviderEndpoint {
+	return ShippingProviderEndpoint{
+		CircuitBreakerList: makeGetCircuitBreakerList(s),
+	}
+}
+
+func makeGetCircuitBreakerList(s service.ShippingProviderService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		result, msg := s.GetCircuitBreakerList()
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
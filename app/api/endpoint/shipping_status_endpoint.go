@@ -0,0 +1,209 @@
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/global"
+	"go-klikdokter/helper/message"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type ShippingStatusEndpoint struct {
+	ChannelStatus       endpoint.Endpoint
+	Save                endpoint.Endpoint
+	Update              endpoint.Endpoint
+	Delete              endpoint.Endpoint
+	Clone               endpoint.Endpoint
+	SaveCourierStatus   endpoint.Endpoint
+	UpdateCourierStatus endpoint.Endpoint
+	DeleteCourierStatus endpoint.Endpoint
+	ChannelTransition   endpoint.Endpoint
+	SaveTransition      endpoint.Endpoint
+	DeleteTransition    endpoint.Endpoint
+}
+
+func MakeShippingStatusEndpoint(s service.ShippingStatusService) ShippingStatusEndpoint {
+	return ShippingStatusEndpoint{
+		ChannelStatus:       makeGetChannelShippingStatus(s),
+		Save:                makeSaveShippingStatus(s),
+		Update:              makeUpdateShippingStatus(s),
+		Delete:              makeDeleteShippingStatus(s),
+		Clone:               makeCloneShippingStatus(s),
+		SaveCourierStatus:   makeSaveShippingCourierStatus(s),
+		UpdateCourierStatus: makeUpdateShippingCourierStatus(s),
+		DeleteCourierStatus: makeDeleteShippingCourierStatus(s),
+		ChannelTransition:   makeGetChannelStatusTransition(s),
+		SaveTransition:      makeSaveStatusTransition(s),
+		DeleteTransition:    makeDeleteStatusTransition(s),
+	}
+}
+
+func makeGetChannelShippingStatus(s service.ShippingStatusService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		result, msg := s.GetChannelShippingStatus(fmt.Sprint(rqst))
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeSaveShippingStatus(s service.ShippingStatusService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		jwtInfo, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.SaveShippingStatus)
+		req.JWTInfo = *jwtInfo
+		result, msg := s.CreateShippingStatus(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeUpdateShippingStatus(s service.ShippingStatusService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		jwtInfo, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.UpdateShippingStatus)
+		req.JWTInfo = *jwtInfo
+		result, msg := s.UpdateShippingStatus(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeDeleteShippingStatus(s service.ShippingStatusService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		msg = s.DeleteShippingStatus(fmt.Sprint(rqst))
+		return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+	}
+}
+
+func makeCloneShippingStatus(s service.ShippingStatusService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		jwtInfo, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.CloneShippingStatus)
+		req.JWTInfo = *jwtInfo
+		result, msg := s.CloneShippingStatus(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeSaveShippingCourierStatus(s service.ShippingStatusService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		jwtInfo, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.SaveShippingCourierStatus)
+		req.JWTInfo = *jwtInfo
+		result, msg := s.CreateShippingCourierStatus(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeUpdateShippingCourierStatus(s service.ShippingStatusService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		jwtInfo, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.UpdateShippingCourierStatus)
+		req.JWTInfo = *jwtInfo
+		result, msg := s.UpdateShippingCourierStatus(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeDeleteShippingCourierStatus(s service.ShippingStatusService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		msg = s.DeleteShippingCourierStatus(fmt.Sprint(rqst))
+		return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+	}
+}
+
+func makeGetChannelStatusTransition(s service.ShippingStatusService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		result, msg := s.GetChannelStatusTransition(fmt.Sprint(rqst))
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeSaveStatusTransition(s service.ShippingStatusService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		jwtInfo, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.SaveStatusTransition)
+		req.JWTInfo = *jwtInfo
+		result, msg := s.CreateStatusTransition(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
+
+func makeDeleteStatusTransition(s service.ShippingStatusService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		msg = s.DeleteStatusTransition(fmt.Sprint(rqst))
+		return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+	}
+}
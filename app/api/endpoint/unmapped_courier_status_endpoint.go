@@ -0,0 +1,55 @@
+package endpoint
+
+import (
+	"context"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/global"
+	"go-klikdokter/helper/message"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type UnmappedCourierStatusEndpoint struct {
+	List endpoint.Endpoint
+	Map  endpoint.Endpoint
+}
+
+func MakeUnmappedCourierStatusEndpoint(s service.UnmappedCourierStatusService) UnmappedCourierStatusEndpoint {
+	return UnmappedCourierStatusEndpoint{
+		List: makeGetUnmappedCourierStatusList(s),
+		Map:  makeMapUnmappedCourierStatus(s),
+	}
+}
+
+func makeGetUnmappedCourierStatusList(s service.UnmappedCourierStatusService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.GetUnmappedCourierStatusList)
+		result, pagination, msg := s.GetUnmappedCourierStatusList(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, pagination), nil
+	}
+}
+
+func makeMapUnmappedCourierStatus(s service.UnmappedCourierStatusService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		jwtInfo, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.MapUnmappedCourierStatus)
+		req.JWTInfo = *jwtInfo
+		result, msg := s.MapUnmappedCourierStatus(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
@@ -0,0 +1,66 @@
+package endpoint
+
+import (
+	"context"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/global"
+	"go-klikdokter/helper/message"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type WebhookEndpoint struct {
+	ReceiveWebhook    endpoint.Endpoint
+	GetWebhookLogList endpoint.Endpoint
+	RerunWebhookLog   endpoint.Endpoint
+}
+
+func MakeWebhookEndpoint(s service.WebhookService) WebhookEndpoint {
+	return WebhookEndpoint{
+		ReceiveWebhook:    makeReceiveWebhook(s),
+		GetWebhookLogList: makeGetWebhookLogList(s),
+		RerunWebhookLog:   makeRerunWebhookLog(s),
+	}
+}
+
+func makeReceiveWebhook(s service.WebhookService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		req := rqst.(request.ReceiveWebhook)
+		msg := s.ReceiveWebhook(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+	}
+}
+
+func makeGetWebhookLogList(s service.WebhookService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		_, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := rqst.(request.GetWebhookLogList)
+		result, pagination, msg := s.GetWebhookLogList(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, pagination), nil
+	}
+}
+
+func makeRerunWebhookLog(s service.WebhookService) endpoint.Endpoint {
+	return func(ctx context.Context, rqst interface{}) (resp interface{}, err error) {
+
+		// Retrieve JWT Info
+		jwtInfo, msg := global.SetJWTInfoFromContext(ctx)
+		if msg.Code != message.SuccessMsg.Code {
+			return base.SetHttpResponse(msg.Code, msg.Message, nil, nil), nil
+		}
+
+		req := request.RerunWebhookLog{UID: rqst.(string)}
+		req.JWTInfo = *jwtInfo
+		result, msg := s.RerunWebhookLog(&req)
+		return base.SetHttpResponse(msg.Code, msg.Message, result, nil), nil
+	}
+}
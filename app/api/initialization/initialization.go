@@ -1,10 +1,12 @@
 package initialization
 
 import (
+	"context"
 	"fmt"
 	"go-klikdokter/app/api/transport"
 	"go-klikdokter/app/model/entity"
 	"go-klikdokter/app/registry"
+	"go-klikdokter/app/service"
 	"go-klikdokter/helper/config"
 	"go-klikdokter/helper/database"
 	"go-klikdokter/helper/global"
@@ -30,14 +32,27 @@ func DbInit(logger log.Logger) (*gorm.DB, error) {
 	_ = db.AutoMigrate(&entity.ShippmentPredefined{})
 	_ = db.AutoMigrate(&entity.CourierCoverageCode{})
 	_ = db.AutoMigrate(&entity.CourierService{})
+	_ = db.AutoMigrate(&entity.CourierHoliday{})
 	_ = db.AutoMigrate(&entity.Channel{})
 	_ = db.AutoMigrate(&entity.ChannelCourier{})
 	_ = db.AutoMigrate(&entity.ChannelCourierService{})
+	_ = db.AutoMigrate(&entity.ChannelCourierCredential{})
+	_ = db.AutoMigrate(&entity.RateCard{})
+	_ = db.AutoMigrate(&entity.ChannelPriceRule{})
+	_ = db.AutoMigrate(&entity.ShippingPromotion{})
+	_ = db.AutoMigrate(&entity.ChannelRecommendationWeight{})
 	_ = db.AutoMigrate(&entity.ShippingStatus{})
 	_ = db.AutoMigrate(&entity.ShippingCourierStatus{})
+	_ = db.AutoMigrate(&entity.ShippingStatusTransition{})
 	_ = db.AutoMigrate(&entity.OrderShipping{})
 	_ = db.AutoMigrate(&entity.OrderShippingItem{})
 	_ = db.AutoMigrate(&entity.OrderShippingHistory{})
+	_ = db.AutoMigrate(&entity.OrderShippingOutbox{})
+	_ = db.AutoMigrate(&entity.ShippingPromotionUsage{})
+	_ = db.AutoMigrate(&entity.ShippingRateQuote{})
+	_ = db.AutoMigrate(&entity.IdempotencyKey{})
+	_ = db.AutoMigrate(&entity.WebhookLog{})
+	_ = db.AutoMigrate(&entity.UnmappedCourierStatus{})
 
 	return db, nil
 }
@@ -45,23 +60,38 @@ func DbInit(logger log.Logger) (*gorm.DB, error) {
 func InitRouting(db *gorm.DB, logger log.Logger, redis cache.RedisCache) *http.ServeMux {
 	// Service registry
 	courierSvc := registry.RegisterCourierService(db, logger)
+	courierHolidaySvc := registry.RegisterCourierHolidayService(db, logger)
 	channelCourierSvc := registry.RegisterChannelCourierService(db, logger)
+	channelCourierCredentialSvc := registry.RegisterChannelCourierCredentialService(db, logger)
 	channelSvc := registry.RegisterChannelService(db, logger)
+	shippingStatusSvc := registry.RegisterShippingStatusService(db, logger)
+	channelPriceRuleSvc := registry.RegisterChannelPriceRuleService(db, logger)
+	shippingPromotionSvc := registry.RegisterShippingPromotionService(db, logger)
+	recommendationWeightSvc := registry.RegisterChannelRecommendationWeightService(db, logger)
 	shipmentPredefinedService := registry.RegisterShipmentPredefinedService(db, logger)
 	courierCoverageCodeSvc := registry.RegisterCourierCoverageCodeService(db, logger)
 	channelCourierServiceSvc := registry.RegisterChannelCourierServiceService(db, logger)
+	rateCardSvc := registry.RegisterRateCardService(db, logger)
 	shippingService := registry.RegisterShippingService(db, logger, redis)
+	orderShippingOutboxSvc := registry.RegisterOrderShippingOutboxService(db, logger)
+	webhookSvc := registry.RegisterWebhookService(db, logger, shippingService)
+	unmappedCourierStatusSvc := registry.RegisterUnmappedCourierStatusService(db, logger, webhookSvc)
+	shippingProviderSvc := registry.RegisterShippingProviderService(logger)
+
+	// Background workers
+	go service.RunOutboxRelay(context.Background(), orderShippingOutboxSvc, viper.GetDuration("outbox.relay.interval"))
+	go service.RunRateQuotePurge(context.Background(), shippingService, viper.GetDuration("setting.rate-quote-purge-interval"))
 
 	// Transport initialization
 	swagHttp := transport.SwaggerHttpHandler(log.With(logger, "SwaggerTransportLayer", "HTTP")) //don't delete or change this !!
-	courierHttp := transport.CourierHttpHandler(courierSvc, channelCourierSvc, log.With(logger, "CourierTransportLayer", "HTTP"))
-	channelCourierHttp := transport.ChannelCourierHttpHandler(channelCourierSvc, log.With(logger, "ChannelCourierTransportLayer", "HTTP"))
+	courierHttp := transport.CourierHttpHandler(courierSvc, channelCourierSvc, courierHolidaySvc, log.With(logger, "CourierTransportLayer", "HTTP"))
+	channelCourierHttp := transport.ChannelCourierHttpHandler(channelCourierSvc, channelCourierCredentialSvc, log.With(logger, "ChannelCourierTransportLayer", "HTTP"))
 	courierCoverageCodeHttp := transport.CourierCoverageCodeHttpHandler(courierCoverageCodeSvc, log.With(logger, "CourierCoverageCodeTransportLayer", "HTTP"))
 	shipmentPredefinedHttp := transport.ShipmentPredefinedHandler(shipmentPredefinedService, log.With(logger, "ShipmentPredefinedTransportLayer", "HTTP"))
-	channelHttp := transport.ChannelHttpHandler(channelSvc, channelCourierSvc, log.With(logger, "ChannelTransportLayer", "HTTP"))
-	channelCourierServiceHttp := transport.ChannelCourierServiceHttpHandler(channelCourierServiceSvc, log.With(logger, "ChannelCourierServiceTransportLayer", "HTTP"))
-	shippingHttp := transport.ShippingHttpHandler(shippingService, log.With(logger, "ShippingTransportLayer", "HTTP"))
-	webhookHttp := transport.WebhookHttpHandler(shippingService, log.With(logger, "WebhookTransportLayer", "HTTP"))
+	channelHttp := transport.ChannelHttpHandler(channelSvc, channelCourierSvc, shippingStatusSvc, channelPriceRuleSvc, shippingPromotionSvc, recommendationWeightSvc, log.With(logger, "ChannelTransportLayer", "HTTP"))
+	channelCourierServiceHttp := transport.ChannelCourierServiceHttpHandler(channelCourierServiceSvc, rateCardSvc, log.With(logger, "ChannelCourierServiceTransportLayer", "HTTP"))
+	shippingHttp := transport.ShippingHttpHandler(shippingService, orderShippingOutboxSvc, webhookSvc, unmappedCourierStatusSvc, shippingProviderSvc, log.With(logger, "ShippingTransportLayer", "HTTP"))
+	webhookHttp := transport.WebhookHttpHandler(webhookSvc, log.With(logger, "WebhookTransportLayer", "HTTP"))
 
 	// Routing path
 	mux := http.NewServeMux()
@@ -18,10 +18,11 @@ import (
 	"github.com/gorilla/schema"
 )
 
-func ChannelCourierHttpHandler(s service.ChannelCourierService, logger log.Logger) http.Handler {
+func ChannelCourierHttpHandler(s service.ChannelCourierService, ccs service.ChannelCourierCredentialService, logger log.Logger) http.Handler {
 	pr := mux.NewRouter()
 
 	ep := endpoint.MakeChannelCourierEndpoints(s)
+	credentialEp := endpoint.MakeChannelCourierCredentialEndpoint(ccs)
 	options := []httptransport.ServerOption{
 		httptransport.ServerErrorLogger(logger),
 		httptransport.ServerErrorEncoder(encoder.EncodeError),
@@ -63,6 +64,27 @@ func ChannelCourierHttpHandler(s service.ChannelCourierService, logger log.Logge
 		options...,
 	))
 
+	pr.Methods("GET").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannelCourier, global.PathChannelCourierCredential)).Handler(httptransport.NewServer(
+		credentialEp.Get,
+		encoder.UIDRequestHTTP,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("PUT").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannelCourier, global.PathChannelCourierCredential)).Handler(httptransport.NewServer(
+		credentialEp.Save,
+		decodeSaveChannelCourierCredential,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("DELETE").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannelCourier, global.PathChannelCourierCredential)).Handler(httptransport.NewServer(
+		credentialEp.Delete,
+		encoder.UIDRequestHTTP,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
 	return pr
 }
 
@@ -100,3 +122,13 @@ func decodeUpdateChannelCourier(ctx context.Context, r *http.Request) (rqst inte
 	req.Uid = mux.Vars(r)[pathUID]
 	return req, nil
 }
+
+func decodeSaveChannelCourierCredential(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var req request.SaveChannelCourierCredential
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		return nil, err
+	}
+
+	req.UID = mux.Vars(r)[pathUID]
+	return req, nil
+}
@@ -18,10 +18,11 @@ import (
 	"github.com/gorilla/schema"
 )
 
-func ChannelCourierServiceHttpHandler(s service.ChannelCourierServiceService, logger log.Logger) http.Handler {
+func ChannelCourierServiceHttpHandler(s service.ChannelCourierServiceService, rcs service.RateCardService, logger log.Logger) http.Handler {
 	pr := mux.NewRouter()
 
 	ep := endpoint.MakeChannelCourierServiceEndpoints(s)
+	rateCardEp := endpoint.MakeRateCardEndpoint(rcs)
 	options := []httptransport.ServerOption{
 		httptransport.ServerErrorLogger(logger),
 		httptransport.ServerErrorEncoder(encoder.EncodeError),
@@ -63,6 +64,20 @@ func ChannelCourierServiceHttpHandler(s service.ChannelCourierServiceService, lo
 		options...,
 	))
 
+	pr.Methods("GET").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannelCourierService, global.PathRateCard)).Handler(httptransport.NewServer(
+		rateCardEp.List,
+		encoder.UIDRequestHTTP,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("POST").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannelCourierService, global.PathRateCard)).Handler(httptransport.NewServer(
+		rateCardEp.Save,
+		decodeSaveRateCard,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
 	return pr
 }
 
@@ -99,3 +114,13 @@ func decodeUpdateChannelCourierService(ctx context.Context, r *http.Request) (rq
 	req.UID = mux.Vars(r)[pathUID]
 	return req, nil
 }
+
+func decodeSaveRateCard(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var req request.SaveRateCard
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		return nil, err
+	}
+
+	req.UID = mux.Vars(r)[pathUID]
+	return req, nil
+}
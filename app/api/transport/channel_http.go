@@ -23,10 +23,14 @@ const (
 	pathUID = "uid"
 )
 
-func ChannelHttpHandler(s service.ChannelService, ccs service.ChannelCourierService, logger log.Logger) http.Handler {
+func ChannelHttpHandler(s service.ChannelService, ccs service.ChannelCourierService, ss service.ShippingStatusService, cpr service.ChannelPriceRuleService, sps service.ShippingPromotionService, crw service.ChannelRecommendationWeightService, logger log.Logger) http.Handler {
 	pr := mux.NewRouter()
 
 	ep := endpoint.MakeChannelEndpoints(s, ccs)
+	ssEp := endpoint.MakeShippingStatusEndpoint(ss)
+	cprEp := endpoint.MakeChannelPriceRuleEndpoint(cpr)
+	spsEp := endpoint.MakeShippingPromotionEndpoint(sps)
+	crwEp := endpoint.MakeChannelRecommendationWeightEndpoint(crw)
 	options := []httptransport.ServerOption{
 		httptransport.ServerErrorLogger(logger),
 		httptransport.ServerErrorEncoder(encoder.EncodeError),
@@ -82,6 +86,153 @@ func ChannelHttpHandler(s service.ChannelService, ccs service.ChannelCourierServ
 		options...,
 	))
 
+	pr.Methods("GET").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathChannelShippingStatus)).Handler(httptransport.NewServer(
+		ssEp.ChannelStatus,
+		encoder.UIDRequestHTTP,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("POST").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathShippingStatus)).Handler(httptransport.NewServer(
+		ssEp.Save,
+		decodeSaveShippingStatus,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("POST").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathShippingStatusClone)).Handler(httptransport.NewServer(
+		ssEp.Clone,
+		decodeCloneShippingStatus,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("PUT").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathShippingStatusUID)).Handler(httptransport.NewServer(
+		ssEp.Update,
+		decodeUpdateShippingStatus,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("DELETE").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathShippingStatusUID)).Handler(httptransport.NewServer(
+		ssEp.Delete,
+		encoder.UIDRequestHTTP,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("POST").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathShippingCourierStatus)).Handler(httptransport.NewServer(
+		ssEp.SaveCourierStatus,
+		decodeSaveShippingCourierStatus,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("PUT").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathShippingCourierStatusUID)).Handler(httptransport.NewServer(
+		ssEp.UpdateCourierStatus,
+		decodeUpdateShippingCourierStatus,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("DELETE").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathShippingCourierStatusUID)).Handler(httptransport.NewServer(
+		ssEp.DeleteCourierStatus,
+		encoder.UIDRequestHTTP,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("GET").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathChannelStatusTransition)).Handler(httptransport.NewServer(
+		ssEp.ChannelTransition,
+		encoder.UIDRequestHTTP,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("POST").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathStatusTransition)).Handler(httptransport.NewServer(
+		ssEp.SaveTransition,
+		decodeSaveStatusTransition,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("DELETE").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathStatusTransitionUID)).Handler(httptransport.NewServer(
+		ssEp.DeleteTransition,
+		encoder.UIDRequestHTTP,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("GET").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathChannelPriceRule)).Handler(httptransport.NewServer(
+		cprEp.List,
+		encoder.UIDRequestHTTP,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("POST").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathPriceRule)).Handler(httptransport.NewServer(
+		cprEp.Save,
+		decodeSaveChannelPriceRule,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("PUT").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathPriceRuleUID)).Handler(httptransport.NewServer(
+		cprEp.Update,
+		decodeUpdateChannelPriceRule,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("DELETE").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathPriceRuleUID)).Handler(httptransport.NewServer(
+		cprEp.Delete,
+		encoder.UIDRequestHTTP,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("GET").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathChannelShippingPromotion)).Handler(httptransport.NewServer(
+		spsEp.List,
+		encoder.UIDRequestHTTP,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("POST").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathShippingPromotion)).Handler(httptransport.NewServer(
+		spsEp.Save,
+		decodeSaveShippingPromotion,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("PUT").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathShippingPromotionUID)).Handler(httptransport.NewServer(
+		spsEp.Update,
+		decodeUpdateShippingPromotion,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("DELETE").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathShippingPromotionUID)).Handler(httptransport.NewServer(
+		spsEp.Delete,
+		encoder.UIDRequestHTTP,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("GET").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathChannelRecommendationWeight)).Handler(httptransport.NewServer(
+		crwEp.Get,
+		encoder.UIDRequestHTTP,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("PUT").Path(fmt.Sprint(global.PrefixBase, global.PrefixChannel, global.PathChannelRecommendationWeight)).Handler(httptransport.NewServer(
+		crwEp.Save,
+		decodeSaveChannelRecommendationWeight,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
 	return pr
 }
 
@@ -154,3 +305,107 @@ func decodeChannelCourierList(ctx context.Context, r *http.Request) (rqst interf
 
 	return params, nil
 }
+
+func decodeSaveShippingStatus(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.SaveShippingStatus
+	if err := json.NewDecoder(r.Body).Decode(&params.Body); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+func decodeUpdateShippingStatus(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.UpdateShippingStatus
+	if err := json.NewDecoder(r.Body).Decode(&params.Body); err != nil {
+		return nil, err
+	}
+
+	params.UID = mux.Vars(r)[pathUID]
+	return params, nil
+}
+
+func decodeCloneShippingStatus(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.CloneShippingStatus
+	if err := json.NewDecoder(r.Body).Decode(&params.Body); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+func decodeSaveStatusTransition(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.SaveStatusTransition
+	if err := json.NewDecoder(r.Body).Decode(&params.Body); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+func decodeSaveShippingCourierStatus(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.SaveShippingCourierStatus
+	if err := json.NewDecoder(r.Body).Decode(&params.Body); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+func decodeUpdateShippingCourierStatus(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.UpdateShippingCourierStatus
+	if err := json.NewDecoder(r.Body).Decode(&params.Body); err != nil {
+		return nil, err
+	}
+
+	params.UID = mux.Vars(r)[pathUID]
+	return params, nil
+}
+
+func decodeSaveChannelPriceRule(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.SaveChannelPriceRule
+	if err := json.NewDecoder(r.Body).Decode(&params.Body); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+func decodeUpdateChannelPriceRule(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.UpdateChannelPriceRule
+	if err := json.NewDecoder(r.Body).Decode(&params.Body); err != nil {
+		return nil, err
+	}
+
+	params.UID = mux.Vars(r)[pathUID]
+	return params, nil
+}
+
+func decodeSaveShippingPromotion(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.SaveShippingPromotion
+	if err := json.NewDecoder(r.Body).Decode(&params.Body); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+func decodeUpdateShippingPromotion(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.UpdateShippingPromotion
+	if err := json.NewDecoder(r.Body).Decode(&params.Body); err != nil {
+		return nil, err
+	}
+
+	params.UID = mux.Vars(r)[pathUID]
+	return params, nil
+}
+
+func decodeSaveChannelRecommendationWeight(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.SaveChannelRecommendationWeight
+	if err := json.NewDecoder(r.Body).Decode(&params.Body); err != nil {
+		return nil, err
+	}
+
+	params.UID = mux.Vars(r)[pathUID]
+	return params, nil
+}
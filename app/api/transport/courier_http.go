@@ -18,10 +18,11 @@ import (
 	"github.com/gorilla/schema"
 )
 
-func CourierHttpHandler(s service.CourierService, cc service.ChannelCourierService, logger log.Logger) http.Handler {
+func CourierHttpHandler(s service.CourierService, cc service.ChannelCourierService, chs service.CourierHolidayService, logger log.Logger) http.Handler {
 	pr := mux.NewRouter()
 
 	ep := endpoint.MakeCourierEndpoints(s, cc)
+	chsEp := endpoint.MakeCourierHolidayEndpoint(chs)
 	options := []httptransport.ServerOption{
 		httptransport.ServerErrorLogger(logger),
 		httptransport.ServerErrorEncoder(encoder.EncodeError),
@@ -105,6 +106,27 @@ func CourierHttpHandler(s service.CourierService, cc service.ChannelCourierServi
 		encoder.EncodeResponseHTTP,
 		options...,
 	))
+
+	pr.Methods("GET").Path(fmt.Sprint(global.PrefixBase, global.PrefixCourier, global.PathCourierHoliday)).Handler(httptransport.NewServer(
+		chsEp.List,
+		decodeListCourierHoliday,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("POST").Path(fmt.Sprint(global.PrefixBase, global.PrefixCourier, global.PathCourierHoliday)).Handler(httptransport.NewServer(
+		chsEp.Save,
+		decodeSaveCourierHoliday,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("DELETE").Path(fmt.Sprint(global.PrefixBase, global.PrefixCourier, global.PathCourierHolidayUID)).Handler(httptransport.NewServer(
+		chsEp.Delete,
+		encoder.UIDRequestHTTP,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
 	return pr
 }
 
@@ -199,3 +221,26 @@ func decodeUpdateCourierService(ctx context.Context, r *http.Request) (rqst inte
 	req.Uid = mux.Vars(r)[pathUID]
 	return req, nil
 }
+
+func decodeListCourierHoliday(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.ListCourierHoliday
+
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	if err = schema.NewDecoder().Decode(&params, r.Form); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+func decodeSaveCourierHoliday(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.SaveCourierHoliday
+	if err := json.NewDecoder(r.Body).Decode(&params.Body); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
@@ -29,10 +29,14 @@ const (
 	channelUID       = "channel-uid"
 )
 
-func ShippingHttpHandler(s service.ShippingService, logger log.Logger) http.Handler {
+func ShippingHttpHandler(s service.ShippingService, os service.OrderShippingOutboxService, ws service.WebhookService, us service.UnmappedCourierStatusService, ps service.ShippingProviderService, logger log.Logger) http.Handler {
 	pr := mux.NewRouter()
 
 	ep := endpoint.MakeShippingEndpoint(s)
+	oep := endpoint.MakeOrderShippingOutboxEndpoint(os)
+	wep := endpoint.MakeWebhookEndpoint(ws)
+	uep := endpoint.MakeUnmappedCourierStatusEndpoint(us)
+	pep := endpoint.MakeShippingProviderEndpoint(ps)
 	options := []httptransport.ServerOption{
 		httptransport.ServerErrorLogger(logger),
 		httptransport.ServerErrorEncoder(encoder.EncodeError),
@@ -46,6 +50,14 @@ func ShippingHttpHandler(s service.ShippingService, logger log.Logger) http.Hand
 		options...,
 	))
 
+	// registered before the shipping type path, batch would be matched as a shipping type
+	pr.Methods("POST").Path(fmt.Sprint(global.PrefixBase, global.PrefixShipping, global.PathShippingRateBatch)).Handler(httptransport.NewServer(
+		ep.GetBatchShippingRate,
+		decodeGetBatchShippingRate,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
 	pr.Methods("POST").Path(fmt.Sprint(global.PrefixBase, global.PrefixShipping, global.PathShippingRateShippingType)).Handler(httptransport.NewServer(
 		ep.GetShippingRateByShippingType,
 		decodeGetShippingRate,
@@ -122,6 +134,55 @@ func ShippingHttpHandler(s service.ShippingService, logger log.Logger) http.Hand
 		encoder.EncodeResponseHTTP,
 		options...,
 	))
+
+	pr.Methods("POST").Path(fmt.Sprint(global.PrefixBase, global.PrefixShipping, global.PathUpdateStatusUID)).Handler(httptransport.NewServer(
+		ep.UpdateStatusOrderShipping,
+		decodeUpdateStatusOrderShipping,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("GET").Path(fmt.Sprint(global.PrefixBase, global.PrefixShipping, global.PathOrderShippingOutbox)).Handler(httptransport.NewServer(
+		oep.List,
+		decodeGetOrderShippingOutboxList,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("GET").Path(fmt.Sprint(global.PrefixBase, global.PrefixShipping, global.PathCircuitBreaker)).Handler(httptransport.NewServer(
+		pep.CircuitBreakerList,
+		encoder.DecodeEmptyRequestHTTP,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("GET").Path(fmt.Sprint(global.PrefixBase, global.PrefixShipping, global.PathWebhookLog)).Handler(httptransport.NewServer(
+		wep.GetWebhookLogList,
+		decodeGetWebhookLogList,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("POST").Path(fmt.Sprint(global.PrefixBase, global.PrefixShipping, global.PathWebhookLogRerun)).Handler(httptransport.NewServer(
+		wep.RerunWebhookLog,
+		encoder.UIDRequestHTTP,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("GET").Path(fmt.Sprint(global.PrefixBase, global.PrefixShipping, global.PathUnmappedCourierStatus)).Handler(httptransport.NewServer(
+		uep.List,
+		decodeGetUnmappedCourierStatusList,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
+
+	pr.Methods("POST").Path(fmt.Sprint(global.PrefixBase, global.PrefixShipping, global.PathUnmappedCourierStatusMap)).Handler(httptransport.NewServer(
+		uep.Map,
+		decodeMapUnmappedCourierStatus,
+		encoder.EncodeResponseHTTP,
+		options...,
+	))
 	return pr
 }
 
@@ -134,12 +195,21 @@ func decodeGetShippingRate(ctx context.Context, r *http.Request) (rqst interface
 	return req, nil
 }
 
+func decodeGetBatchShippingRate(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var req request.BatchShippingRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
 func decodeCreateDelivery(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
 	var req request.CreateDelivery
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return nil, err
 	}
 
+	req.IdempotencyKey = r.Header.Get("Idempotency-Key")
 	return req, nil
 }
 
@@ -170,6 +240,55 @@ func decodeGetOrderShippingList(ctx context.Context, r *http.Request) (rqst inte
 	return params, nil
 }
 
+func decodeGetOrderShippingOutboxList(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.GetOrderShippingOutboxList
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	if err = schema.NewDecoder().Decode(&params, r.Form); err != nil {
+		return nil, err
+	}
+	params.GetFilter()
+	return params, nil
+}
+
+func decodeGetWebhookLogList(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.GetWebhookLogList
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	if err = schema.NewDecoder().Decode(&params, r.Form); err != nil {
+		return nil, err
+	}
+	params.GetFilter()
+	return params, nil
+}
+
+func decodeGetUnmappedCourierStatusList(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.GetUnmappedCourierStatusList
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	if err = schema.NewDecoder().Decode(&params, r.Form); err != nil {
+		return nil, err
+	}
+	params.GetFilter()
+	return params, nil
+}
+
+func decodeMapUnmappedCourierStatus(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.MapUnmappedCourierStatus
+	if err := json.NewDecoder(r.Body).Decode(&params.Body); err != nil {
+		return nil, err
+	}
+
+	params.UID = mux.Vars(r)[pathUID]
+	return params, nil
+}
+
 func decodeOrderShippingDownload(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
 	var params request.DownloadOrderShipping
 	if err := r.ParseForm(); err != nil {
@@ -237,6 +356,16 @@ func decodeRepickupOrder(ctx context.Context, r *http.Request) (rqst interface{}
 	return params, nil
 }
 
+func decodeUpdateStatusOrderShipping(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+	var params request.UpdateStatusOrderShipping
+	if err := json.NewDecoder(r.Body).Decode(&params.Body); err != nil {
+		return nil, err
+	}
+
+	params.UID = mux.Vars(r)[pathUID]
+	return params, nil
+}
+
 func encodeOrderShippingDownload(ctx context.Context, w http.ResponseWriter, resp interface{}) error {
 	httpResponse := base.GetHttpResponse(resp)
 	code := httpResponse.Meta.Code
@@ -283,7 +412,8 @@ func mapToCsvArrays(orderShippings []response.DownloadOrderShipping) [][]string
 		"merchant_address", "merchant_province_name", "merchant_city_name", "merchant_district_name",
 		"merchant_subdistrict", "merchant_postal_code", "total_weight", "total_volume", "total_product_price",
 		"total_final_weight", "contain_prescription", "insurance", "insurance_cost", "shipping_cost",
-		"total_shipping_cost", "actual_shipping_cost", "shipping_notes", "shipping_status_name", "order_status_history",
+		"total_shipping_cost", "actual_shipping_cost", "cod", "cod_amount", "cod_fee", "shipping_notes",
+		"shipping_status_name", "order_status_history",
 	}
 
 	for i, os := range orderShippings {
@@ -325,6 +455,9 @@ func mapToCsvArrays(orderShippings []response.DownloadOrderShipping) [][]string
 			os.ShippingCost,
 			os.TotalShippingCost,
 			os.ActualShippingCost,
+			os.Cod,
+			os.CodAmount,
+			os.CodFee,
 			os.ShippingNotes,
 			os.ShippingStatusName,
 			os.OrderStatusHistory,
@@ -2,13 +2,14 @@ package transport
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"go-klikdokter/app/api/endpoint"
 	"go-klikdokter/app/model/base/encoder"
 	"go-klikdokter/app/model/request"
 	"go-klikdokter/app/service"
 	"go-klikdokter/helper/global"
+	"go-klikdokter/helper/http_helper/shipping_provider"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/go-kit/kit/auth/jwt"
@@ -17,10 +18,10 @@ import (
 	"github.com/gorilla/mux"
 )
 
-func WebhookHttpHandler(s service.ShippingService, logger log.Logger) http.Handler {
+func WebhookHttpHandler(s service.WebhookService, logger log.Logger) http.Handler {
 	pr := mux.NewRouter()
 
-	ep := endpoint.MakeShippingEndpoint(s)
+	ep := endpoint.MakeWebhookEndpoint(s)
 	options := []httptransport.ServerOption{
 		httptransport.ServerErrorLogger(logger),
 		httptransport.ServerErrorEncoder(encoder.EncodeError),
@@ -28,15 +29,15 @@ func WebhookHttpHandler(s service.ShippingService, logger log.Logger) http.Handl
 	}
 
 	pr.Methods("POST").Path(fmt.Sprint(global.PrefixBase, global.PrefixWebhook, global.PathShipper)).Handler(httptransport.NewServer(
-		ep.UpdateStatusShipper,
-		decodeUpdateStatusShipper,
+		ep.ReceiveWebhook,
+		decodeReceiveWebhook(shipping_provider.ShipperCode),
 		encoder.EncodeResponseHTTP,
 		options...,
 	))
 
 	pr.Methods("POST").Path(fmt.Sprint(global.PrefixBase, global.PrefixWebhook, global.PathGrab)).Handler(httptransport.NewServer(
-		ep.UpdateStatusGrab,
-		decodeUpdateStatusGrab,
+		ep.ReceiveWebhook,
+		decodeReceiveWebhook(shipping_provider.GrabCode),
 		encoder.EncodeResponseHTTP,
 		options...,
 	))
@@ -44,23 +45,18 @@ func WebhookHttpHandler(s service.ShippingService, logger log.Logger) http.Handl
 	return pr
 }
 
-func decodeUpdateStatusShipper(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
-	var req request.WebhookUpdateStatusShipper
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		return nil, err
+// decodeReceiveWebhook keeps the raw request, it is parsed by the webhook service so it can be stored as it was received
+func decodeReceiveWebhook(provider string) httptransport.DecodeRequestFunc {
+	return func(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return request.ReceiveWebhook{
+			Provider: provider,
+			Header:   r.Header.Clone(),
+			Body:     body,
+		}, nil
 	}
-
-	return req, nil
-}
-
-func decodeUpdateStatusGrab(ctx context.Context, r *http.Request) (rqst interface{}, err error) {
-	var req request.WebhookUpdateStatusGrabRequest
-	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
-		return nil, err
-	}
-
-	req.AuthorizationID = r.Header.Get("Authorization-Id")
-	req.Authorization = r.Header.Get("Authorization")
-
-	return req, nil
 }
@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"go-klikdokter/app/model/base"
+)
+
+// ChannelCourierCredential is the account of the channel at the provider of the courier, the provider calls of the channel
+// are made under it instead of the account in the config. The secret is encrypted with setting.credential-encryption-key.
+type ChannelCourierCredential struct {
+	base.BaseIDModel
+	ChannelCourierID uint64 `gorm:"type:bigint;not null;uniqueIndex"`
+	ClientID         string `gorm:"type:varchar(255)"`
+	Secret           string `gorm:"type:text;not null"`
+
+	ChannelCourier *ChannelCourier `gorm:"foreignKey:channel_courier_id"`
+}
+
+func (ChannelCourierCredential) TableName() string {
+	return "channel_courier_credential"
+}
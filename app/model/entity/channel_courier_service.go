@@ -46,6 +46,7 @@ type ChannelCourierServiceForShippingRate struct {
 	CourierTypeCode             string         `gorm:"column:courier_type_code"`
 	CourierTypeName             string         `gorm:"column:courier_type_name"`
 	CourierServiceUID           string         `gorm:"column:courier_service_uid"`
+	CourierServiceID            uint64         `gorm:"column:courier_service_id"`
 	ShippingCode                string         `gorm:"column:shipping_code"`
 	ShippingName                string         `gorm:"column:shipping_name"`
 	ShippingDescription         string         `gorm:"column:shipping_description"`
@@ -62,15 +63,30 @@ type ChannelCourierServiceForShippingRate struct {
 	ChannelCourierServiceStatus int32          `gorm:"column:channel_courier_service_status"`
 	HidePurpose                 int32          `gorm:"column:hide_purpose"`
 	PrescriptionAllowed         int32          `gorm:"column:prescription_allowed"`
+	ChannelCourierServiceID     uint64         `gorm:"column:channel_courier_service_id"`
+	MaxVolume                   float64        `gorm:"column:max_volume"`
+	MaxDistance                 float64        `gorm:"column:max_distance"`
+	MinPurchase                 float64        `gorm:"column:min_purchase"`
+	MaxPurchase                 float64        `gorm:"column:max_purchase"`
+	StartTime                   datatype.Time  `gorm:"column:start_time"`
+	EndTime                     datatype.Time  `gorm:"column:end_time"`
+	CodAvailable                int32          `gorm:"column:cod_available"`
+	CodFeeType                  string         `gorm:"column:cod_fee_type"`
+	CodFee                      float64        `gorm:"column:cod_fee"`
+	CodFeeMin                   float64        `gorm:"column:cod_fee_min"`
+	InsuranceMandatory          int32          `gorm:"column:insurance_mandatory"`
+	InsuranceFeeType            string         `gorm:"column:insurance_fee_type"`
+	InsuranceMin                float64        `gorm:"column:insurance_min"`
+	PrioritySort                int32          `gorm:"column:priority_sort"`
 }
 
-func (c *ChannelCourierServiceForShippingRate) Validate(finalWeight *float64, prescription_allowed *bool) message.Message {
+func (c *ChannelCourierServiceForShippingRate) Validate(shipment *Shipment) message.Message {
 
 	if msg, isValid := c.IsValidCourier(); !isValid {
 		return msg
 	}
 
-	if msg, isValid := c.IsValidCourierService(finalWeight, prescription_allowed); !isValid {
+	if msg, isValid := c.IsValidCourierService(shipment); !isValid {
 		return msg
 	}
 
@@ -96,7 +112,7 @@ func (c *ChannelCourierServiceForShippingRate) IsValidCourier() (message.Message
 }
 
 // validate courier service data
-func (c *ChannelCourierServiceForShippingRate) IsValidCourierService(finalWeight *float64, prescription_allowed *bool) (message.Message, bool) {
+func (c *ChannelCourierServiceForShippingRate) IsValidCourierService(shipment *Shipment) (message.Message, bool) {
 
 	if c.CourierServiceStatus != 1 {
 		return message.CourierServiceNotActiveMsg, false
@@ -106,13 +122,38 @@ func (c *ChannelCourierServiceForShippingRate) IsValidCourierService(finalWeight
 		return message.ChannelCourierServiceNotActiveMsg, false
 	}
 
-	if c.MaxWeight > 0 && c.MaxWeight < *finalWeight {
-		return message.WeightExceedsmsg, false
+	if msg := c.Limit().Validate(shipment); msg != message.SuccessMsg {
+		return msg, false
 	}
 
-	if *prescription_allowed && c.PrescriptionAllowed != 1 {
-		return message.PrescriptionNotAllowedMsg, false
+	return message.SuccessMsg, true
+}
+
+// CodFeeAmount is the fee charged to collect codAmount on delivery
+func (c *ChannelCourierServiceForShippingRate) CodFeeAmount(codAmount float64) float64 {
+	return CalculateFee(c.CodFeeType, c.CodFee, c.CodFeeMin, codAmount)
+}
+
+func (c *ChannelCourierServiceForShippingRate) InsurancePolicy() *CourierServiceInsurance {
+	return &CourierServiceInsurance{
+		Available: c.UseInsurance,
+		Mandatory: c.InsuranceMandatory == 1,
+		FeeType:   c.InsuranceFeeType,
+		Fee:       c.InsuranceFee,
+		Min:       c.InsuranceMin,
 	}
+}
 
-	return message.SuccessMsg, true
+func (c *ChannelCourierServiceForShippingRate) Limit() *CourierServiceLimit {
+	return &CourierServiceLimit{
+		MaxWeight:           c.MaxWeight,
+		MaxVolume:           c.MaxVolume,
+		MaxDistance:         c.MaxDistance,
+		MinPurchase:         c.MinPurchase,
+		MaxPurchase:         c.MaxPurchase,
+		StartTime:           c.StartTime,
+		EndTime:             c.EndTime,
+		CodAvailable:        c.CodAvailable,
+		PrescriptionAllowed: c.PrescriptionAllowed,
+	}
 }
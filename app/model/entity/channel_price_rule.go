@@ -0,0 +1,178 @@
+package entity
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/helper/message"
+	"go-klikdokter/pkg/util"
+	"math"
+	"strings"
+)
+
+const (
+	PriceAdjustmentMarkupPercentage   = "markup_percentage"
+	PriceAdjustmentMarkupFixed        = "markup_fixed"
+	PriceAdjustmentDiscountPercentage = "discount_percentage"
+	PriceAdjustmentDiscountFixed      = "discount_fixed"
+)
+
+const (
+	RoundingNearest = "nearest"
+	RoundingUp      = "up"
+	RoundingDown    = "down"
+)
+
+// ChannelPriceRule adjusts the provider price of the shipping rates of a channel,
+// an empty scope matches every shipping rate. Matching rules are applied in priority order.
+type ChannelPriceRule struct {
+	base.BaseIDModel
+	ChannelID        uint64  `gorm:"type:bigint;not null;index"`
+	Name             string  `gorm:"type:varchar(100);size:100;not null"`
+	CourierID        *uint64 `gorm:"type:bigint"`
+	CourierServiceID *uint64 `gorm:"type:bigint"`
+	ShippingType     string  `gorm:"type:varchar(50);size:50;not null;default:''"`
+
+	// matches destination postal codes starting with the prefix
+	PostalCodePrefix string `gorm:"type:varchar(50);size:50;not null;default:''"`
+
+	// final weight band in kg, a zero bound is open
+	MinWeight float64 `gorm:"type:numeric;not null;default:0"`
+	MaxWeight float64 `gorm:"type:numeric;not null;default:0"`
+
+	AdjustmentType  string  `gorm:"type:varchar(30);size:30;not null"`
+	AdjustmentValue float64 `gorm:"type:numeric;not null;default:0"`
+
+	// the adjusted price is rounded to a multiple of RoundTo when it is set
+	RoundTo  float64 `gorm:"type:numeric;not null;default:0"`
+	Rounding string  `gorm:"type:varchar(30);size:30;not null;default:''"`
+
+	Priority int    `gorm:"type:int;not null;default:0"`
+	Status   *int32 `gorm:"type:int;not null;default:1"`
+
+	Channel        *Channel        `gorm:"foreignKey:channel_id"`
+	Courier        *Courier        `gorm:"foreignKey:courier_id"`
+	CourierService *CourierService `gorm:"foreignKey:courier_service_id"`
+}
+
+func (ChannelPriceRule) TableName() string {
+	return "channel_price_rule"
+}
+
+// PricedShipment is a shipping rate matched against the scope of a ChannelPriceRule
+type PricedShipment struct {
+	CourierID        uint64
+	CourierServiceID uint64
+	ShippingType     string
+	PostalCode       string
+	FinalWeight      float64
+}
+
+// PriceAdjustment is the amount a rule added to the price, a discount is negative
+type PriceAdjustment struct {
+	RuleUID string
+	Name    string
+	Amount  float64
+}
+
+func (r *ChannelPriceRule) Validate() message.Message {
+	if r.Name == "" {
+		return message.ErrReq
+	}
+
+	switch r.AdjustmentType {
+	case PriceAdjustmentMarkupPercentage, PriceAdjustmentMarkupFixed, PriceAdjustmentDiscountPercentage, PriceAdjustmentDiscountFixed:
+	default:
+		return message.ErrInvalidPriceAdjustmentType
+	}
+
+	if r.AdjustmentValue < 0 || r.RoundTo < 0 {
+		return message.ErrInvalidPriceRule
+	}
+
+	if r.MinWeight < 0 || r.MaxWeight < 0 || (r.MaxWeight > 0 && r.MaxWeight < r.MinWeight) {
+		return message.ErrInvalidPriceRule
+	}
+
+	switch r.Rounding {
+	case "", RoundingNearest, RoundingUp, RoundingDown:
+	default:
+		return message.ErrInvalidPriceRule
+	}
+
+	return message.SuccessMsg
+}
+
+func (r *ChannelPriceRule) IsActive() bool {
+	return r.Status == nil || *r.Status == 1
+}
+
+func (r *ChannelPriceRule) Matches(shipment *PricedShipment) bool {
+	if r.CourierID != nil && *r.CourierID != shipment.CourierID {
+		return false
+	}
+
+	if r.CourierServiceID != nil && *r.CourierServiceID != shipment.CourierServiceID {
+		return false
+	}
+
+	if r.ShippingType != "" && r.ShippingType != shipment.ShippingType {
+		return false
+	}
+
+	if r.PostalCodePrefix != "" && !strings.HasPrefix(shipment.PostalCode, r.PostalCodePrefix) {
+		return false
+	}
+
+	if shipment.FinalWeight < r.MinWeight {
+		return false
+	}
+
+	return r.MaxWeight == 0 || shipment.FinalWeight <= r.MaxWeight
+}
+
+// Apply adjusts the price then rounds it, the price never goes below zero
+func (r *ChannelPriceRule) Apply(price float64) float64 {
+	switch r.AdjustmentType {
+	case PriceAdjustmentMarkupPercentage:
+		price += price * r.AdjustmentValue / 100
+	case PriceAdjustmentMarkupFixed:
+		price += r.AdjustmentValue
+	case PriceAdjustmentDiscountPercentage:
+		price -= price * r.AdjustmentValue / 100
+	case PriceAdjustmentDiscountFixed:
+		price -= r.AdjustmentValue
+	}
+
+	if r.RoundTo > 0 {
+		switch r.Rounding {
+		case RoundingUp:
+			price = math.Ceil(price/r.RoundTo) * r.RoundTo
+		case RoundingDown:
+			price = math.Floor(price/r.RoundTo) * r.RoundTo
+		default:
+			price = math.Round(price/r.RoundTo) * r.RoundTo
+		}
+	}
+
+	return util.RoundFloat(math.Max(price, 0), 2)
+}
+
+// ApplyPriceRules applies the matching active rules in the given order
+func ApplyPriceRules(rules []ChannelPriceRule, shipment *PricedShipment, price float64) (float64, []PriceAdjustment) {
+	var adjustments []PriceAdjustment
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.IsActive() || !rule.Matches(shipment) {
+			continue
+		}
+
+		adjusted := rule.Apply(price)
+		adjustments = append(adjustments, PriceAdjustment{
+			RuleUID: rule.UID,
+			Name:    rule.Name,
+			Amount:  util.RoundFloat(adjusted-price, 2),
+		})
+		price = adjusted
+	}
+
+	return price, adjustments
+}
@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/helper/message"
+	"go-klikdokter/pkg/util"
+)
+
+// ChannelRecommendationWeight weighs the factors of the recommended score of the shipping rates of a channel,
+// channels without weights use the weights of setting.recommendation.weight
+type ChannelRecommendationWeight struct {
+	base.BaseIDModel
+	ChannelID         uint64  `gorm:"type:bigint;not null;uniqueIndex"`
+	PriceWeight       float64 `gorm:"type:numeric;not null;default:0"`
+	EtdWeight         float64 `gorm:"type:numeric;not null;default:0"`
+	PriorityWeight    float64 `gorm:"type:numeric;not null;default:0"`
+	ReliabilityWeight float64 `gorm:"type:numeric;not null;default:0"`
+
+	Channel *Channel `gorm:"foreignKey:channel_id"`
+}
+
+func (ChannelRecommendationWeight) TableName() string {
+	return "channel_recommendation_weight"
+}
+
+// RecommendationFactors of a shipping rate are normalized between 0 and 1, higher is better
+type RecommendationFactors struct {
+	Price       float64
+	Etd         float64
+	Priority    float64
+	Reliability float64
+}
+
+func (w *ChannelRecommendationWeight) Validate() message.Message {
+	if w.PriceWeight < 0 || w.EtdWeight < 0 || w.PriorityWeight < 0 || w.ReliabilityWeight < 0 {
+		return message.ErrInvalidRecommendationWeight
+	}
+
+	if w.total() == 0 {
+		return message.ErrInvalidRecommendationWeight
+	}
+
+	return message.SuccessMsg
+}
+
+// Score is the weighted average of the factors
+func (w *ChannelRecommendationWeight) Score(factors *RecommendationFactors) float64 {
+	total := w.total()
+	if total == 0 {
+		return 0
+	}
+
+	score := w.PriceWeight*factors.Price +
+		w.EtdWeight*factors.Etd +
+		w.PriorityWeight*factors.Priority +
+		w.ReliabilityWeight*factors.Reliability
+
+	return util.RoundFloat(score/total, 4)
+}
+
+func (w *ChannelRecommendationWeight) total() float64 {
+	return w.PriceWeight + w.EtdWeight + w.PriorityWeight + w.ReliabilityWeight
+}
+
+// CourierServiceReliability counts the recent orders of a courier service of a channel
+type CourierServiceReliability struct {
+	CourierServiceID uint64 `gorm:"column:courier_service_id"`
+	Total            int64  `gorm:"column:total"`
+	Cancelled        int64  `gorm:"column:cancelled"`
+}
+
+// Rate is the share of the orders that were not cancelled
+func (r *CourierServiceReliability) Rate() float64 {
+	if r.Total == 0 {
+		return 1
+	}
+
+	return float64(r.Total-r.Cancelled) / float64(r.Total)
+}
+
+// MeanReliability is the mean rate of the courier services with recent orders, a service without orders is rated
+// at the mean so it is neither favored nor penalized. Every service is rated the same when none has orders.
+func MeanReliability(reliability map[uint64]float64) float64 {
+	if len(reliability) == 0 {
+		return 1
+	}
+
+	var total float64
+	for _, v := range reliability {
+		total += v
+	}
+
+	return total / float64(len(reliability))
+}
+
+// NormalizeLowerIsBetter scales the value between the min and max of the compared rates to 1 for the min and 0 for the max
+func NormalizeLowerIsBetter(value, min, max float64) float64 {
+	if max <= min {
+		return 1
+	}
+
+	return (max - value) / (max - min)
+}
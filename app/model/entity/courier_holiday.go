@@ -0,0 +1,124 @@
+package entity
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/pkg/util"
+	"go-klikdokter/pkg/util/datatype"
+	"math"
+	"time"
+)
+
+// CourierHoliday is a day the courier does not pick up or deliver,
+// a holiday without a courier applies to every courier
+type CourierHoliday struct {
+	base.BaseIDModel
+	CourierID   *uint64   `gorm:"type:bigint;index"`
+	HolidayDate time.Time `gorm:"type:date;not null;index"`
+	Name        string    `gorm:"type:varchar(100);size:100;not null"`
+
+	Courier *Courier `gorm:"foreignKey:courier_id"`
+}
+
+func (CourierHoliday) TableName() string {
+	return "courier_holiday"
+}
+
+// DeliveryEstimate is the window the shipment is expected to be delivered in
+type DeliveryEstimate struct {
+	From time.Time
+	To   time.Time
+}
+
+// DeliveryCalendar knows the working days of the couriers
+type DeliveryCalendar struct {
+	nonWorkingDays map[time.Weekday]bool
+
+	// key: courier id, 0 for the holidays of every courier
+	holidays map[uint64]map[string]bool
+}
+
+func NewDeliveryCalendar(nonWorkingDays []time.Weekday, holidays []CourierHoliday) *DeliveryCalendar {
+	calendar := &DeliveryCalendar{
+		nonWorkingDays: make(map[time.Weekday]bool, len(nonWorkingDays)),
+		holidays:       make(map[uint64]map[string]bool),
+	}
+
+	for _, v := range nonWorkingDays {
+		calendar.nonWorkingDays[v] = true
+	}
+
+	for _, v := range holidays {
+		var courierID uint64
+		if v.CourierID != nil {
+			courierID = *v.CourierID
+		}
+
+		if calendar.holidays[courierID] == nil {
+			calendar.holidays[courierID] = make(map[string]bool)
+		}
+		calendar.holidays[courierID][v.HolidayDate.Format(util.LayoutDateOnly)] = true
+	}
+
+	return calendar
+}
+
+// IsWorkingDay checks the date of day in its own location
+func (c *DeliveryCalendar) IsWorkingDay(courierID uint64, day time.Time) bool {
+	if c.nonWorkingDays[day.Weekday()] {
+		return false
+	}
+
+	date := day.Format(util.LayoutDateOnly)
+	return !c.holidays[0][date] && !c.holidays[courierID][date]
+}
+
+// Estimate counts the etd in working days from the pickup of the courier service,
+// the delivery is expected from the pickup time of the first day until the end of the last day.
+// A courier service outside its operating hours is unavailable (see CourierServiceLimit.Validate), so the pickup is at
+// on a working day and rolls over to the start time of the next working day otherwise. at is in the location of the origin,
+// the same location the operating hours are checked in.
+func (c *DeliveryCalendar) Estimate(courierID uint64, startTime datatype.Time, etdMin, etdMax float64, at time.Time) *DeliveryEstimate {
+	pickup := c.pickupTime(courierID, startTime, at)
+
+	from := c.addWorkingDays(courierID, pickup, int(math.Ceil(etdMin)))
+	to := c.addWorkingDays(courierID, pickup, int(math.Ceil(math.Max(etdMin, etdMax))))
+	to = time.Date(to.Year(), to.Month(), to.Day(), 23, 59, 59, 0, to.Location())
+
+	return &DeliveryEstimate{From: from, To: to}
+}
+
+// the estimate gives up looking for a working day after a year of holidays
+const maxCalendarDays = 366
+
+func (c *DeliveryCalendar) pickupTime(courierID uint64, startTime datatype.Time, at time.Time) time.Time {
+	if c.IsWorkingDay(courierID, at) {
+		return at
+	}
+
+	start, ok := startTime.SecondOfDay(at.Location())
+	if !ok {
+		start = 0
+	}
+
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+	for i := 0; i < maxCalendarDays; i++ {
+		day = day.AddDate(0, 0, 1)
+		if c.IsWorkingDay(courierID, day) {
+			return day.Add(time.Duration(start) * time.Second)
+		}
+	}
+
+	return at
+}
+
+func (c *DeliveryCalendar) addWorkingDays(courierID uint64, from time.Time, days int) time.Time {
+	result := from
+	for i := 0; days > 0 && i < maxCalendarDays; i++ {
+		result = result.AddDate(0, 0, 1)
+		if c.IsWorkingDay(courierID, result) {
+			days--
+		}
+	}
+
+	return result
+}
@@ -94,6 +94,10 @@ type CourierServiceDetailDTO struct {
 	// in: integer
 	Insurance int32 `gorm:"not null;default:0" json:"insurance"`
 
+	// Insurance Mandatory of the Courier Service, the insurance is applied even when it is not requested
+	// in: integer
+	InsuranceMandatory int32 `gorm:"not null;default:0" json:"insurance_mandatory"`
+
 	// Insurance Min of the Courier Service
 	// in: float64
 	InsuranceMin float64 `gorm:"not null;default:0" json:"insurance_min"`
@@ -106,6 +110,18 @@ type CourierServiceDetailDTO struct {
 	// in: float64
 	InsuranceFee float64 `gorm:"not null;default:0" json:"insurance_fee"`
 
+	// Cod Fee Type of the Courier Service, percentage or fixed
+	// in: string
+	CodFeeType string `gorm:"not null;default:''" json:"cod_fee_type"`
+
+	// Cod Fee of the Courier Service
+	// in: float64
+	CodFee float64 `gorm:"not null;default:0" json:"cod_fee"`
+
+	// Cod Fee Min of the Courier Service
+	// in: float64
+	CodFeeMin float64 `gorm:"not null;default:0" json:"cod_fee_min"`
+
 	// Start Time of the Courier Service
 	// example:"15:04:05+07"
 	StartTime datatype.Time `gorm:"null" json:"start_time"`
@@ -212,6 +228,10 @@ type CourierService struct {
 	// in: integer
 	Insurance int32 `gorm:"type:int;not null;default:0" json:"insurance"`
 
+	// Insurance Mandatory of the Courier Service, the insurance is applied even when it is not requested
+	// in: integer
+	InsuranceMandatory int32 `gorm:"type:int;not null;default:0" json:"insurance_mandatory"`
+
 	// Insurance Min of the Courier Service
 	// in: float64
 	InsuranceMin float64 `gorm:"type:numeric;not null;default:0" json:"insurance_min"`
@@ -224,6 +244,18 @@ type CourierService struct {
 	// in: float64
 	InsuranceFee float64 `gorm:"type:numeric;not null;default:0" json:"insurance_fee"`
 
+	// Cod Fee Type of the Courier Service, percentage or fixed
+	// in: string
+	CodFeeType string `gorm:"type:varchar(30);size:30;not null;default:''" json:"cod_fee_type"`
+
+	// Cod Fee of the Courier Service
+	// in: float64
+	CodFee float64 `gorm:"type:numeric;not null;default:0" json:"cod_fee"`
+
+	// Cod Fee Min of the Courier Service
+	// in: float64
+	CodFeeMin float64 `gorm:"type:numeric;not null;default:0" json:"cod_fee_min"`
+
 	// Start Time of the Courier Service
 	// in: time
 	StartTime datatype.Time `gorm:"type:time;null" json:"start_time"`
@@ -246,9 +278,15 @@ type CourierService struct {
 	ImagePath datatype.JSONB `gorm:"type:jsonb;null" json:"image_path"`
 
 	Courier *Courier `json:"-" gorm:"foreignKey:courier_id"`
+
+	// readonly field, price of the courier service on the requested channel
+	PriceInternal float64 `gorm:"-:migration;->" json:"-"`
+
+	// readonly field, channel courier service of the requested channel
+	ChannelCourierServiceID uint64 `gorm:"-:migration;->" json:"-"`
 }
 
-func (c *CourierService) Validate(weight float64, isPrescription bool) message.Message {
+func (c *CourierService) Validate(shipment *Shipment) message.Message {
 
 	if c.Courier != nil {
 		if msg := c.Courier.Validate(); msg != message.SuccessMsg {
@@ -260,13 +298,34 @@ func (c *CourierService) Validate(weight float64, isPrescription bool) message.M
 		return message.CourierServiceNotActiveMsg
 	}
 
-	if c.MaxWeight > 0 && c.MaxWeight < weight {
-		return message.WeightExceedsMsg
-	}
+	return c.Limit().Validate(shipment)
+}
+
+// CodFeeAmount is the fee charged to collect codAmount on delivery
+func (c *CourierService) CodFeeAmount(codAmount float64) float64 {
+	return CalculateFee(c.CodFeeType, c.CodFee, c.CodFeeMin, codAmount)
+}
 
-	if isPrescription && c.PrescriptionAllowed == 0 {
-		return message.PrescriptionNotAllowedMsg
+func (c *CourierService) InsurancePolicy() *CourierServiceInsurance {
+	return &CourierServiceInsurance{
+		Available: c.Insurance == 1,
+		Mandatory: c.InsuranceMandatory == 1,
+		FeeType:   c.InsuranceFeeType,
+		Fee:       c.InsuranceFee,
+		Min:       c.InsuranceMin,
 	}
+}
 
-	return message.SuccessMsg
+func (c *CourierService) Limit() *CourierServiceLimit {
+	return &CourierServiceLimit{
+		MaxWeight:           c.MaxWeight,
+		MaxVolume:           c.MaxVolume,
+		MaxDistance:         c.MaxDistance,
+		MinPurchase:         c.MinPurchase,
+		MaxPurchase:         c.MaxPurchase,
+		StartTime:           c.StartTime,
+		EndTime:             c.EndTime,
+		CodAvailable:        c.CodAvailable,
+		PrescriptionAllowed: c.PrescriptionAllowed,
+	}
 }
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"go-klikdokter/pkg/util"
+	"math"
+)
+
+// fee types of the cod and insurance fee of a courier service
+const (
+	FeeTypePercentage = "percentage"
+	FeeTypeFixed      = "fixed"
+)
+
+func IsValidFeeType(feeType string) bool {
+	return feeType == "" || feeType == FeeTypePercentage || feeType == FeeTypeFixed
+}
+
+// CalculateFee charges fee on value, a percentage fee is a percent of the value and is raised to minimum,
+// an empty fee type has no fee
+func CalculateFee(feeType string, fee, minimum, value float64) float64 {
+	switch feeType {
+	case FeeTypePercentage:
+		return util.RoundFloat(math.Max(value*fee/100, minimum), 2)
+	case FeeTypeFixed:
+		return fee
+	}
+
+	return 0
+}
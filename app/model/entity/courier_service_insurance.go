@@ -0,0 +1,39 @@
+package entity
+
+// CourierServiceInsurance is the insurance configured on a courier service
+type CourierServiceInsurance struct {
+	Available bool
+	Mandatory bool
+	FeeType   string
+	Fee       float64
+	Min       float64
+}
+
+// InsurancePremium insures the product value of a shipment,
+// Applied is false when the insurance is optional and not requested
+type InsurancePremium struct {
+	InsuredValue float64
+	Premium      float64
+	Mandatory    bool
+	Applied      bool
+}
+
+// Calculate charges a percentage premium of the product value raised to the minimum,
+// any other fee type is charged as a fixed premium
+func (i *CourierServiceInsurance) Calculate(productValue float64, useInsurance bool) InsurancePremium {
+	if !i.Available && !i.Mandatory {
+		return InsurancePremium{}
+	}
+
+	feeType := FeeTypeFixed
+	if i.FeeType == FeeTypePercentage {
+		feeType = FeeTypePercentage
+	}
+
+	return InsurancePremium{
+		InsuredValue: productValue,
+		Premium:      CalculateFee(feeType, i.Fee, i.Min, productValue),
+		Mandatory:    i.Mandatory,
+		Applied:      i.Mandatory || useInsurance,
+	}
+}
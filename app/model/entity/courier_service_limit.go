@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"go-klikdokter/helper/message"
+	"go-klikdokter/pkg/util/datatype"
+	"time"
+)
+
+// CourierServiceLimit is the eligibility configured on a courier service, a zero value has no limit
+type CourierServiceLimit struct {
+	MaxWeight           float64
+	MaxVolume           float64
+	MaxDistance         float64
+	MinPurchase         float64
+	MaxPurchase         float64
+	StartTime           datatype.Time
+	EndTime             datatype.Time
+	CodAvailable        int32
+	PrescriptionAllowed int32
+}
+
+// Shipment is checked against the CourierServiceLimit,
+// Distance is 0 when the coordinates are not known, At is in the timezone of the origin
+type Shipment struct {
+	FinalWeight  float64
+	Volume       float64
+	Distance     float64
+	Purchase     float64
+	COD          bool
+	Prescription bool
+	At           time.Time
+}
+
+func (l *CourierServiceLimit) Validate(shipment *Shipment) message.Message {
+	if l.MaxWeight > 0 && l.MaxWeight < shipment.FinalWeight {
+		return message.WeightExceedsMsg
+	}
+
+	if l.MaxVolume > 0 && l.MaxVolume < shipment.Volume {
+		return message.VolumeExceedsMsg
+	}
+
+	if l.MaxDistance > 0 && l.MaxDistance < shipment.Distance {
+		return message.DistanceExceedsMsg
+	}
+
+	if l.MinPurchase > 0 && shipment.Purchase < l.MinPurchase {
+		return message.PurchaseBelowMinimumMsg
+	}
+
+	if l.MaxPurchase > 0 && l.MaxPurchase < shipment.Purchase {
+		return message.PurchaseExceedsMsg
+	}
+
+	if shipment.Prescription && l.PrescriptionAllowed != 1 {
+		return message.PrescriptionNotAllowedMsg
+	}
+
+	if shipment.COD && l.CodAvailable != 1 {
+		return message.CodNotAvailableMsg
+	}
+
+	if !l.isOperating(shipment.At) {
+		return message.OutsideOperatingHoursMsg
+	}
+
+	return message.SuccessMsg
+}
+
+// the operating hours may cross midnight when the end time is before the start time
+func (l *CourierServiceLimit) isOperating(at time.Time) bool {
+	start, okStart := l.StartTime.SecondOfDay(at.Location())
+	end, okEnd := l.EndTime.SecondOfDay(at.Location())
+	if !okStart || !okEnd || start == end {
+		return true
+	}
+
+	now := at.Hour()*3600 + at.Minute()*60 + at.Second()
+	if start < end {
+		return now >= start && now < end
+	}
+
+	return now >= start || now < end
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/pkg/util/datatype"
+	"time"
+)
+
+const (
+	IdempotencyStatusProcessing = "processing"
+	IdempotencyStatusBooked     = "booked"
+	IdempotencyStatusCompleted  = "completed"
+)
+
+// IdempotencyKey keeps the result of a create delivery request so a retry with the same key can be replayed.
+// The request processing the key holds it until LockedUntil, a retry takes over the key once the lease is over.
+type IdempotencyKey struct {
+	base.BaseIDModel
+	Key          string         `gorm:"type:varchar(100);size:100;not null;uniqueIndex"`
+	RequestHash  string         `gorm:"type:varchar(64);size:64;not null"`
+	Status       string         `gorm:"type:varchar(20);size:20;not null"`
+	LockedUntil  *time.Time     `gorm:"type:timestamp;null"`
+	BookingData  datatype.JSONB `gorm:"type:jsonb;null"`
+	ResponseData datatype.JSONB `gorm:"type:jsonb;null"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_key"
+}
+
+// Locked tells the key is still being processed or booked by another request
+func (k *IdempotencyKey) Locked(now time.Time) bool {
+	return k.Status != IdempotencyStatusCompleted && k.LockedUntil != nil && now.Before(*k.LockedUntil)
+}
@@ -61,20 +61,42 @@ type OrderShipping struct {
 	ContainPrescription  uint      `gorm:"type:numeric;not null"`
 	Insurance            bool      `gorm:"type:boolean;null"`
 	InsuranceCost        float64   `gorm:"type:numeric;null"`
+	InsuredValue         float64   `gorm:"type:numeric;not null;default:0"`
+	InsuranceMandatory   bool      `gorm:"type:boolean;not null;default:false"`
 	ShippingCost         float64   `gorm:"type:numeric;null"`
 	TotalShippingCost    float64   `gorm:"type:numeric;null"`
 	ActualShippingCost   float64   `gorm:"type:numeric;null"`
+	COD                  bool      `gorm:"type:boolean;not null;default:false"`
+	CodAmount            float64   `gorm:"type:numeric;not null;default:0"`
+	CodFee               float64   `gorm:"type:numeric;not null;default:0"`
+	ShippingDiscount     float64   `gorm:"type:numeric;not null;default:0"`
+	ShippingPromotionID  *uint64   `gorm:"type:bigint;null"`
+	RateQuoteUID         string    `gorm:"type:varchar(21);not null;default:''"`
+	QuotedShippingCost   float64   `gorm:"type:numeric;not null;default:0"`
 	ShippingNotes        string    `gorm:"type:varchar(255);null"`
 	BookingID            string    `gorm:"type:varchar(50);null"`
 	Airwaybill           string    `gorm:"type:varchar(50);null"`
 	Status               string    `gorm:"type:varchar(50);null"`
 	PickupCode           *string   `gorm:"type:varchar(50);null"`
 
+	// the order was cancelled on request of the channel instead of by the courier
+	CancelledByChannel bool `gorm:"type:boolean;not null;default:false"`
+
+	// the quote claimed by the order when it is booked, the discount of the quote is booked with the order
+	RateQuote *ShippingRateQuote `gorm:"-"`
+
+	// delivery window estimated with the working days of the courier when the order is booked
+	EstimatedDeliveryFrom *time.Time `gorm:"type:timestamp;null"`
+	EstimatedDeliveryTo   *time.Time `gorm:"type:timestamp;null"`
+
 	Channel              *Channel               `gorm:"foreignKey:channel_id"`
 	Courier              *Courier               `gorm:"foreignKey:courier_id"`
 	CourierService       *CourierService        `gorm:"foreignKey:courier_service_id"`
 	OrderShippingItem    []OrderShippingItem    `gorm:"foreignKey:order_shipping_id"`
 	OrderShippingHistory []OrderShippingHistory `gorm:"foreignKey:order_shipping_id"`
+	OrderShippingOutbox  []OrderShippingOutbox  `gorm:"foreignKey:order_shipping_id"`
+
+	ShippingPromotionUsage []ShippingPromotionUsage `gorm:"foreignKey:order_shipping_id"`
 }
 
 func (o *OrderShipping) FromCreateDeliveryRequest(req *request.CreateDelivery) {
@@ -140,6 +162,8 @@ func (o *OrderShipping) FromCreateDeliveryRequest(req *request.CreateDelivery) {
 	o.TotalProductPrice = req.Package.TotalProductPrice
 	o.TotalFinalWeight = math.Max(volumeWeight, req.Package.TotalWeight)
 	o.ContainPrescription = req.Package.ContainPrescription
+	o.COD = req.COD
+	o.CodAmount = req.CodAmount()
 	o.ShippingNotes = req.Notes
 	o.OrderShippingItem = orderShippingItems
 	o.OrderShippingHistory = []OrderShippingHistory{}
@@ -148,6 +172,32 @@ func (o *OrderShipping) FromCreateDeliveryRequest(req *request.CreateDelivery) {
 		UpdatedBy: req.Username,
 	}
 }
+
+// ApplyCodFee keeps the cod fee of the courier service apart from the shipping cost,
+// the same way as the shipping rate does not include it in the total price
+func (o *OrderShipping) ApplyCodFee(courierService *CourierService) {
+	if !o.COD {
+		return
+	}
+
+	o.CodFee = courierService.CodFeeAmount(o.CodAmount)
+}
+
+// ApplyShippingPromotion deducts the discount from the shipping cost charged to the customer,
+// the usage is saved with the order shipping
+func (o *OrderShipping) ApplyShippingPromotion(promotion *ShippingPromotion, discount float64) {
+	o.ShippingPromotionID = &promotion.ID
+	o.ShippingDiscount = discount
+	o.TotalShippingCost = util.RoundFloat(o.TotalShippingCost-discount, 2)
+	o.ShippingPromotionUsage = append(o.ShippingPromotionUsage, ShippingPromotionUsage{
+		ShippingPromotionID: promotion.ID,
+		Amount:              discount,
+		BaseIDModel: base.BaseIDModel{
+			CreatedBy: o.CreatedBy,
+		},
+	})
+}
+
 func (o *OrderShipping) AddHistoryStatus(s *ShippingCourierStatus, note string, driverInfo ...string) {
 	if len(driverInfo) == 1 && !o.isDriverInfoExist(driverInfo[0]) {
 		note += driverInfo[0]
@@ -168,6 +218,20 @@ func (o *OrderShipping) AddHistoryStatus(s *ShippingCourierStatus, note string,
 	})
 }
 
+// AddOutbox queues an event to be saved together with the order shipping
+func (o *OrderShipping) AddOutbox(topic string, payload []byte) {
+	o.OrderShippingOutbox = append(o.OrderShippingOutbox, OrderShippingOutbox{
+		OrderShippingID: o.ID,
+		Topic:           topic,
+		Payload:         payload,
+		Status:          OutboxStatusPending,
+		NextAttemptAt:   time.Now().In(util.Loc),
+		BaseIDModel: base.BaseIDModel{
+			CreatedBy: util.ReplaceEmptyString(o.UpdatedBy, o.CreatedBy),
+		},
+	})
+}
+
 func (o *OrderShipping) isHistoryStatusExist(statusCode, note string) bool {
 	for _, v := range o.OrderShippingHistory {
 		if v.StatusCode == statusCode && v.Note == note {
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/pkg/util/datatype"
+	"time"
+)
+
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusDelivered = "delivered"
+	OutboxStatusFailed    = "failed"
+)
+
+// OrderShippingOutbox is an order shipping event waiting to be published by the outbox relay
+type OrderShippingOutbox struct {
+	base.BaseIDModel
+	OrderShippingID uint64         `gorm:"type:bigint;not null;index"`
+	Topic           string         `gorm:"type:varchar(255);size:255;not null"`
+	Payload         datatype.JSONB `gorm:"type:jsonb;not null"`
+	Status          string         `gorm:"type:varchar(20);size:20;not null;index"`
+	Attempts        int            `gorm:"type:int;not null;default:0"`
+	LastError       string         `gorm:"type:text;null"`
+	NextAttemptAt   time.Time      `gorm:"type:timestamp;not null"`
+	DeliveredAt     *time.Time     `gorm:"type:timestamp;null"`
+}
+
+func (OrderShippingOutbox) TableName() string {
+	return "order_shipping_outbox"
+}
@@ -0,0 +1,255 @@
+package entity
+
+import (
+	"encoding/json"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/helper/message"
+	"go-klikdokter/pkg/util"
+	"go-klikdokter/pkg/util/datatype"
+	"math"
+	"strings"
+	"time"
+)
+
+// RateCard is a versioned price list of an internal courier service on a channel.
+// The card in effect with the latest effective_from is used to price a shipment.
+type RateCard struct {
+	base.BaseIDModel
+	ChannelCourierServiceID uint64     `gorm:"type:bigint;not null;uniqueIndex:idx_rate_card_version"`
+	Version                 int        `gorm:"type:int;not null;uniqueIndex:idx_rate_card_version"`
+	EffectiveFrom           time.Time  `gorm:"type:timestamp;not null"`
+	EffectiveTo             *time.Time `gorm:"type:timestamp"`
+	BaseFare                float64    `gorm:"type:decimal(18,4);not null;default:0"`
+	MinimumCharge           float64    `gorm:"type:decimal(18,4);not null;default:0"`
+
+	// []RateCardTier charged per km
+	DistanceTiers datatype.JSONB `gorm:"type:jsonb"`
+
+	// []RateCardTier charged per kg of the final weight
+	WeightTiers datatype.JSONB `gorm:"type:jsonb"`
+
+	// []RateCardZone
+	Zones datatype.JSONB `gorm:"type:jsonb"`
+
+	// []RateCardZoneSurcharge
+	ZoneSurcharges datatype.JSONB `gorm:"type:jsonb"`
+
+	ChannelCourierService *ChannelCourierService `gorm:"foreignKey:channel_courier_service_id"`
+}
+
+func (RateCard) TableName() string {
+	return "rate_card"
+}
+
+// RateCardTier charges Rate for every unit above the previous tier up to UpTo,
+// an UpTo of 0 is only allowed on the last tier and has no upper bound
+type RateCardTier struct {
+	UpTo float64 `json:"up_to"`
+	Rate float64 `json:"rate"`
+}
+
+// RateCardZone groups postal codes, a postal code belongs to the zone when it starts with one of the prefixes
+type RateCardZone struct {
+	Code        string   `json:"code"`
+	PostalCodes []string `json:"postal_codes"`
+}
+
+// RateCardZoneSurcharge is added when the origin and destination are in the zones,
+// an empty zone matches any address
+type RateCardZoneSurcharge struct {
+	OriginZone      string  `json:"origin_zone"`
+	DestinationZone string  `json:"destination_zone"`
+	Amount          float64 `json:"amount"`
+}
+
+// RateCardPrice is the breakdown of a price calculated from a rate card
+type RateCardPrice struct {
+	RateCardUID    string
+	Version        int
+	BaseFare       float64
+	DistanceCharge float64
+	WeightCharge   float64
+	ZoneSurcharge  float64
+	// added when the charges are below the minimum charge
+	MinimumChargeAdjustment float64
+	Total                   float64
+}
+
+func (c *RateCard) IsEffective(at time.Time) bool {
+	if at.Before(c.EffectiveFrom) {
+		return false
+	}
+
+	return c.EffectiveTo == nil || at.Before(*c.EffectiveTo)
+}
+
+func (c *RateCard) Validate() message.Message {
+	if c.EffectiveFrom.IsZero() {
+		return message.ErrRateCardEffectiveFromRequired
+	}
+
+	if c.EffectiveTo != nil && !c.EffectiveTo.After(c.EffectiveFrom) {
+		return message.ErrInvalidDateRange
+	}
+
+	if c.BaseFare < 0 || c.MinimumCharge < 0 {
+		return message.ErrInvalidRateCard
+	}
+
+	if !validRateCardTiers(c.GetDistanceTiers()) || !validRateCardTiers(c.GetWeightTiers()) {
+		return message.ErrInvalidRateCardTier
+	}
+
+	zones := map[string]bool{}
+	for _, v := range c.GetZones() {
+		if v.Code == "" || zones[v.Code] {
+			return message.ErrInvalidRateCardZone
+		}
+		zones[v.Code] = true
+	}
+
+	for _, v := range c.GetZoneSurcharges() {
+		if v.Amount < 0 {
+			return message.ErrInvalidRateCardZone
+		}
+
+		if (v.OriginZone != "" && !zones[v.OriginZone]) || (v.DestinationZone != "" && !zones[v.DestinationZone]) {
+			return message.ErrInvalidRateCardZone
+		}
+	}
+
+	return message.SuccessMsg
+}
+
+// Calculate prices a shipment, all matching zone surcharges are added
+func (c *RateCard) Calculate(distance, weight float64, originPostalCode, destinationPostalCode string) RateCardPrice {
+	price := RateCardPrice{
+		RateCardUID:    c.UID,
+		Version:        c.Version,
+		BaseFare:       c.BaseFare,
+		DistanceCharge: util.RoundFloat(tieredCharge(c.GetDistanceTiers(), distance), 2),
+		WeightCharge:   util.RoundFloat(tieredCharge(c.GetWeightTiers(), weight), 2),
+	}
+
+	zones := c.GetZones()
+	originZone := findRateCardZone(zones, originPostalCode)
+	destinationZone := findRateCardZone(zones, destinationPostalCode)
+
+	for _, v := range c.GetZoneSurcharges() {
+		if v.OriginZone != "" && v.OriginZone != originZone {
+			continue
+		}
+
+		if v.DestinationZone != "" && v.DestinationZone != destinationZone {
+			continue
+		}
+
+		price.ZoneSurcharge += v.Amount
+	}
+
+	price.Total = price.BaseFare + price.DistanceCharge + price.WeightCharge + price.ZoneSurcharge
+	if price.Total < c.MinimumCharge {
+		price.MinimumChargeAdjustment = c.MinimumCharge - price.Total
+		price.Total = c.MinimumCharge
+	}
+
+	price.Total = util.RoundFloat(price.Total, 2)
+	return price
+}
+
+func (c *RateCard) GetDistanceTiers() []RateCardTier {
+	var tiers []RateCardTier
+	if !c.DistanceTiers.IsNull() {
+		_ = json.Unmarshal(c.DistanceTiers, &tiers)
+	}
+	return tiers
+}
+
+func (c *RateCard) GetWeightTiers() []RateCardTier {
+	var tiers []RateCardTier
+	if !c.WeightTiers.IsNull() {
+		_ = json.Unmarshal(c.WeightTiers, &tiers)
+	}
+	return tiers
+}
+
+func (c *RateCard) GetZones() []RateCardZone {
+	var zones []RateCardZone
+	if !c.Zones.IsNull() {
+		_ = json.Unmarshal(c.Zones, &zones)
+	}
+	return zones
+}
+
+func (c *RateCard) GetZoneSurcharges() []RateCardZoneSurcharge {
+	var surcharges []RateCardZoneSurcharge
+	if !c.ZoneSurcharges.IsNull() {
+		_ = json.Unmarshal(c.ZoneSurcharges, &surcharges)
+	}
+	return surcharges
+}
+
+// tiers must be ascending, only the last tier may be unbounded
+func validRateCardTiers(tiers []RateCardTier) bool {
+	var prev float64
+	for i, v := range tiers {
+		if v.Rate < 0 {
+			return false
+		}
+
+		if v.UpTo == 0 && i == len(tiers)-1 {
+			continue
+		}
+
+		if v.UpTo <= prev {
+			return false
+		}
+		prev = v.UpTo
+	}
+
+	return true
+}
+
+func tieredCharge(tiers []RateCardTier, value float64) float64 {
+	var (
+		charge float64
+		prev   float64
+	)
+
+	for _, v := range tiers {
+		if value <= prev {
+			break
+		}
+
+		upper := value
+		if v.UpTo > 0 {
+			upper = math.Min(value, v.UpTo)
+		}
+
+		charge += (upper - prev) * v.Rate
+		prev = upper
+	}
+
+	// above the last bounded tier the last rate keeps applying
+	if len(tiers) > 0 && value > prev {
+		charge += (value - prev) * tiers[len(tiers)-1].Rate
+	}
+
+	return charge
+}
+
+func findRateCardZone(zones []RateCardZone, postalCode string) string {
+	if postalCode == "" {
+		return ""
+	}
+
+	for _, v := range zones {
+		for _, prefix := range v.PostalCodes {
+			if prefix != "" && strings.HasPrefix(postalCode, prefix) {
+				return v.Code
+			}
+		}
+	}
+
+	return ""
+}
@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"encoding/json"
+	"fmt"
 	"go-klikdokter/app/model/base"
 	"go-klikdokter/pkg/util/datatype"
 )
@@ -18,3 +20,46 @@ type ShippingCourierStatus struct {
 func (ShippingCourierStatus) TableName() string {
 	return "shipping_courier_status"
 }
+
+// CourierStatusCodes returns the courier status codes mapped to the shipping status
+func (s *ShippingCourierStatus) CourierStatusCodes() []string {
+	courierStatus := map[string]interface{}{}
+	if !s.StatusCourier.IsNull() {
+		_ = json.Unmarshal(s.StatusCourier, &courierStatus)
+	}
+
+	var codes []string
+	switch v := courierStatus["status"].(type) {
+	case []interface{}:
+		for _, code := range v {
+			codes = append(codes, fmt.Sprint(code))
+		}
+	case string:
+		codes = []string{v}
+	}
+
+	return codes
+}
+
+// SetCourierStatus replaces the courier status codes mapped to the shipping status
+func (s *ShippingCourierStatus) SetCourierStatus(statusCodes []string) {
+	courierStatus := map[string]interface{}{}
+	if !s.StatusCourier.IsNull() {
+		_ = json.Unmarshal(s.StatusCourier, &courierStatus)
+	}
+
+	courierStatus["status"] = statusCodes
+	s.StatusCourier, _ = json.Marshal(courierStatus)
+}
+
+// AddCourierStatus adds the courier status code to the codes mapped to the shipping status
+func (s *ShippingCourierStatus) AddCourierStatus(statusCode string) {
+	codes := s.CourierStatusCodes()
+	for _, v := range codes {
+		if v == statusCode {
+			return
+		}
+	}
+
+	s.SetCourierStatus(append(codes, statusCode))
+}
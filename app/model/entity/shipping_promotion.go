@@ -0,0 +1,188 @@
+package entity
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/helper/message"
+	"go-klikdokter/pkg/util"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	PromotionDiscountFreeShipping = "free_shipping"
+	PromotionDiscountPercentage   = "percentage"
+	PromotionDiscountFixed        = "fixed"
+)
+
+// ShippingPromotion discounts the shipping price of a channel during the campaign window,
+// a promotion with a voucher code is only applied when the code is used. An empty scope matches every shipment.
+type ShippingPromotion struct {
+	base.BaseIDModel
+	ChannelID        uint64  `gorm:"type:bigint;not null;index"`
+	Name             string  `gorm:"type:varchar(100);size:100;not null"`
+	VoucherCode      string  `gorm:"type:varchar(50);size:50;not null;default:''"`
+	CourierServiceID *uint64 `gorm:"type:bigint"`
+
+	// the total product price of the order must reach the minimum
+	MinTotalProductPrice float64 `gorm:"type:numeric;not null;default:0"`
+
+	// matches destination postal codes starting with the prefix
+	PostalCodePrefix string `gorm:"type:varchar(50);size:50;not null;default:''"`
+
+	StartTime time.Time  `gorm:"type:timestamp;not null"`
+	EndTime   *time.Time `gorm:"type:timestamp"`
+
+	DiscountType  string  `gorm:"type:varchar(30);size:30;not null"`
+	DiscountValue float64 `gorm:"type:numeric;not null;default:0"`
+
+	// caps the discount of a shipment, 0 is not capped
+	MaxDiscount float64 `gorm:"type:numeric;not null;default:0"`
+
+	// total discount the campaign can give, 0 is not capped
+	BudgetCap  float64 `gorm:"type:numeric;not null;default:0"`
+	UsedBudget float64 `gorm:"type:numeric;not null;default:0"`
+
+	Status *int32 `gorm:"type:int;not null;default:1"`
+
+	Channel        *Channel        `gorm:"foreignKey:channel_id"`
+	CourierService *CourierService `gorm:"foreignKey:courier_service_id"`
+}
+
+func (ShippingPromotion) TableName() string {
+	return "shipping_promotion"
+}
+
+// ShippingPromotionUsage is the discount given to a booked order shipping
+type ShippingPromotionUsage struct {
+	base.BaseIDModel
+	ShippingPromotionID uint64  `gorm:"type:bigint;not null;index"`
+	OrderShippingID     uint64  `gorm:"type:bigint;not null;index"`
+	Amount              float64 `gorm:"type:numeric;not null"`
+}
+
+func (ShippingPromotionUsage) TableName() string {
+	return "shipping_promotion_usage"
+}
+
+// PromotedShipment is a shipment matched against the scope of a ShippingPromotion
+type PromotedShipment struct {
+	CourierServiceID  uint64
+	TotalProductPrice float64
+	PostalCode        string
+	VoucherCode       string
+	At                time.Time
+}
+
+func (p *ShippingPromotion) Validate() message.Message {
+	if p.Name == "" {
+		return message.ErrReq
+	}
+
+	switch p.DiscountType {
+	case PromotionDiscountFreeShipping, PromotionDiscountPercentage, PromotionDiscountFixed:
+	default:
+		return message.ErrInvalidPromotionDiscountType
+	}
+
+	if p.StartTime.IsZero() {
+		return message.ErrShippingPromotionStartTimeRequired
+	}
+
+	if p.EndTime != nil && !p.EndTime.After(p.StartTime) {
+		return message.ErrInvalidDateRange
+	}
+
+	if p.DiscountValue < 0 || p.MaxDiscount < 0 || p.BudgetCap < 0 || p.MinTotalProductPrice < 0 {
+		return message.ErrInvalidShippingPromotion
+	}
+
+	return message.SuccessMsg
+}
+
+func (p *ShippingPromotion) IsActive(at time.Time) bool {
+	if p.Status != nil && *p.Status != 1 {
+		return false
+	}
+
+	if at.Before(p.StartTime) {
+		return false
+	}
+
+	return p.EndTime == nil || at.Before(*p.EndTime)
+}
+
+func (p *ShippingPromotion) Matches(shipment *PromotedShipment) bool {
+	if !p.IsActive(shipment.At) {
+		return false
+	}
+
+	if p.VoucherCode != "" && !strings.EqualFold(p.VoucherCode, shipment.VoucherCode) {
+		return false
+	}
+
+	if p.CourierServiceID != nil && *p.CourierServiceID != shipment.CourierServiceID {
+		return false
+	}
+
+	if p.PostalCodePrefix != "" && !strings.HasPrefix(shipment.PostalCode, p.PostalCodePrefix) {
+		return false
+	}
+
+	return shipment.TotalProductPrice >= p.MinTotalProductPrice
+}
+
+// RemainingBudget is -1 when the budget is not capped
+func (p *ShippingPromotion) RemainingBudget() float64 {
+	if p.BudgetCap == 0 {
+		return -1
+	}
+
+	return math.Max(p.BudgetCap-p.UsedBudget, 0)
+}
+
+// Discount never exceeds the price, the max discount or the remaining budget
+func (p *ShippingPromotion) Discount(price float64) float64 {
+	var discount float64
+	switch p.DiscountType {
+	case PromotionDiscountFreeShipping:
+		discount = price
+	case PromotionDiscountPercentage:
+		discount = price * p.DiscountValue / 100
+	case PromotionDiscountFixed:
+		discount = p.DiscountValue
+	}
+
+	if p.MaxDiscount > 0 {
+		discount = math.Min(discount, p.MaxDiscount)
+	}
+
+	if remaining := p.RemainingBudget(); remaining >= 0 {
+		discount = math.Min(discount, remaining)
+	}
+
+	return util.RoundFloat(math.Max(math.Min(discount, price), 0), 2)
+}
+
+// BestShippingPromotion returns the matching promotion giving the highest discount,
+// promotions do not stack
+func BestShippingPromotion(promotions []ShippingPromotion, shipment *PromotedShipment, price float64) (*ShippingPromotion, float64) {
+	var (
+		best     *ShippingPromotion
+		discount float64
+	)
+
+	for i := range promotions {
+		promotion := &promotions[i]
+		if !promotion.Matches(shipment) {
+			continue
+		}
+
+		if d := promotion.Discount(price); d > discount {
+			best = promotion
+			discount = d
+		}
+	}
+
+	return best, discount
+}
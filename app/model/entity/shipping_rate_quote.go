@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/helper/message"
+	"go-klikdokter/pkg/util/datatype"
+	"time"
+)
+
+// ShippingRateQuote is the price of a courier service shown by the shipping rate,
+// the quote is claimed by the order no that books it
+type ShippingRateQuote struct {
+	base.BaseIDModel
+	ChannelID             uint64  `gorm:"type:bigint;not null;index"`
+	CourierServiceUID     string  `gorm:"type:varchar(50);size:50;not null"`
+	OriginPostalCode      string  `gorm:"type:varchar(50);size:50;not null;default:''"`
+	DestinationPostalCode string  `gorm:"type:varchar(50);size:50;not null;default:''"`
+	TotalWeight           float64 `gorm:"type:numeric;not null;default:0"`
+	TotalProductPrice     float64 `gorm:"type:numeric;not null;default:0"`
+	FinalWeight           float64 `gorm:"type:numeric;not null;default:0"`
+	COD                   bool    `gorm:"type:boolean;not null;default:false"`
+	UseInsurance          bool    `gorm:"type:boolean;not null;default:false"`
+	OriginalPrice         float64 `gorm:"type:numeric;not null;default:0"`
+	TotalPrice            float64 `gorm:"type:numeric;not null;default:0"`
+	ShippingDiscount      float64 `gorm:"type:numeric;not null;default:0"`
+	ShippingPromotionUID  string  `gorm:"type:varchar(21);not null;default:''"`
+	InsuranceFee          float64 `gorm:"type:numeric;not null;default:0"`
+	CodFee                float64 `gorm:"type:numeric;not null;default:0"`
+
+	// the shipping rate service as returned to the customer
+	Breakdown datatype.JSONB `gorm:"type:jsonb"`
+
+	ExpiredAt time.Time  `gorm:"type:timestamp;not null;index"`
+	OrderNo   string     `gorm:"type:varchar(50);size:50;not null;default:''"`
+	UsedAt    *time.Time `gorm:"type:timestamp"`
+}
+
+func (ShippingRateQuote) TableName() string {
+	return "shipping_rate_quote"
+}
+
+// Validate checks the quote was given for the same channel, courier service, package, cod and insurance as the delivery
+func (q *ShippingRateQuote) Validate(channelID uint64, req *request.CreateDelivery, at time.Time) message.Message {
+	if q.ChannelID != channelID || q.CourierServiceUID != req.CouirerServiceUID {
+		return message.ErrRateQuoteMismatch
+	}
+
+	if q.OrderNo != "" && q.OrderNo != req.OrderNo {
+		return message.ErrRateQuoteUsed
+	}
+
+	if !at.Before(q.ExpiredAt) {
+		return message.ErrRateQuoteExpired
+	}
+
+	if q.TotalWeight != req.Package.TotalWeight || q.TotalProductPrice != req.Package.TotalProductPrice {
+		return message.ErrRateQuoteMismatch
+	}
+
+	// the cod fee and insurance fee of the quote are only given for the same options
+	if q.COD != req.COD || q.UseInsurance != req.UseInsurance {
+		return message.ErrRateQuoteMismatch
+	}
+
+	if (q.OriginPostalCode != "" && q.OriginPostalCode != req.Origin.PostalCode) ||
+		(q.DestinationPostalCode != "" && q.DestinationPostalCode != req.Destination.PostalCode) {
+		return message.ErrRateQuoteMismatch
+	}
+
+	return message.SuccessMsg
+}
@@ -0,0 +1,66 @@
+package entity
+
+import "go-klikdokter/app/model/base"
+
+// ShippingStatusTransition is an allowed move between two shipping status codes of a channel
+type ShippingStatusTransition struct {
+	base.BaseIDModel
+	ChannelID      uint64   `gorm:"type:bigint;not null;uniqueIndex:idx_shipping_status_transition"`
+	FromStatusCode string   `gorm:"type:varchar(50);size:50;not null;uniqueIndex:idx_shipping_status_transition"`
+	ToStatusCode   string   `gorm:"type:varchar(50);size:50;not null;uniqueIndex:idx_shipping_status_transition"`
+	Channel        *Channel `gorm:"foreignKey:channel_id"`
+}
+
+func (ShippingStatusTransition) TableName() string {
+	return "shipping_status_transition"
+}
+
+// DefaultTerminalStatusCodes can not be left by the orders of a channel without any transition configured
+var DefaultTerminalStatusCodes = []string{"delivered", "cancelled"}
+
+// ShippingStatusTransitions is the transition graph of a channel.
+// A status without any outgoing transition is a terminal status.
+// A channel without any transition configured may move between any status except out of the default terminal status.
+type ShippingStatusTransitions []ShippingStatusTransition
+
+func (t ShippingStatusTransitions) CanTransition(fromStatusCode, toStatusCode string) bool {
+	if fromStatusCode == "" || fromStatusCode == toStatusCode {
+		return true
+	}
+
+	if len(t) == 0 {
+		return !isDefaultTerminalStatus(fromStatusCode)
+	}
+
+	for _, v := range t {
+		if v.FromStatusCode == fromStatusCode && v.ToStatusCode == toStatusCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t ShippingStatusTransitions) IsTerminal(statusCode string) bool {
+	if len(t) == 0 {
+		return isDefaultTerminalStatus(statusCode)
+	}
+
+	for _, v := range t {
+		if v.FromStatusCode == statusCode {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isDefaultTerminalStatus(statusCode string) bool {
+	for _, v := range DefaultTerminalStatusCodes {
+		if v == statusCode {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"encoding/json"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/pkg/util/datatype"
+	"time"
+)
+
+const (
+	UnmappedStatusOpen   = "open"
+	UnmappedStatusMapped = "mapped"
+
+	// number of latest payloads kept as sample of an unmapped status
+	unmappedSampleSize = 5
+)
+
+// UnmappedCourierStatus is a courier status code received by webhook that has no shipping status mapping yet
+type UnmappedCourierStatus struct {
+	base.BaseIDModel
+	CourierID               uint64                 `gorm:"type:bigint;not null;uniqueIndex:idx_unmapped_courier_status"`
+	StatusCode              string                 `gorm:"type:varchar(100);size:100;not null;uniqueIndex:idx_unmapped_courier_status"`
+	StatusName              string                 `gorm:"type:varchar(255);size:255"`
+	SamplePayloads          datatype.JSONB         `gorm:"type:jsonb"`
+	OccurrenceCount         int                    `gorm:"type:int;not null;default:0"`
+	FirstSeenAt             time.Time              `gorm:"type:timestamp;not null"`
+	LastSeenAt              time.Time              `gorm:"type:timestamp;not null"`
+	Status                  string                 `gorm:"type:varchar(20);size:20;not null;index"`
+	ShippingCourierStatusID *uint64                `gorm:"type:bigint;null"`
+	MappedAt                *time.Time             `gorm:"type:timestamp;null"`
+	Courier                 *Courier               `gorm:"foreignKey:courier_id"`
+	ShippingCourierStatus   *ShippingCourierStatus `gorm:"foreignKey:shipping_courier_status_id"`
+}
+
+func (UnmappedCourierStatus) TableName() string {
+	return "unmapped_courier_status"
+}
+
+// AddOccurrence counts the status again and keeps the payload as one of the latest samples
+func (u *UnmappedCourierStatus) AddOccurrence(payload []byte, seenAt time.Time) {
+	var samples []json.RawMessage
+	if !u.SamplePayloads.IsNull() {
+		_ = json.Unmarshal(u.SamplePayloads, &samples)
+	}
+
+	if json.Valid(payload) {
+		samples = append(samples, payload)
+	}
+
+	if len(samples) > unmappedSampleSize {
+		samples = samples[len(samples)-unmappedSampleSize:]
+	}
+
+	u.SamplePayloads, _ = json.Marshal(samples)
+	u.OccurrenceCount++
+	u.LastSeenAt = seenAt
+	if u.FirstSeenAt.IsZero() {
+		u.FirstSeenAt = seenAt
+	}
+}
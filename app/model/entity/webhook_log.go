@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/pkg/util/datatype"
+	"time"
+)
+
+const (
+	WebhookStatusReceived  = "received"
+	WebhookStatusProcessed = "processed"
+	WebhookStatusFailed    = "failed"
+)
+
+// WebhookLog is an inbound courier webhook as it was received, with its processing outcome.
+// Authorized webhooks are unique by provider and event id, a repeated event is counted as duplicate.
+// A webhook is held while its courier status is not mapped, it is re-run once the status is mapped.
+type WebhookLog struct {
+	base.BaseIDModel
+	Provider       string         `gorm:"type:varchar(50);size:50;not null;uniqueIndex:idx_webhook_log_event,where:authorized"`
+	EventID        string         `gorm:"type:varchar(255);size:255;not null;uniqueIndex:idx_webhook_log_event,where:authorized"`
+	OrderNo        string         `gorm:"type:varchar(100);size:100;index"`
+	StatusCode     string         `gorm:"type:varchar(100);size:100"`
+	Headers        datatype.JSONB `gorm:"type:jsonb"`
+	Body           string         `gorm:"type:text"`
+	Authorized     bool           `gorm:"type:boolean;not null;default:false"`
+	Status         string         `gorm:"type:varchar(20);size:20;not null;index"`
+	Held           bool           `gorm:"type:boolean;not null;default:false;index"`
+	ErrorCode      int            `gorm:"type:int"`
+	ErrorMessage   string         `gorm:"type:text"`
+	Attempts       int            `gorm:"type:int;not null;default:0"`
+	DuplicateCount int            `gorm:"type:int;not null;default:0"`
+	ProcessedAt    *time.Time     `gorm:"type:timestamp;null"`
+}
+
+func (WebhookLog) TableName() string {
+	return "webhook_log"
+}
@@ -0,0 +1,35 @@
+package request
+
+import "go-klikdokter/helper/global"
+
+// swagger:parameters GetChannelCourierCredential DeleteChannelCourierCredential
+type ChannelCourierCredentialByUID struct {
+	// Channel Courier UID
+	// in: path
+	// required: true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters SaveChannelCourierCredential
+type SaveChannelCourierCredential struct {
+	// Channel Courier UID
+	// in: path
+	// required: true
+	UID string `json:"uid"`
+
+	// in: body
+	Body ChannelCourierCredentialBody `json:"body"`
+
+	global.JWTInfo
+}
+
+// swagger:model ChannelCourierCredentialBody
+type ChannelCourierCredentialBody struct {
+	// Client id of the grab account, shipper only needs the secret
+	// example: 555309ddf82347deab1c6d11716d4d66
+	ClientID string `json:"client_id"`
+
+	// Api key of the shipper account or client secret of the grab account, it is stored encrypted and never returned
+	// required: true
+	Secret string `json:"secret"`
+}
@@ -0,0 +1,80 @@
+package request
+
+import "go-klikdokter/helper/global"
+
+// swagger:parameters GetChannelPriceRule DeleteChannelPriceRule
+type ChannelPriceRuleByUID struct {
+	// in: path
+	// required: true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters SaveChannelPriceRule
+type SaveChannelPriceRule struct {
+	// in: body
+	Body SaveChannelPriceRuleBody `json:"body"`
+
+	global.JWTInfo
+}
+
+// swagger:model SaveChannelPriceRuleBody
+type SaveChannelPriceRuleBody struct {
+	// required: true
+	ChannelUID string `json:"channel_uid"`
+
+	ChannelPriceRuleBody
+}
+
+// swagger:parameters UpdateChannelPriceRule
+type UpdateChannelPriceRule struct {
+	// in: path
+	// required: true
+	UID string `json:"uid"`
+
+	// in: body
+	Body ChannelPriceRuleBody `json:"body"`
+
+	global.JWTInfo
+}
+
+// swagger:model ChannelPriceRuleBody
+type ChannelPriceRuleBody struct {
+	// required: true
+	Name string `json:"name"`
+
+	// Empty matches every courier
+	CourierUID string `json:"courier_uid"`
+
+	// Empty matches every courier service
+	CourierServiceUID string `json:"courier_service_uid"`
+
+	// Empty matches every shipping type
+	ShippingType string `json:"shipping_type"`
+
+	// Matches destination postal codes starting with the prefix, empty matches every region
+	PostalCodePrefix string `json:"postal_code_prefix"`
+
+	// Final weight band in kg, 0 is open
+	MinWeight float64 `json:"min_weight"`
+	MaxWeight float64 `json:"max_weight"`
+
+	// required: true
+	// enum: markup_percentage,markup_fixed,discount_percentage,discount_fixed
+	AdjustmentType string `json:"adjustment_type"`
+
+	// Percent for percentage adjustments, amount for fixed adjustments
+	AdjustmentValue float64 `json:"adjustment_value"`
+
+	// The adjusted price is rounded to a multiple of round_to, 0 does not round
+	// example: 500
+	RoundTo float64 `json:"round_to"`
+
+	// enum: nearest,up,down
+	Rounding string `json:"rounding"`
+
+	// Rules are applied from the lowest priority
+	Priority int `json:"priority"`
+
+	// 1 is active, default 1
+	Status *int32 `json:"status"`
+}
@@ -0,0 +1,40 @@
+package request
+
+import "go-klikdokter/helper/global"
+
+// swagger:parameters GetChannelRecommendationWeight
+type ChannelRecommendationWeightByUID struct {
+	// in: path
+	// required: true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters SaveChannelRecommendationWeight
+type SaveChannelRecommendationWeight struct {
+	// Channel UID
+	// in: path
+	// required: true
+	UID string `json:"uid"`
+
+	// in: body
+	Body ChannelRecommendationWeightBody `json:"body"`
+
+	global.JWTInfo
+}
+
+// swagger:model ChannelRecommendationWeightBody
+type ChannelRecommendationWeightBody struct {
+	// The weights are relative to each other, at least one must be set
+	// example: 0.4
+	PriceWeight float64 `json:"price_weight"`
+
+	// example: 0.3
+	EtdWeight float64 `json:"etd_weight"`
+
+	// example: 0.2
+	PriorityWeight float64 `json:"priority_weight"`
+
+	// Share of the orders of the courier service that were not cancelled
+	// example: 0.1
+	ReliabilityWeight float64 `json:"reliability_weight"`
+}
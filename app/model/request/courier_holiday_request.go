@@ -0,0 +1,43 @@
+package request
+
+import "go-klikdokter/helper/global"
+
+// swagger:parameters ListCourierHoliday
+type ListCourierHoliday struct {
+	// Empty lists the holidays of every courier
+	// in: query
+	CourierUID string `schema:"courier_uid" json:"courier_uid"`
+
+	// Defaults to the current year
+	// in: query
+	Year int `schema:"year" json:"year"`
+}
+
+// swagger:parameters DeleteCourierHoliday
+type CourierHolidayByUID struct {
+	// in: path
+	// required: true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters SaveCourierHoliday
+type SaveCourierHoliday struct {
+	// in: body
+	Body SaveCourierHolidayBody `json:"body"`
+
+	global.JWTInfo
+}
+
+// swagger:model SaveCourierHolidayBody
+type SaveCourierHolidayBody struct {
+	// Empty applies the holiday to every courier
+	CourierUID string `json:"courier_uid"`
+
+	// required: true
+	// example: 2022-12-25
+	Date string `json:"date"`
+
+	// required: true
+	// example: Christmas Day
+	Name string `json:"name"`
+}
@@ -93,11 +93,15 @@ type SaveCourierServiceRequest struct {
 	// in: integer
 	Insurance int32 `json:"insurance"`
 
+	// Insurance Mandatory of the Courier Service
+	// in: integer
+	InsuranceMandatory int32 `json:"insurance_mandatory"`
+
 	// Insurance Min of the Courier Service
 	// in: float64
 	InsuranceMin float64 `json:"insurance_min"`
 
-	// Insurance Fee Type of the Courier Service
+	// Insurance Fee Type of the Courier Service, percentage or fixed
 	// in: string
 	InsuranceFeeType string `json:"insurance_fee_type"`
 
@@ -105,6 +109,18 @@ type SaveCourierServiceRequest struct {
 	// in: float64
 	InsuranceFee float64 `json:"insurance_fee"`
 
+	// Cod Fee Type of the Courier Service, percentage or fixed
+	// in: string
+	CodFeeType string `json:"cod_fee_type"`
+
+	// Cod Fee of the Courier Service
+	// in: float64
+	CodFee float64 `json:"cod_fee"`
+
+	// Cod Fee Min of the Courier Service
+	// in: float64
+	CodFeeMin float64 `json:"cod_fee_min"`
+
 	// Start Time of the Courier Service
 	// example:"15:04:05+07"
 	StartTime datatype.Time `json:"start_time"`
@@ -282,11 +298,15 @@ type UpdateCourierServiceRequest struct {
 	// in: integer
 	Insurance int `json:"insurance"`
 
+	// Insurance Mandatory of the Courier Service
+	// in: integer
+	InsuranceMandatory int `json:"insurance_mandatory"`
+
 	// Insurance Min of the Courier Service
 	// in: float64
 	InsuranceMin float64 `json:"insurance_min"`
 
-	// Insurance Fee Type of the Courier Service
+	// Insurance Fee Type of the Courier Service, percentage or fixed
 	// in: string
 	InsuranceFeeType string `json:"insurance_fee_type"`
 
@@ -294,6 +314,18 @@ type UpdateCourierServiceRequest struct {
 	// in: float64
 	InsuranceFee float64 `json:"insurance_fee"`
 
+	// Cod Fee Type of the Courier Service, percentage or fixed
+	// in: string
+	CodFeeType string `json:"cod_fee_type"`
+
+	// Cod Fee of the Courier Service
+	// in: float64
+	CodFee float64 `json:"cod_fee"`
+
+	// Cod Fee Min of the Courier Service
+	// in: float64
+	CodFeeMin float64 `json:"cod_fee_min"`
+
 	// Start Time of the Courier Service
 	// example:"15:04:05+07"
 	// in: time
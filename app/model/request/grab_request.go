@@ -1,5 +1,7 @@
 package request
 
+import "fmt"
+
 type Dimensions struct {
 	Height int `json:"height"`
 	Width  int `json:"width"`
@@ -48,12 +50,13 @@ type CreateDeliveryGrab struct {
 	ServiceType     string    `json:"serviceType"`
 	PaymentMethod   string    `json:"paymentMethod"`
 	Packages        []Package `json:"packages"`
-	//CashOnDelivery  CashOnDelivery      `json:"cashOnDelivery"`
-	Sender      GrabSenderRecipient `json:"sender"`
-	Recipient   GrabSenderRecipient `json:"recipient"`
-	Origin      Origin              `json:"origin"`
-	Destination Destination         `json:"destination"`
-	Schedule    Schedule            `json:"schedule"`
+	// amount collected from the recipient, the delivery fee is still paid by PaymentMethod
+	CashOnDelivery *CashOnDelivery     `json:"cashOnDelivery,omitempty"`
+	Sender         GrabSenderRecipient `json:"sender"`
+	Recipient      GrabSenderRecipient `json:"recipient"`
+	Origin         Origin              `json:"origin"`
+	Destination    Destination         `json:"destination"`
+	Schedule       Schedule            `json:"schedule"`
 }
 
 type CashOnDelivery struct {
@@ -100,6 +103,11 @@ type WebhookUpdateStatusGrab struct {
 	Driver          Driver                      `json:"driver"`
 }
 
+// EventID identifies a grab status event, grab sends the same event again when it is not acknowledged
+func (w *WebhookUpdateStatusGrab) EventID() string {
+	return fmt.Sprint(w.DeliveryID, ":", w.Status, ":", w.Timestamp)
+}
+
 type UpdateStatusSenderRecipient struct {
 	Name         string `json:"name"`
 	Address      string `json:"address"`
@@ -0,0 +1,37 @@
+package request
+
+import "encoding/json"
+
+// swagger:parameters GetOrderShippingOutboxList
+type GetOrderShippingOutboxList struct {
+	// Filter : {"status":["delivered","failed"],"order_no":["001","002"],"order_shipping_uid":["001","002"],"topic":["value","value"]}
+	// in: query
+	Filter string `json:"filter"`
+
+	// Maximun records per page
+	// in: int32
+	Limit int `schema:"limit" binding:"omitempty,numeric,min=1,max=100" json:"limit"`
+
+	// Page No
+	// in: int32
+	Page int `schema:"page" binding:"omitempty,numeric,min=1" json:"page"`
+
+	// Sort fields
+	// in: string
+	Sort string `schema:"sort" binding:"omitempty" json:"sort"`
+
+	Filters GetOrderShippingOutboxFilter `json:"-"`
+}
+
+type GetOrderShippingOutboxFilter struct {
+	Status           []string `json:"status"`
+	OrderNo          []string `json:"order_no"`
+	OrderShippingUID []string `json:"order_shipping_uid"`
+	Topic            []string `json:"topic"`
+}
+
+func (m *GetOrderShippingOutboxList) GetFilter() {
+	if len(m.Filter) > 0 {
+		_ = json.Unmarshal([]byte(m.Filter), &m.Filters)
+	}
+}
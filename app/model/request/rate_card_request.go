@@ -0,0 +1,77 @@
+package request
+
+import (
+	"go-klikdokter/helper/global"
+	"time"
+)
+
+// swagger:parameters ListRateCard
+type RateCardByChannelCourierService struct {
+	// Channel courier service UID
+	// in: path
+	// required: true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters SaveRateCard
+type SaveRateCard struct {
+	// Channel courier service UID
+	// in: path
+	// required: true
+	UID string `json:"uid"`
+
+	// in: body
+	Body SaveRateCardBody `json:"body"`
+
+	global.JWTInfo
+}
+
+// swagger:model SaveRateCardBody
+type SaveRateCardBody struct {
+	// required: true
+	// example: 2022-01-01T00:00:00Z
+	EffectiveFrom time.Time `json:"effective_from"`
+
+	// Card has no end date when empty
+	EffectiveTo *time.Time `json:"effective_to"`
+
+	BaseFare float64 `json:"base_fare"`
+
+	// Total price is raised to the minimum charge
+	MinimumCharge float64 `json:"minimum_charge"`
+
+	// Rate per km, up_to 0 on the last tier means unbounded
+	// example: [{"up_to":5,"rate":2000},{"up_to":0,"rate":1500}]
+	DistanceTiers []RateCardTier `json:"distance_tiers"`
+
+	// Rate per kg of the final weight, up_to 0 on the last tier means unbounded
+	// example: [{"up_to":1,"rate":0},{"up_to":0,"rate":3000}]
+	WeightTiers []RateCardTier `json:"weight_tiers"`
+
+	// Postal code prefixes of each zone
+	// example: [{"code":"JKT","postal_codes":["10","11","12"]}]
+	Zones []RateCardZone `json:"zones"`
+
+	// Empty origin_zone or destination_zone matches any address
+	// example: [{"origin_zone":"","destination_zone":"JKT","amount":5000}]
+	ZoneSurcharges []RateCardZoneSurcharge `json:"zone_surcharges"`
+}
+
+// swagger:model RateCardTier
+type RateCardTier struct {
+	UpTo float64 `json:"up_to"`
+	Rate float64 `json:"rate"`
+}
+
+// swagger:model RateCardZone
+type RateCardZone struct {
+	Code        string   `json:"code"`
+	PostalCodes []string `json:"postal_codes"`
+}
+
+// swagger:model RateCardZoneSurcharge
+type RateCardZoneSurcharge struct {
+	OriginZone      string  `json:"origin_zone"`
+	DestinationZone string  `json:"destination_zone"`
+	Amount          float64 `json:"amount"`
+}
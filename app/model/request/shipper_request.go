@@ -19,7 +19,7 @@ func NewGetPricingDomesticRequest(origin, destination int, input *GetShippingRat
 			Longitude: input.Destination.Longitude,
 		},
 		Page:      1,
-		COD:       false,
+		COD:       input.COD,
 		ForOrder:  false,
 		ItemValue: input.TotalProductPrice,
 	}
@@ -0,0 +1,83 @@
+package request
+
+import (
+	"go-klikdokter/helper/global"
+	"time"
+)
+
+// swagger:parameters GetShippingPromotion DeleteShippingPromotion
+type ShippingPromotionByUID struct {
+	// in: path
+	// required: true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters SaveShippingPromotion
+type SaveShippingPromotion struct {
+	// in: body
+	Body SaveShippingPromotionBody `json:"body"`
+
+	global.JWTInfo
+}
+
+// swagger:model SaveShippingPromotionBody
+type SaveShippingPromotionBody struct {
+	// required: true
+	ChannelUID string `json:"channel_uid"`
+
+	ShippingPromotionBody
+}
+
+// swagger:parameters UpdateShippingPromotion
+type UpdateShippingPromotion struct {
+	// in: path
+	// required: true
+	UID string `json:"uid"`
+
+	// in: body
+	Body ShippingPromotionBody `json:"body"`
+
+	global.JWTInfo
+}
+
+// swagger:model ShippingPromotionBody
+type ShippingPromotionBody struct {
+	// required: true
+	Name string `json:"name"`
+
+	// The promotion is only applied when the voucher code is used, empty is applied automatically
+	VoucherCode string `json:"voucher_code"`
+
+	// Empty matches every courier service
+	CourierServiceUID string `json:"courier_service_uid"`
+
+	// example: 100000
+	MinTotalProductPrice float64 `json:"min_total_product_price"`
+
+	// Matches destination postal codes starting with the prefix, empty matches every region
+	PostalCodePrefix string `json:"postal_code_prefix"`
+
+	// required: true
+	// example: 2022-01-01T00:00:00+07:00
+	StartTime time.Time `json:"start_time"`
+
+	// Empty has no end
+	// example: 2022-02-01T00:00:00+07:00
+	EndTime *time.Time `json:"end_time"`
+
+	// required: true
+	// enum: free_shipping,percentage,fixed
+	DiscountType string `json:"discount_type"`
+
+	// Percent for percentage discounts, amount for fixed discounts
+	DiscountValue float64 `json:"discount_value"`
+
+	// Caps the discount of a shipment, 0 is not capped
+	MaxDiscount float64 `json:"max_discount"`
+
+	// Total discount the campaign can give, 0 is not capped
+	BudgetCap float64 `json:"budget_cap"`
+
+	// 1 is active, default 1
+	Status *int32 `json:"status"`
+}
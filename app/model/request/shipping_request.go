@@ -1,6 +1,8 @@
 package request
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"go-klikdokter/helper/global"
@@ -31,10 +33,31 @@ type GetShippingRateRequest struct {
 	TotalLength         float64           `json:"total_length"`
 	TotalProductPrice   float64           `json:"total_product_price"`
 	ContainPrescription bool              `json:"contain_prescription"`
+	COD                 bool              `json:"cod"`
+	UseInsurance        bool              `json:"use_insurance"`
+	VoucherCode         string            `json:"voucher_code"`
 	Origin              AreaDetailPayload `json:"origin"`
 	Destination         AreaDetailPayload `json:"destination"`
 	CourierServiceUID   []string          `json:"courier_service_uid"`
 	ChannelCode         string            `json:"-"`
+	ChannelID           uint64            `json:"-"`
+
+	// Orders the services of every shipping type: cheapest, fastest or recommended. Empty keeps the channel priority
+	Sort string `json:"sort"`
+}
+
+const (
+	ShippingRateSortCheapest    = "cheapest"
+	ShippingRateSortFastest     = "fastest"
+	ShippingRateSortRecommended = "recommended"
+)
+
+func IsValidShippingRateSort(sort string) bool {
+	switch sort {
+	case "", ShippingRateSortCheapest, ShippingRateSortFastest, ShippingRateSortRecommended:
+		return true
+	}
+	return false
 }
 
 func (g *GetShippingRateRequest) CheckCoordinate() (bool, message.Message) {
@@ -46,12 +69,96 @@ func (g *GetShippingRateRequest) CheckCoordinate() (bool, message.Message) {
 	return true, message.SuccessMsg
 }
 
+//swagger:parameters BatchShippingRate
+type BatchShippingRate struct {
+	//in: body
+	Body BatchShippingRateRequest `json:"body"`
+}
+
+type BatchShippingRateRequest struct {
+	ChannelUID        string                      `json:"channel_uid"`
+	ShippingType      string                      `json:"shipping_type"`
+	CourierServiceUID []string                    `json:"courier_service_uid"`
+	Sort              string                      `json:"sort"`
+	Shipments         []BatchShippingRateShipment `json:"shipments"`
+}
+
+type BatchShippingRateShipment struct {
+	// Results are keyed by the id
+	// required: true
+	ID                  string            `json:"id"`
+	TotalWeight         float64           `json:"total_weight"`
+	TotalWidth          float64           `json:"total_width"`
+	TotalHeight         float64           `json:"total_heigth"`
+	TotalLength         float64           `json:"total_length"`
+	TotalProductPrice   float64           `json:"total_product_price"`
+	ContainPrescription bool              `json:"contain_prescription"`
+	COD                 bool              `json:"cod"`
+	UseInsurance        bool              `json:"use_insurance"`
+	VoucherCode         string            `json:"voucher_code"`
+	Origin              AreaDetailPayload `json:"origin"`
+	Destination         AreaDetailPayload `json:"destination"`
+}
+
+func (b *BatchShippingRateRequest) Validate(maxShipments int) message.Message {
+	if !IsValidShippingRateSort(b.Sort) {
+		return message.ErrInvalidShippingRateSort
+	}
+
+	if len(b.Shipments) == 0 {
+		return message.ErrShipmentsRequired
+	}
+
+	if len(b.Shipments) > maxShipments {
+		return message.ErrTooManyShipments
+	}
+
+	ids := make(map[string]bool, len(b.Shipments))
+	for _, v := range b.Shipments {
+		if v.ID == "" {
+			return message.ErrShipmentIDRequired
+		}
+
+		if ids[v.ID] {
+			return message.ErrDuplicateShipmentID
+		}
+		ids[v.ID] = true
+	}
+
+	return message.SuccessMsg
+}
+
+// ShippingRateRequest rates the shipment with the channel and courier services of the batch
+func (b *BatchShippingRateRequest) ShippingRateRequest(shipment *BatchShippingRateShipment) GetShippingRateRequest {
+	return GetShippingRateRequest{
+		ShippingType:        b.ShippingType,
+		ChannelUID:          b.ChannelUID,
+		TotalWeight:         shipment.TotalWeight,
+		TotalWidth:          shipment.TotalWidth,
+		TotalHeight:         shipment.TotalHeight,
+		TotalLength:         shipment.TotalLength,
+		TotalProductPrice:   shipment.TotalProductPrice,
+		ContainPrescription: shipment.ContainPrescription,
+		COD:                 shipment.COD,
+		UseInsurance:        shipment.UseInsurance,
+		VoucherCode:         shipment.VoucherCode,
+		Origin:              shipment.Origin,
+		Destination:         shipment.Destination,
+		CourierServiceUID:   b.CourierServiceUID,
+		Sort:                b.Sort,
+	}
+}
+
 type AreaDetailPayload struct {
 	CountryCode string `json:"country_code"`
 	PostalCode  string `json:"postal_code"`
 	Subdistrict string `json:"subdistrict"`
 	Latitude    string `json:"latitude"`
 	Longitude   string `json:"longitude"`
+
+	// IANA timezone of the origin to estimate the delivery dates in, default Asia/Jakarta
+	// example: Asia/Makassar
+	Timezone string `json:"timezone"`
 }
 
 type ChannelCourierServicePayloadItem struct {
@@ -75,6 +182,10 @@ type CreateDeiveryArea struct {
 	ProvinceName string `json:"province_name"`
 	CityName     string `json:"city_name"`
 	DistrictName string `json:"district_name"`
+
+	// IANA timezone of the origin to estimate the delivery dates in, default Asia/Jakarta
+	// example: Asia/Makassar
+	Timezone string `json:"timezone"`
 }
 
 type CreateDeliveryProduct struct {
@@ -96,6 +207,10 @@ type CreateDeliveryPackage struct {
 
 //swagger:parameters CreateDelivery
 type CreateDeliveryRequest struct {
+	// Retry with the same key replays the original result
+	//in:header
+	IdempotencyKey string `json:"Idempotency-Key"`
+
 	//in:body
 	Body CreateDelivery `json:"body"`
 }
@@ -106,6 +221,8 @@ type CreateDelivery struct {
 	OrderNo           string                `json:"order_no"`
 	COD               bool                  `json:"cod"`
 	UseInsurance      bool                  `json:"use_insurance"`
+	VoucherCode       string                `json:"voucher_code"`
+	QuoteID           string                `json:"quote_id"`
 	Notes             string                `json:"notes"`
 	Merchant          CreateDeliveryPartner `json:"merchant"`
 	Customer          CreateDeliveryPartner `json:"customer"`
@@ -113,6 +230,23 @@ type CreateDelivery struct {
 	Destination       CreateDeiveryArea     `json:"destination"`
 	Package           CreateDeliveryPackage `json:"package"`
 	Username          string                `json:"username"`
+	IdempotencyKey    string                `json:"-"`
+}
+
+// Hash is used to detect a different payload sent under the same idempotency key
+func (c *CreateDelivery) Hash() string {
+	body, _ := json.Marshal(c)
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// CodAmount is collected from the customer on delivery
+func (c *CreateDelivery) CodAmount() float64 {
+	if !c.COD {
+		return 0
+	}
+
+	return c.Package.TotalProductPrice
 }
 
 func (c *CreateDelivery) CheckCoordinate() (bool, message.Message) {
@@ -141,7 +275,8 @@ func (c *CreateDelivery) ToCreateOrderShipperPackage() *CreateOrderShipperPackag
 		Length: c.Package.TotalLength,
 		Width:  c.Package.TotalWidth,
 		Weight: c.Package.TotalWeight,
-		Price:  c.Package.TotalProductPrice,
+		// shipper collects the package price when the courier cod is set
+		Price: c.Package.TotalProductPrice,
 	}
 }
 
@@ -224,6 +359,11 @@ type WebhookUpdateStatusShipper struct {
 	global.JWTInfo
 }
 
+// EventID identifies a shipper status event, shipper sends the same event again when it is not acknowledged
+func (w *WebhookUpdateStatusShipper) EventID() string {
+	return fmt.Sprint(w.OrderID, ":", w.ExternalStatus.Code, ":", w.StatusDate.Unix())
+}
+
 type ShippingStatus struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
@@ -378,6 +518,29 @@ func (u *UpdateOrderShippingDriverInfo) Description() string {
 	return desc
 }
 
+// swagger:parameters UpdateStatusOrderShipping
+type UpdateStatusOrderShipping struct {
+	// in: path
+	// required: true
+	UID string `json:"uid"`
+
+	// in: body
+	Body UpdateStatusOrderShippingBody `json:"body"`
+}
+
+// swagger:model UpdateStatusOrderShippingBody
+type UpdateStatusOrderShippingBody struct {
+	// required: true
+	ChannelUID string `json:"channel_uid"`
+	// required: true
+	// example: delivered
+	StatusCode string `json:"status_code"`
+	// example: Paket diterima oleh customer
+	Notes      string                        `json:"notes"`
+	DriverInfo UpdateOrderShippingDriverInfo `json:"driver_info"`
+	Username   string                        `json:"username"`
+}
+
 // swagger:parameters GetOrderShippingLabel
 type GetOrderShippingLabel struct {
 	// in: path
@@ -0,0 +1,140 @@
+package request
+
+import "go-klikdokter/helper/global"
+
+// swagger:parameters SaveShippingStatus
+type SaveShippingStatus struct {
+	// in: body
+	Body SaveShippingStatusBody `json:"body"`
+
+	global.JWTInfo
+}
+
+// swagger:model SaveShippingStatusBody
+type SaveShippingStatusBody struct {
+	// required: true
+	ChannelUID string `json:"channel_uid"`
+
+	// required: true
+	StatusCode string `json:"status_code"`
+
+	// required: true
+	StatusName string `json:"status_name"`
+
+	Description string `json:"description"`
+}
+
+// swagger:parameters UpdateShippingStatus
+type UpdateShippingStatus struct {
+	// in: path
+	// required: true
+	UID string `json:"uid"`
+
+	// in: body
+	Body UpdateShippingStatusBody `json:"body"`
+
+	global.JWTInfo
+}
+
+// swagger:model UpdateShippingStatusBody
+type UpdateShippingStatusBody struct {
+	// required: true
+	StatusCode string `json:"status_code"`
+
+	// required: true
+	StatusName string `json:"status_name"`
+
+	Description string `json:"description"`
+}
+
+// swagger:parameters DeleteShippingStatus DeleteShippingCourierStatus GetChannelShippingStatus GetChannelStatusTransition DeleteStatusTransition
+type ShippingStatusByUID struct {
+	// in: path
+	// required: true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters CloneShippingStatus
+type CloneShippingStatus struct {
+	// in: body
+	Body CloneShippingStatusBody `json:"body"`
+
+	global.JWTInfo
+}
+
+// swagger:model CloneShippingStatusBody
+type CloneShippingStatusBody struct {
+	// Channel to copy the shipping status, courier status mappings and status transitions from
+	// required: true
+	SourceChannelUID string `json:"source_channel_uid"`
+
+	// Channel without any shipping status yet
+	// required: true
+	TargetChannelUID string `json:"target_channel_uid"`
+}
+
+// swagger:parameters SaveShippingCourierStatus
+type SaveShippingCourierStatus struct {
+	// in: body
+	Body SaveShippingCourierStatusBody `json:"body"`
+
+	global.JWTInfo
+}
+
+// swagger:model SaveShippingCourierStatusBody
+type SaveShippingCourierStatusBody struct {
+	// required: true
+	ShippingStatusUID string `json:"shipping_status_uid"`
+
+	// required: true
+	CourierUID string `json:"courier_uid"`
+
+	// Status codes sent by the courier
+	// required: true
+	// example: ["1000","1010"]
+	StatusCourier []string `json:"status_courier"`
+}
+
+// swagger:parameters UpdateShippingCourierStatus
+type UpdateShippingCourierStatus struct {
+	// in: path
+	// required: true
+	UID string `json:"uid"`
+
+	// in: body
+	Body UpdateShippingCourierStatusBody `json:"body"`
+
+	global.JWTInfo
+}
+
+// swagger:model UpdateShippingCourierStatusBody
+type UpdateShippingCourierStatusBody struct {
+	// Status codes sent by the courier
+	// required: true
+	// example: ["1000","1010"]
+	StatusCourier []string `json:"status_courier"`
+}
+
+// swagger:parameters SaveStatusTransition
+type SaveStatusTransition struct {
+	// in: body
+	Body SaveStatusTransitionBody `json:"body"`
+
+	global.JWTInfo
+}
+
+// swagger:model SaveStatusTransitionBody
+type SaveStatusTransitionBody struct {
+	// required: true
+	ChannelUID string `json:"channel_uid"`
+
+	// Status code of the channel the order moves from
+	// required: true
+	// example: request_pickup
+	FromStatusCode string `json:"from_status_code"`
+
+	// Status code of the channel the order moves to
+	// required: true
+	// example: delivered
+	ToStatusCode string `json:"to_status_code"`
+}
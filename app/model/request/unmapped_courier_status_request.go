@@ -0,0 +1,58 @@
+package request
+
+import (
+	"encoding/json"
+	"go-klikdokter/helper/global"
+)
+
+// swagger:parameters GetUnmappedCourierStatusList
+type GetUnmappedCourierStatusList struct {
+	// Filter : {"courier_code":["shipper","grab"],"status_code":["1000"],"status":["open","mapped"]}
+	// in: query
+	Filter string `json:"filter"`
+
+	// Maximun records per page
+	// in: int32
+	Limit int `schema:"limit" binding:"omitempty,numeric,min=1,max=100" json:"limit"`
+
+	// Page No
+	// in: int32
+	Page int `schema:"page" binding:"omitempty,numeric,min=1" json:"page"`
+
+	// Sort fields
+	// in: string
+	Sort string `schema:"sort" binding:"omitempty" json:"sort"`
+
+	Filters GetUnmappedCourierStatusFilter `json:"-"`
+}
+
+type GetUnmappedCourierStatusFilter struct {
+	CourierCode []string `json:"courier_code"`
+	StatusCode  []string `json:"status_code"`
+	Status      []string `json:"status"`
+}
+
+func (m *GetUnmappedCourierStatusList) GetFilter() {
+	if len(m.Filter) > 0 {
+		_ = json.Unmarshal([]byte(m.Filter), &m.Filters)
+	}
+}
+
+// swagger:parameters MapUnmappedCourierStatus
+type MapUnmappedCourierStatus struct {
+	// in: path
+	// required: true
+	UID string `json:"uid"`
+
+	// in: body
+	Body MapUnmappedCourierStatusBody `json:"body"`
+
+	global.JWTInfo
+}
+
+// swagger:model MapUnmappedCourierStatusBody
+type MapUnmappedCourierStatusBody struct {
+	// Shipping status the courier status code is mapped to
+	// required: true
+	ShippingStatusUID string `json:"shipping_status_uid"`
+}
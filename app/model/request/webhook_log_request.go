@@ -0,0 +1,57 @@
+package request
+
+import (
+	"encoding/json"
+	"go-klikdokter/helper/global"
+	"net/http"
+)
+
+// ReceiveWebhook is an inbound courier webhook before it is parsed
+type ReceiveWebhook struct {
+	Provider string
+	Header   http.Header
+	Body     []byte
+}
+
+// swagger:parameters GetWebhookLogList
+type GetWebhookLogList struct {
+	// Filter : {"provider":["shipper","grab"],"status":["failed"],"order_no":["001","002"],"event_id":["value","value"]}
+	// in: query
+	Filter string `json:"filter"`
+
+	// Maximun records per page
+	// in: int32
+	Limit int `schema:"limit" binding:"omitempty,numeric,min=1,max=100" json:"limit"`
+
+	// Page No
+	// in: int32
+	Page int `schema:"page" binding:"omitempty,numeric,min=1" json:"page"`
+
+	// Sort fields
+	// in: string
+	Sort string `schema:"sort" binding:"omitempty" json:"sort"`
+
+	Filters GetWebhookLogFilter `json:"-"`
+}
+
+type GetWebhookLogFilter struct {
+	Provider []string `json:"provider"`
+	Status   []string `json:"status"`
+	OrderNo  []string `json:"order_no"`
+	EventID  []string `json:"event_id"`
+}
+
+func (m *GetWebhookLogList) GetFilter() {
+	if len(m.Filter) > 0 {
+		_ = json.Unmarshal([]byte(m.Filter), &m.Filters)
+	}
+}
+
+// swagger:parameters RerunWebhookLog
+type RerunWebhookLog struct {
+	// in: path
+	// required: true
+	UID string `json:"uid"`
+
+	global.JWTInfo
+}
@@ -0,0 +1,29 @@
+package response
+
+import "go-klikdokter/app/model/entity"
+
+//swagger:response ChannelCourierCredential
+type ChannelCourierCredentialResponse struct {
+	//in:body
+	Body ChannelCourierCredential `json:"body"`
+}
+
+//swagger:model ChannelCourierCredentialResponse
+type ChannelCourierCredential struct {
+	ChannelCourierUID string `json:"channel_courier_uid"`
+	CourierCode       string `json:"courier_code"`
+	ClientID          string `json:"client_id"`
+}
+
+func NewChannelCourierCredential(input *entity.ChannelCourierCredential, channelCourier *entity.ChannelCourier) *ChannelCourierCredential {
+	result := &ChannelCourierCredential{
+		ChannelCourierUID: channelCourier.UID,
+		ClientID:          input.ClientID,
+	}
+
+	if channelCourier.Courier != nil {
+		result.CourierCode = channelCourier.Courier.Code
+	}
+
+	return result
+}
@@ -0,0 +1,79 @@
+package response
+
+import "go-klikdokter/app/model/entity"
+
+//swagger:response ChannelPriceRule
+type ChannelPriceRuleResponse struct {
+	//in:body
+	Body ChannelPriceRule `json:"body"`
+}
+
+//swagger:model ChannelPriceRuleResponse
+type ChannelPriceRule struct {
+	UID               string  `json:"uid"`
+	ChannelUID        string  `json:"channel_uid"`
+	Name              string  `json:"name"`
+	CourierUID        string  `json:"courier_uid"`
+	CourierServiceUID string  `json:"courier_service_uid"`
+	ShippingType      string  `json:"shipping_type"`
+	PostalCodePrefix  string  `json:"postal_code_prefix"`
+	MinWeight         float64 `json:"min_weight"`
+	MaxWeight         float64 `json:"max_weight"`
+	AdjustmentType    string  `json:"adjustment_type"`
+	AdjustmentValue   float64 `json:"adjustment_value"`
+	RoundTo           float64 `json:"round_to"`
+	Rounding          string  `json:"rounding"`
+	Priority          int     `json:"priority"`
+	Status            int32   `json:"status"`
+}
+
+func NewChannelPriceRule(input *entity.ChannelPriceRule, channelUID string) *ChannelPriceRule {
+	rule := &ChannelPriceRule{
+		UID:              input.UID,
+		ChannelUID:       channelUID,
+		Name:             input.Name,
+		ShippingType:     input.ShippingType,
+		PostalCodePrefix: input.PostalCodePrefix,
+		MinWeight:        input.MinWeight,
+		MaxWeight:        input.MaxWeight,
+		AdjustmentType:   input.AdjustmentType,
+		AdjustmentValue:  input.AdjustmentValue,
+		RoundTo:          input.RoundTo,
+		Rounding:         input.Rounding,
+		Priority:         input.Priority,
+		Status:           1,
+	}
+
+	if input.Status != nil {
+		rule.Status = *input.Status
+	}
+
+	if input.Courier != nil {
+		rule.CourierUID = input.Courier.UID
+	}
+
+	if input.CourierService != nil {
+		rule.CourierServiceUID = input.CourierService.UID
+	}
+
+	return rule
+}
+
+//swagger:model PriceAdjustment
+type PriceAdjustment struct {
+	RuleUID string  `json:"rule_uid"`
+	Name    string  `json:"name"`
+	Amount  float64 `json:"amount"`
+}
+
+func NewPriceAdjustments(input []entity.PriceAdjustment) []PriceAdjustment {
+	var result []PriceAdjustment
+	for _, v := range input {
+		result = append(result, PriceAdjustment{
+			RuleUID: v.RuleUID,
+			Name:    v.Name,
+			Amount:  v.Amount,
+		})
+	}
+	return result
+}
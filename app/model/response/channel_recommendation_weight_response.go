@@ -0,0 +1,32 @@
+package response
+
+import "go-klikdokter/app/model/entity"
+
+//swagger:response ChannelRecommendationWeight
+type ChannelRecommendationWeightResponse struct {
+	//in:body
+	Body ChannelRecommendationWeight `json:"body"`
+}
+
+//swagger:model ChannelRecommendationWeightResponse
+type ChannelRecommendationWeight struct {
+	ChannelUID        string  `json:"channel_uid"`
+	PriceWeight       float64 `json:"price_weight"`
+	EtdWeight         float64 `json:"etd_weight"`
+	PriorityWeight    float64 `json:"priority_weight"`
+	ReliabilityWeight float64 `json:"reliability_weight"`
+
+	// false when the channel uses the default weights
+	Configured bool `json:"configured"`
+}
+
+func NewChannelRecommendationWeight(input *entity.ChannelRecommendationWeight, channelUID string) *ChannelRecommendationWeight {
+	return &ChannelRecommendationWeight{
+		ChannelUID:        channelUID,
+		PriceWeight:       input.PriceWeight,
+		EtdWeight:         input.EtdWeight,
+		PriorityWeight:    input.PriorityWeight,
+		ReliabilityWeight: input.ReliabilityWeight,
+		Configured:        input.ChannelID != 0,
+	}
+}
@@ -13,6 +13,12 @@ type GetChannelCourierStatusResponse struct {
 
 // swagger:model GetChannelCourierStatusResponse
 type GetChannelCourierStatusResponseItem struct {
+	// Courier status mapping UID
+	UID string `json:"uid"`
+
+	// Shipping status UID
+	ShippingStatusUID string `json:"shipping_status_uid"`
+
 	// Channel code
 	ChannelCode string `json:"channel_code"`
 
@@ -34,6 +40,7 @@ type GetChannelCourierStatusResponseItem struct {
 
 func NewGetChannelCourierStatusResponseItem(input entity.ShippingCourierStatus) GetChannelCourierStatusResponseItem {
 	resp := GetChannelCourierStatusResponseItem{
+		UID:           input.UID,
 		CourierStatus: input.StatusCourier,
 	}
 
@@ -44,6 +51,7 @@ func NewGetChannelCourierStatusResponseItem(input entity.ShippingCourierStatus)
 			resp.ChannelCode = input.ShippingStatus.Channel.ChannelCode
 		}
 
+		resp.ShippingStatusUID = input.ShippingStatus.UID
 		resp.StatusCode = input.ShippingStatus.StatusCode
 		resp.StatusTitle = input.ShippingStatus.StatusName
 	}
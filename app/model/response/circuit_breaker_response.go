@@ -0,0 +1,25 @@
+package response
+
+//swagger:response CircuitBreaker
+type CircuitBreakerResponse struct {
+	//in:body
+	Body CircuitBreaker `json:"body"`
+}
+
+//swagger:model CircuitBreakerResponse
+type CircuitBreaker struct {
+	// example: shipper
+	CourierCode string `json:"courier_code"`
+
+	// rate, create, track or cancel
+	Operation string `json:"operation"`
+
+	// the calls of the operation are rejected while the circuit is open
+	// example: closed
+	State string `json:"state"`
+}
+
+const (
+	CircuitOpen   = "open"
+	CircuitClosed = "closed"
+)
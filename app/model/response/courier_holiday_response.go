@@ -0,0 +1,38 @@
+package response
+
+import (
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/pkg/util"
+)
+
+//swagger:response CourierHoliday
+type CourierHolidayResponse struct {
+	//in:body
+	Body CourierHoliday `json:"body"`
+}
+
+//swagger:model CourierHolidayResponse
+type CourierHoliday struct {
+	UID string `json:"uid"`
+
+	// empty for the holidays of every courier
+	CourierUID string `json:"courier_uid"`
+
+	// example: 2022-12-25
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+func NewCourierHoliday(input *entity.CourierHoliday) *CourierHoliday {
+	holiday := &CourierHoliday{
+		UID:  input.UID,
+		Date: input.HolidayDate.Format(util.LayoutDateOnly),
+		Name: input.Name,
+	}
+
+	if input.Courier != nil {
+		holiday.CourierUID = input.Courier.UID
+	}
+
+	return holiday
+}
@@ -37,6 +37,12 @@ func (g *GrabError) Error() error {
 	return errors.New(reason)
 }
 
+type GrabAuth struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
 type GrabDeliveryQuotes struct {
 	Quotes      []Quote     `json:"quotes"`
 	Origin      Origin      `json:"origin"`
@@ -0,0 +1,27 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+//swagger:response GetOrderShippingOutboxList
+type GetOrderShippingOutboxListResponse struct {
+	//in:body
+	Body []GetOrderShippingOutboxList `json:"body"`
+}
+
+//swagger:model GetOrderShippingOutboxListResponse
+type GetOrderShippingOutboxList struct {
+	UID              string          `gorm:"column:uid" json:"uid"`
+	OrderShippingUID string          `gorm:"column:order_shipping_uid" json:"order_shipping_uid"`
+	OrderNo          string          `gorm:"column:order_no" json:"order_no"`
+	Topic            string          `gorm:"column:topic" json:"topic"`
+	Status           string          `gorm:"column:status" json:"status"`
+	Attempts         int             `gorm:"column:attempts" json:"attempts"`
+	LastError        string          `gorm:"column:last_error" json:"last_error"`
+	Payload          json.RawMessage `gorm:"column:payload" json:"payload"`
+	NextAttemptAt    time.Time       `gorm:"column:next_attempt_at" json:"next_attempt_at"`
+	DeliveredAt      *time.Time      `gorm:"column:delivered_at" json:"delivered_at"`
+	CreatedAt        time.Time       `gorm:"column:created_at" json:"created_at"`
+}
@@ -0,0 +1,76 @@
+package response
+
+import (
+	"go-klikdokter/app/model/entity"
+	"time"
+)
+
+// swagger:model PriceBreakdown
+type PriceBreakdown struct {
+	RateCardUID             string  `json:"rate_card_uid"`
+	RateCardVersion         int     `json:"rate_card_version"`
+	BaseFare                float64 `json:"base_fare"`
+	DistanceCharge          float64 `json:"distance_charge"`
+	WeightCharge            float64 `json:"weight_charge"`
+	ZoneSurcharge           float64 `json:"zone_surcharge"`
+	MinimumChargeAdjustment float64 `json:"minimum_charge_adjustment"`
+	Total                   float64 `json:"total"`
+}
+
+func NewPriceBreakdown(input entity.RateCardPrice) *PriceBreakdown {
+	return &PriceBreakdown{
+		RateCardUID:             input.RateCardUID,
+		RateCardVersion:         input.Version,
+		BaseFare:                input.BaseFare,
+		DistanceCharge:          input.DistanceCharge,
+		WeightCharge:            input.WeightCharge,
+		ZoneSurcharge:           input.ZoneSurcharge,
+		MinimumChargeAdjustment: input.MinimumChargeAdjustment,
+		Total:                   input.Total,
+	}
+}
+
+// swagger:response RateCard
+type RateCardResponse struct {
+	// in: body
+	Body RateCard `json:"body"`
+}
+
+// swagger:response RateCardList
+type RateCardListResponse struct {
+	// in: body
+	Body []RateCard `json:"body"`
+}
+
+// swagger:model RateCard
+type RateCard struct {
+	UID            string                         `json:"uid"`
+	Version        int                            `json:"version"`
+	EffectiveFrom  time.Time                      `json:"effective_from"`
+	EffectiveTo    *time.Time                     `json:"effective_to"`
+	BaseFare       float64                        `json:"base_fare"`
+	MinimumCharge  float64                        `json:"minimum_charge"`
+	DistanceTiers  []entity.RateCardTier          `json:"distance_tiers"`
+	WeightTiers    []entity.RateCardTier          `json:"weight_tiers"`
+	Zones          []entity.RateCardZone          `json:"zones"`
+	ZoneSurcharges []entity.RateCardZoneSurcharge `json:"zone_surcharges"`
+	CreatedBy      string                         `json:"created_by"`
+	CreatedAt      time.Time                      `json:"created_at"`
+}
+
+func NewRateCard(input *entity.RateCard) *RateCard {
+	return &RateCard{
+		UID:            input.UID,
+		Version:        input.Version,
+		EffectiveFrom:  input.EffectiveFrom,
+		EffectiveTo:    input.EffectiveTo,
+		BaseFare:       input.BaseFare,
+		MinimumCharge:  input.MinimumCharge,
+		DistanceTiers:  input.GetDistanceTiers(),
+		WeightTiers:    input.GetWeightTiers(),
+		Zones:          input.GetZones(),
+		ZoneSurcharges: input.GetZoneSurcharges(),
+		CreatedBy:      input.CreatedBy,
+		CreatedAt:      input.CreatedAt,
+	}
+}
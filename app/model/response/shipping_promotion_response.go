@@ -0,0 +1,79 @@
+package response
+
+import (
+	"go-klikdokter/app/model/entity"
+	"time"
+)
+
+//swagger:response ShippingPromotion
+type ShippingPromotionResponse struct {
+	//in:body
+	Body ShippingPromotion `json:"body"`
+}
+
+//swagger:model ShippingPromotionResponse
+type ShippingPromotion struct {
+	UID                  string     `json:"uid"`
+	ChannelUID           string     `json:"channel_uid"`
+	Name                 string     `json:"name"`
+	VoucherCode          string     `json:"voucher_code"`
+	CourierServiceUID    string     `json:"courier_service_uid"`
+	MinTotalProductPrice float64    `json:"min_total_product_price"`
+	PostalCodePrefix     string     `json:"postal_code_prefix"`
+	StartTime            time.Time  `json:"start_time"`
+	EndTime              *time.Time `json:"end_time"`
+	DiscountType         string     `json:"discount_type"`
+	DiscountValue        float64    `json:"discount_value"`
+	MaxDiscount          float64    `json:"max_discount"`
+	BudgetCap            float64    `json:"budget_cap"`
+	UsedBudget           float64    `json:"used_budget"`
+	Status               int32      `json:"status"`
+}
+
+func NewShippingPromotion(input *entity.ShippingPromotion, channelUID string) *ShippingPromotion {
+	promotion := &ShippingPromotion{
+		UID:                  input.UID,
+		ChannelUID:           channelUID,
+		Name:                 input.Name,
+		VoucherCode:          input.VoucherCode,
+		MinTotalProductPrice: input.MinTotalProductPrice,
+		PostalCodePrefix:     input.PostalCodePrefix,
+		StartTime:            input.StartTime,
+		EndTime:              input.EndTime,
+		DiscountType:         input.DiscountType,
+		DiscountValue:        input.DiscountValue,
+		MaxDiscount:          input.MaxDiscount,
+		BudgetCap:            input.BudgetCap,
+		UsedBudget:           input.UsedBudget,
+		Status:               1,
+	}
+
+	if input.Status != nil {
+		promotion.Status = *input.Status
+	}
+
+	if input.CourierService != nil {
+		promotion.CourierServiceUID = input.CourierService.UID
+	}
+
+	return promotion
+}
+
+//swagger:model ShippingRatePromotion
+type ShippingRatePromotion struct {
+	UID         string `json:"uid"`
+	Name        string `json:"name"`
+	VoucherCode string `json:"voucher_code"`
+}
+
+func NewShippingRatePromotion(input *entity.ShippingPromotion) *ShippingRatePromotion {
+	if input == nil {
+		return nil
+	}
+
+	return &ShippingRatePromotion{
+		UID:         input.UID,
+		Name:        input.Name,
+		VoucherCode: input.VoucherCode,
+	}
+}
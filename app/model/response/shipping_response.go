@@ -53,10 +53,50 @@ type GetShippingRateService struct {
 	MaxDay                  int                   `json:"max_day"`
 	UnitPrice               float64               `json:"unit_price"`
 	TotalPrice              float64               `json:"total_price"`
+	OriginalPrice           float64               `json:"original_price"`
 	InsuranceFee            float64               `json:"insurance_fee"`
 	MustUseInsurance        bool                  `json:"must_use_insurance"`
 	InsuranceApplied        bool                  `json:"insurance_applied"`
+	InsuredValue            float64               `json:"insured_value"`
 	Distance                float64               `json:"distance"`
+	CodAvailable            bool                  `json:"cod_available"`
+
+	// only filled when cod is requested, it is not included in the total price
+	CodFee float64 `json:"cod_fee"`
+
+	// only filled when the price is calculated from a rate card
+	PriceBreakdown *PriceBreakdown `json:"price_breakdown,omitempty"`
+
+	// channel price rules applied to the original price
+	PriceAdjustments []PriceAdjustment `json:"price_adjustments,omitempty"`
+
+	// discount of the best matching shipping promotion, the customer pays total_price - shipping_discount
+	ShippingDiscount float64                `json:"shipping_discount"`
+	Promotion        *ShippingRatePromotion `json:"promotion,omitempty"`
+
+	// quote to create the delivery with, only given to available services
+	QuoteID        string     `json:"quote_id,omitempty"`
+	QuoteExpiredAt *time.Time `json:"quote_expired_at,omitempty"`
+
+	// weighted score of price, etd, channel priority and courier reliability, only given to available services.
+	// The service with the highest score of the response is recommended
+	RecommendationScore float64 `json:"recommendation_score"`
+	Recommended         bool    `json:"recommended"`
+
+	// delivery window counted in working days from the next pickup of the courier service,
+	// only given to available services
+	EstimatedDeliveryFrom *time.Time `json:"estimated_delivery_from,omitempty"`
+	EstimatedDeliveryTo   *time.Time `json:"estimated_delivery_to,omitempty"`
+}
+
+// PayablePrice is the shipping price the customer pays
+func (g *GetShippingRateService) PayablePrice() float64 {
+	return g.TotalPrice - g.ShippingDiscount
+}
+
+// AverageEtd is the estimated delivery between the minimum and maximum
+func (g *GetShippingRateService) AverageEtd() float64 {
+	return (g.Etd_Min + g.Etd_Max) / 2
 }
 
 func (g *GetShippingRateService) FromShipper(val PricingsItem) {
@@ -198,7 +238,9 @@ type ShippingRateData struct {
 	InsuranceFee     float64
 	MustUseInsurance bool
 	InsuranceApplied bool
+	InsuredValue     float64
 	Distance         float64
+	PriceBreakdown   *PriceBreakdown
 }
 
 func (s *ShippingRateData) UpdateMessage(msg message.Message) {
@@ -224,7 +266,9 @@ func (s *ShippingRateData) UpdateMessage(msg message.Message) {
 	s.InsuranceFee = 0
 	s.MustUseInsurance = false
 	s.InsuranceApplied = false
+	s.InsuredValue = 0
 	s.Distance = 0
+	s.PriceBreakdown = nil
 }
 
 type ShippingRateSummary struct {
@@ -386,8 +430,30 @@ type GetOrderShippingDetail struct {
 	Insurance bool `json:"insurance"`
 	//example: 10000
 	InsuranceCost float64 `json:"insurance_cost"`
+	//example: 150000
+	InsuredValue float64 `json:"insured_value"`
+	//example: false
+	InsuranceMandatory bool `json:"insurance_mandatory"`
 	//example: 2000
 	TotalShippingCost float64 `json:"total_shipping_cost"`
+	//example: true
+	Cod bool `json:"cod"`
+	//example: 150000
+	CodAmount float64 `json:"cod_amount"`
+	//example: 3000
+	CodFee float64 `json:"cod_fee"`
+	//example: 5000
+	ShippingDiscount float64 `json:"shipping_discount"`
+	//example: V1StGXR8_Z5jdHi6B-myT
+	RateQuoteUID string `json:"rate_quote_uid"`
+	//provider price of the rate quote, compare with shipping_cost
+	//example: 20000
+	QuotedShippingCost float64 `json:"quoted_shipping_cost"`
+	//estimated when the order was booked
+	//example: 2022-12-26T13:00:00+07:00
+	EstimatedDeliveryFrom *time.Time `json:"estimated_delivery_from"`
+	//example: 2022-12-27T23:59:59+07:00
+	EstimatedDeliveryTo *time.Time `json:"estimated_delivery_to"`
 	//example: Notes
 	ShippingNotes string `json:"shipping_notes"`
 	//example: fhdsfg0376762345dfg
@@ -492,7 +558,12 @@ type GetOrderShippingLabelResponse struct {
 	ShippingCost         float64                      `json:"shipping_cost"`
 	Insurance            bool                         `json:"insurance"`
 	InsuranceCost        float64                      `json:"insurance_cost"`
+	InsuredValue         float64                      `json:"insured_value"`
+	InsuranceMandatory   bool                         `json:"insurance_mandatory"`
 	TotalShippingCost    float64                      `json:"total_shipping_cost"`
+	Cod                  bool                         `json:"cod"`
+	CodAmount            float64                      `json:"cod_amount"`
+	CodFee               float64                      `json:"cod_fee"`
 	ShippingNotes        string                       `json:"shipping_notes"`
 	MerchantUID          string                       `json:"merchant_uid"`
 	MerchantName         string                       `json:"merchant_name"`
@@ -562,6 +633,9 @@ type DownloadOrderShipping struct {
 	ShippingCost         string
 	TotalShippingCost    string
 	ActualShippingCost   string
+	Cod                  string
+	CodAmount            string
+	CodFee               string
 	ShippingNotes        string
 	ShippingStatusName   string
 	OrderStatusHistory   string
@@ -0,0 +1,105 @@
+package response
+
+import (
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/pkg/util/datatype"
+)
+
+//swagger:response ShippingStatus
+type ShippingStatusResponse struct {
+	//in:body
+	Body ShippingStatus `json:"body"`
+}
+
+//swagger:model ShippingStatusResponse
+type ShippingStatus struct {
+	UID         string `json:"uid"`
+	StatusCode  string `json:"status_code"`
+	StatusName  string `json:"status_name"`
+	Description string `json:"description"`
+}
+
+func NewShippingStatus(input *entity.ShippingStatus) *ShippingStatus {
+	return &ShippingStatus{
+		UID:         input.UID,
+		StatusCode:  input.StatusCode,
+		StatusName:  input.StatusName,
+		Description: input.Description,
+	}
+}
+
+//swagger:response ChannelShippingStatus
+type ChannelShippingStatusResponse struct {
+	//in:body
+	Body ChannelShippingStatus `json:"body"`
+}
+
+//swagger:model ChannelShippingStatusResponse
+type ChannelShippingStatus struct {
+	ChannelUID     string           `json:"channel_uid"`
+	ShippingStatus []ShippingStatus `json:"shipping_status"`
+	// required status codes the channel does not have yet
+	MissingStatusCodes []string `json:"missing_status_codes"`
+}
+
+//swagger:response ShippingCourierStatus
+type ShippingCourierStatusResponse struct {
+	//in:body
+	Body ShippingCourierStatus `json:"body"`
+}
+
+//swagger:model ShippingCourierStatusResponse
+type ShippingCourierStatus struct {
+	UID               string         `json:"uid"`
+	ShippingStatusUID string         `json:"shipping_status_uid"`
+	CourierUID        string         `json:"courier_uid"`
+	StatusCode        string         `json:"status_code"`
+	StatusCourier     datatype.JSONB `json:"status_courier"`
+}
+
+func NewShippingCourierStatus(input *entity.ShippingCourierStatus, shippingStatus *entity.ShippingStatus, courier *entity.Courier) *ShippingCourierStatus {
+	return &ShippingCourierStatus{
+		UID:               input.UID,
+		ShippingStatusUID: shippingStatus.UID,
+		CourierUID:        courier.UID,
+		StatusCode:        input.StatusCode,
+		StatusCourier:     input.StatusCourier,
+	}
+}
+
+//swagger:response StatusTransition
+type StatusTransitionResponse struct {
+	//in:body
+	Body StatusTransition `json:"body"`
+}
+
+//swagger:model StatusTransitionResponse
+type StatusTransition struct {
+	UID            string `json:"uid"`
+	FromStatusCode string `json:"from_status_code"`
+	ToStatusCode   string `json:"to_status_code"`
+}
+
+func NewStatusTransition(input *entity.ShippingStatusTransition) *StatusTransition {
+	return &StatusTransition{
+		UID:            input.UID,
+		FromStatusCode: input.FromStatusCode,
+		ToStatusCode:   input.ToStatusCode,
+	}
+}
+
+//swagger:response ChannelStatusTransition
+type ChannelStatusTransitionResponse struct {
+	//in:body
+	Body ChannelStatusTransition `json:"body"`
+}
+
+//swagger:model ChannelStatusTransitionResponse
+type ChannelStatusTransition struct {
+	ChannelUID       string             `json:"channel_uid"`
+	StatusTransition []StatusTransition `json:"status_transition"`
+	// true when the channel has no transition, the orders may move between any status except out of the terminal status
+	Default bool `json:"default"`
+	// status codes without any outgoing transition
+	TerminalStatusCodes []string `json:"terminal_status_codes"`
+}
@@ -0,0 +1,48 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+//swagger:response GetUnmappedCourierStatusList
+type GetUnmappedCourierStatusListResponse struct {
+	//in:body
+	Body []GetUnmappedCourierStatusList `json:"body"`
+}
+
+//swagger:model GetUnmappedCourierStatusListResponse
+type GetUnmappedCourierStatusList struct {
+	UID                string          `gorm:"column:uid" json:"uid"`
+	CourierCode        string          `gorm:"column:courier_code" json:"courier_code"`
+	CourierName        string          `gorm:"column:courier_name" json:"courier_name"`
+	StatusCode         string          `gorm:"column:status_code" json:"status_code"`
+	StatusName         string          `gorm:"column:status_name" json:"status_name"`
+	OccurrenceCount    int             `gorm:"column:occurrence_count" json:"occurrence_count"`
+	SamplePayloads     json.RawMessage `gorm:"column:sample_payloads" json:"sample_payloads"`
+	FirstSeenAt        time.Time       `gorm:"column:first_seen_at" json:"first_seen_at"`
+	LastSeenAt         time.Time       `gorm:"column:last_seen_at" json:"last_seen_at"`
+	Status             string          `gorm:"column:status" json:"status"`
+	ShippingStatusCode *string         `gorm:"column:shipping_status_code" json:"shipping_status_code"`
+	MappedAt           *time.Time      `gorm:"column:mapped_at" json:"mapped_at"`
+}
+
+//swagger:response MapUnmappedCourierStatus
+type MapUnmappedCourierStatusResponse struct {
+	//in:body
+	Body MapUnmappedCourierStatus `json:"body"`
+}
+
+//swagger:model MapUnmappedCourierStatusResponse
+type MapUnmappedCourierStatus struct {
+	UID                string `json:"uid"`
+	CourierCode        string `json:"courier_code"`
+	StatusCode         string `json:"status_code"`
+	ShippingStatusCode string `json:"shipping_status_code"`
+	// held webhooks that are applied after the mapping
+	Reapplied int `json:"reapplied"`
+	// held webhooks that still fail, they stay in the webhook log as failed
+	Failed int `json:"failed"`
+	// open while webhooks of the other channels are still held
+	Status string `json:"status"`
+}
@@ -0,0 +1,32 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+//swagger:response WebhookLog
+type WebhookLogResponse struct {
+	//in:body
+	Body []WebhookLog `json:"body"`
+}
+
+//swagger:model WebhookLogResponse
+type WebhookLog struct {
+	UID            string          `gorm:"column:uid" json:"uid"`
+	Provider       string          `gorm:"column:provider" json:"provider"`
+	EventID        string          `gorm:"column:event_id" json:"event_id"`
+	OrderNo        string          `gorm:"column:order_no" json:"order_no"`
+	StatusCode     string          `gorm:"column:status_code" json:"status_code"`
+	Headers        json.RawMessage `gorm:"column:headers" json:"headers"`
+	Body           string          `gorm:"column:body" json:"body"`
+	Authorized     bool            `gorm:"column:authorized" json:"authorized"`
+	Status         string          `gorm:"column:status" json:"status"`
+	ErrorCode      int             `gorm:"column:error_code" json:"error_code"`
+	ErrorMessage   string          `gorm:"column:error_message" json:"error_message"`
+	Attempts       int             `gorm:"column:attempts" json:"attempts"`
+	DuplicateCount int             `gorm:"column:duplicate_count" json:"duplicate_count"`
+	ProcessedAt    *time.Time      `gorm:"column:processed_at" json:"processed_at"`
+	CreatedAt      time.Time       `gorm:"column:created_at" json:"created_at"`
+	UpdatedBy      string          `gorm:"column:updated_by" json:"updated_by"`
+}
@@ -21,6 +21,15 @@ func RegisterCourierService(db *gorm.DB, logger log.Logger) service.CourierServi
 	)
 }
 
+func RegisterCourierHolidayService(db *gorm.DB, logger log.Logger) service.CourierHolidayService {
+	repo := rp.NewBaseRepository(db)
+	return service.NewCourierHolidayService(
+		logger, repo,
+		rp.NewCourierRepository(repo),
+		rp.NewCourierHolidayRepository(repo),
+	)
+}
+
 func RegisterChannelService(db *gorm.DB, logger log.Logger) service.ChannelService {
 	return service.NewChannelService(
 		logger,
@@ -30,6 +39,48 @@ func RegisterChannelService(db *gorm.DB, logger log.Logger) service.ChannelServi
 	)
 }
 
+func RegisterShippingStatusService(db *gorm.DB, logger log.Logger) service.ShippingStatusService {
+	repo := rp.NewBaseRepository(db)
+	return service.NewShippingStatusService(
+		logger, repo,
+		rp.NewChannelRepository(repo),
+		rp.NewCourierRepository(repo),
+		rp.NewShippingStatusRepository(repo),
+		rp.NewShippingCourierStatusRepository(repo),
+		rp.NewShippingStatusTransitionRepository(repo),
+	)
+}
+
+func RegisterChannelPriceRuleService(db *gorm.DB, logger log.Logger) service.ChannelPriceRuleService {
+	repo := rp.NewBaseRepository(db)
+	return service.NewChannelPriceRuleService(
+		logger, repo,
+		rp.NewChannelRepository(repo),
+		rp.NewCourierRepository(repo),
+		rp.NewCourierServiceRepository(repo),
+		rp.NewChannelPriceRuleRepository(repo),
+	)
+}
+
+func RegisterShippingPromotionService(db *gorm.DB, logger log.Logger) service.ShippingPromotionService {
+	repo := rp.NewBaseRepository(db)
+	return service.NewShippingPromotionService(
+		logger, repo,
+		rp.NewChannelRepository(repo),
+		rp.NewCourierServiceRepository(repo),
+		rp.NewShippingPromotionRepository(repo),
+	)
+}
+
+func RegisterChannelRecommendationWeightService(db *gorm.DB, logger log.Logger) service.ChannelRecommendationWeightService {
+	repo := rp.NewBaseRepository(db)
+	return service.NewChannelRecommendationWeightService(
+		logger, repo,
+		rp.NewChannelRepository(repo),
+		rp.NewChannelRecommendationWeightRepository(repo),
+	)
+}
+
 func RegisterChannelCourierService(db *gorm.DB, logger log.Logger) service.ChannelCourierService {
 	repo := rp.NewBaseRepository(db)
 	return service.NewChannelCourierService(
@@ -54,6 +105,15 @@ func RegisterShipmentPredefinedService(db *gorm.DB, logger log.Logger) service.S
 	)
 }
 
+func RegisterChannelCourierCredentialService(db *gorm.DB, logger log.Logger) service.ChannelCourierCredentialService {
+	repo := rp.NewBaseRepository(db)
+	return service.NewChannelCourierCredentialService(
+		logger, repo,
+		rp.NewChannelCourierRepository(repo),
+		rp.NewChannelCourierCredentialRepository(repo),
+	)
+}
+
 func RegisterChannelCourierServiceService(db *gorm.DB, logger log.Logger) service.ChannelCourierServiceService {
 	repo := rp.NewBaseRepository(db)
 	return service.NewChannelCourierServiceService(
@@ -63,6 +123,15 @@ func RegisterChannelCourierServiceService(db *gorm.DB, logger log.Logger) servic
 		rp.NewCourierServiceRepository(repo))
 }
 
+func RegisterRateCardService(db *gorm.DB, logger log.Logger) service.RateCardService {
+	repo := rp.NewBaseRepository(db)
+	return service.NewRateCardService(
+		logger, repo,
+		rp.NewChannelCourierServiceRepository(repo),
+		rp.NewRateCardRepository(repo),
+	)
+}
+
 func RegisterShippingService(db *gorm.DB, logger log.Logger, redis cache.RedisCache) service.ShippingService {
 	repo := rp.NewBaseRepository(db)
 	return service.NewShippingService(
@@ -70,12 +139,62 @@ func RegisterShippingService(db *gorm.DB, logger log.Logger, redis cache.RedisCa
 		rp.NewChannelRepository(repo),
 		rp.NewCourierServiceRepository(repo),
 		rp.NewCourierCoverageCodeRepository(repo),
-		shipping_provider.NewShipper(rp.NewCourierCoverageCodeRepository(repo), logger),
+		RegisterShippingProvider(repo, logger, redis),
 		redis,
 		rp.NewOrderShippingRepository(repo),
 		rp.NewCourierRepository(repo),
 		rp.NewShippingCourierStatusRepository(repo),
+		rp.NewShippingStatusTransitionRepository(repo),
+		rp.NewIdempotencyKeyRepository(repo),
+		rp.NewRateCardRepository(repo),
+		rp.NewChannelPriceRuleRepository(repo),
+		rp.NewShippingPromotionRepository(repo),
+		rp.NewShippingRateQuoteRepository(repo),
+		rp.NewChannelRecommendationWeightRepository(repo),
+		rp.NewCourierHolidayRepository(repo),
+		rp.NewChannelCourierCredentialRepository(repo),
+	)
+}
+
+func RegisterOrderShippingOutboxService(db *gorm.DB, logger log.Logger) service.OrderShippingOutboxService {
+	repo := rp.NewBaseRepository(db)
+	return service.NewOrderShippingOutboxService(
+		logger, repo,
+		rp.NewOrderShippingOutboxRepository(repo),
 		http_helper.NewDaprEndpoint(logger),
-		shipping_provider.NewGrab(logger),
 	)
 }
+
+func RegisterWebhookService(db *gorm.DB, logger log.Logger, shippingService service.ShippingService) service.WebhookService {
+	repo := rp.NewBaseRepository(db)
+	return service.NewWebhookService(
+		logger, repo,
+		rp.NewWebhookLogRepository(repo),
+		rp.NewUnmappedCourierStatusRepository(repo),
+		rp.NewCourierRepository(repo),
+		shippingService,
+	)
+}
+
+func RegisterUnmappedCourierStatusService(db *gorm.DB, logger log.Logger, webhookService service.WebhookService) service.UnmappedCourierStatusService {
+	repo := rp.NewBaseRepository(db)
+	return service.NewUnmappedCourierStatusService(
+		logger, repo,
+		rp.NewUnmappedCourierStatusRepository(repo),
+		rp.NewShippingStatusRepository(repo),
+		rp.NewShippingCourierStatusRepository(repo),
+		rp.NewWebhookLogRepository(repo),
+		webhookService,
+	)
+}
+
+func RegisterShippingProvider(repo rp.BaseRepository, logger log.Logger, redis cache.RedisCache) shipping_provider.ShippingProviderRegistry {
+	return shipping_provider.NewShippingProviderRegistry(
+		shipping_provider.NewCircuitBreakerProvider(shipping_provider.NewShipperProvider(shipping_provider.NewShipper(rp.NewCourierCoverageCodeRepository(repo), logger))),
+		shipping_provider.NewCircuitBreakerProvider(shipping_provider.NewGrabProvider(shipping_provider.NewGrab(logger, redis))),
+	)
+}
+
+func RegisterShippingProviderService(logger log.Logger) service.ShippingProviderService {
+	return service.NewShippingProviderService(logger)
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	"go-klikdokter/app/model/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChannelCourierCredentialRepository interface {
+	FindByChannelCourierID(channelCourierID uint64) (*entity.ChannelCourierCredential, error)
+	FindByChannelAndCourier(channelID, courierID uint64) (*entity.ChannelCourierCredential, error)
+	Save(input *entity.ChannelCourierCredential) error
+	Delete(input *entity.ChannelCourierCredential) error
+}
+
+type channelCourierCredentialRepositoryImpl struct {
+	base BaseRepository
+}
+
+func NewChannelCourierCredentialRepository(br BaseRepository) ChannelCourierCredentialRepository {
+	return &channelCourierCredentialRepositoryImpl{br}
+}
+
+func (r *channelCourierCredentialRepositoryImpl) FindByChannelCourierID(channelCourierID uint64) (*entity.ChannelCourierCredential, error) {
+	result := &entity.ChannelCourierCredential{}
+	err := r.base.GetDB().
+		Where(&entity.ChannelCourierCredential{ChannelCourierID: channelCourierID}).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *channelCourierCredentialRepositoryImpl) FindByChannelAndCourier(channelID, courierID uint64) (*entity.ChannelCourierCredential, error) {
+	db := r.base.GetDB()
+	channelCourier := db.Model(&entity.ChannelCourier{}).
+		Select("id").
+		Where(&entity.ChannelCourier{ChannelID: channelID, CourierID: courierID})
+
+	result := &entity.ChannelCourierCredential{}
+	err := db.Where("channel_courier_id IN (?)", channelCourier).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *channelCourierCredentialRepositoryImpl) Save(input *entity.ChannelCourierCredential) error {
+	return r.base.GetDB().Omit(clause.Associations).Save(input).Error
+}
+
+func (r *channelCourierCredentialRepositoryImpl) Delete(input *entity.ChannelCourierCredential) error {
+	return r.base.GetDB().Delete(input).Error
+}
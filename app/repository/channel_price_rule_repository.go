@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChannelPriceRuleRepository interface {
+	FindByUID(uid string) (*entity.ChannelPriceRule, error)
+	FindByChannelID(channelID uint64) ([]entity.ChannelPriceRule, error)
+	FindActiveByChannelID(channelID uint64) ([]entity.ChannelPriceRule, error)
+	Save(input *entity.ChannelPriceRule) error
+	Delete(input *entity.ChannelPriceRule) error
+}
+
+type channelPriceRuleRepositoryImpl struct {
+	base BaseRepository
+}
+
+func NewChannelPriceRuleRepository(br BaseRepository) ChannelPriceRuleRepository {
+	return &channelPriceRuleRepositoryImpl{br}
+}
+
+func (r *channelPriceRuleRepositoryImpl) FindByUID(uid string) (*entity.ChannelPriceRule, error) {
+	result := &entity.ChannelPriceRule{}
+	err := r.base.GetDB().
+		Preload("Channel").
+		Preload("Courier").
+		Preload("CourierService").
+		Where(&entity.ChannelPriceRule{BaseIDModel: base.BaseIDModel{UID: uid}}).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *channelPriceRuleRepositoryImpl) FindByChannelID(channelID uint64) ([]entity.ChannelPriceRule, error) {
+	var result []entity.ChannelPriceRule
+	err := r.base.GetDB().
+		Preload("Courier").
+		Preload("CourierService").
+		Where(&entity.ChannelPriceRule{ChannelID: channelID}).
+		Order("priority, id").
+		Find(&result).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindActiveByChannelID returns the rules in the order they are applied
+func (r *channelPriceRuleRepositoryImpl) FindActiveByChannelID(channelID uint64) ([]entity.ChannelPriceRule, error) {
+	var result []entity.ChannelPriceRule
+	err := r.base.GetDB().
+		Where("channel_id = ? AND status = 1", channelID).
+		Order("priority, id").
+		Find(&result).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *channelPriceRuleRepositoryImpl) Save(input *entity.ChannelPriceRule) error {
+	return r.base.GetDB().Omit(clause.Associations).Save(input).Error
+}
+
+func (r *channelPriceRuleRepositoryImpl) Delete(input *entity.ChannelPriceRule) error {
+	return r.base.GetDB().Delete(input).Error
+}
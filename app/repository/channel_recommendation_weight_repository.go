@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"errors"
+	"go-klikdokter/app/model/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChannelRecommendationWeightRepository interface {
+	FindByChannelID(channelID uint64) (*entity.ChannelRecommendationWeight, error)
+	Save(input *entity.ChannelRecommendationWeight) error
+}
+
+type channelRecommendationWeightRepositoryImpl struct {
+	base BaseRepository
+}
+
+func NewChannelRecommendationWeightRepository(br BaseRepository) ChannelRecommendationWeightRepository {
+	return &channelRecommendationWeightRepositoryImpl{br}
+}
+
+func (r *channelRecommendationWeightRepositoryImpl) FindByChannelID(channelID uint64) (*entity.ChannelRecommendationWeight, error) {
+	result := &entity.ChannelRecommendationWeight{}
+	err := r.base.GetDB().
+		Where(&entity.ChannelRecommendationWeight{ChannelID: channelID}).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *channelRecommendationWeightRepositoryImpl) Save(input *entity.ChannelRecommendationWeight) error {
+	return r.base.GetDB().Omit(clause.Associations).Save(input).Error
+}
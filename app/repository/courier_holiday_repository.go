@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"errors"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CourierHolidayRepository interface {
+	FindByUID(uid string) (*entity.CourierHoliday, error)
+	FindByCourierID(courierID *uint64, from, to time.Time) ([]entity.CourierHoliday, error)
+	FindByCourierIDs(courierIDs []uint64, from, to time.Time) ([]entity.CourierHoliday, error)
+	Save(input *entity.CourierHoliday) error
+	Delete(input *entity.CourierHoliday) error
+}
+
+type courierHolidayRepositoryImpl struct {
+	base BaseRepository
+}
+
+func NewCourierHolidayRepository(br BaseRepository) CourierHolidayRepository {
+	return &courierHolidayRepositoryImpl{br}
+}
+
+func (r *courierHolidayRepositoryImpl) FindByUID(uid string) (*entity.CourierHoliday, error) {
+	result := &entity.CourierHoliday{}
+	err := r.base.GetDB().
+		Preload("Courier").
+		Where(&entity.CourierHoliday{BaseIDModel: base.BaseIDModel{UID: uid}}).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindByCourierID returns the holidays of the courier between the dates, a nil courier returns the holidays of every courier
+func (r *courierHolidayRepositoryImpl) FindByCourierID(courierID *uint64, from, to time.Time) ([]entity.CourierHoliday, error) {
+	var result []entity.CourierHoliday
+	query := r.base.GetDB().
+		Preload("Courier").
+		Where("holiday_date BETWEEN ? AND ?", from, to)
+
+	if courierID != nil {
+		query = query.Where("courier_id = ?", *courierID)
+	} else {
+		query = query.Where("courier_id IS NULL")
+	}
+
+	err := query.Order("holiday_date").Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindByCourierIDs returns the holidays of the couriers and of every courier between the dates
+func (r *courierHolidayRepositoryImpl) FindByCourierIDs(courierIDs []uint64, from, to time.Time) ([]entity.CourierHoliday, error) {
+	var result []entity.CourierHoliday
+	err := r.base.GetDB().
+		Where("courier_id IN ? OR courier_id IS NULL", courierIDs).
+		Where("holiday_date BETWEEN ? AND ?", from, to).
+		Find(&result).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *courierHolidayRepositoryImpl) Save(input *entity.CourierHoliday) error {
+	return r.base.GetDB().Omit(clause.Associations).Save(input).Error
+}
+
+func (r *courierHolidayRepositoryImpl) Delete(input *entity.CourierHoliday) error {
+	return r.base.GetDB().Delete(input).Error
+}
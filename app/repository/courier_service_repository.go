@@ -227,6 +227,7 @@ func (r *courierServiceRepo) FindCourierServiceByChannelAndUIDs(channel_uid stri
 	query := db.Model(&entity.ChannelCourierService{}).
 		Select(
 			"cs.uid AS courier_service_uid",
+			"cs.id AS courier_service_id",
 			"cs.shipping_code AS shipping_code",
 			"cs.shipping_name AS shipping_name",
 			"cs.shipping_description AS shipping_description",
@@ -252,6 +253,21 @@ func (r *courierServiceRepo) FindCourierServiceByChannelAndUIDs(channel_uid stri
 			"channel_courier_service.status AS channel_courier_service_status",
 			"c.hide_purpose AS hide_purpose",
 			"cs.prescription_allowed AS prescription_allowed",
+			"channel_courier_service.id AS channel_courier_service_id",
+			"cs.max_volume AS max_volume",
+			"cs.max_distance AS max_distance",
+			"cs.min_purchase AS min_purchase",
+			"cs.max_purchase AS max_purchase",
+			"cs.start_time AS start_time",
+			"cs.end_time AS end_time",
+			"cs.cod_available AS cod_available",
+			"cs.cod_fee_type AS cod_fee_type",
+			"cs.cod_fee AS cod_fee",
+			"cs.cod_fee_min AS cod_fee_min",
+			"cs.insurance_mandatory AS insurance_mandatory",
+			"cs.insurance_fee_type AS insurance_fee_type",
+			"cs.insurance_min AS insurance_min",
+			"cc.priority_sort AS priority_sort",
 		).
 		Joins("INNER JOIN channel_courier cc ON cc.id = channel_courier_service.channel_courier_id").
 		Joins("INNER JOIN courier_service cs ON cs.id = channel_courier_service.courier_service_id").
@@ -295,6 +311,7 @@ func (r *courierServiceRepo) FindCourierService(channelUID, courierServiceUID st
 	var courierService *entity.CourierService
 
 	query := db.Model(&entity.CourierService{}).
+		Select("courier_service.*", "ccs.price_internal AS price_internal", "ccs.id AS channel_courier_service_id").
 		Preload("Courier").
 		Joins("INNER JOIN channel_courier_service ccs ON ccs.courier_service_id = courier_service.id").
 		Joins("INNER JOIN channel_courier cc ON cc.id = ccs.channel_courier_id").
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"errors"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrIdempotencyKeyTaken is returned when another request has created the key first
+var ErrIdempotencyKeyTaken = errors.New("idempotency key is already taken")
+
+type IdempotencyKeyRepository interface {
+	FindByKey(key string) (*entity.IdempotencyKey, error)
+	Create(input *entity.IdempotencyKey) (*entity.IdempotencyKey, error)
+	TakeOver(input *entity.IdempotencyKey, lockedUntil time.Time) (bool, error)
+	Update(input *entity.IdempotencyKey) error
+	Delete(id uint64) error
+}
+
+type idempotencyKeyRepositoryImpl struct {
+	base BaseRepository
+}
+
+func NewIdempotencyKeyRepository(br BaseRepository) IdempotencyKeyRepository {
+	return &idempotencyKeyRepositoryImpl{br}
+}
+
+func (r *idempotencyKeyRepositoryImpl) FindByKey(key string) (*entity.IdempotencyKey, error) {
+	result := &entity.IdempotencyKey{}
+	err := r.base.GetDB().
+		Where(&entity.IdempotencyKey{Key: key}).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Create returns ErrIdempotencyKeyTaken when the unique key has been created by a concurrent request
+func (r *idempotencyKeyRepositoryImpl) Create(input *entity.IdempotencyKey) (*entity.IdempotencyKey, error) {
+	result := r.base.GetDB().Clauses(clause.OnConflict{DoNothing: true}).Create(input)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, ErrIdempotencyKeyTaken
+	}
+
+	return input, nil
+}
+
+// TakeOver leases a key whose previous request stopped while processing or booking it, the status and the lease
+// read with the key must still be in place so only one request gets the key
+func (r *idempotencyKeyRepositoryImpl) TakeOver(input *entity.IdempotencyKey, lockedUntil time.Time) (bool, error) {
+	db := r.base.GetDB().
+		Model(&entity.IdempotencyKey{}).
+		Where("id = ? AND status = ?", input.ID, input.Status)
+
+	if input.LockedUntil == nil {
+		db = db.Where("locked_until IS NULL")
+	} else {
+		db = db.Where("locked_until = ?", *input.LockedUntil)
+	}
+
+	result := db.Updates(map[string]interface{}{"locked_until": lockedUntil, "updated_at": time.Now()})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	input.LockedUntil = &lockedUntil
+	return true, nil
+}
+
+func (r *idempotencyKeyRepositoryImpl) Update(input *entity.IdempotencyKey) error {
+	return r.base.GetDB().Model(input).Updates(input).Error
+}
+
+func (r *idempotencyKeyRepositoryImpl) Delete(id uint64) error {
+	return r.base.GetDB().
+		Where(&entity.IdempotencyKey{BaseIDModel: base.BaseIDModel{ID: id}}).
+		Delete(&entity.IdempotencyKey{}).Error
+}
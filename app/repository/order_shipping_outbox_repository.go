@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"errors"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/pkg/util"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrderShippingOutboxRepository interface {
+	ClaimPending(limit int, lease time.Duration) ([]entity.OrderShippingOutbox, error)
+	Update(input *entity.OrderShippingOutbox) error
+	FindByParams(limit, page int, sort string, filter map[string]interface{}) ([]response.GetOrderShippingOutboxList, *base.Pagination, error)
+}
+
+type orderShippingOutboxRepository struct {
+	base BaseRepository
+}
+
+func NewOrderShippingOutboxRepository(br BaseRepository) OrderShippingOutboxRepository {
+	return &orderShippingOutboxRepository{br}
+}
+
+// ClaimPending locks the due events and pushes their next attempt by the lease,
+// so other relay instances skip them while they are being published
+func (r *orderShippingOutboxRepository) ClaimPending(limit int, lease time.Duration) ([]entity.OrderShippingOutbox, error) {
+	var result []entity.OrderShippingOutbox
+	now := time.Now().In(util.Loc)
+
+	err := r.base.GetDB().Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", entity.OutboxStatusPending, now).
+			Order("id").
+			Limit(limit).
+			Find(&result).Error
+		if err != nil {
+			return err
+		}
+
+		if len(result) == 0 {
+			return nil
+		}
+
+		ids := make([]uint64, 0, len(result))
+		for _, v := range result {
+			ids = append(ids, v.ID)
+		}
+
+		return tx.Model(&entity.OrderShippingOutbox{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *orderShippingOutboxRepository) Update(input *entity.OrderShippingOutbox) error {
+	return r.base.GetDB().Model(input).
+		Select("status", "attempts", "last_error", "next_attempt_at", "delivered_at").
+		Updates(input).Error
+}
+
+func (r *orderShippingOutboxRepository) FindByParams(limit, page int, sort string, filter map[string]interface{}) ([]response.GetOrderShippingOutboxList, *base.Pagination, error) {
+	pagination := &base.Pagination{}
+
+	var result []response.GetOrderShippingOutboxList
+
+	query := r.base.GetDB().
+		Model(&entity.OrderShippingOutbox{}).
+		Select(
+			"order_shipping_outbox.uid AS uid",
+			"os.uid AS order_shipping_uid",
+			"os.order_no AS order_no",
+			"order_shipping_outbox.topic AS topic",
+			"order_shipping_outbox.status AS status",
+			"order_shipping_outbox.attempts AS attempts",
+			"order_shipping_outbox.last_error AS last_error",
+			"order_shipping_outbox.payload AS payload",
+			"order_shipping_outbox.next_attempt_at AS next_attempt_at",
+			"order_shipping_outbox.delivered_at AS delivered_at",
+			"order_shipping_outbox.created_at AS created_at",
+		).
+		Joins("INNER JOIN order_shipping os ON os.id = order_shipping_outbox.order_shipping_id")
+
+	for k, v := range filter {
+
+		if !util.IsNilOrEmpty(v) {
+
+			switch k {
+			case "status":
+				query = query.Where("order_shipping_outbox.status IN ?", v.([]string))
+
+			case "order_no":
+				query = query.Where(like("os.order_no", v.([]string)))
+
+			case "order_shipping_uid":
+				query = query.Where("os.uid IN ?", v.([]string))
+
+			case "topic":
+				query = query.Where(like("order_shipping_outbox.topic", v.([]string)))
+
+			}
+		}
+	}
+
+	sort = strings.ReplaceAll(sort, "order_shipping_uid", "os.uid")
+	sort = strings.ReplaceAll(sort, "order_no", "os.order_no")
+	sort = util.ReplaceEmptyString(sort, "order_shipping_outbox.id desc")
+
+	query = query.Order(sort)
+
+	pagination.Limit = limit
+	pagination.Page = page
+	err := query.Scopes(r.base.Paginate(&entity.OrderShippingOutbox{}, pagination, query, int64(len(result)))).
+		Find(&result).
+		Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	return result, pagination, nil
+}
@@ -7,6 +7,7 @@ import (
 	"go-klikdokter/app/model/response"
 	"go-klikdokter/pkg/util"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -20,6 +21,7 @@ type OrderShippingRepository interface {
 	FindByParams(limit, page int, sort string, filter map[string]interface{}) ([]response.GetOrderShippingList, *base.Pagination, error)
 	FindByUIDs(channelUID string, uid []string) ([]entity.OrderShipping, error)
 	Download(filter map[string]interface{}) ([]response.DownloadOrderShipping, error)
+	FindCourierServiceReliability(channelID uint64, cancelledStatus string, since time.Time) ([]entity.CourierServiceReliability, error)
 }
 
 type orderShippingRepository struct {
@@ -255,6 +257,24 @@ func (r *orderShippingRepository) FindByUIDs(channelUID string, uid []string) ([
 	return result, nil
 }
 
+// FindCourierServiceReliability counts the orders of the channel per courier service since the time,
+// the orders cancelled on request of the channel say nothing about the courier and are left out
+func (r *orderShippingRepository) FindCourierServiceReliability(channelID uint64, cancelledStatus string, since time.Time) ([]entity.CourierServiceReliability, error) {
+	var result []entity.CourierServiceReliability
+	err := r.base.GetDB().
+		Model(&entity.OrderShipping{}).
+		Select("courier_service_id, COUNT(*) AS total, COUNT(CASE WHEN status = ? THEN 1 END) AS cancelled", cancelledStatus).
+		Where("channel_id = ? AND created_at >= ? AND cancelled_by_channel = ?", channelID, since, false).
+		Group("courier_service_id").
+		Scan(&result).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 func (r *orderShippingRepository) Download(filter map[string]interface{}) ([]response.DownloadOrderShipping, error) {
 	var result []response.DownloadOrderShipping
 
@@ -303,6 +323,9 @@ func (r *orderShippingRepository) Download(filter map[string]interface{}) ([]res
 			"order_shipping.shipping_cost",
 			"order_shipping.total_shipping_cost",
 			"order_shipping.actual_shipping_cost",
+			"order_shipping.cod",
+			"order_shipping.cod_amount",
+			"order_shipping.cod_fee",
 			"order_shipping.shipping_notes",
 			"ss.status_name AS shipping_status_name",
 			"osh.order_status_history as order_status_history",
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"go-klikdokter/app/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RateCardRepository interface {
+	FindByChannelCourierServiceID(channelCourierServiceID uint64) ([]entity.RateCard, error)
+	FindEffective(channelCourierServiceIDs []uint64, at time.Time) (map[uint64]*entity.RateCard, error)
+	Create(input *entity.RateCard) error
+}
+
+type rateCardRepositoryImpl struct {
+	base BaseRepository
+}
+
+func NewRateCardRepository(br BaseRepository) RateCardRepository {
+	return &rateCardRepositoryImpl{br}
+}
+
+func (r *rateCardRepositoryImpl) FindByChannelCourierServiceID(channelCourierServiceID uint64) ([]entity.RateCard, error) {
+	var result []entity.RateCard
+	err := r.base.GetDB().
+		Where(&entity.RateCard{ChannelCourierServiceID: channelCourierServiceID}).
+		Order("version DESC").
+		Find(&result).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindEffective returns the rate card in effect keyed by channel courier service id,
+// a later effective_from supersedes the previous version
+func (r *rateCardRepositoryImpl) FindEffective(channelCourierServiceIDs []uint64, at time.Time) (map[uint64]*entity.RateCard, error) {
+	result := make(map[uint64]*entity.RateCard)
+	if len(channelCourierServiceIDs) == 0 {
+		return result, nil
+	}
+
+	var cards []entity.RateCard
+	err := r.base.GetDB().
+		Where("channel_courier_service_id IN ?", channelCourierServiceIDs).
+		Where("effective_from <= ?", at).
+		Where("effective_to IS NULL OR effective_to > ?", at).
+		Order("effective_from DESC").
+		Order("version DESC").
+		Find(&cards).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range cards {
+		if _, ok := result[cards[i].ChannelCourierServiceID]; !ok {
+			result[cards[i].ChannelCourierServiceID] = &cards[i]
+		}
+	}
+
+	return result, nil
+}
+
+// Create numbers the card as the next version of the channel courier service,
+// a concurrent create of the same version fails on the unique index
+func (r *rateCardRepositoryImpl) Create(input *entity.RateCard) error {
+	return r.base.GetDB().Transaction(func(tx *gorm.DB) error {
+		var version int
+		err := tx.Model(&entity.RateCard{}).
+			Where(&entity.RateCard{ChannelCourierServiceID: input.ChannelCourierServiceID}).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&version).Error
+		if err != nil {
+			return err
+		}
+
+		input.Version = version + 1
+		return tx.Omit(clause.Associations).Create(input).Error
+	})
+}
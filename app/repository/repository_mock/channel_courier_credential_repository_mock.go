@@ -0,0 +1,63 @@
+package repository_mock
+
+import (
+	"go-klikdokter/app/model/entity"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ChannelCourierCredentialRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *ChannelCourierCredentialRepositoryMock) FindByChannelCourierID(channelCourierID uint64) (*entity.ChannelCourierCredential, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.ChannelCourierCredential), nil
+}
+
+func (r *ChannelCourierCredentialRepositoryMock) FindByChannelAndCourier(channelID, courierID uint64) (*entity.ChannelCourierCredential, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.ChannelCourierCredential), nil
+}
+
+func (r *ChannelCourierCredentialRepositoryMock) Save(input *entity.ChannelCourierCredential) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
+
+func (r *ChannelCourierCredentialRepositoryMock) Delete(input *entity.ChannelCourierCredential) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package repository_mock
+
+import (
+	"go-klikdokter/app/model/entity"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ChannelPriceRuleRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *ChannelPriceRuleRepositoryMock) FindByUID(uid string) (*entity.ChannelPriceRule, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.ChannelPriceRule), nil
+}
+
+func (r *ChannelPriceRuleRepositoryMock) FindByChannelID(channelID uint64) ([]entity.ChannelPriceRule, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	return arguments.Get(0).([]entity.ChannelPriceRule), nil
+}
+
+func (r *ChannelPriceRuleRepositoryMock) FindActiveByChannelID(channelID uint64) ([]entity.ChannelPriceRule, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	return arguments.Get(0).([]entity.ChannelPriceRule), nil
+}
+
+func (r *ChannelPriceRuleRepositoryMock) Save(input *entity.ChannelPriceRule) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
+
+func (r *ChannelPriceRuleRepositoryMock) Delete(input *entity.ChannelPriceRule) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
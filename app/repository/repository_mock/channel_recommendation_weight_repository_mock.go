@@ -0,0 +1,37 @@
+package repository_mock
+
+import (
+	"go-klikdokter/app/model/entity"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ChannelRecommendationWeightRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *ChannelRecommendationWeightRepositoryMock) FindByChannelID(channelID uint64) (*entity.ChannelRecommendationWeight, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.ChannelRecommendationWeight), nil
+}
+
+func (r *ChannelRecommendationWeightRepositoryMock) Save(input *entity.ChannelRecommendationWeight) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
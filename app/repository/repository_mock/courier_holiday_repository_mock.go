@@ -0,0 +1,80 @@
+package repository_mock
+
+import (
+	"go-klikdokter/app/model/entity"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CourierHolidayRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *CourierHolidayRepositoryMock) FindByUID(uid string) (*entity.CourierHoliday, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.CourierHoliday), nil
+}
+
+func (r *CourierHolidayRepositoryMock) FindByCourierID(courierID *uint64, from, to time.Time) ([]entity.CourierHoliday, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).([]entity.CourierHoliday), nil
+}
+
+func (r *CourierHolidayRepositoryMock) FindByCourierIDs(courierIDs []uint64, from, to time.Time) ([]entity.CourierHoliday, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).([]entity.CourierHoliday), nil
+}
+
+func (r *CourierHolidayRepositoryMock) Save(input *entity.CourierHoliday) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
+
+func (r *CourierHolidayRepositoryMock) Delete(input *entity.CourierHoliday) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
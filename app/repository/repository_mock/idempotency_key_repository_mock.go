@@ -0,0 +1,77 @@
+package repository_mock
+
+import (
+	"go-klikdokter/app/model/entity"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type IdempotencyKeyRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *IdempotencyKeyRepositoryMock) FindByKey(key string) (*entity.IdempotencyKey, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.IdempotencyKey), nil
+}
+
+func (r *IdempotencyKeyRepositoryMock) Create(input *entity.IdempotencyKey) (*entity.IdempotencyKey, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	return input, nil
+}
+
+func (r *IdempotencyKeyRepositoryMock) TakeOver(input *entity.IdempotencyKey, lockedUntil time.Time) (bool, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return false, arguments.Get(1).(error)
+		}
+	}
+
+	if !arguments.Bool(0) {
+		return false, nil
+	}
+
+	input.LockedUntil = &lockedUntil
+	return true, nil
+}
+
+func (r *IdempotencyKeyRepositoryMock) Update(input *entity.IdempotencyKey) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyKeyRepositoryMock) Delete(id uint64) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
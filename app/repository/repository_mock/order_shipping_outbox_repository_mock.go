@@ -0,0 +1,52 @@
+package repository_mock
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/response"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type OrderShippingOutboxRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *OrderShippingOutboxRepositoryMock) ClaimPending(limit int, lease time.Duration) ([]entity.OrderShippingOutbox, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).([]entity.OrderShippingOutbox), nil
+}
+
+func (r *OrderShippingOutboxRepositoryMock) Update(input *entity.OrderShippingOutbox) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
+
+func (r *OrderShippingOutboxRepositoryMock) FindByParams(limit, page int, sort string, filter map[string]interface{}) ([]response.GetOrderShippingOutboxList, *base.Pagination, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 2 {
+		if arguments.Get(2) != nil {
+			return nil, nil, arguments.Get(2).(error)
+		}
+	}
+
+	return arguments.Get(0).([]response.GetOrderShippingOutboxList), arguments.Get(1).(*base.Pagination), nil
+}
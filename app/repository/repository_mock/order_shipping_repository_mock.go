@@ -4,6 +4,7 @@ import (
 	"go-klikdokter/app/model/base"
 	"go-klikdokter/app/model/entity"
 	"go-klikdokter/app/model/response"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -111,3 +112,19 @@ func (r *OrderShippingRepositoryMock) Download(filter map[string]interface{}) ([
 
 	return arguments.Get(0).([]response.DownloadOrderShipping), nil
 }
+
+func (r *OrderShippingRepositoryMock) FindCourierServiceReliability(channelID uint64, cancelledStatus string, since time.Time) ([]entity.CourierServiceReliability, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).([]entity.CourierServiceReliability), nil
+}
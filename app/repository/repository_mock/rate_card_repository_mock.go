@@ -0,0 +1,46 @@
+package repository_mock
+
+import (
+	"go-klikdokter/app/model/entity"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type RateCardRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *RateCardRepositoryMock) FindByChannelCourierServiceID(channelCourierServiceID uint64) ([]entity.RateCard, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	return arguments.Get(0).([]entity.RateCard), nil
+}
+
+func (r *RateCardRepositoryMock) FindEffective(channelCourierServiceIDs []uint64, at time.Time) (map[uint64]*entity.RateCard, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	return arguments.Get(0).(map[uint64]*entity.RateCard), nil
+}
+
+func (r *RateCardRepositoryMock) Create(input *entity.RateCard) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
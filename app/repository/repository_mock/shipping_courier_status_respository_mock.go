@@ -32,7 +32,7 @@ func (r *ShippingCourierStatusRepositoryMock) FindByCode(channelID, courierID ui
 	return arguments.Get(0).(*entity.ShippingCourierStatus), nil
 }
 
-func (r *ShippingCourierStatusRepositoryMock) FindByCourierStatus(courierID uint64, statusCode string) (*entity.ShippingCourierStatus, error) {
+func (r *ShippingCourierStatusRepositoryMock) FindByCourierStatus(channelID, courierID uint64, statusCode string) (*entity.ShippingCourierStatus, error) {
 	arguments := r.Mock.Called()
 
 	if len(arguments) > 1 {
@@ -47,3 +47,79 @@ func (r *ShippingCourierStatusRepositoryMock) FindByCourierStatus(courierID uint
 
 	return arguments.Get(0).(*entity.ShippingCourierStatus), nil
 }
+
+func (r *ShippingCourierStatusRepositoryMock) FindByShippingStatusID(courierID, shippingStatusID uint64) (*entity.ShippingCourierStatus, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.ShippingCourierStatus), nil
+}
+
+func (r *ShippingCourierStatusRepositoryMock) Save(input *entity.ShippingCourierStatus) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
+
+func (r *ShippingCourierStatusRepositoryMock) FindByUID(uid string) (*entity.ShippingCourierStatus, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.ShippingCourierStatus), nil
+}
+
+func (r *ShippingCourierStatusRepositoryMock) FindByChannelCourier(channelID, courierID uint64) ([]entity.ShippingCourierStatus, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	return arguments.Get(0).([]entity.ShippingCourierStatus), nil
+}
+
+func (r *ShippingCourierStatusRepositoryMock) CountByShippingStatusID(shippingStatusID uint64) (int64, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return 0, arguments.Get(1).(error)
+		}
+	}
+
+	return arguments.Get(0).(int64), nil
+}
+
+func (r *ShippingCourierStatusRepositoryMock) Delete(input *entity.ShippingCourierStatus) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package repository_mock
+
+import (
+	"go-klikdokter/app/model/entity"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ShippingPromotionRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *ShippingPromotionRepositoryMock) FindByUID(uid string) (*entity.ShippingPromotion, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.ShippingPromotion), nil
+}
+
+func (r *ShippingPromotionRepositoryMock) FindByChannelID(channelID uint64) ([]entity.ShippingPromotion, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	return arguments.Get(0).([]entity.ShippingPromotion), nil
+}
+
+func (r *ShippingPromotionRepositoryMock) FindActiveByChannelID(channelID uint64, at time.Time) ([]entity.ShippingPromotion, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	return arguments.Get(0).([]entity.ShippingPromotion), nil
+}
+
+func (r *ShippingPromotionRepositoryMock) Save(input *entity.ShippingPromotion) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
+
+func (r *ShippingPromotionRepositoryMock) Delete(input *entity.ShippingPromotion) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
+
+func (r *ShippingPromotionRepositoryMock) ReserveBudget(id uint64, amount float64) (bool, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return false, arguments.Get(1).(error)
+		}
+	}
+
+	return arguments.Bool(0), nil
+}
+
+func (r *ShippingPromotionRepositoryMock) ReleaseBudget(id uint64, amount float64) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
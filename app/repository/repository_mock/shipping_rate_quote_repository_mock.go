@@ -0,0 +1,78 @@
+package repository_mock
+
+import (
+	"go-klikdokter/app/model/entity"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ShippingRateQuoteRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *ShippingRateQuoteRepositoryMock) CreateBatch(input []entity.ShippingRateQuote) ([]entity.ShippingRateQuote, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return input, nil
+	}
+
+	return arguments.Get(0).([]entity.ShippingRateQuote), nil
+}
+
+func (r *ShippingRateQuoteRepositoryMock) FindByUID(uid string) (*entity.ShippingRateQuote, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.ShippingRateQuote), nil
+}
+
+func (r *ShippingRateQuoteRepositoryMock) Claim(uid, orderNo string, at time.Time) (bool, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return false, arguments.Get(1).(error)
+		}
+	}
+
+	return arguments.Bool(0), nil
+}
+
+func (r *ShippingRateQuoteRepositoryMock) Release(uid, orderNo string) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
+
+func (r *ShippingRateQuoteRepositoryMock) DeleteExpired(before time.Time, limit int) (int64, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return 0, arguments.Get(1).(error)
+		}
+	}
+
+	return arguments.Get(0).(int64), nil
+}
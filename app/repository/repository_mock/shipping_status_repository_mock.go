@@ -0,0 +1,85 @@
+package repository_mock
+
+import (
+	"go-klikdokter/app/model/entity"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ShippingStatusRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *ShippingStatusRepositoryMock) FindByUID(uid string) (*entity.ShippingStatus, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.ShippingStatus), nil
+}
+
+func (r *ShippingStatusRepositoryMock) FindByChannelID(channelID uint64) ([]entity.ShippingStatus, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	return arguments.Get(0).([]entity.ShippingStatus), nil
+}
+
+func (r *ShippingStatusRepositoryMock) FindByCode(channelID uint64, statusCode string) (*entity.ShippingStatus, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.ShippingStatus), nil
+}
+
+func (r *ShippingStatusRepositoryMock) Save(input *entity.ShippingStatus) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
+
+func (r *ShippingStatusRepositoryMock) Delete(input *entity.ShippingStatus) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
+
+func (r *ShippingStatusRepositoryMock) CloneChannel(sourceChannelID, targetChannelID uint64, actor string) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
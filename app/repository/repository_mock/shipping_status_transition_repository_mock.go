@@ -0,0 +1,79 @@
+package repository_mock
+
+import (
+	"go-klikdokter/app/model/entity"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ShippingStatusTransitionRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *ShippingStatusTransitionRepositoryMock) FindByChannelID(channelID uint64) (entity.ShippingStatusTransitions, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(entity.ShippingStatusTransitions), nil
+}
+
+func (r *ShippingStatusTransitionRepositoryMock) FindByUID(uid string) (*entity.ShippingStatusTransition, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.ShippingStatusTransition), nil
+}
+
+func (r *ShippingStatusTransitionRepositoryMock) FindByStatusCodes(channelID uint64, fromStatusCode, toStatusCode string) (*entity.ShippingStatusTransition, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.ShippingStatusTransition), nil
+}
+
+func (r *ShippingStatusTransitionRepositoryMock) Save(input *entity.ShippingStatusTransition) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
+
+func (r *ShippingStatusTransitionRepositoryMock) Delete(input *entity.ShippingStatusTransition) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
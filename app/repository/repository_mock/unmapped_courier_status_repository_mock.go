@@ -0,0 +1,67 @@
+package repository_mock
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/response"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type UnmappedCourierStatusRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *UnmappedCourierStatusRepositoryMock) FindByCourierStatus(courierID uint64, statusCode string) (*entity.UnmappedCourierStatus, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.UnmappedCourierStatus), nil
+}
+
+func (r *UnmappedCourierStatusRepositoryMock) FindByUID(uid string) (*entity.UnmappedCourierStatus, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.UnmappedCourierStatus), nil
+}
+
+func (r *UnmappedCourierStatusRepositoryMock) Save(input *entity.UnmappedCourierStatus) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
+
+func (r *UnmappedCourierStatusRepositoryMock) FindByParams(limit, page int, sort string, filter map[string]interface{}) ([]response.GetUnmappedCourierStatusList, *base.Pagination, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 2 {
+		if arguments.Get(2) != nil {
+			return nil, nil, arguments.Get(2).(error)
+		}
+	}
+
+	return arguments.Get(0).([]response.GetUnmappedCourierStatusList), arguments.Get(1).(*base.Pagination), nil
+}
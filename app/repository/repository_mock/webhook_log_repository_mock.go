@@ -0,0 +1,105 @@
+package repository_mock
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/response"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type WebhookLogRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (r *WebhookLogRepositoryMock) FindByEventID(provider, eventID string) (*entity.WebhookLog, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.WebhookLog), nil
+}
+
+func (r *WebhookLogRepositoryMock) FindByUID(uid string) (*entity.WebhookLog, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).(*entity.WebhookLog), nil
+}
+
+func (r *WebhookLogRepositoryMock) FindHeldByStatusCode(provider, statusCode string, channelID uint64) ([]entity.WebhookLog, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return nil, arguments.Get(1).(error)
+		}
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	}
+
+	return arguments.Get(0).([]entity.WebhookLog), nil
+}
+
+func (r *WebhookLogRepositoryMock) CountHeldByStatusCode(provider, statusCode string) (int64, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 1 {
+		if arguments.Get(1) != nil {
+			return 0, arguments.Get(1).(error)
+		}
+	}
+
+	return arguments.Get(0).(int64), nil
+}
+
+func (r *WebhookLogRepositoryMock) Create(input *entity.WebhookLog) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
+
+func (r *WebhookLogRepositoryMock) Save(input *entity.WebhookLog) error {
+	arguments := r.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
+}
+
+func (r *WebhookLogRepositoryMock) FindByParams(limit, page int, sort string, filter map[string]interface{}) ([]response.WebhookLog, *base.Pagination, error) {
+	arguments := r.Mock.Called()
+
+	if len(arguments) > 2 {
+		if arguments.Get(2) != nil {
+			return nil, nil, arguments.Get(2).(error)
+		}
+	}
+
+	return arguments.Get(0).([]response.WebhookLog), arguments.Get(1).(*base.Pagination), nil
+}
@@ -10,12 +10,19 @@ import (
 	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ShippingCourierStatusRepository interface {
 	FindByParams(limit int, page int, sort string, filters map[string]interface{}) ([]entity.ShippingCourierStatus, *base.Pagination, error)
 	FindByCode(channelID, courierID uint64, statusCode string) (*entity.ShippingCourierStatus, error)
-	FindByCourierStatus(courierID uint64, statusCode string) (*entity.ShippingCourierStatus, error)
+	FindByCourierStatus(channelID, courierID uint64, statusCode string) (*entity.ShippingCourierStatus, error)
+	FindByShippingStatusID(courierID, shippingStatusID uint64) (*entity.ShippingCourierStatus, error)
+	FindByUID(uid string) (*entity.ShippingCourierStatus, error)
+	FindByChannelCourier(channelID, courierID uint64) ([]entity.ShippingCourierStatus, error)
+	CountByShippingStatusID(shippingStatusID uint64) (int64, error)
+	Save(input *entity.ShippingCourierStatus) error
+	Delete(input *entity.ShippingCourierStatus) error
 }
 
 type shippingCourierStatusRepositoryImpl struct {
@@ -117,12 +124,18 @@ func (r *shippingCourierStatusRepositoryImpl) FindByCode(channelID, courierID ui
 	return result, nil
 }
 
-func (r *shippingCourierStatusRepositoryImpl) FindByCourierStatus(courierID uint64, statusCode string) (*entity.ShippingCourierStatus, error) {
+// FindByCourierStatus finds the mapping of the channel that has the status code of the courier,
+// the status of the mapping is either a list of codes or a single code
+func (r *shippingCourierStatusRepositoryImpl) FindByCourierStatus(channelID, courierID uint64, statusCode string) (*entity.ShippingCourierStatus, error) {
 	result := &entity.ShippingCourierStatus{}
 	query := r.base.GetDB().
 		Preload("ShippingStatus").
+		Joins("INNER JOIN shipping_status ss ON ss.id = shipping_courier_status.shipping_status_id AND ss.channel_id = ?", channelID).
 		Where(&entity.ShippingCourierStatus{CourierID: courierID}).
-		Where(fmt.Sprintf("(shipping_courier_status.status_courier->'status')::text ilike '%%\"%s\"%%'", statusCode))
+		Where(`CASE jsonb_typeof(shipping_courier_status.status_courier->'status')
+			WHEN 'array' THEN EXISTS (SELECT 1 FROM jsonb_array_elements_text(shipping_courier_status.status_courier->'status') code WHERE LOWER(code) = LOWER(?))
+			ELSE LOWER(shipping_courier_status.status_courier->>'status') = LOWER(?)
+		END`, statusCode, statusCode)
 
 	err := query.First(result).Error
 
@@ -130,7 +143,74 @@ func (r *shippingCourierStatusRepositoryImpl) FindByCourierStatus(courierID uint
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *shippingCourierStatusRepositoryImpl) FindByShippingStatusID(courierID, shippingStatusID uint64) (*entity.ShippingCourierStatus, error) {
+	result := &entity.ShippingCourierStatus{}
+	err := r.base.GetDB().
+		Where(&entity.ShippingCourierStatus{CourierID: courierID, ShippingStatusID: shippingStatusID}).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *shippingCourierStatusRepositoryImpl) Save(input *entity.ShippingCourierStatus) error {
+	return r.base.GetDB().Omit(clause.Associations).Save(input).Error
+}
+
+func (r *shippingCourierStatusRepositoryImpl) FindByUID(uid string) (*entity.ShippingCourierStatus, error) {
+	result := &entity.ShippingCourierStatus{}
+	err := r.base.GetDB().
+		Preload("ShippingStatus").
+		Preload("Courier").
+		Where(&entity.ShippingCourierStatus{BaseIDModel: base.BaseIDModel{UID: uid}}).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *shippingCourierStatusRepositoryImpl) FindByChannelCourier(channelID, courierID uint64) ([]entity.ShippingCourierStatus, error) {
+	var result []entity.ShippingCourierStatus
+	err := r.base.GetDB().
+		Joins("INNER JOIN shipping_status ss ON ss.id = shipping_courier_status.shipping_status_id AND ss.channel_id = ?", channelID).
+		Where(&entity.ShippingCourierStatus{CourierID: courierID}).
+		Find(&result).Error
+
+	if err != nil {
+		return nil, err
 	}
 
 	return result, nil
 }
+
+func (r *shippingCourierStatusRepositoryImpl) CountByShippingStatusID(shippingStatusID uint64) (int64, error) {
+	var count int64
+	err := r.base.GetDB().
+		Model(&entity.ShippingCourierStatus{}).
+		Where(&entity.ShippingCourierStatus{ShippingStatusID: shippingStatusID}).
+		Count(&count).Error
+
+	return count, err
+}
+
+func (r *shippingCourierStatusRepositoryImpl) Delete(input *entity.ShippingCourierStatus) error {
+	return r.base.GetDB().Delete(input).Error
+}
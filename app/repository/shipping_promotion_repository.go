@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"errors"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ShippingPromotionRepository interface {
+	FindByUID(uid string) (*entity.ShippingPromotion, error)
+	FindByChannelID(channelID uint64) ([]entity.ShippingPromotion, error)
+	FindActiveByChannelID(channelID uint64, at time.Time) ([]entity.ShippingPromotion, error)
+	Save(input *entity.ShippingPromotion) error
+	Delete(input *entity.ShippingPromotion) error
+	ReserveBudget(id uint64, amount float64) (bool, error)
+	ReleaseBudget(id uint64, amount float64) error
+}
+
+type shippingPromotionRepositoryImpl struct {
+	base BaseRepository
+}
+
+func NewShippingPromotionRepository(br BaseRepository) ShippingPromotionRepository {
+	return &shippingPromotionRepositoryImpl{br}
+}
+
+func (r *shippingPromotionRepositoryImpl) FindByUID(uid string) (*entity.ShippingPromotion, error) {
+	result := &entity.ShippingPromotion{}
+	err := r.base.GetDB().
+		Preload("Channel").
+		Preload("CourierService").
+		Where(&entity.ShippingPromotion{BaseIDModel: base.BaseIDModel{UID: uid}}).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *shippingPromotionRepositoryImpl) FindByChannelID(channelID uint64) ([]entity.ShippingPromotion, error) {
+	var result []entity.ShippingPromotion
+	err := r.base.GetDB().
+		Preload("CourierService").
+		Where(&entity.ShippingPromotion{ChannelID: channelID}).
+		Order("start_time desc, id").
+		Find(&result).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindActiveByChannelID skips the promotions outside the campaign window or without budget left
+func (r *shippingPromotionRepositoryImpl) FindActiveByChannelID(channelID uint64, at time.Time) ([]entity.ShippingPromotion, error) {
+	var result []entity.ShippingPromotion
+	err := r.base.GetDB().
+		Where("channel_id = ? AND status = 1 AND start_time <= ?", channelID, at).
+		Where("end_time IS NULL OR end_time > ?", at).
+		Where("budget_cap = 0 OR used_budget < budget_cap").
+		Order("id").
+		Find(&result).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *shippingPromotionRepositoryImpl) Save(input *entity.ShippingPromotion) error {
+	return r.base.GetDB().Omit(clause.Associations).Save(input).Error
+}
+
+func (r *shippingPromotionRepositoryImpl) Delete(input *entity.ShippingPromotion) error {
+	return r.base.GetDB().Delete(input).Error
+}
+
+// ReserveBudget adds the amount to the used budget in a single update,
+// it returns false when the remaining budget is not enough
+func (r *shippingPromotionRepositoryImpl) ReserveBudget(id uint64, amount float64) (bool, error) {
+	result := r.base.GetDB().
+		Model(&entity.ShippingPromotion{}).
+		Where("id = ? AND (budget_cap = 0 OR used_budget + ? <= budget_cap)", id, amount).
+		UpdateColumn("used_budget", gorm.Expr("used_budget + ?", amount))
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *shippingPromotionRepositoryImpl) ReleaseBudget(id uint64, amount float64) error {
+	return r.base.GetDB().
+		Model(&entity.ShippingPromotion{}).
+		Where("id = ?", id).
+		UpdateColumn("used_budget", gorm.Expr("GREATEST(used_budget - ?, 0)", amount)).Error
+}
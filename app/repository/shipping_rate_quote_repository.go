@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"errors"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ShippingRateQuoteRepository interface {
+	CreateBatch(input []entity.ShippingRateQuote) ([]entity.ShippingRateQuote, error)
+	FindByUID(uid string) (*entity.ShippingRateQuote, error)
+	Claim(uid, orderNo string, at time.Time) (bool, error)
+	Release(uid, orderNo string) error
+	DeleteExpired(before time.Time, limit int) (int64, error)
+}
+
+type shippingRateQuoteRepositoryImpl struct {
+	base BaseRepository
+}
+
+func NewShippingRateQuoteRepository(br BaseRepository) ShippingRateQuoteRepository {
+	return &shippingRateQuoteRepositoryImpl{br}
+}
+
+func (r *shippingRateQuoteRepositoryImpl) CreateBatch(input []entity.ShippingRateQuote) ([]entity.ShippingRateQuote, error) {
+	if err := r.base.GetDB().Create(&input).Error; err != nil {
+		return nil, err
+	}
+
+	return input, nil
+}
+
+func (r *shippingRateQuoteRepositoryImpl) FindByUID(uid string) (*entity.ShippingRateQuote, error) {
+	result := &entity.ShippingRateQuote{}
+	err := r.base.GetDB().
+		Where(&entity.ShippingRateQuote{BaseIDModel: base.BaseIDModel{UID: uid}}).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Claim binds the quote to the order no, a retry of the same order no can claim it again.
+// It returns false when another order has claimed the quote.
+func (r *shippingRateQuoteRepositoryImpl) Claim(uid, orderNo string, at time.Time) (bool, error) {
+	result := r.base.GetDB().
+		Model(&entity.ShippingRateQuote{}).
+		Where("uid = ? AND (order_no = '' OR order_no = ?)", uid, orderNo).
+		UpdateColumns(map[string]interface{}{"order_no": orderNo, "used_at": at})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *shippingRateQuoteRepositoryImpl) Release(uid, orderNo string) error {
+	return r.base.GetDB().
+		Model(&entity.ShippingRateQuote{}).
+		Where("uid = ? AND order_no = ?", uid, orderNo).
+		UpdateColumns(map[string]interface{}{"order_no": "", "used_at": nil}).Error
+}
+
+// DeleteExpired removes at most limit quotes that expired before the time without being claimed by an order
+func (r *shippingRateQuoteRepositoryImpl) DeleteExpired(before time.Time, limit int) (int64, error) {
+	expired := r.base.GetDB().
+		Model(&entity.ShippingRateQuote{}).
+		Select("id").
+		Where("expired_at < ? AND order_no = ''", before).
+		Limit(limit)
+
+	result := r.base.GetDB().
+		Where("id IN (?)", expired).
+		Delete(&entity.ShippingRateQuote{})
+
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"errors"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ShippingStatusRepository interface {
+	FindByUID(uid string) (*entity.ShippingStatus, error)
+	FindByChannelID(channelID uint64) ([]entity.ShippingStatus, error)
+	FindByCode(channelID uint64, statusCode string) (*entity.ShippingStatus, error)
+	Save(input *entity.ShippingStatus) error
+	Delete(input *entity.ShippingStatus) error
+	CloneChannel(sourceChannelID, targetChannelID uint64, actor string) error
+}
+
+type shippingStatusRepositoryImpl struct {
+	base BaseRepository
+}
+
+func NewShippingStatusRepository(br BaseRepository) ShippingStatusRepository {
+	return &shippingStatusRepositoryImpl{br}
+}
+
+func (r *shippingStatusRepositoryImpl) FindByUID(uid string) (*entity.ShippingStatus, error) {
+	result := &entity.ShippingStatus{}
+	err := r.base.GetDB().
+		Preload("Channel").
+		Where(&entity.ShippingStatus{BaseIDModel: base.BaseIDModel{UID: uid}}).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *shippingStatusRepositoryImpl) FindByChannelID(channelID uint64) ([]entity.ShippingStatus, error) {
+	var result []entity.ShippingStatus
+	err := r.base.GetDB().
+		Where(&entity.ShippingStatus{ChannelID: channelID}).
+		Order("id").
+		Find(&result).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *shippingStatusRepositoryImpl) FindByCode(channelID uint64, statusCode string) (*entity.ShippingStatus, error) {
+	result := &entity.ShippingStatus{}
+	err := r.base.GetDB().
+		Where(&entity.ShippingStatus{ChannelID: channelID, StatusCode: statusCode}).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Save keeps the status code copied on the courier status mappings in sync with the shipping status.
+// A renamed status code is carried over to the status transitions, the order shippings and their history
+// of the channel in the same transaction.
+func (r *shippingStatusRepositoryImpl) Save(input *entity.ShippingStatus) error {
+	return r.base.GetDB().Transaction(func(tx *gorm.DB) error {
+		previous := &entity.ShippingStatus{}
+		if input.ID != 0 {
+			if err := tx.Select("status_code").First(previous, input.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Omit(clause.Associations).Save(input).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entity.ShippingCourierStatus{}).
+			Where(&entity.ShippingCourierStatus{ShippingStatusID: input.ID}).
+			Update("status_code", input.StatusCode).Error; err != nil {
+			return err
+		}
+
+		if previous.StatusCode == "" || previous.StatusCode == input.StatusCode {
+			return nil
+		}
+
+		return renameStatusCode(tx, input, previous.StatusCode)
+	})
+}
+
+func renameStatusCode(tx *gorm.DB, input *entity.ShippingStatus, previousStatusCode string) error {
+	if err := tx.Model(&entity.ShippingStatusTransition{}).
+		Where(&entity.ShippingStatusTransition{ChannelID: input.ChannelID, FromStatusCode: previousStatusCode}).
+		Update("from_status_code", input.StatusCode).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&entity.ShippingStatusTransition{}).
+		Where(&entity.ShippingStatusTransition{ChannelID: input.ChannelID, ToStatusCode: previousStatusCode}).
+		Update("to_status_code", input.StatusCode).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&entity.OrderShipping{}).
+		Where(&entity.OrderShipping{ChannelID: input.ChannelID, Status: previousStatusCode}).
+		Update("status", input.StatusCode).Error; err != nil {
+		return err
+	}
+
+	courierStatusIDs := tx.Model(&entity.ShippingCourierStatus{}).
+		Select("id").
+		Where(&entity.ShippingCourierStatus{ShippingStatusID: input.ID})
+
+	return tx.Model(&entity.OrderShippingHistory{}).
+		Where("shipping_courier_status_id IN (?)", courierStatusIDs).
+		Update("status_code", input.StatusCode).Error
+}
+
+func (r *shippingStatusRepositoryImpl) Delete(input *entity.ShippingStatus) error {
+	return r.base.GetDB().Delete(input).Error
+}
+
+// CloneChannel copies the shipping status, courier status mappings and status transitions of the source channel to the target channel
+func (r *shippingStatusRepositoryImpl) CloneChannel(sourceChannelID, targetChannelID uint64, actor string) error {
+	return r.base.GetDB().Transaction(func(tx *gorm.DB) error {
+		var statuses []entity.ShippingStatus
+		if err := tx.Where(&entity.ShippingStatus{ChannelID: sourceChannelID}).Order("id").Find(&statuses).Error; err != nil {
+			return err
+		}
+
+		for _, v := range statuses {
+			status := &entity.ShippingStatus{
+				ChannelID:   targetChannelID,
+				StatusCode:  v.StatusCode,
+				StatusName:  v.StatusName,
+				Description: v.Description,
+			}
+			status.CreatedBy = actor
+			if err := tx.Omit(clause.Associations).Create(status).Error; err != nil {
+				return err
+			}
+
+			var courierStatuses []entity.ShippingCourierStatus
+			if err := tx.Where(&entity.ShippingCourierStatus{ShippingStatusID: v.ID}).Find(&courierStatuses).Error; err != nil {
+				return err
+			}
+
+			for _, cs := range courierStatuses {
+				courierStatus := &entity.ShippingCourierStatus{
+					ShippingStatusID: status.ID,
+					CourierID:        cs.CourierID,
+					StatusCode:       status.StatusCode,
+					StatusCourier:    cs.StatusCourier,
+				}
+				courierStatus.CreatedBy = actor
+				if err := tx.Omit(clause.Associations).Create(courierStatus).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		var transitions []entity.ShippingStatusTransition
+		if err := tx.Where(&entity.ShippingStatusTransition{ChannelID: sourceChannelID}).Find(&transitions).Error; err != nil {
+			return err
+		}
+
+		for _, v := range transitions {
+			transition := &entity.ShippingStatusTransition{
+				ChannelID:      targetChannelID,
+				FromStatusCode: v.FromStatusCode,
+				ToStatusCode:   v.ToStatusCode,
+			}
+			transition.CreatedBy = actor
+			if err := tx.Omit(clause.Associations).Create(transition).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
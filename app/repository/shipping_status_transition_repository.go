@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"errors"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ShippingStatusTransitionRepository interface {
+	FindByChannelID(channelID uint64) (entity.ShippingStatusTransitions, error)
+	FindByUID(uid string) (*entity.ShippingStatusTransition, error)
+	FindByStatusCodes(channelID uint64, fromStatusCode, toStatusCode string) (*entity.ShippingStatusTransition, error)
+	Save(input *entity.ShippingStatusTransition) error
+	Delete(input *entity.ShippingStatusTransition) error
+}
+
+type shippingStatusTransitionRepositoryImpl struct {
+	base BaseRepository
+}
+
+func NewShippingStatusTransitionRepository(br BaseRepository) ShippingStatusTransitionRepository {
+	return &shippingStatusTransitionRepositoryImpl{br}
+}
+
+func (r *shippingStatusTransitionRepositoryImpl) FindByChannelID(channelID uint64) (entity.ShippingStatusTransitions, error) {
+	var result entity.ShippingStatusTransitions
+	err := r.base.GetDB().
+		Where(&entity.ShippingStatusTransition{ChannelID: channelID}).
+		Order("id").
+		Find(&result).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *shippingStatusTransitionRepositoryImpl) FindByUID(uid string) (*entity.ShippingStatusTransition, error) {
+	result := &entity.ShippingStatusTransition{}
+	err := r.base.GetDB().
+		Preload("Channel").
+		Where(&entity.ShippingStatusTransition{BaseIDModel: base.BaseIDModel{UID: uid}}).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *shippingStatusTransitionRepositoryImpl) FindByStatusCodes(channelID uint64, fromStatusCode, toStatusCode string) (*entity.ShippingStatusTransition, error) {
+	result := &entity.ShippingStatusTransition{}
+	err := r.base.GetDB().
+		Where(&entity.ShippingStatusTransition{ChannelID: channelID, FromStatusCode: fromStatusCode, ToStatusCode: toStatusCode}).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *shippingStatusTransitionRepositoryImpl) Save(input *entity.ShippingStatusTransition) error {
+	return r.base.GetDB().Omit(clause.Associations).Save(input).Error
+}
+
+func (r *shippingStatusTransitionRepositoryImpl) Delete(input *entity.ShippingStatusTransition) error {
+	return r.base.GetDB().Delete(input).Error
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"errors"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/pkg/util"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UnmappedCourierStatusRepository interface {
+	FindByCourierStatus(courierID uint64, statusCode string) (*entity.UnmappedCourierStatus, error)
+	FindByUID(uid string) (*entity.UnmappedCourierStatus, error)
+	Save(input *entity.UnmappedCourierStatus) error
+	FindByParams(limit, page int, sort string, filter map[string]interface{}) ([]response.GetUnmappedCourierStatusList, *base.Pagination, error)
+}
+
+type unmappedCourierStatusRepository struct {
+	base BaseRepository
+}
+
+func NewUnmappedCourierStatusRepository(br BaseRepository) UnmappedCourierStatusRepository {
+	return &unmappedCourierStatusRepository{br}
+}
+
+func (r *unmappedCourierStatusRepository) FindByCourierStatus(courierID uint64, statusCode string) (*entity.UnmappedCourierStatus, error) {
+	result := &entity.UnmappedCourierStatus{}
+	err := r.base.GetDB().
+		Where(&entity.UnmappedCourierStatus{CourierID: courierID, StatusCode: statusCode}).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *unmappedCourierStatusRepository) FindByUID(uid string) (*entity.UnmappedCourierStatus, error) {
+	result := &entity.UnmappedCourierStatus{}
+	err := r.base.GetDB().
+		Preload("Courier").
+		Where(&entity.UnmappedCourierStatus{BaseIDModel: base.BaseIDModel{UID: uid}}).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *unmappedCourierStatusRepository) Save(input *entity.UnmappedCourierStatus) error {
+	return r.base.GetDB().Omit(clause.Associations).Save(input).Error
+}
+
+func (r *unmappedCourierStatusRepository) FindByParams(limit, page int, sort string, filter map[string]interface{}) ([]response.GetUnmappedCourierStatusList, *base.Pagination, error) {
+	pagination := &base.Pagination{}
+
+	var result []response.GetUnmappedCourierStatusList
+
+	query := r.base.GetDB().
+		Model(&entity.UnmappedCourierStatus{}).
+		Select(
+			"unmapped_courier_status.uid AS uid",
+			"c.code AS courier_code",
+			"c.courier_name AS courier_name",
+			"unmapped_courier_status.status_code AS status_code",
+			"unmapped_courier_status.status_name AS status_name",
+			"unmapped_courier_status.occurrence_count AS occurrence_count",
+			"unmapped_courier_status.sample_payloads AS sample_payloads",
+			"unmapped_courier_status.first_seen_at AS first_seen_at",
+			"unmapped_courier_status.last_seen_at AS last_seen_at",
+			"unmapped_courier_status.status AS status",
+			"ss.status_code AS shipping_status_code",
+			"unmapped_courier_status.mapped_at AS mapped_at",
+		).
+		Joins("INNER JOIN courier c ON c.id = unmapped_courier_status.courier_id").
+		Joins("LEFT JOIN shipping_courier_status scs ON scs.id = unmapped_courier_status.shipping_courier_status_id").
+		Joins("LEFT JOIN shipping_status ss ON ss.id = scs.shipping_status_id")
+
+	for k, v := range filter {
+
+		if !util.IsNilOrEmpty(v) {
+
+			switch k {
+			case "courier_code":
+				query = query.Where("c.code IN ?", v.([]string))
+
+			case "status_code":
+				query = query.Where("unmapped_courier_status.status_code IN ?", v.([]string))
+
+			case "status":
+				query = query.Where("unmapped_courier_status.status IN ?", v.([]string))
+
+			}
+		}
+	}
+
+	sort = strings.ReplaceAll(sort, "courier_code", "c.code")
+	sort = util.ReplaceEmptyString(sort, "unmapped_courier_status.last_seen_at desc")
+
+	query = query.Order(sort)
+
+	pagination.Limit = limit
+	pagination.Page = page
+	err := query.Scopes(r.base.Paginate(&entity.UnmappedCourierStatus{}, pagination, query, int64(len(result)))).
+		Find(&result).
+		Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	return result, pagination, nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"errors"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/pkg/util"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrWebhookEventTaken is returned when a concurrent request has stored the event first
+var ErrWebhookEventTaken = errors.New("webhook event is already stored")
+
+type WebhookLogRepository interface {
+	FindByEventID(provider, eventID string) (*entity.WebhookLog, error)
+	FindByUID(uid string) (*entity.WebhookLog, error)
+	FindHeldByStatusCode(provider, statusCode string, channelID uint64) ([]entity.WebhookLog, error)
+	CountHeldByStatusCode(provider, statusCode string) (int64, error)
+	Create(input *entity.WebhookLog) error
+	Save(input *entity.WebhookLog) error
+	FindByParams(limit, page int, sort string, filter map[string]interface{}) ([]response.WebhookLog, *base.Pagination, error)
+}
+
+type webhookLogRepository struct {
+	base BaseRepository
+}
+
+func NewWebhookLogRepository(br BaseRepository) WebhookLogRepository {
+	return &webhookLogRepository{br}
+}
+
+// FindByEventID only looks at authorized webhooks, unauthorized requests are never deduplicated
+func (r *webhookLogRepository) FindByEventID(provider, eventID string) (*entity.WebhookLog, error) {
+	result := &entity.WebhookLog{}
+	err := r.base.GetDB().
+		Where("provider = ? AND event_id = ? AND authorized", provider, eventID).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *webhookLogRepository) FindByUID(uid string) (*entity.WebhookLog, error) {
+	result := &entity.WebhookLog{}
+	err := r.base.GetDB().
+		Where(&entity.WebhookLog{BaseIDModel: base.BaseIDModel{UID: uid}}).
+		First(result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindHeldByStatusCode returns the authorized webhooks of the orders of the channel
+// that failed because the courier status is not mapped
+func (r *webhookLogRepository) FindHeldByStatusCode(provider, statusCode string, channelID uint64) ([]entity.WebhookLog, error) {
+	var result []entity.WebhookLog
+	err := r.held(provider, statusCode).
+		Where("order_no IN (SELECT order_no FROM order_shipping WHERE channel_id = ?)", channelID).
+		Order("id").
+		Find(&result).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CountHeldByStatusCode counts the held webhooks of the status code across all channels
+func (r *webhookLogRepository) CountHeldByStatusCode(provider, statusCode string) (int64, error) {
+	var count int64
+	err := r.held(provider, statusCode).
+		Model(&entity.WebhookLog{}).
+		Count(&count).Error
+
+	return count, err
+}
+
+func (r *webhookLogRepository) held(provider, statusCode string) *gorm.DB {
+	return r.base.GetDB().
+		Where("provider = ? AND status_code = ? AND authorized AND held", provider, statusCode).
+		Where(&entity.WebhookLog{Status: entity.WebhookStatusFailed})
+}
+
+// Create returns ErrWebhookEventTaken when the authorized event has been stored by a concurrent request
+func (r *webhookLogRepository) Create(input *entity.WebhookLog) error {
+	result := r.base.GetDB().Clauses(clause.OnConflict{DoNothing: true}).Create(input)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrWebhookEventTaken
+	}
+
+	return nil
+}
+
+func (r *webhookLogRepository) Save(input *entity.WebhookLog) error {
+	return r.base.GetDB().Save(input).Error
+}
+
+func (r *webhookLogRepository) FindByParams(limit, page int, sort string, filter map[string]interface{}) ([]response.WebhookLog, *base.Pagination, error) {
+	pagination := &base.Pagination{}
+
+	var result []response.WebhookLog
+
+	query := r.base.GetDB().
+		Model(&entity.WebhookLog{}).
+		Select(
+			"uid",
+			"provider",
+			"event_id",
+			"order_no",
+			"status_code",
+			"headers",
+			"body",
+			"authorized",
+			"status",
+			"error_code",
+			"error_message",
+			"attempts",
+			"duplicate_count",
+			"processed_at",
+			"created_at",
+			"updated_by",
+		)
+
+	for k, v := range filter {
+
+		if !util.IsNilOrEmpty(v) {
+
+			switch k {
+			case "provider":
+				query = query.Where("provider IN ?", v.([]string))
+
+			case "status":
+				query = query.Where("status IN ?", v.([]string))
+
+			case "order_no":
+				query = query.Where(like("order_no", v.([]string)))
+
+			case "event_id":
+				query = query.Where("event_id IN ?", v.([]string))
+
+			}
+		}
+	}
+
+	sort = strings.TrimSpace(sort)
+	sort = util.ReplaceEmptyString(sort, "id desc")
+
+	query = query.Order(sort)
+
+	pagination.Limit = limit
+	pagination.Page = page
+	err := query.Scopes(r.base.Paginate(&entity.WebhookLog{}, pagination, query, int64(len(result)))).
+		Find(&result).
+		Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	return result, pagination, nil
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/app/repository"
+	"go-klikdokter/helper/http_helper/shipping_provider"
+	"go-klikdokter/helper/message"
+	"go-klikdokter/pkg/util"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/spf13/viper"
+)
+
+type ChannelCourierCredentialService interface {
+	GetChannelCourierCredential(uid string) (*response.ChannelCourierCredential, message.Message)
+	SaveChannelCourierCredential(req *request.SaveChannelCourierCredential) (*response.ChannelCourierCredential, message.Message)
+	DeleteChannelCourierCredential(uid string) message.Message
+}
+
+type channelCourierCredentialServiceImpl struct {
+	logger             log.Logger
+	baseRepo           repository.BaseRepository
+	channelCourierRepo repository.ChannelCourierRepository
+	credentialRepo     repository.ChannelCourierCredentialRepository
+}
+
+func NewChannelCourierCredentialService(
+	l log.Logger,
+	br repository.BaseRepository,
+	ccr repository.ChannelCourierRepository,
+	cr repository.ChannelCourierCredentialRepository,
+) ChannelCourierCredentialService {
+	return &channelCourierCredentialServiceImpl{l, br, ccr, cr}
+}
+
+// the secrets of the provider credentials are encrypted with AES, the key must be 16, 24 or 32 bytes
+func credentialEncryptionKey() string {
+	return viper.GetString("setting.credential-encryption-key")
+}
+
+// swagger:operation GET /channel/channel-courier/{uid}/credential Channel-Courier-Service GetChannelCourierCredential
+// Get Provider Credential of Channel Courier
+//
+// Description :
+// The account of the channel at the provider of the courier, the secret is not returned
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/ChannelCourierCredentialResponse'
+func (s *channelCourierCredentialServiceImpl) GetChannelCourierCredential(uid string) (*response.ChannelCourierCredential, message.Message) {
+	logger := log.With(s.logger, "ChannelCourierCredentialService", "GetChannelCourierCredential")
+
+	channelCourier, msg := s.findChannelCourier(logger, uid)
+	if msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	credential, err := s.credentialRepo.FindByChannelCourierID(channelCourier.ID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.credentialRepo.FindByChannelCourierID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if credential == nil {
+		return nil, message.ErrProviderCredentialNotFound
+	}
+
+	return response.NewChannelCourierCredential(credential, channelCourier), message.SuccessMsg
+}
+
+// swagger:operation PUT /channel/channel-courier/{uid}/credential Channel-Courier-Service SaveChannelCourierCredential
+// Save Provider Credential of Channel Courier
+//
+// Description :
+// The provider calls of the channel are made under this account instead of the account of the service, the secret is stored encrypted
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/ChannelCourierCredentialResponse'
+func (s *channelCourierCredentialServiceImpl) SaveChannelCourierCredential(req *request.SaveChannelCourierCredential) (*response.ChannelCourierCredential, message.Message) {
+	logger := log.With(s.logger, "ChannelCourierCredentialService", "SaveChannelCourierCredential")
+
+	clientID := strings.TrimSpace(req.Body.ClientID)
+	if len(req.Body.Secret) == 0 {
+		return nil, message.ErrProviderSecretRequired
+	}
+
+	channelCourier, msg := s.findChannelCourier(logger, req.UID)
+	if msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	courier := channelCourier.Courier
+	if courier == nil || (courier.CourierType != shipping_provider.ThirPartyCourier && courier.CourierType != shipping_provider.AggregatorCourier) {
+		return nil, message.ErrProviderCredentialNotSupported
+	}
+
+	if courier.Code == shipping_provider.GrabCode && clientID == "" {
+		return nil, message.ErrProviderClientIDRequired
+	}
+
+	secret, err := util.AESEncryption(req.Body.Secret, credentialEncryptionKey())
+	if err != nil {
+		_ = level.Error(logger).Log("util.AESEncryption", err.Error())
+		return nil, message.ErrSaveData
+	}
+
+	credential, err := s.credentialRepo.FindByChannelCourierID(channelCourier.ID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.credentialRepo.FindByChannelCourierID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if credential == nil {
+		credential = &entity.ChannelCourierCredential{ChannelCourierID: channelCourier.ID}
+		credential.CreatedBy = req.ActorName
+	} else {
+		credential.UpdatedBy = req.ActorName
+	}
+
+	credential.ClientID = clientID
+	credential.Secret = secret
+
+	if err := s.credentialRepo.Save(credential); err != nil {
+		_ = level.Error(logger).Log("s.credentialRepo.Save", err.Error())
+		return nil, message.ErrSaveData
+	}
+
+	return response.NewChannelCourierCredential(credential, channelCourier), message.SuccessMsg
+}
+
+// swagger:operation DELETE /channel/channel-courier/{uid}/credential Channel-Courier-Service DeleteChannelCourierCredential
+// Delete Provider Credential of Channel Courier
+//
+// Description :
+// The provider calls of the channel are made under the account of the service again
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           type: object
+func (s *channelCourierCredentialServiceImpl) DeleteChannelCourierCredential(uid string) message.Message {
+	logger := log.With(s.logger, "ChannelCourierCredentialService", "DeleteChannelCourierCredential")
+
+	channelCourier, msg := s.findChannelCourier(logger, uid)
+	if msg != message.SuccessMsg {
+		return msg
+	}
+
+	credential, err := s.credentialRepo.FindByChannelCourierID(channelCourier.ID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.credentialRepo.FindByChannelCourierID", err.Error())
+		return message.ErrDB
+	}
+
+	if credential == nil {
+		return message.ErrProviderCredentialNotFound
+	}
+
+	if err := s.credentialRepo.Delete(credential); err != nil {
+		_ = level.Error(logger).Log("s.credentialRepo.Delete", err.Error())
+		return message.FailedMsg
+	}
+
+	return message.SuccessMsg
+}
+
+func (s *channelCourierCredentialServiceImpl) findChannelCourier(logger log.Logger, uid string) (*entity.ChannelCourier, message.Message) {
+	channelCourier, err := s.channelCourierRepo.GetChannelCourierByUID(uid)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelCourierRepo.GetChannelCourierByUID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if channelCourier == nil {
+		return nil, message.ErrChannelCourierNotFound
+	}
+
+	return channelCourier, message.SuccessMsg
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"errors"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/app/repository"
+	"go-klikdokter/helper/message"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"gorm.io/gorm"
+)
+
+type ChannelPriceRuleService interface {
+	ListChannelPriceRule(channelUID string) ([]response.ChannelPriceRule, message.Message)
+	CreateChannelPriceRule(req *request.SaveChannelPriceRule) (*response.ChannelPriceRule, message.Message)
+	UpdateChannelPriceRule(req *request.UpdateChannelPriceRule) (*response.ChannelPriceRule, message.Message)
+	DeleteChannelPriceRule(uid string) message.Message
+}
+
+type channelPriceRuleServiceImpl struct {
+	logger               log.Logger
+	baseRepo             repository.BaseRepository
+	channelRepo          repository.ChannelRepository
+	courierRepo          repository.CourierRepository
+	courierServiceRepo   repository.CourierServiceRepository
+	channelPriceRuleRepo repository.ChannelPriceRuleRepository
+}
+
+func NewChannelPriceRuleService(
+	l log.Logger,
+	br repository.BaseRepository,
+	chr repository.ChannelRepository,
+	cr repository.CourierRepository,
+	csr repository.CourierServiceRepository,
+	cprr repository.ChannelPriceRuleRepository,
+) ChannelPriceRuleService {
+	return &channelPriceRuleServiceImpl{l, br, chr, cr, csr, cprr}
+}
+
+// swagger:operation GET /channel/channel-app/{uid}/price-rule Channel-Apps GetChannelPriceRule
+// List Channel Price Rule
+//
+// Description :
+// Price rules of the channel in the order they are applied
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             records:
+//               type: array
+//               items:
+//                 $ref: '#/definitions/ChannelPriceRuleResponse'
+func (s *channelPriceRuleServiceImpl) ListChannelPriceRule(channelUID string) ([]response.ChannelPriceRule, message.Message) {
+	logger := log.With(s.logger, "ChannelPriceRuleService", "ListChannelPriceRule")
+
+	channel, err := s.channelRepo.FindByUid(&channelUID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelRepo.FindByUid", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if channel == nil {
+		return nil, message.ErrChannelNotFound
+	}
+
+	rules, err := s.channelPriceRuleRepo.FindByChannelID(channel.ID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelPriceRuleRepo.FindByChannelID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	result := []response.ChannelPriceRule{}
+	for i := range rules {
+		result = append(result, *response.NewChannelPriceRule(&rules[i], channel.UID))
+	}
+
+	return result, message.SuccessMsg
+}
+
+// swagger:operation POST /channel/price-rule Channel-Apps SaveChannelPriceRule
+// Add Channel Price Rule
+//
+// Description :
+// Adjusts the provider price of the shipping rates of the channel
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/ChannelPriceRuleResponse'
+func (s *channelPriceRuleServiceImpl) CreateChannelPriceRule(req *request.SaveChannelPriceRule) (*response.ChannelPriceRule, message.Message) {
+	logger := log.With(s.logger, "ChannelPriceRuleService", "CreateChannelPriceRule")
+
+	if req.Body.ChannelUID == "" {
+		return nil, message.ErrChannelUIDRequired
+	}
+
+	channel, err := s.channelRepo.FindByUid(&req.Body.ChannelUID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelRepo.FindByUid", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if channel == nil {
+		return nil, message.ErrChannelNotFound
+	}
+
+	rule := &entity.ChannelPriceRule{ChannelID: channel.ID}
+	rule.CreatedBy = req.ActorName
+
+	if msg := s.setChannelPriceRule(logger, rule, &req.Body.ChannelPriceRuleBody); msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	if err := s.channelPriceRuleRepo.Save(rule); err != nil {
+		_ = level.Error(logger).Log("s.channelPriceRuleRepo.Save", err.Error())
+		return nil, message.ErrSaveData
+	}
+
+	return response.NewChannelPriceRule(rule, channel.UID), message.SuccessMsg
+}
+
+// swagger:operation PUT /channel/price-rule/{uid} Channel-Apps UpdateChannelPriceRule
+// Update Channel Price Rule
+//
+// Description :
+//
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/ChannelPriceRuleResponse'
+func (s *channelPriceRuleServiceImpl) UpdateChannelPriceRule(req *request.UpdateChannelPriceRule) (*response.ChannelPriceRule, message.Message) {
+	logger := log.With(s.logger, "ChannelPriceRuleService", "UpdateChannelPriceRule")
+
+	rule, err := s.channelPriceRuleRepo.FindByUID(req.UID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelPriceRuleRepo.FindByUID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if rule == nil {
+		return nil, message.ErrPriceRuleNotFound
+	}
+
+	rule.UpdatedBy = req.ActorName
+	if msg := s.setChannelPriceRule(logger, rule, &req.Body); msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	if err := s.channelPriceRuleRepo.Save(rule); err != nil {
+		_ = level.Error(logger).Log("s.channelPriceRuleRepo.Save", err.Error())
+		return nil, message.ErrSaveData
+	}
+
+	var channelUID string
+	if rule.Channel != nil {
+		channelUID = rule.Channel.UID
+	}
+
+	return response.NewChannelPriceRule(rule, channelUID), message.SuccessMsg
+}
+
+// swagger:operation DELETE /channel/price-rule/{uid} Channel-Apps DeleteChannelPriceRule
+// Delete Channel Price Rule
+//
+// Description :
+//
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           type: object
+func (s *channelPriceRuleServiceImpl) DeleteChannelPriceRule(uid string) message.Message {
+	logger := log.With(s.logger, "ChannelPriceRuleService", "DeleteChannelPriceRule")
+
+	rule, err := s.channelPriceRuleRepo.FindByUID(uid)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelPriceRuleRepo.FindByUID", err.Error())
+		return message.ErrDB
+	}
+
+	if rule == nil {
+		return message.ErrPriceRuleNotFound
+	}
+
+	if err := s.channelPriceRuleRepo.Delete(rule); err != nil {
+		_ = level.Error(logger).Log("s.channelPriceRuleRepo.Delete", err.Error())
+		return message.FailedMsg
+	}
+
+	return message.SuccessMsg
+}
+
+// setChannelPriceRule copies the body to the rule, the courier service has to belong to the courier of the rule
+func (s *channelPriceRuleServiceImpl) setChannelPriceRule(logger log.Logger, rule *entity.ChannelPriceRule, body *request.ChannelPriceRuleBody) message.Message {
+	rule.Name = body.Name
+	rule.ShippingType = body.ShippingType
+	rule.PostalCodePrefix = body.PostalCodePrefix
+	rule.MinWeight = body.MinWeight
+	rule.MaxWeight = body.MaxWeight
+	rule.AdjustmentType = body.AdjustmentType
+	rule.AdjustmentValue = body.AdjustmentValue
+	rule.RoundTo = body.RoundTo
+	rule.Rounding = body.Rounding
+	rule.Priority = body.Priority
+	if body.Status != nil {
+		rule.Status = body.Status
+	}
+
+	if msg := rule.Validate(); msg != message.SuccessMsg {
+		return msg
+	}
+
+	rule.Courier = nil
+	rule.CourierID = nil
+	if body.CourierUID != "" {
+		courier, err := s.courierRepo.FindByUid(&body.CourierUID)
+		if err != nil {
+			_ = level.Error(logger).Log("s.courierRepo.FindByUid", err.Error())
+			return message.ErrDB
+		}
+
+		if courier == nil {
+			return message.ErrCourierNotFound
+		}
+
+		rule.Courier = courier
+		rule.CourierID = &courier.ID
+	}
+
+	rule.CourierService = nil
+	rule.CourierServiceID = nil
+	if body.CourierServiceUID != "" {
+		courierService, err := s.courierServiceRepo.FindByUid(&body.CourierServiceUID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return message.ErrNoDataCourierService
+			}
+			_ = level.Error(logger).Log("s.courierServiceRepo.FindByUid", err.Error())
+			return message.ErrDB
+		}
+
+		if courierService == nil || (rule.CourierID != nil && *rule.CourierID != courierService.CourierID) {
+			return message.ErrNoDataCourierService
+		}
+
+		rule.CourierService = courierService
+		rule.CourierServiceID = &courierService.ID
+	}
+
+	return message.SuccessMsg
+}
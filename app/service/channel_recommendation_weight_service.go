@@ -0,0 +1,142 @@
+package service
+
+import (
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/app/repository"
+	"go-klikdokter/helper/message"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+type ChannelRecommendationWeightService interface {
+	GetChannelRecommendationWeight(channelUID string) (*response.ChannelRecommendationWeight, message.Message)
+	SaveChannelRecommendationWeight(req *request.SaveChannelRecommendationWeight) (*response.ChannelRecommendationWeight, message.Message)
+}
+
+type channelRecommendationWeightServiceImpl struct {
+	logger                   log.Logger
+	baseRepo                 repository.BaseRepository
+	channelRepo              repository.ChannelRepository
+	recommendationWeightRepo repository.ChannelRecommendationWeightRepository
+}
+
+func NewChannelRecommendationWeightService(
+	l log.Logger,
+	br repository.BaseRepository,
+	chr repository.ChannelRepository,
+	crwr repository.ChannelRecommendationWeightRepository,
+) ChannelRecommendationWeightService {
+	return &channelRecommendationWeightServiceImpl{l, br, chr, crwr}
+}
+
+// swagger:operation GET /channel/channel-app/{uid}/recommendation-weight Channel-Apps GetChannelRecommendationWeight
+// Get Channel Recommendation Weight
+//
+// Description :
+// Weights of the recommended score of the shipping rates of the channel, the default weights are returned when the channel has none
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/ChannelRecommendationWeightResponse'
+func (s *channelRecommendationWeightServiceImpl) GetChannelRecommendationWeight(channelUID string) (*response.ChannelRecommendationWeight, message.Message) {
+	logger := log.With(s.logger, "ChannelRecommendationWeightService", "GetChannelRecommendationWeight")
+
+	channel, err := s.channelRepo.FindByUid(&channelUID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelRepo.FindByUid", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if channel == nil {
+		return nil, message.ErrChannelNotFound
+	}
+
+	weight, err := s.recommendationWeightRepo.FindByChannelID(channel.ID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.recommendationWeightRepo.FindByChannelID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if weight == nil {
+		weight = defaultRecommendationWeight()
+	}
+
+	return response.NewChannelRecommendationWeight(weight, channel.UID), message.SuccessMsg
+}
+
+// swagger:operation PUT /channel/channel-app/{uid}/recommendation-weight Channel-Apps SaveChannelRecommendationWeight
+// Save Channel Recommendation Weight
+//
+// Description :
+// Weighs price, etd, channel priority and courier reliability in the recommended score of the shipping rates of the channel
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/ChannelRecommendationWeightResponse'
+func (s *channelRecommendationWeightServiceImpl) SaveChannelRecommendationWeight(req *request.SaveChannelRecommendationWeight) (*response.ChannelRecommendationWeight, message.Message) {
+	logger := log.With(s.logger, "ChannelRecommendationWeightService", "SaveChannelRecommendationWeight")
+
+	channel, err := s.channelRepo.FindByUid(&req.UID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelRepo.FindByUid", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if channel == nil {
+		return nil, message.ErrChannelNotFound
+	}
+
+	weight, err := s.recommendationWeightRepo.FindByChannelID(channel.ID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.recommendationWeightRepo.FindByChannelID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if weight == nil {
+		weight = &entity.ChannelRecommendationWeight{ChannelID: channel.ID}
+		weight.CreatedBy = req.ActorName
+	} else {
+		weight.UpdatedBy = req.ActorName
+	}
+
+	weight.PriceWeight = req.Body.PriceWeight
+	weight.EtdWeight = req.Body.EtdWeight
+	weight.PriorityWeight = req.Body.PriorityWeight
+	weight.ReliabilityWeight = req.Body.ReliabilityWeight
+
+	if msg := weight.Validate(); msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	if err := s.recommendationWeightRepo.Save(weight); err != nil {
+		_ = level.Error(logger).Log("s.recommendationWeightRepo.Save", err.Error())
+		return nil, message.ErrSaveData
+	}
+
+	return response.NewChannelRecommendationWeight(weight, channel.UID), message.SuccessMsg
+}
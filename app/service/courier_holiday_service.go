@@ -0,0 +1,197 @@
+package service
+
+import (
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/app/repository"
+	"go-klikdokter/helper/message"
+	"go-klikdokter/pkg/util"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+type CourierHolidayService interface {
+	ListCourierHoliday(req *request.ListCourierHoliday) ([]response.CourierHoliday, message.Message)
+	CreateCourierHoliday(req *request.SaveCourierHoliday) (*response.CourierHoliday, message.Message)
+	DeleteCourierHoliday(uid string) message.Message
+}
+
+type courierHolidayServiceImpl struct {
+	logger             log.Logger
+	baseRepo           repository.BaseRepository
+	courierRepo        repository.CourierRepository
+	courierHolidayRepo repository.CourierHolidayRepository
+}
+
+func NewCourierHolidayService(
+	l log.Logger,
+	br repository.BaseRepository,
+	cr repository.CourierRepository,
+	chr repository.CourierHolidayRepository,
+) CourierHolidayService {
+	return &courierHolidayServiceImpl{l, br, cr, chr}
+}
+
+// swagger:operation GET /courier/courier-holiday Couriers ListCourierHoliday
+// List Courier Holiday
+//
+// Description :
+// Holidays of the year the courier does not pick up or deliver, used to estimate delivery dates
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             records:
+//               type: array
+//               items:
+//                 $ref: '#/definitions/CourierHolidayResponse'
+func (s *courierHolidayServiceImpl) ListCourierHoliday(req *request.ListCourierHoliday) ([]response.CourierHoliday, message.Message) {
+	logger := log.With(s.logger, "CourierHolidayService", "ListCourierHoliday")
+
+	var courierID *uint64
+	if req.CourierUID != "" {
+		courier, err := s.courierRepo.FindByUid(&req.CourierUID)
+		if err != nil {
+			_ = level.Error(logger).Log("s.courierRepo.FindByUid", err.Error())
+			return nil, message.ErrDB
+		}
+
+		if courier == nil {
+			return nil, message.ErrCourierNotFound
+		}
+		courierID = &courier.ID
+	}
+
+	year := req.Year
+	if year == 0 {
+		year = time.Now().In(util.Loc).Year()
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	holidays, err := s.courierHolidayRepo.FindByCourierID(courierID, from, from.AddDate(1, 0, -1))
+	if err != nil {
+		_ = level.Error(logger).Log("s.courierHolidayRepo.FindByCourierID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	result := []response.CourierHoliday{}
+	for i := range holidays {
+		result = append(result, *response.NewCourierHoliday(&holidays[i]))
+	}
+
+	return result, message.SuccessMsg
+}
+
+// swagger:operation POST /courier/courier-holiday Couriers SaveCourierHoliday
+// Add Courier Holiday
+//
+// Description :
+// The holiday applies to every courier when the courier is empty
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/CourierHolidayResponse'
+func (s *courierHolidayServiceImpl) CreateCourierHoliday(req *request.SaveCourierHoliday) (*response.CourierHoliday, message.Message) {
+	logger := log.With(s.logger, "CourierHolidayService", "CreateCourierHoliday")
+
+	// the date is kept as is, it is compared with the date in the timezone of the merchant
+	date, err := time.Parse(util.LayoutDateOnly, req.Body.Date)
+	if err != nil {
+		return nil, message.ErrInvalidHolidayDate
+	}
+
+	holiday := &entity.CourierHoliday{
+		HolidayDate: date,
+		Name:        strings.TrimSpace(req.Body.Name),
+	}
+	holiday.CreatedBy = req.ActorName
+
+	if holiday.Name == "" {
+		return nil, message.ErrHolidayNameRequired
+	}
+
+	if req.Body.CourierUID != "" {
+		courier, err := s.courierRepo.FindByUid(&req.Body.CourierUID)
+		if err != nil {
+			_ = level.Error(logger).Log("s.courierRepo.FindByUid", err.Error())
+			return nil, message.ErrDB
+		}
+
+		if courier == nil {
+			return nil, message.ErrCourierNotFound
+		}
+
+		holiday.Courier = courier
+		holiday.CourierID = &courier.ID
+	}
+
+	if err := s.courierHolidayRepo.Save(holiday); err != nil {
+		_ = level.Error(logger).Log("s.courierHolidayRepo.Save", err.Error())
+		return nil, message.ErrSaveData
+	}
+
+	return response.NewCourierHoliday(holiday), message.SuccessMsg
+}
+
+// swagger:operation DELETE /courier/courier-holiday/{uid} Couriers DeleteCourierHoliday
+// Delete Courier Holiday
+//
+// Description :
+//
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           type: object
+func (s *courierHolidayServiceImpl) DeleteCourierHoliday(uid string) message.Message {
+	logger := log.With(s.logger, "CourierHolidayService", "DeleteCourierHoliday")
+
+	holiday, err := s.courierHolidayRepo.FindByUID(uid)
+	if err != nil {
+		_ = level.Error(logger).Log("s.courierHolidayRepo.FindByUID", err.Error())
+		return message.ErrDB
+	}
+
+	if holiday == nil {
+		return message.ErrCourierHolidayNotFound
+	}
+
+	if err := s.courierHolidayRepo.Delete(holiday); err != nil {
+		_ = level.Error(logger).Log("s.courierHolidayRepo.Delete", err.Error())
+		return message.FailedMsg
+	}
+
+	return message.SuccessMsg
+}
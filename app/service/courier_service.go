@@ -327,6 +327,9 @@ func (s *courierServiceImpl) DeleteCourier(uid string) message.Message {
 //               $ref: '#/definitions/CourierService'
 func (s *courierServiceImpl) CreateCourierService(input request.SaveCourierServiceRequest) (*entity.CourierService, message.Message) {
 	logger := log.With(s.logger, "CourierServiceService", "CreateCourierService")
+	if !entity.IsValidFeeType(input.CodFeeType) || !entity.IsValidFeeType(input.InsuranceFeeType) {
+		return nil, message.ErrInvalidFeeType
+	}
 	//Check exist courier_uid update
 	courier, err := s.courierRepo.FindByUid(&input.CourierUId)
 	if err != nil {
@@ -364,6 +367,7 @@ func (s *courierServiceImpl) CreateCourierService(input request.SaveCourierServi
 		Logo:                input.Logo,
 		CodAvailable:        input.CodAvailable,
 		PrescriptionAllowed: input.PrescriptionAllowed,
+		InsuranceMandatory:  input.InsuranceMandatory,
 		Cancelable:          input.Cancelable,
 		TrackingAvailable:   input.TrackingAvailable,
 		Status:              &defaultStatus, //Default
@@ -378,6 +382,9 @@ func (s *courierServiceImpl) CreateCourierService(input request.SaveCourierServi
 		InsuranceMin:     input.InsuranceMin,
 		InsuranceFeeType: input.InsuranceFeeType,
 		InsuranceFee:     input.InsuranceFee,
+		CodFeeType:       input.CodFeeType,
+		CodFee:           input.CodFee,
+		CodFeeMin:        input.CodFeeMin,
 		StartTime:        input.StartTime,
 		EndTime:          input.EndTime,
 		Repickup:         input.Repickup,
@@ -515,6 +522,9 @@ func (s *courierServiceImpl) GetListCourierService(input request.CourierServiceL
 //               $ref: '#/definitions/CourierService'
 func (s *courierServiceImpl) UpdateCourierService(uid string, input request.UpdateCourierServiceRequest) (*entity.CourierService, message.Message) {
 	logger := log.With(s.logger, "CourierServiceService", "UpdateCourierService")
+	if !entity.IsValidFeeType(input.CodFeeType) || !entity.IsValidFeeType(input.InsuranceFeeType) {
+		return nil, message.ErrInvalidFeeType
+	}
 	//Check exist courierServiceUId
 	courierService, err := s.courierServiceRepo.FindByUid(&uid)
 	if err != nil {
@@ -571,9 +581,13 @@ func (s *courierServiceImpl) UpdateCourierService(uid string, input request.Upda
 		"min_purchase":         input.MinPurchase,
 		"max_purchase":         input.MaxPurchase,
 		"insurance":            input.Insurance,
+		"insurance_mandatory":  input.InsuranceMandatory,
 		"insurance_min":        input.InsuranceMin,
 		"insurance_fee_type":   input.InsuranceFeeType,
 		"insurance_fee":        input.InsuranceFee,
+		"cod_fee_type":         input.CodFeeType,
+		"cod_fee":              input.CodFee,
+		"cod_fee_min":          input.CodFeeMin,
 		"start_time":           input.StartTime,
 		"end_time":             input.EndTime,
 		"repickup":             input.Repickup,
@@ -718,9 +732,13 @@ func ToCourierServiceDetailDTO(cs *entity.CourierService) *entity.CourierService
 		MinPurchase:         cs.MinPurchase,
 		MaxPurchase:         cs.MaxPurchase,
 		Insurance:           cs.Insurance,
+		InsuranceMandatory:  cs.InsuranceMandatory,
 		InsuranceMin:        cs.InsuranceMin,
 		InsuranceFeeType:    cs.InsuranceFeeType,
 		InsuranceFee:        cs.InsuranceFee,
+		CodFeeType:          cs.CodFeeType,
+		CodFee:              cs.CodFee,
+		CodFeeMin:           cs.CodFeeMin,
 		StartTime:           cs.StartTime,
 		EndTime:             cs.EndTime,
 		Repickup:            cs.Repickup,
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/app/repository"
+	"go-klikdokter/helper/http_helper"
+	"go-klikdokter/helper/message"
+	"go-klikdokter/pkg/util"
+	"math"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultOutboxRelayInterval = 5 * time.Second
+	defaultOutboxBatchSize     = 100
+	defaultOutboxMaxAttempts   = 10
+	defaultOutboxBackoff       = 10 * time.Second
+	defaultOutboxMaxBackoff    = time.Hour
+	defaultOutboxLease         = time.Minute
+)
+
+type OrderShippingOutboxService interface {
+	RelayOutbox(ctx context.Context) int
+	GetOrderShippingOutboxList(req *request.GetOrderShippingOutboxList) ([]response.GetOrderShippingOutboxList, *base.Pagination, message.Message)
+}
+
+type orderShippingOutboxServiceImpl struct {
+	logger       log.Logger
+	baseRepo     repository.BaseRepository
+	outboxRepo   repository.OrderShippingOutboxRepository
+	daprEndpoint http_helper.DaprEndpoint
+}
+
+func NewOrderShippingOutboxService(
+	l log.Logger,
+	br repository.BaseRepository,
+	obr repository.OrderShippingOutboxRepository,
+	de http_helper.DaprEndpoint,
+) OrderShippingOutboxService {
+	return &orderShippingOutboxServiceImpl{l, br, obr, de}
+}
+
+// RunOutboxRelay publishes the pending outbox events on every interval until the context is done,
+// it is meant to run in its own goroutine
+func RunOutboxRelay(ctx context.Context, s OrderShippingOutboxService, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultOutboxRelayInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// keep draining while full batches are claimed
+		for ctx.Err() == nil {
+			if s.RelayOutbox(ctx) < outboxBatchSize() {
+				break
+			}
+		}
+	}
+}
+
+// RelayOutbox publishes one batch of due events and returns how many were claimed.
+// A failed event is retried with exponential backoff until it reaches the max attempts.
+func (s *orderShippingOutboxServiceImpl) RelayOutbox(ctx context.Context) int {
+	logger := log.With(s.logger, "OrderShippingOutboxService", "RelayOutbox")
+
+	outbox, err := s.outboxRepo.ClaimPending(outboxBatchSize(), defaultOutboxLease)
+	if err != nil {
+		_ = level.Error(logger).Log("s.outboxRepo.ClaimPending", err.Error())
+		return 0
+	}
+
+	for i := range outbox {
+		event := &outbox[i]
+		err := s.daprEndpoint.PublishKafka(ctx, event.Topic, json.RawMessage(event.Payload))
+
+		now := time.Now().In(util.Loc)
+		event.Attempts++
+		if err == nil {
+			event.Status = entity.OutboxStatusDelivered
+			event.LastError = ""
+			event.DeliveredAt = &now
+		} else {
+			event.LastError = err.Error()
+			event.NextAttemptAt = now.Add(outboxBackoff(event.Attempts))
+			if event.Attempts >= outboxMaxAttempts() {
+				event.Status = entity.OutboxStatusFailed
+			}
+		}
+
+		if err := s.outboxRepo.Update(event); err != nil {
+			_ = level.Error(logger).Log(event.UID, err.Error())
+		}
+	}
+
+	return len(outbox)
+}
+
+func outboxBatchSize() int {
+	if v := viper.GetInt("outbox.relay.batch-size"); v > 0 {
+		return v
+	}
+	return defaultOutboxBatchSize
+}
+
+func outboxMaxAttempts() int {
+	if v := viper.GetInt("outbox.relay.max-attempts"); v > 0 {
+		return v
+	}
+	return defaultOutboxMaxAttempts
+}
+
+func outboxBackoff(attempts int) time.Duration {
+	backoff := viper.GetDuration("outbox.relay.backoff")
+	if backoff <= 0 {
+		backoff = defaultOutboxBackoff
+	}
+
+	delay := time.Duration(float64(backoff) * math.Pow(2, float64(attempts-1)))
+	if delay <= 0 || delay > defaultOutboxMaxBackoff {
+		return defaultOutboxMaxBackoff
+	}
+	return delay
+}
+
+// swagger:operation GET /shipping/outbox Shipping GetOrderShippingOutboxList
+// Get Order Shipping Outbox Events
+//
+// Description :
+// List of order shipping status events published by the outbox relay, filter by status to inspect delivered and failed events
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaPaginationResponse'
+//         data:
+//           properties:
+//             records:
+//               type: array
+//               items:
+//                 $ref: '#/definitions/GetOrderShippingOutboxListResponse'
+func (s *orderShippingOutboxServiceImpl) GetOrderShippingOutboxList(req *request.GetOrderShippingOutboxList) ([]response.GetOrderShippingOutboxList, *base.Pagination, message.Message) {
+	logger := log.With(s.logger, "OrderShippingOutboxService", "GetOrderShippingOutboxList")
+
+	filter := make(map[string]interface{})
+	filter["status"] = req.Filters.Status
+	filter["order_no"] = req.Filters.OrderNo
+	filter["order_shipping_uid"] = req.Filters.OrderShippingUID
+	filter["topic"] = req.Filters.Topic
+
+	result, pagination, err := s.outboxRepo.FindByParams(req.Limit, req.Page, req.Sort, filter)
+	if err != nil {
+		_ = level.Error(logger).Log("s.outboxRepo.FindByParams", err.Error())
+		return result, pagination, message.ErrNoData
+	}
+
+	return result, pagination, message.SuccessMsg
+}
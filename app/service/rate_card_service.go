@@ -0,0 +1,152 @@
+package service
+
+import (
+	"encoding/json"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/app/repository"
+	"go-klikdokter/helper/http_helper/shipping_provider"
+	"go-klikdokter/helper/message"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+type RateCardService interface {
+	ListRateCard(channelCourierServiceUID string) ([]response.RateCard, message.Message)
+	CreateRateCard(req *request.SaveRateCard) (*response.RateCard, message.Message)
+}
+
+type rateCardServiceImpl struct {
+	logger                    log.Logger
+	baseRepo                  repository.BaseRepository
+	channelCourierServiceRepo repository.ChannelCourierServiceRepository
+	rateCardRepo              repository.RateCardRepository
+}
+
+func NewRateCardService(
+	l log.Logger,
+	br repository.BaseRepository,
+	ccsr repository.ChannelCourierServiceRepository,
+	rcr repository.RateCardRepository,
+) RateCardService {
+	return &rateCardServiceImpl{l, br, ccsr, rcr}
+}
+
+// swagger:operation GET /channel/channel-courier-service/{uid}/rate-card Channel-Courier-Service ListRateCard
+// List Rate Card
+//
+// Description :
+// All versions of the rate card of the channel courier service, latest version first
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             records:
+//               type: array
+//               items:
+//                 $ref: '#/definitions/RateCard'
+func (s *rateCardServiceImpl) ListRateCard(channelCourierServiceUID string) ([]response.RateCard, message.Message) {
+	logger := log.With(s.logger, "RateCardService", "ListRateCard")
+
+	channelCourierService, msg := s.findChannelCourierService(logger, channelCourierServiceUID)
+	if msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	rateCards, err := s.rateCardRepo.FindByChannelCourierServiceID(channelCourierService.ID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.rateCardRepo.FindByChannelCourierServiceID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	result := []response.RateCard{}
+	for i := range rateCards {
+		result = append(result, *response.NewRateCard(&rateCards[i]))
+	}
+
+	return result, message.SuccessMsg
+}
+
+// swagger:operation POST /channel/channel-courier-service/{uid}/rate-card Channel-Courier-Service SaveRateCard
+// Add Rate Card
+//
+// Description :
+// Adds the next version of the rate card, the version with the latest effective_from in effect prices the shipment
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/RateCard'
+func (s *rateCardServiceImpl) CreateRateCard(req *request.SaveRateCard) (*response.RateCard, message.Message) {
+	logger := log.With(s.logger, "RateCardService", "CreateRateCard")
+
+	channelCourierService, msg := s.findChannelCourierService(logger, req.UID)
+	if msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	if channelCourierService.ChannelCourier == nil || channelCourierService.ChannelCourier.Courier == nil ||
+		channelCourierService.ChannelCourier.Courier.CourierType != shipping_provider.InternalCourier {
+		return nil, message.ErrRateCardInternalCourierOnly
+	}
+
+	rateCard := &entity.RateCard{
+		ChannelCourierServiceID: channelCourierService.ID,
+		EffectiveFrom:           req.Body.EffectiveFrom,
+		EffectiveTo:             req.Body.EffectiveTo,
+		BaseFare:                req.Body.BaseFare,
+		MinimumCharge:           req.Body.MinimumCharge,
+	}
+	rateCard.DistanceTiers, _ = json.Marshal(req.Body.DistanceTiers)
+	rateCard.WeightTiers, _ = json.Marshal(req.Body.WeightTiers)
+	rateCard.Zones, _ = json.Marshal(req.Body.Zones)
+	rateCard.ZoneSurcharges, _ = json.Marshal(req.Body.ZoneSurcharges)
+	rateCard.CreatedBy = req.ActorName
+	rateCard.UpdatedBy = req.ActorName
+
+	if msg := rateCard.Validate(); msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	if err := s.rateCardRepo.Create(rateCard); err != nil {
+		_ = level.Error(logger).Log("s.rateCardRepo.Create", err.Error())
+		return nil, message.ErrSaveData
+	}
+
+	return response.NewRateCard(rateCard), message.SuccessMsg
+}
+
+func (s *rateCardServiceImpl) findChannelCourierService(logger log.Logger, uid string) (*entity.ChannelCourierService, message.Message) {
+	channelCourierService, err := s.channelCourierServiceRepo.GetChannelCourierServiceByUID(uid)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelCourierServiceRepo.GetChannelCourierServiceByUID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if channelCourierService == nil {
+		return nil, message.ErrNoData
+	}
+
+	return channelCourierService, message.SuccessMsg
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"errors"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/app/repository"
+	"go-klikdokter/helper/message"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"gorm.io/gorm"
+)
+
+type ShippingPromotionService interface {
+	ListShippingPromotion(channelUID string) ([]response.ShippingPromotion, message.Message)
+	CreateShippingPromotion(req *request.SaveShippingPromotion) (*response.ShippingPromotion, message.Message)
+	UpdateShippingPromotion(req *request.UpdateShippingPromotion) (*response.ShippingPromotion, message.Message)
+	DeleteShippingPromotion(uid string) message.Message
+}
+
+type shippingPromotionServiceImpl struct {
+	logger                log.Logger
+	baseRepo              repository.BaseRepository
+	channelRepo           repository.ChannelRepository
+	courierServiceRepo    repository.CourierServiceRepository
+	shippingPromotionRepo repository.ShippingPromotionRepository
+}
+
+func NewShippingPromotionService(
+	l log.Logger,
+	br repository.BaseRepository,
+	chr repository.ChannelRepository,
+	csr repository.CourierServiceRepository,
+	spr repository.ShippingPromotionRepository,
+) ShippingPromotionService {
+	return &shippingPromotionServiceImpl{l, br, chr, csr, spr}
+}
+
+// swagger:operation GET /channel/channel-app/{uid}/shipping-promotion Channel-Apps GetShippingPromotion
+// List Shipping Promotion
+//
+// Description :
+// Shipping promotions of the channel with the budget used so far
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             records:
+//               type: array
+//               items:
+//                 $ref: '#/definitions/ShippingPromotionResponse'
+func (s *shippingPromotionServiceImpl) ListShippingPromotion(channelUID string) ([]response.ShippingPromotion, message.Message) {
+	logger := log.With(s.logger, "ShippingPromotionService", "ListShippingPromotion")
+
+	channel, err := s.channelRepo.FindByUid(&channelUID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelRepo.FindByUid", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if channel == nil {
+		return nil, message.ErrChannelNotFound
+	}
+
+	promotions, err := s.shippingPromotionRepo.FindByChannelID(channel.ID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingPromotionRepo.FindByChannelID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	result := []response.ShippingPromotion{}
+	for i := range promotions {
+		result = append(result, *response.NewShippingPromotion(&promotions[i], channel.UID))
+	}
+
+	return result, message.SuccessMsg
+}
+
+// swagger:operation POST /channel/shipping-promotion Channel-Apps SaveShippingPromotion
+// Add Shipping Promotion
+//
+// Description :
+// Discounts the shipping rates of the channel during the campaign
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/ShippingPromotionResponse'
+func (s *shippingPromotionServiceImpl) CreateShippingPromotion(req *request.SaveShippingPromotion) (*response.ShippingPromotion, message.Message) {
+	logger := log.With(s.logger, "ShippingPromotionService", "CreateShippingPromotion")
+
+	if req.Body.ChannelUID == "" {
+		return nil, message.ErrChannelUIDRequired
+	}
+
+	channel, err := s.channelRepo.FindByUid(&req.Body.ChannelUID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelRepo.FindByUid", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if channel == nil {
+		return nil, message.ErrChannelNotFound
+	}
+
+	promotion := &entity.ShippingPromotion{ChannelID: channel.ID}
+	promotion.CreatedBy = req.ActorName
+
+	if msg := s.setShippingPromotion(logger, promotion, &req.Body.ShippingPromotionBody); msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	if err := s.shippingPromotionRepo.Save(promotion); err != nil {
+		_ = level.Error(logger).Log("s.shippingPromotionRepo.Save", err.Error())
+		return nil, message.ErrSaveData
+	}
+
+	return response.NewShippingPromotion(promotion, channel.UID), message.SuccessMsg
+}
+
+// swagger:operation PUT /channel/shipping-promotion/{uid} Channel-Apps UpdateShippingPromotion
+// Update Shipping Promotion
+//
+// Description :
+// The used budget is kept
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/ShippingPromotionResponse'
+func (s *shippingPromotionServiceImpl) UpdateShippingPromotion(req *request.UpdateShippingPromotion) (*response.ShippingPromotion, message.Message) {
+	logger := log.With(s.logger, "ShippingPromotionService", "UpdateShippingPromotion")
+
+	promotion, err := s.shippingPromotionRepo.FindByUID(req.UID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingPromotionRepo.FindByUID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if promotion == nil {
+		return nil, message.ErrShippingPromotionNotFound
+	}
+
+	promotion.UpdatedBy = req.ActorName
+	if msg := s.setShippingPromotion(logger, promotion, &req.Body); msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	if err := s.shippingPromotionRepo.Save(promotion); err != nil {
+		_ = level.Error(logger).Log("s.shippingPromotionRepo.Save", err.Error())
+		return nil, message.ErrSaveData
+	}
+
+	var channelUID string
+	if promotion.Channel != nil {
+		channelUID = promotion.Channel.UID
+	}
+
+	return response.NewShippingPromotion(promotion, channelUID), message.SuccessMsg
+}
+
+// swagger:operation DELETE /channel/shipping-promotion/{uid} Channel-Apps DeleteShippingPromotion
+// Delete Shipping Promotion
+//
+// Description :
+//
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           type: object
+func (s *shippingPromotionServiceImpl) DeleteShippingPromotion(uid string) message.Message {
+	logger := log.With(s.logger, "ShippingPromotionService", "DeleteShippingPromotion")
+
+	promotion, err := s.shippingPromotionRepo.FindByUID(uid)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingPromotionRepo.FindByUID", err.Error())
+		return message.ErrDB
+	}
+
+	if promotion == nil {
+		return message.ErrShippingPromotionNotFound
+	}
+
+	if err := s.shippingPromotionRepo.Delete(promotion); err != nil {
+		_ = level.Error(logger).Log("s.shippingPromotionRepo.Delete", err.Error())
+		return message.FailedMsg
+	}
+
+	return message.SuccessMsg
+}
+
+func (s *shippingPromotionServiceImpl) setShippingPromotion(logger log.Logger, promotion *entity.ShippingPromotion, body *request.ShippingPromotionBody) message.Message {
+	promotion.Name = body.Name
+	promotion.VoucherCode = strings.ToUpper(strings.TrimSpace(body.VoucherCode))
+	promotion.MinTotalProductPrice = body.MinTotalProductPrice
+	promotion.PostalCodePrefix = body.PostalCodePrefix
+	promotion.StartTime = body.StartTime
+	promotion.EndTime = body.EndTime
+	promotion.DiscountType = body.DiscountType
+	promotion.DiscountValue = body.DiscountValue
+	promotion.MaxDiscount = body.MaxDiscount
+	promotion.BudgetCap = body.BudgetCap
+	if body.Status != nil {
+		promotion.Status = body.Status
+	}
+
+	if msg := promotion.Validate(); msg != message.SuccessMsg {
+		return msg
+	}
+
+	promotion.CourierService = nil
+	promotion.CourierServiceID = nil
+	if body.CourierServiceUID != "" {
+		courierService, err := s.courierServiceRepo.FindByUid(&body.CourierServiceUID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return message.ErrNoDataCourierService
+			}
+			_ = level.Error(logger).Log("s.courierServiceRepo.FindByUid", err.Error())
+			return message.ErrDB
+		}
+
+		if courierService == nil {
+			return message.ErrNoDataCourierService
+		}
+
+		promotion.CourierService = courierService
+		promotion.CourierServiceID = &courierService.ID
+	}
+
+	return message.SuccessMsg
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/helper/circuitbreaker"
+	"go-klikdokter/helper/http_helper/shipping_provider"
+	"go-klikdokter/helper/message"
+
+	"github.com/go-kit/log"
+)
+
+type ShippingProviderService interface {
+	GetCircuitBreakerList() ([]response.CircuitBreaker, message.Message)
+}
+
+type shippingProviderServiceImpl struct {
+	logger log.Logger
+}
+
+func NewShippingProviderService(l log.Logger) ShippingProviderService {
+	return &shippingProviderServiceImpl{l}
+}
+
+// swagger:operation GET /shipping/circuit-breaker Shipping GetCircuitBreakerList
+// Get Circuit Breaker List
+//
+// Description :
+// State of the circuit breaker of every operation of the shipping providers, the services of a courier are unavailable while its rate circuit is open
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             records:
+//               type: array
+//               items:
+//                 $ref: '#/definitions/CircuitBreakerResponse'
+func (s *shippingProviderServiceImpl) GetCircuitBreakerList() ([]response.CircuitBreaker, message.Message) {
+	result := []response.CircuitBreaker{}
+	for _, v := range circuitbreaker.States() {
+		courierCode, operation := shipping_provider.ParseCircuitBreakerCommand(v.Command)
+
+		state := response.CircuitClosed
+		if v.Open {
+			state = response.CircuitOpen
+		}
+
+		result = append(result, response.CircuitBreaker{
+			CourierCode: courierCode,
+			Operation:   operation,
+			State:       state,
+		})
+	}
+
+	return result, message.SuccessMsg
+}
@@ -1,7 +1,9 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go-klikdokter/app/model/base"
 	"go-klikdokter/app/model/entity"
@@ -9,12 +11,12 @@ import (
 	"go-klikdokter/app/model/response"
 	"go-klikdokter/app/repository"
 	"go-klikdokter/helper/global"
-	"go-klikdokter/helper/http_helper"
 	"go-klikdokter/helper/http_helper/shipping_provider"
 	"go-klikdokter/helper/message"
 	"go-klikdokter/pkg/cache"
 	"go-klikdokter/pkg/util"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -26,20 +28,23 @@ import (
 )
 
 type ShippingService interface {
-	GetShippingRate(input request.GetShippingRateRequest) ([]response.GetShippingRateResponse, message.Message)
-	GetShippingRateByShippingType(input request.GetShippingRateRequest) ([]response.GetShippingRateResponse, message.Message)
-	CreateDelivery(input *request.CreateDelivery) (*response.CreateDelivery, message.Message)
-	OrderShippingTracking(req *request.GetOrderShippingTracking) ([]response.GetOrderShippingTracking, message.Message)
+	GetShippingRate(ctx context.Context, input request.GetShippingRateRequest) ([]response.GetShippingRateResponse, message.Message)
+	GetBatchShippingRate(ctx context.Context, input *request.BatchShippingRateRequest) (map[string][]response.GetShippingRateResponse, message.Message)
+	GetShippingRateByShippingType(ctx context.Context, input request.GetShippingRateRequest) ([]response.GetShippingRateResponse, message.Message)
+	CreateDelivery(ctx context.Context, input *request.CreateDelivery) (*response.CreateDelivery, message.Message)
+	OrderShippingTracking(ctx context.Context, req *request.GetOrderShippingTracking) ([]response.GetOrderShippingTracking, message.Message)
 	UpdateStatusShipper(req *request.WebhookUpdateStatusShipper) (*entity.OrderShipping, message.Message)
 	GetOrderShippingList(req *request.GetOrderShippingList) ([]response.GetOrderShippingList, *base.Pagination, message.Message)
 	GetOrderShippingDetailByUID(uid string) (*response.GetOrderShippingDetail, message.Message)
-	CancelPickup(req *request.CancelPickup) message.Message
-	CancelOrder(req *request.CancelOrder) message.Message
+	CancelPickup(ctx context.Context, req *request.CancelPickup) message.Message
+	CancelOrder(ctx context.Context, req *request.CancelOrder) message.Message
 	GetOrderShippingLabel(req *request.GetOrderShippingLabel) ([]response.GetOrderShippingLabelResponse, message.Message)
-	RepickupOrder(req *request.RepickupOrderRequest) (*response.RepickupOrderResponse, message.Message)
-	ShippingTracking(req *request.GetOrderShippingTracking) ([]response.GetOrderShippingTracking, message.Message)
+	RepickupOrder(ctx context.Context, req *request.RepickupOrderRequest) (*response.RepickupOrderResponse, message.Message)
+	ShippingTracking(ctx context.Context, req *request.GetOrderShippingTracking) ([]response.GetOrderShippingTracking, message.Message)
 	UpdateStatusGrab(req *request.WebhookUpdateStatusGrabRequest) message.Message
 	DownloadOrderShipping(req *request.DownloadOrderShipping) ([]response.DownloadOrderShipping, message.Message)
+	UpdateStatusOrderShipping(req *request.UpdateStatusOrderShipping) message.Message
+	PurgeExpiredRateQuotes() int
 }
 
 type shippingServiceImpl struct {
@@ -48,13 +53,20 @@ type shippingServiceImpl struct {
 	channelRepo               repository.ChannelRepository
 	courierServiceRepo        repository.CourierServiceRepository
 	courierCoverageCode       repository.CourierCoverageCodeRepository
-	shipper                   shipping_provider.Shipper
+	shippingProvider          shipping_provider.ShippingProviderRegistry
 	redis                     cache.RedisCache
 	orderShipping             repository.OrderShippingRepository
 	courierRepo               repository.CourierRepository
 	shippingCourierStatusRepo repository.ShippingCourierStatusRepository
-	daprEndpoint              http_helper.DaprEndpoint
-	grab                      shipping_provider.Grab
+	statusTransitionRepo      repository.ShippingStatusTransitionRepository
+	idempotencyKeyRepo        repository.IdempotencyKeyRepository
+	rateCardRepo              repository.RateCardRepository
+	channelPriceRuleRepo      repository.ChannelPriceRuleRepository
+	shippingPromotionRepo     repository.ShippingPromotionRepository
+	shippingRateQuoteRepo     repository.ShippingRateQuoteRepository
+	recommendationWeightRepo  repository.ChannelRecommendationWeightRepository
+	courierHolidayRepo        repository.CourierHolidayRepository
+	credentialRepo            repository.ChannelCourierCredentialRepository
 }
 
 func NewShippingService(
@@ -63,16 +75,23 @@ func NewShippingService(
 	chrp repository.ChannelRepository,
 	csrp repository.CourierServiceRepository,
 	cccrp repository.CourierCoverageCodeRepository,
-	sh shipping_provider.Shipper,
+	sp shipping_provider.ShippingProviderRegistry,
 	rc cache.RedisCache,
 	osr repository.OrderShippingRepository,
 	cr repository.CourierRepository,
 	scs repository.ShippingCourierStatusRepository,
-	de http_helper.DaprEndpoint,
-	gr shipping_provider.Grab,
+	sstr repository.ShippingStatusTransitionRepository,
+	ikr repository.IdempotencyKeyRepository,
+	rcr repository.RateCardRepository,
+	cprr repository.ChannelPriceRuleRepository,
+	spr repository.ShippingPromotionRepository,
+	srqr repository.ShippingRateQuoteRepository,
+	crwr repository.ChannelRecommendationWeightRepository,
+	chr repository.CourierHolidayRepository,
+	cccr repository.ChannelCourierCredentialRepository,
 ) ShippingService {
 	return &shippingServiceImpl{
-		l, br, chrp, csrp, cccrp, sh, rc, osr, cr, scs, de, gr,
+		l, br, chrp, csrp, cccrp, sp, rc, osr, cr, scs, sstr, ikr, rcr, cprr, spr, srqr, crwr, chr, cccr,
 	}
 }
 
@@ -96,11 +115,11 @@ func NewShippingService(
 //           properties:
 //             record:
 //               $ref: '#/definitions/ShippmentPredefinedDetail'
-func (s *shippingServiceImpl) GetShippingRateByShippingType(input request.GetShippingRateRequest) ([]response.GetShippingRateResponse, message.Message) {
+func (s *shippingServiceImpl) GetShippingRateByShippingType(ctx context.Context, input request.GetShippingRateRequest) ([]response.GetShippingRateResponse, message.Message) {
 	if input.ShippingType == "" {
 		return []response.GetShippingRateResponse{}, message.ErrShippingTypeRequired
 	}
-	return s.GetShippingRate(input)
+	return s.GetShippingRate(ctx, input)
 }
 
 // swagger:operation POST /shipping/shipping-rate Shipping ShippingRate
@@ -123,46 +142,451 @@ func (s *shippingServiceImpl) GetShippingRateByShippingType(input request.GetShi
 //           properties:
 //             record:
 //               $ref: '#/definitions/ShippingRate'
-func (s *shippingServiceImpl) GetShippingRate(input request.GetShippingRateRequest) ([]response.GetShippingRateResponse, message.Message) {
+func (s *shippingServiceImpl) GetShippingRate(ctx context.Context, input request.GetShippingRateRequest) ([]response.GetShippingRateResponse, message.Message) {
 	logger := log.With(s.logger, "ShippingService", "GetShippingRate")
 
-	if len(input.CourierServiceUID) == 0 {
-		return []response.GetShippingRateResponse{}, message.ErrCourierServiceIsRequired
+	if !request.IsValidShippingRateSort(input.Sort) {
+		return []response.GetShippingRateResponse{}, message.ErrInvalidShippingRateSort
+	}
+
+	scope, msg := s.findShippingRateScope(logger, input.ChannelUID, input.CourierServiceUID, input.ContainPrescription, input.ShippingType)
+	if msg != message.SuccessMsg {
+		return []response.GetShippingRateResponse{}, msg
+	}
+
+	return s.shippingRate(ctx, scope, &input), message.SuccessMsg
+}
+
+// swagger:operation POST /shipping/shipping-rate/batch Shipping BatchShippingRate
+// Get Shipping Rate of Multiple Shipments
+//
+// Description :
+// Rates every shipment of the channel, the results are keyed by the shipment id.
+// The shipments not started before the timeout of the batch are left out of the results.
+// A shipment started before the timeout is returned with the rates that are ready,
+// the couriers that did not answer in time are unavailable with the provider timeout message
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//            $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               type: object
+//               additionalProperties:
+//                 type: array
+//                 items:
+//                   $ref: '#/definitions/ShippingRate'
+func (s *shippingServiceImpl) GetBatchShippingRate(ctx context.Context, input *request.BatchShippingRateRequest) (map[string][]response.GetShippingRateResponse, message.Message) {
+	logger := log.With(s.logger, "ShippingService", "GetBatchShippingRate")
+
+	maxShipments := viper.GetInt("setting.batch-shipping-rate.max-shipments")
+	if maxShipments <= 0 {
+		maxShipments = defaultBatchMaxShipments
+	}
+
+	if msg := input.Validate(maxShipments); msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	// the prescription of every shipment is checked when it is rated
+	scope, msg := s.findShippingRateScope(logger, input.ChannelUID, input.CourierServiceUID, false, input.ShippingType)
+	if msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	concurrency := viper.GetInt("setting.batch-shipping-rate.concurrency")
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	timeout := viper.GetDuration("setting.batch-shipping-rate.timeout")
+	if timeout <= 0 {
+		timeout = defaultBatchTimeout
+	}
+
+	// the providers of the shipments being rated are cancelled at the timeout and the rest are not rated
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// every shipment writes to its own index, the map is built after all of them are rated
+	var (
+		rates = make([][]response.GetShippingRateResponse, len(input.Shipments))
+		sem   = make(chan struct{}, concurrency)
+		wg    sync.WaitGroup
+	)
+
+shipments:
+	for i := range input.Shipments {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break shipments
+		}
+
+		// both are ready when a shipment finishes at the timeout
+		if ctx.Err() != nil {
+			<-sem
+			break shipments
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			req := input.ShippingRateRequest(&input.Shipments[i])
+			rates[i] = s.shippingRate(ctx, scope, &req)
+		}(i)
+	}
+	wg.Wait()
+
+	result := make(map[string][]response.GetShippingRateResponse, len(input.Shipments))
+	for i, v := range input.Shipments {
+		if rates[i] != nil {
+			result[v.ID] = rates[i]
+		}
+	}
+
+	if skipped := len(input.Shipments) - len(result); skipped > 0 {
+		_ = level.Warn(logger).Log("skipped", skipped, "msg", "batch timed out")
+	}
+
+	return result, message.SuccessMsg
+}
+
+// shippingRateScope is looked up once and shared by every shipment rated for the channel
+type shippingRateScope struct {
+	channel         *entity.Channel
+	courierServices []entity.ChannelCourierServiceForShippingRate
+	priceRules      []entity.ChannelPriceRule
+	promotions      []entity.ShippingPromotion
+	weight          *entity.ChannelRecommendationWeight
+
+	// key: courier service id
+	reliability map[uint64]float64
+
+	// working days of the couriers of the scope
+	calendar *entity.DeliveryCalendar
+}
+
+const (
+	defaultBatchMaxShipments = 50
+	defaultBatchConcurrency  = 5
+	defaultBatchTimeout      = 15 * time.Second
+)
+
+func (s *shippingServiceImpl) findShippingRateScope(logger log.Logger, channelUID string, courierServiceUIDs []string, containPrescription bool, shippingType string) (*shippingRateScope, message.Message) {
+	if len(courierServiceUIDs) == 0 {
+		return nil, message.ErrCourierServiceIsRequired
 	}
 
 	// find Channel By UID
-	channel, err := s.channelRepo.FindByUid(&input.ChannelUID)
+	channel, err := s.channelRepo.FindByUid(&channelUID)
 
 	if err != nil {
 		_ = level.Error(logger).Log("s.channelRepo.FindByUid", err.Error())
-		return []response.GetShippingRateResponse{}, message.ErrChannelNotFound
+		return nil, message.ErrChannelNotFound
 	}
 
 	if channel == nil {
-		return []response.GetShippingRateResponse{}, message.ErrChannelNotFound
+		return nil, message.ErrChannelNotFound
 	}
 
-	input.ChannelCode = channel.ChannelCode
-
 	// find Courier Servies By Channel UID and Courier Servies UID Slice
-	courierServices, err := s.courierServiceRepo.FindCourierServiceByChannelAndUIDs(input.ChannelUID, input.CourierServiceUID, input.ContainPrescription, input.ShippingType)
+	courierServices, err := s.courierServiceRepo.FindCourierServiceByChannelAndUIDs(channelUID, courierServiceUIDs, containPrescription, shippingType)
 
 	if err != nil {
 		_ = level.Error(logger).Log("s.courierServiceRepo.FindCourierServiceByChannelAndUIDs", err.Error())
-		return []response.GetShippingRateResponse{}, message.CourierServiceNotFoundMsg
+		return nil, message.CourierServiceNotFoundMsg
 	}
 
 	if len(courierServices) == 0 {
-		return []response.GetShippingRateResponse{}, message.CourierServiceNotFoundMsg
+		return nil, message.CourierServiceNotFoundMsg
+	}
+
+	priceRules, err := s.channelPriceRuleRepo.FindActiveByChannelID(channel.ID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelPriceRuleRepo.FindActiveByChannelID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	promotions, err := s.shippingPromotionRepo.FindActiveByChannelID(channel.ID, time.Now().In(util.Loc))
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingPromotionRepo.FindActiveByChannelID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	// the rates are still recommended with the default weights when the weights of the channel can not be found
+	weight, err := s.recommendationWeightRepo.FindByChannelID(channel.ID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.recommendationWeightRepo.FindByChannelID", err.Error())
+		weight = nil
+	}
+
+	if weight == nil {
+		weight = defaultRecommendationWeight()
+	}
+
+	var courierIDs []uint64
+	unique := make(map[uint64]bool)
+	for _, v := range courierServices {
+		if !unique[v.CourierID] {
+			unique[v.CourierID] = true
+			courierIDs = append(courierIDs, v.CourierID)
+		}
+	}
+
+	return &shippingRateScope{
+		channel:         channel,
+		courierServices: courierServices,
+		priceRules:      priceRules,
+		promotions:      promotions,
+		weight:          weight,
+		reliability:     s.courierServiceReliability(logger, channel.ID),
+		calendar:        s.deliveryCalendar(logger, courierIDs, time.Now().In(util.Loc)),
+	}, message.SuccessMsg
+}
+
+// used when cache.redis.expired-in-minute.reliability is not configured
+const defaultReliabilityCacheMinutes = 60
+
+// courierServiceReliability is the share of the orders of the channel per courier service that were not cancelled by
+// the courier, it is cached per channel. Every courier service is rated neutral when the orders can not be counted.
+func (s *shippingServiceImpl) courierServiceReliability(logger log.Logger, channelID uint64) map[uint64]float64 {
+	key := fmt.Sprintf("%s:reliability:%d", viper.GetString("cache.redis.base-key"), channelID)
+
+	var reliability map[uint64]float64
+	_ = s.redis.GetJsonStruct(key, &reliability)
+	if reliability != nil {
+		return reliability
+	}
+
+	window := viper.GetDuration("setting.recommendation.reliability-window")
+	if window <= 0 {
+		window = defaultReliabilityWindow
+	}
+
+	orders, err := s.orderShipping.FindCourierServiceReliability(channelID, shipping_provider.StatusCancelled, time.Now().In(util.Loc).Add(-window))
+	if err != nil {
+		_ = level.Error(logger).Log("s.orderShipping.FindCourierServiceReliability", err.Error())
+		return map[uint64]float64{}
+	}
+
+	reliability = make(map[uint64]float64, len(orders))
+	for i := range orders {
+		reliability[orders[i].CourierServiceID] = orders[i].Rate()
+	}
+
+	expiredIn := viper.GetInt("cache.redis.expired-in-minute.reliability")
+	if expiredIn <= 0 {
+		expiredIn = defaultReliabilityCacheMinutes
+	}
+	s.redis.SetJsonStruct(key, reliability, expiredIn)
+
+	return reliability
+}
+
+// used when setting.delivery-date.holiday-horizon is not configured
+const defaultHolidayHorizon = 60 * 24 * time.Hour
+
+// deliveryCalendar loads the holidays of the couriers the delivery can be estimated with,
+// the delivery is estimated without holidays when they can not be loaded
+func (s *shippingServiceImpl) deliveryCalendar(logger log.Logger, courierIDs []uint64, at time.Time) *entity.DeliveryCalendar {
+	horizon := viper.GetDuration("setting.delivery-date.holiday-horizon")
+	if horizon <= 0 {
+		horizon = defaultHolidayHorizon
+	}
+
+	// a day earlier, the date of the holiday may already be today in a timezone east of util.Loc
+	holidays, err := s.courierHolidayRepo.FindByCourierIDs(courierIDs, at.AddDate(0, 0, -1), at.Add(horizon))
+	if err != nil {
+		_ = level.Error(logger).Log("s.courierHolidayRepo.FindByCourierIDs", err.Error())
+	}
+
+	return entity.NewDeliveryCalendar(deliveryNonWorkingDays(), holidays)
+}
+
+// deliveryNonWorkingDays are the weekdays of setting.delivery-date.non-working-days, default sunday
+func deliveryNonWorkingDays() []time.Weekday {
+	if !viper.IsSet("setting.delivery-date.non-working-days") {
+		return []time.Weekday{time.Sunday}
+	}
+
+	var result []time.Weekday
+	for _, name := range viper.GetStringSlice("setting.delivery-date.non-working-days") {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.EqualFold(day.String(), strings.TrimSpace(name)) {
+				result = append(result, day)
+			}
+		}
+	}
+
+	return result
+}
+
+// used when setting.recommendation.reliability-window is not configured
+const defaultReliabilityWindow = 30 * 24 * time.Hour
+
+// defaultRecommendationWeight is used by channels without recommendation weights,
+// the weights of setting.recommendation.weight are used when they are valid
+func defaultRecommendationWeight() *entity.ChannelRecommendationWeight {
+	weight := &entity.ChannelRecommendationWeight{
+		PriceWeight:       viper.GetFloat64("setting.recommendation.weight.price"),
+		EtdWeight:         viper.GetFloat64("setting.recommendation.weight.etd"),
+		PriorityWeight:    viper.GetFloat64("setting.recommendation.weight.priority"),
+		ReliabilityWeight: viper.GetFloat64("setting.recommendation.weight.reliability"),
+	}
+
+	if weight.Validate() != message.SuccessMsg {
+		return &entity.ChannelRecommendationWeight{PriceWeight: 0.4, EtdWeight: 0.3, PriorityWeight: 0.2, ReliabilityWeight: 0.1}
+	}
+
+	return weight
+}
+
+// shippingRate prices one shipment with the internal and third party couriers of the scope
+func (s *shippingServiceImpl) shippingRate(ctx context.Context, scope *shippingRateScope, input *request.GetShippingRateRequest) []response.GetShippingRateResponse {
+	input.ChannelCode = scope.channel.ChannelCode
+	input.ChannelID = scope.channel.ID
+
+	price := s.getAllCourierPrice(ctx, scope.courierServices, input)
+
+	resp := toGetShippingRateResponseList(input, scope.courierServices, price, scope.priceRules, scope.promotions)
+	recommendShippingRate(resp, scope)
+	estimateShippingRateDelivery(resp, scope, input)
+	sortShippingRate(resp, input.Sort)
+	s.saveRateQuotes(scope.channel.ID, input, resp)
+
+	return resp
+}
+
+// used when setting.rate-quote-ttl is not configured
+const defaultRateQuoteTTL = 15 * time.Minute
+
+const (
+	defaultRateQuotePurgeInterval = time.Hour
+	rateQuotePurgeBatchSize       = 1000
+)
+
+// RunRateQuotePurge deletes the expired quotes that were not claimed by an order on every interval until
+// the context is done, it is meant to run in its own goroutine
+func RunRateQuotePurge(ctx context.Context, s ShippingService, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultRateQuotePurgeInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// keep deleting while full batches are deleted
+		for ctx.Err() == nil {
+			if s.PurgeExpiredRateQuotes() < rateQuotePurgeBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// PurgeExpiredRateQuotes deletes one batch of the expired quotes that were not claimed and returns how many were deleted,
+// a claimed quote is kept with its order
+func (s *shippingServiceImpl) PurgeExpiredRateQuotes() int {
+	logger := log.With(s.logger, "ShippingService", "PurgeExpiredRateQuotes")
+
+	deleted, err := s.shippingRateQuoteRepo.DeleteExpired(time.Now().In(util.Loc), rateQuotePurgeBatchSize)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingRateQuoteRepo.DeleteExpired", err.Error())
+		return 0
+	}
+
+	return int(deleted)
+}
+
+// saveRateQuotes gives every available service a quote the delivery can be created with,
+// the rates are still returned without quotes when saving fails
+func (s *shippingServiceImpl) saveRateQuotes(channelID uint64, req *request.GetShippingRateRequest, rates []response.GetShippingRateResponse) {
+	logger := log.With(s.logger, "ShippingService", "SaveRateQuotes")
+
+	ttl := viper.GetDuration("setting.rate-quote-ttl")
+	if ttl <= 0 {
+		ttl = defaultRateQuoteTTL
+	}
+	expiredAt := time.Now().In(util.Loc).Add(ttl)
+
+	var quotes []entity.ShippingRateQuote
+	for _, rate := range rates {
+		for _, service := range rate.Services {
+			if service.AvailableCode != 200 {
+				continue
+			}
+
+			breakdown, _ := json.Marshal(service)
+			quotes = append(quotes, entity.ShippingRateQuote{
+				ChannelID:             channelID,
+				CourierServiceUID:     service.CourierServiceUID,
+				OriginPostalCode:      req.Origin.PostalCode,
+				DestinationPostalCode: req.Destination.PostalCode,
+				TotalWeight:           req.TotalWeight,
+				TotalProductPrice:     req.TotalProductPrice,
+				FinalWeight:           service.FinalWeight,
+				COD:                   req.COD,
+				UseInsurance:          req.UseInsurance,
+				OriginalPrice:         service.OriginalPrice,
+				TotalPrice:            service.TotalPrice,
+				ShippingDiscount:      service.ShippingDiscount,
+				ShippingPromotionUID:  shippingRatePromotionUID(service.Promotion),
+				InsuranceFee:          service.InsuranceFee,
+				CodFee:                service.CodFee,
+				Breakdown:             breakdown,
+				ExpiredAt:             expiredAt,
+			})
+		}
+	}
+
+	if len(quotes) == 0 {
+		return
+	}
+
+	quotes, err := s.shippingRateQuoteRepo.CreateBatch(quotes)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingRateQuoteRepo.CreateBatch", err.Error())
+		return
 	}
 
-	price := s.getAllCourierPrice(courierServices, &input)
+	// quotes are created in the order of the available services
+	i := 0
+	for r := range rates {
+		for j := range rates[r].Services {
+			service := &rates[r].Services[j]
+			if service.AvailableCode != 200 || i >= len(quotes) {
+				continue
+			}
 
-	return toGetShippingRateResponseList(&input, courierServices, price), message.SuccessMsg
+			service.QuoteID = quotes[i].UID
+			service.QuoteExpiredAt = &quotes[i].ExpiredAt
+			i++
+		}
+	}
 }
 
 // function to populate price data
-func (s *shippingServiceImpl) getAllCourierPrice(courierServices []entity.ChannelCourierServiceForShippingRate, req *request.GetShippingRateRequest) *response.ShippingRateCommonResponse {
+func (s *shippingServiceImpl) getAllCourierPrice(ctx context.Context, courierServices []entity.ChannelCourierServiceForShippingRate, req *request.GetShippingRateRequest) *response.ShippingRateCommonResponse {
 	var resp = &response.ShippingRateCommonResponse{
 		Rate:       make(map[string]response.ShippingRateData),
 		Summary:    make(map[string]response.ShippingRateSummary),
@@ -194,7 +618,7 @@ func (s *shippingServiceImpl) getAllCourierPrice(courierServices []entity.Channe
 	internalAndMerchantPrice := s.internalAndMerchantPrice(courierList, courierServices, req)
 
 	// Get third party price if any
-	thirdPartyPrice := s.getThirdPartyPrice(courierList, req)
+	thirdPartyPrice := s.getThirdPartyPrice(ctx, courierList, req)
 
 	resp.Add(internalAndMerchantPrice)
 	resp.Add(thirdPartyPrice)
@@ -224,19 +648,32 @@ func (s *shippingServiceImpl) internalAndMerchantPrice(courierList []entity.Cour
 		volume       = util.CalculateVolume(req.TotalHeight, req.TotalWidth, req.TotalLength)
 		volumeWeight = util.CalculateVolumeWeightKg(req.TotalHeight, req.TotalWidth, req.TotalLength)
 		finalWeight  = math.Max(req.TotalWeight, volumeWeight)
-
-		lat1, _  = strconv.ParseFloat(req.Origin.Latitude, 64)
-		long1, _ = strconv.ParseFloat(req.Origin.Longitude, 64)
-		lat2, _  = strconv.ParseFloat(req.Destination.Latitude, 64)
-		long2, _ = strconv.ParseFloat(req.Destination.Longitude, 64)
-
-		distance = util.CalculateDistanceInKm(lat1, long1, lat2, long2)
+		distance     = shipmentDistance(req.Origin.Latitude, req.Origin.Longitude, req.Destination.Latitude, req.Destination.Longitude)
 	)
 
 	// Check courier coverage
 	origin := s.courierCoverageCode.FindInternalAndMerchantCourierCoverage(courierIDs, req.Origin.CountryCode, req.Origin.PostalCode)
 	destination := s.courierCoverageCode.FindInternalAndMerchantCourierCoverage(courierIDs, req.Destination.CountryCode, req.Destination.PostalCode)
 
+	// internal couriers with a rate card in effect are priced from the card instead of the flat price
+	var (
+		channelCourierServiceIDs []uint64
+		rateCards                map[uint64]*entity.RateCard
+		err                      error
+	)
+	for _, v := range courierService {
+		if v.CourierTypeCode == shipping_provider.InternalCourier {
+			channelCourierServiceIDs = append(channelCourierServiceIDs, v.ChannelCourierServiceID)
+		}
+	}
+
+	if len(channelCourierServiceIDs) > 0 {
+		rateCards, err = s.rateCardRepo.FindEffective(channelCourierServiceIDs, time.Now().In(util.Loc))
+		if err != nil {
+			_ = level.Error(s.logger).Log("s.rateCardRepo.FindEffective", err.Error())
+		}
+	}
+
 	for _, v := range courierService {
 
 		_, originOK := origin[v.CourierCode]
@@ -247,19 +684,20 @@ func (s *shippingServiceImpl) internalAndMerchantPrice(courierList []entity.Cour
 		}
 
 		key := global.CourierShippingCodeKey(v.CourierCode, v.ShippingCode)
+		insurance := v.InsurancePolicy().Calculate(req.TotalProductPrice, req.UseInsurance)
 		value := response.ShippingRateData{
 			Distance:         distance,
 			TotalPrice:       v.Price,
-			InsuranceFee:     v.InsuranceFee,
-			InsuranceApplied: v.UseInsurance,
-			MustUseInsurance: v.UseInsurance,
+			InsuranceFee:     insurance.Premium,
+			InsuranceApplied: insurance.Applied,
+			MustUseInsurance: insurance.Mandatory,
+			InsuredValue:     insurance.InsuredValue,
 			Weight:           req.TotalWeight,
 			Volume:           volume,
 			VolumeWeight:     volumeWeight,
 			FinalWeight:      finalWeight,
-			MinDay:           0,
-			MaxDay:           0,
-			UnitPrice:        0,
+			MinDay:           int(math.Ceil(v.EtdMin)),
+			MaxDay:           int(math.Ceil(v.EtdMax)),
 			AvailableCode:    200,
 			Error:            response.SetShippingRateErrorMessage(message.SuccessMsg),
 		}
@@ -268,6 +706,21 @@ func (s *shippingServiceImpl) internalAndMerchantPrice(courierList []entity.Cour
 			value.TotalPrice = 0
 		}
 
+		if rateCard, ok := rateCards[v.ChannelCourierServiceID]; ok && v.CourierTypeCode == shipping_provider.InternalCourier {
+			breakdown := rateCard.Calculate(distance, finalWeight, req.Origin.PostalCode, req.Destination.PostalCode)
+			value.TotalPrice = breakdown.Total
+			value.PriceBreakdown = response.NewPriceBreakdown(breakdown)
+		}
+
+		// price could not be calculated without the rate card
+		if err != nil && v.CourierTypeCode == shipping_provider.InternalCourier {
+			value.UpdateMessage(message.ErrDB)
+		}
+
+		if finalWeight > 0 {
+			value.UnitPrice = util.RoundFloat(value.TotalPrice/finalWeight, 2)
+		}
+
 		// check if origin or destination not available
 		if !(originOK && destinationOK) {
 			value.UpdateMessage(message.ErrCourierCoverageCodeUidNotExist)
@@ -279,75 +732,122 @@ func (s *shippingServiceImpl) internalAndMerchantPrice(courierList []entity.Cour
 	return price
 }
 
-// function to get shipping rate from third party shipping provider
-func (s *shippingServiceImpl) getThirdPartyPrice(courier []entity.Courier, input *request.GetShippingRateRequest) *response.ShippingRateCommonResponse {
+// used when setting.shipping-rate.timeout is not configured
+const defaultShippingRateTimeout = 5 * time.Second
+
+// getThirdPartyPrice asks the providers of every courier at once and waits for them until the timeout of the shipping rate,
+// the couriers that have not answered by then are unavailable and the answers of the others are still returned
+func (s *shippingServiceImpl) getThirdPartyPrice(ctx context.Context, courier []entity.Courier, input *request.GetShippingRateRequest) *response.ShippingRateCommonResponse {
 	var resp = &response.ShippingRateCommonResponse{
 		Rate:       make(map[string]response.ShippingRateData),
 		Summary:    map[string]response.ShippingRateSummary{},
 		CourierMsg: map[string]message.Message{},
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(len(courier))
+	timeout := viper.GetDuration("setting.shipping-rate.timeout")
+	if timeout <= 0 {
+		timeout = defaultShippingRateTimeout
+	}
+
+	// the providers still running after the timeout are cancelled
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// only this goroutine merges the prices, the channel is buffered so a late courier never blocks
+	type courierPrice struct {
+		code  string
+		price *response.ShippingRateCommonResponse
+	}
+	prices := make(chan courierPrice, len(courier))
+	pending := make(map[string]bool)
+
 	for _, v := range courier {
+		if v.CourierType != shipping_provider.ThirPartyCourier && v.CourierType != shipping_provider.AggregatorCourier {
+			continue
+		}
+		pending[v.Code] = true
 
 		go func(c entity.Courier) {
-			defer wg.Done()
-			var (
-				courierPrice *response.ShippingRateCommonResponse
-				err          error
-			)
-
-			if c.CourierType != shipping_provider.ThirPartyCourier && c.CourierType != shipping_provider.AggregatorCourier {
-				return
+			prices <- courierPrice{code: c.Code, price: s.getCourierPrice(ctx, &c, input)}
+		}(v)
+	}
+
+	for len(pending) > 0 {
+		select {
+		case v := <-prices:
+			delete(pending, v.code)
+			resp.Add(v.price)
+		case <-ctx.Done():
+			for code := range pending {
+				resp.CourierMsg[code] = message.ProviderTimeoutMsg
 			}
+			return resp
+		}
+	}
 
-			// try to get price data from cache
-			baseKey := viper.GetString("cache.redis.base-key")
-			key := fmt.Sprintf("%s:%s:%s:%s:%s:%s:%s:%s:%s:%s:%f",
-				baseKey,
-				c.Code,
-				input.Origin.PostalCode,
-				input.Destination.PostalCode,
-				input.Origin.Subdistrict,
-				input.Destination.Subdistrict,
-				input.Origin.Latitude,
-				input.Origin.Longitude,
-				input.Destination.Latitude,
-				input.Destination.Longitude,
-				input.TotalWeight,
-			)
-
-			_ = s.redis.GetJsonStruct(key, &courierPrice)
-			// if cache doesn't exist
-			if courierPrice == nil {
-				switch c.Code {
-				case shipping_provider.ShipperCode:
-					courierPrice, err = s.shipper.GetShippingRate(&c.ID, input)
-				case shipping_provider.GrabCode:
-					courierPrice, err = s.grab.GetShippingRate(input)
-				default:
-					resp.CourierMsg[c.Code] = message.InvalidCourierCodeMsg
-					return
-				}
+	return resp
+}
 
-				if err == nil {
-					// save price to redis cache
-					s.redis.SetJsonStruct(key, courierPrice, viper.GetInt("cache.redis.expired-in-minute.shipping-rate"))
-				}
+// getCourierPrice gets the price of the third party courier from the cache or its provider
+func (s *shippingServiceImpl) getCourierPrice(ctx context.Context, c *entity.Courier, input *request.GetShippingRateRequest) *response.ShippingRateCommonResponse {
+	var (
+		courierPrice *response.ShippingRateCommonResponse
+		err          error
+	)
 
+	credential, msg := s.providerCredential(input.ChannelID, c.ID)
+	if msg != message.SuccessMsg {
+		return &response.ShippingRateCommonResponse{
+			CourierMsg: map[string]message.Message{c.Code: msg},
+		}
+	}
+
+	// try to get price data from cache, the prices of the account of the channel are kept apart,
+	// the provider prices cod, the item value and the dimensions too
+	baseKey := viper.GetString("cache.redis.base-key")
+	key := fmt.Sprintf("%s:%s:%s:%s:%s:%s:%s:%s:%s:%s:%f:%f:%f:%f:%t:%f:%s",
+		baseKey,
+		c.Code,
+		input.Origin.PostalCode,
+		input.Destination.PostalCode,
+		input.Origin.Subdistrict,
+		input.Destination.Subdistrict,
+		input.Origin.Latitude,
+		input.Origin.Longitude,
+		input.Destination.Latitude,
+		input.Destination.Longitude,
+		input.TotalWeight,
+		input.TotalLength,
+		input.TotalWidth,
+		input.TotalHeight,
+		input.COD,
+		input.TotalProductPrice,
+		credential.Account(),
+	)
+
+	_ = s.redis.GetJsonStruct(key, &courierPrice)
+	// if cache doesn't exist
+	if courierPrice == nil {
+		provider, ok := s.shippingProvider.Get(c.Code)
+		if !ok {
+			return &response.ShippingRateCommonResponse{
+				CourierMsg: map[string]message.Message{c.Code: message.InvalidCourierCodeMsg},
 			}
+		}
 
-			resp.Add(courierPrice)
-		}(v)
+		courierPrice, err = provider.GetShippingRate(shipping_provider.WithCredential(ctx, credential), &c.ID, input)
+		if err == nil {
+			// save price to redis cache
+			s.redis.SetJsonStruct(key, courierPrice, viper.GetInt("cache.redis.expired-in-minute.shipping-rate"))
+		}
 	}
-	wg.Wait()
 
-	return resp
+	return courierPrice
 }
 
 // function to generate ShippingRateResponseList
-func toGetShippingRateResponseList(req *request.GetShippingRateRequest, courierServices []entity.ChannelCourierServiceForShippingRate, price *response.ShippingRateCommonResponse) []response.GetShippingRateResponse {
+// the channel price rules are applied to the aggregated provider prices, the provider price is kept as the original price
+func toGetShippingRateResponseList(req *request.GetShippingRateRequest, courierServices []entity.ChannelCourierServiceForShippingRate, price *response.ShippingRateCommonResponse, priceRules []entity.ChannelPriceRule, promotions []entity.ShippingPromotion) []response.GetShippingRateResponse {
 	shippingTypeList := viper.GetStringSlice("setting.shipping-type")
 
 	// use this if config is empty
@@ -365,10 +865,20 @@ func toGetShippingRateResponseList(req *request.GetShippingRateRequest, courierS
 		return dbEtd
 	}
 
+	shipment := &entity.Shipment{
+		Volume:       util.CalculateVolume(req.TotalHeight, req.TotalWidth, req.TotalLength),
+		Distance:     shipmentDistance(req.Origin.Latitude, req.Origin.Longitude, req.Destination.Latitude, req.Destination.Longitude),
+		Purchase:     req.TotalProductPrice,
+		COD:          req.COD,
+		Prescription: req.ContainPrescription,
+		At:           time.Now().In(util.LoadLocation(req.Origin.Timezone)),
+	}
+
 	for _, v := range courierServices {
 		p := price.FindShippingCode(v.CourierCode, v.ShippingCode)
 
-		if msg := v.Validate(&p.FinalWeight, &req.ContainPrescription); msg != message.SuccessMsg {
+		shipment.FinalWeight = p.FinalWeight
+		if msg := v.Validate(shipment); msg != message.SuccessMsg {
 			p.UpdateMessage(msg)
 		}
 
@@ -406,7 +916,10 @@ func toGetShippingRateResponseList(req *request.GetShippingRateRequest, courierS
 			InsuranceFee:     p.InsuranceFee,
 			MustUseInsurance: p.MustUseInsurance,
 			InsuranceApplied: p.InsuranceApplied,
+			InsuredValue:     p.InsuredValue,
 			Distance:         p.Distance,
+			PriceBreakdown:   p.PriceBreakdown,
+			CodAvailable:     v.CodAvailable == 1,
 
 			// uses date from courier
 			// if it does not exist get uses from database
@@ -414,6 +927,49 @@ func toGetShippingRateResponseList(req *request.GetShippingRateRequest, courierS
 			Etd_Max: estimation(p.Etd_Max, v.EtdMax),
 		}
 
+		service.OriginalPrice = service.TotalPrice
+		if service.AvailableCode == 200 && len(priceRules) > 0 {
+			adjusted, adjustments := entity.ApplyPriceRules(priceRules, &entity.PricedShipment{
+				CourierID:        v.CourierID,
+				CourierServiceID: v.CourierServiceID,
+				ShippingType:     v.ShippingTypeCode,
+				PostalCode:       req.Destination.PostalCode,
+				FinalWeight:      service.FinalWeight,
+			}, service.TotalPrice)
+
+			if len(adjustments) > 0 {
+				service.TotalPrice = adjusted
+				service.PriceAdjustments = response.NewPriceAdjustments(adjustments)
+				if service.FinalWeight > 0 {
+					service.UnitPrice = util.RoundFloat(adjusted/service.FinalWeight, 2)
+				}
+			}
+		}
+
+		if service.AvailableCode == 200 && len(promotions) > 0 {
+			promotion, discount := entity.BestShippingPromotion(promotions, &entity.PromotedShipment{
+				CourierServiceID:  v.CourierServiceID,
+				TotalProductPrice: req.TotalProductPrice,
+				PostalCode:        req.Destination.PostalCode,
+				VoucherCode:       req.VoucherCode,
+				At:                shipment.At,
+			}, service.TotalPrice)
+
+			if promotion != nil {
+				service.ShippingDiscount = discount
+				service.Promotion = response.NewShippingRatePromotion(promotion)
+			}
+		}
+
+		// third party couriers insure the product price
+		if service.InsuredValue == 0 && service.InsuranceFee > 0 {
+			service.InsuredValue = req.TotalProductPrice
+		}
+
+		if req.COD && service.AvailableCode == 200 {
+			service.CodFee = v.CodFeeAmount(req.TotalProductPrice)
+		}
+
 		price.SummaryPerShippingType(v.ShippingTypeCode, service.TotalPrice, service.Etd_Max, service.Etd_Min, service.AvailableCode)
 
 		if _, ok := shippingTypeMap[v.ShippingTypeCode]; !ok {
@@ -449,6 +1005,120 @@ func toGetShippingRateResponseList(req *request.GetShippingRateRequest, courierS
 	return resp
 }
 
+// recommendShippingRate scores the available services against each other and recommends the highest score of the response
+func recommendShippingRate(rates []response.GetShippingRateResponse, scope *shippingRateScope) {
+	courierServices := make(map[string]*entity.ChannelCourierServiceForShippingRate, len(scope.courierServices))
+	for i := range scope.courierServices {
+		courierServices[scope.courierServices[i].CourierServiceUID] = &scope.courierServices[i]
+	}
+
+	var available []*response.GetShippingRateService
+	for i := range rates {
+		for j := range rates[i].Services {
+			if rates[i].Services[j].AvailableCode == 200 {
+				available = append(available, &rates[i].Services[j])
+			}
+		}
+	}
+
+	if len(available) == 0 {
+		return
+	}
+
+	minPrice, maxPrice := available[0].PayablePrice(), available[0].PayablePrice()
+	minEtd, maxEtd := available[0].AverageEtd(), available[0].AverageEtd()
+	minPriority, maxPriority := math.MaxFloat64, 0.0
+	for _, v := range available {
+		minPrice, maxPrice = math.Min(minPrice, v.PayablePrice()), math.Max(maxPrice, v.PayablePrice())
+		minEtd, maxEtd = math.Min(minEtd, v.AverageEtd()), math.Max(maxEtd, v.AverageEtd())
+
+		if cs, ok := courierServices[v.CourierServiceUID]; ok {
+			minPriority = math.Min(minPriority, float64(cs.PrioritySort))
+			maxPriority = math.Max(maxPriority, float64(cs.PrioritySort))
+		}
+	}
+
+	meanReliability := entity.MeanReliability(scope.reliability)
+
+	var recommended *response.GetShippingRateService
+	for _, v := range available {
+		factors := &entity.RecommendationFactors{
+			Price:       entity.NormalizeLowerIsBetter(v.PayablePrice(), minPrice, maxPrice),
+			Etd:         entity.NormalizeLowerIsBetter(v.AverageEtd(), minEtd, maxEtd),
+			Reliability: meanReliability,
+		}
+
+		if cs, ok := courierServices[v.CourierServiceUID]; ok {
+			factors.Priority = entity.NormalizeLowerIsBetter(float64(cs.PrioritySort), minPriority, maxPriority)
+			if rate, ok := scope.reliability[cs.CourierServiceID]; ok {
+				factors.Reliability = rate
+			}
+		}
+
+		v.RecommendationScore = scope.weight.Score(factors)
+		if recommended == nil || v.RecommendationScore > recommended.RecommendationScore {
+			recommended = v
+		}
+	}
+
+	recommended.Recommended = true
+}
+
+// estimateShippingRateDelivery gives the available services the delivery window in the timezone of the origin
+func estimateShippingRateDelivery(rates []response.GetShippingRateResponse, scope *shippingRateScope, input *request.GetShippingRateRequest) {
+	courierServices := make(map[string]*entity.ChannelCourierServiceForShippingRate, len(scope.courierServices))
+	for i := range scope.courierServices {
+		courierServices[scope.courierServices[i].CourierServiceUID] = &scope.courierServices[i]
+	}
+
+	at := time.Now().In(util.LoadLocation(input.Origin.Timezone))
+	for i := range rates {
+		for j := range rates[i].Services {
+			service := &rates[i].Services[j]
+			cs, ok := courierServices[service.CourierServiceUID]
+			if service.AvailableCode != 200 || !ok {
+				continue
+			}
+
+			estimate := scope.calendar.Estimate(cs.CourierID, cs.StartTime, service.Etd_Min, service.Etd_Max, at)
+			service.EstimatedDeliveryFrom = &estimate.From
+			service.EstimatedDeliveryTo = &estimate.To
+		}
+	}
+}
+
+// sortShippingRate orders the services of every shipping type, unavailable services are always last
+func sortShippingRate(rates []response.GetShippingRateResponse, mode string) {
+	if mode == "" {
+		return
+	}
+
+	for i := range rates {
+		services := rates[i].Services
+		sort.SliceStable(services, func(a, b int) bool {
+			x, y := &services[a], &services[b]
+			if (x.AvailableCode == 200) != (y.AvailableCode == 200) {
+				return x.AvailableCode == 200
+			}
+
+			switch mode {
+			case request.ShippingRateSortCheapest:
+				if x.PayablePrice() != y.PayablePrice() {
+					return x.PayablePrice() < y.PayablePrice()
+				}
+				return x.AverageEtd() < y.AverageEtd()
+			case request.ShippingRateSortFastest:
+				if x.AverageEtd() != y.AverageEtd() {
+					return x.AverageEtd() < y.AverageEtd()
+				}
+				return x.PayablePrice() < y.PayablePrice()
+			default:
+				return x.RecommendationScore > y.RecommendationScore
+			}
+		})
+	}
+}
+
 func (s *shippingServiceImpl) populateCreateDelivery(input *request.CreateDelivery) (*entity.CourierService, *entity.OrderShipping, *entity.ShippingCourierStatus, *entity.ShippingCourierStatus, message.Message) {
 	logger := log.With(s.logger, "ShippingService", "PopulateCreateDelivery")
 
@@ -474,7 +1144,7 @@ func (s *shippingServiceImpl) populateCreateDelivery(input *request.CreateDelive
 		return nil, nil, nil, nil, message.CourierServiceNotFoundMsg
 	}
 
-	if msg := courierService.Validate(input.Package.TotalWeight, input.Package.ContainPrescription > 0); msg != message.SuccessMsg {
+	if msg := courierService.Validate(createDeliveryShipment(input)); msg != message.SuccessMsg {
 		return nil, nil, nil, nil, msg
 	}
 
@@ -500,6 +1170,14 @@ func (s *shippingServiceImpl) populateCreateDelivery(input *request.CreateDelive
 		return nil, nil, nil, nil, message.ShippingStatusNotFoundMsg
 	}
 
+	var quote *entity.ShippingRateQuote
+	if input.QuoteID != "" {
+		var msg message.Message
+		if quote, msg = s.claimRateQuote(logger, channel.ID, input); msg != message.SuccessMsg {
+			return nil, nil, nil, nil, msg
+		}
+	}
+
 	// if order no doesn't exist create new one
 	if orderShipping == nil {
 		orderShipping = &entity.OrderShipping{}
@@ -508,66 +1186,431 @@ func (s *shippingServiceImpl) populateCreateDelivery(input *request.CreateDelive
 		orderShipping.CourierID = courierService.CourierID
 		orderShipping.CourierServiceID = courierService.ID
 	}
+
+	if quote != nil {
+		orderShipping.RateQuote = quote
+		orderShipping.RateQuoteUID = quote.UID
+		orderShipping.QuotedShippingCost = quote.OriginalPrice
+	}
 	orderShipping.UpdatedBy = input.Username
 	return courierService, orderShipping, createdStatus, requestPickupStatus, message.SuccessMsg
 }
 
-// swagger:operation POST /shipping/order-shipping Shipping CreateDelivery
-// Create Order Shipping
-//
-// Description :
-//
-// ---
-//
-// responses:
-//   '200':
-//     description: Success Response.
-//     schema:
-//       properties:
-//         meta:
-//            $ref: '#/definitions/MetaResponse'
-//         data:
-//           properties:
-//             record:
-//               $ref: '#/definitions/CreateDeliveryResponse'
-func (s *shippingServiceImpl) CreateDelivery(input *request.CreateDelivery) (*response.CreateDelivery, message.Message) {
-	logger := log.With(s.logger, "ShippingService", "CreateDelivery")
+// swagger:operation POST /shipping/order-shipping Shipping CreateDelivery
+// Create Order Shipping
+//
+// Description :
+//
+// ---
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//            $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/CreateDeliveryResponse'
+func (s *shippingServiceImpl) CreateDelivery(ctx context.Context, input *request.CreateDelivery) (*response.CreateDelivery, message.Message) {
+	if len(input.IdempotencyKey) > 0 {
+		return s.createDeliveryIdempotent(ctx, input)
+	}
+
+	courierService, orderShipping, created, requestPickup, msg := s.populateCreateDelivery(input)
+	if msg != message.SuccessMsg {
+		return &response.CreateDelivery{}, msg
+	}
+
+	_, msg = s.createDelivery(ctx, orderShipping, courierService, input, nil)
+	if msg != message.SuccessMsg {
+		s.releaseRateQuote(orderShipping)
+		return &response.CreateDelivery{}, msg
+	}
+
+	s.applyShippingPromotion(orderShipping, courierService, input)
+	s.estimateDeliveryDate(orderShipping, courierService, input)
+
+	return s.saveCreatedOrderShipping(orderShipping, created, requestPickup, input)
+}
+
+// used when setting.idempotency-key-lease is not configured
+const defaultIdempotencyKeyLease = 5 * time.Minute
+
+// the key is saved again before giving up, the booking made by the provider must not be lost
+const idempotencyKeyUpdateAttempts = 3
+
+func idempotencyKeyLease() time.Time {
+	lease := viper.GetDuration("setting.idempotency-key-lease")
+	if lease <= 0 {
+		lease = defaultIdempotencyKeyLease
+	}
+
+	return time.Now().Add(lease)
+}
+
+// createDeliveryIdempotent replays the stored result of a retried request.
+// The provider booking is kept before saving the order, so a retry after a failed save
+// does not book the same order twice at the provider.
+func (s *shippingServiceImpl) createDeliveryIdempotent(ctx context.Context, input *request.CreateDelivery) (*response.CreateDelivery, message.Message) {
+	logger := log.With(s.logger, "ShippingService", "CreateDeliveryIdempotent")
+	requestHash := input.Hash()
+
+	idempotencyKey, err := s.idempotencyKeyRepo.FindByKey(input.IdempotencyKey)
+	if err != nil {
+		_ = level.Error(logger).Log("s.idempotencyKeyRepo.FindByKey", err.Error())
+		return &response.CreateDelivery{}, message.ErrDB
+	}
+
+	var booking *response.CreateDeliveryThirdPartyData
+	if idempotencyKey != nil {
+		if idempotencyKey.RequestHash != requestHash {
+			return &response.CreateDelivery{}, message.ErrIdempotencyKeyConflict
+		}
+
+		switch idempotencyKey.Status {
+		case entity.IdempotencyStatusCompleted:
+			resp := &response.CreateDelivery{}
+			if err := json.Unmarshal(idempotencyKey.ResponseData, resp); err != nil {
+				_ = level.Error(logger).Log("json.Unmarshal", err.Error())
+				return &response.CreateDelivery{}, message.ErrDB
+			}
+			return resp, message.SuccessMsg
+
+		case entity.IdempotencyStatusBooked:
+			if len(idempotencyKey.BookingData) > 0 {
+				booking = &response.CreateDeliveryThirdPartyData{}
+				if err := json.Unmarshal(idempotencyKey.BookingData, booking); err != nil {
+					_ = level.Error(logger).Log("json.Unmarshal", err.Error())
+					return &response.CreateDelivery{}, message.ErrDB
+				}
+			}
+		}
+
+		if idempotencyKey.Locked(time.Now()) {
+			return &response.CreateDelivery{}, message.ErrIdempotencyKeyInProgress
+		}
+
+		// the request processing the key stopped, a concurrent retry may have taken it over first
+		taken, err := s.idempotencyKeyRepo.TakeOver(idempotencyKey, idempotencyKeyLease())
+		if err != nil {
+			_ = level.Error(logger).Log("s.idempotencyKeyRepo.TakeOver", err.Error())
+			return &response.CreateDelivery{}, message.ErrDB
+		}
+
+		if !taken {
+			return &response.CreateDelivery{}, message.ErrIdempotencyKeyInProgress
+		}
+
+		// the request stopped after saving the order, the saved order is the result of the key
+		if resp, msg := s.completeSavedOrderShipping(logger, idempotencyKey, input); resp != nil || msg != message.SuccessMsg {
+			return resp, msg
+		}
+	} else {
+		// the unique key makes a concurrent request with the same key fail here
+		lockedUntil := idempotencyKeyLease()
+		idempotencyKey, err = s.idempotencyKeyRepo.Create(&entity.IdempotencyKey{
+			Key:         input.IdempotencyKey,
+			RequestHash: requestHash,
+			Status:      entity.IdempotencyStatusProcessing,
+			LockedUntil: &lockedUntil,
+		})
+		if errors.Is(err, repository.ErrIdempotencyKeyTaken) {
+			return &response.CreateDelivery{}, message.ErrIdempotencyKeyInProgress
+		}
+
+		if err != nil {
+			_ = level.Error(logger).Log("s.idempotencyKeyRepo.Create", err.Error())
+			return &response.CreateDelivery{}, message.ErrDB
+		}
+	}
+
+	courierService, orderShipping, created, requestPickup, msg := s.populateCreateDelivery(input)
+	if msg != message.SuccessMsg {
+		s.releaseIdempotencyKey(logger, idempotencyKey)
+		return &response.CreateDelivery{}, msg
+	}
+
+	orderData, msg := s.createDelivery(ctx, orderShipping, courierService, input, booking)
+	if msg != message.SuccessMsg {
+		s.releaseIdempotencyKey(logger, idempotencyKey)
+		s.releaseRateQuote(orderShipping)
+		return &response.CreateDelivery{}, msg
+	}
+
+	// the order is still saved when the booking can not be kept with the key, the saved order keeps the booking
+	// and a retry after the lease finds the order instead of booking again
+	if idempotencyKey.Status != entity.IdempotencyStatusBooked {
+		idempotencyKey.Status = entity.IdempotencyStatusBooked
+		if orderData != nil {
+			idempotencyKey.BookingData, _ = json.Marshal(orderData)
+		}
+
+		s.updateIdempotencyKey(logger, idempotencyKey)
+	}
+
+	s.applyShippingPromotion(orderShipping, courierService, input)
+	s.estimateDeliveryDate(orderShipping, courierService, input)
+
+	resp, msg := s.saveCreatedOrderShipping(orderShipping, created, requestPickup, input)
+	if msg != message.SuccessMsg {
+		return resp, msg
+	}
+
+	idempotencyKey.Status = entity.IdempotencyStatusCompleted
+	idempotencyKey.ResponseData, _ = json.Marshal(resp)
+	s.updateIdempotencyKey(logger, idempotencyKey)
+
+	return resp, message.SuccessMsg
+}
+
+// completeSavedOrderShipping completes the key with the order saved by the request that stopped before completing it,
+// no response is returned when the order has not been saved
+func (s *shippingServiceImpl) completeSavedOrderShipping(logger log.Logger, idempotencyKey *entity.IdempotencyKey, input *request.CreateDelivery) (*response.CreateDelivery, message.Message) {
+	orderShipping, err := s.orderShipping.FindByOrderNo(input.OrderNo)
+	if err != nil {
+		_ = level.Error(logger).Log("s.orderShipping.FindByOrderNo", err.Error())
+		return &response.CreateDelivery{}, message.ErrDB
+	}
+
+	if orderShipping == nil {
+		return nil, message.SuccessMsg
+	}
+
+	resp := &response.CreateDelivery{
+		OrderNoAPI:       input.OrderNo,
+		OrderShippingUID: orderShipping.UID,
+	}
+
+	idempotencyKey.Status = entity.IdempotencyStatusCompleted
+	idempotencyKey.ResponseData, _ = json.Marshal(resp)
+	s.updateIdempotencyKey(logger, idempotencyKey)
+
+	return resp, message.SuccessMsg
+}
+
+func (s *shippingServiceImpl) updateIdempotencyKey(logger log.Logger, idempotencyKey *entity.IdempotencyKey) {
+	for i := 1; i <= idempotencyKeyUpdateAttempts; i++ {
+		err := s.idempotencyKeyRepo.Update(idempotencyKey)
+		if err == nil {
+			return
+		}
+
+		_ = level.Error(logger).Log("s.idempotencyKeyRepo.Update", err.Error(), "attempt", i)
+	}
+}
+
+// nothing has been booked yet, release the key so the request can be retried
+func (s *shippingServiceImpl) releaseIdempotencyKey(logger log.Logger, idempotencyKey *entity.IdempotencyKey) {
+	if idempotencyKey.Status != entity.IdempotencyStatusProcessing {
+		return
+	}
+
+	if err := s.idempotencyKeyRepo.Delete(idempotencyKey.ID); err != nil {
+		_ = level.Error(logger).Log("s.idempotencyKeyRepo.Delete", err.Error())
+	}
+}
+
+func (s *shippingServiceImpl) saveCreatedOrderShipping(orderShipping *entity.OrderShipping, created, requestPickup *entity.ShippingCourierStatus, input *request.CreateDelivery) (*response.CreateDelivery, message.Message) {
+	logger := log.With(s.logger, "ShippingService", "CreateDelivery")
+
+	orderShipping.AddHistoryStatus(created, fmt.Sprintf("Booking ID [%s]", orderShipping.BookingID))
+
+	if orderShipping.Status == shipping_provider.StatusRequestPickup {
+		orderShipping.AddHistoryStatus(requestPickup, fmt.Sprintf("Pickup Code [%s]", *orderShipping.PickupCode))
+	}
+
+	saved, err := s.orderShipping.Upsert(orderShipping)
+	if err != nil {
+		_ = level.Error(logger).Log("", err.Error())
+		s.releaseShippingPromotion(logger, orderShipping)
+		s.releaseRateQuote(orderShipping)
+		return &response.CreateDelivery{}, message.ErrSaveOrderShipping
+	}
+	orderShipping = saved
+
+	return &response.CreateDelivery{
+		OrderNoAPI:       input.OrderNo,
+		OrderShippingUID: orderShipping.UID,
+	}, message.SuccessMsg
+}
+
+// claimRateQuote binds the quote of the shipping rate to the order,
+// the quote must be given for the same package and must not be expired or claimed by another order
+func (s *shippingServiceImpl) claimRateQuote(logger log.Logger, channelID uint64, input *request.CreateDelivery) (*entity.ShippingRateQuote, message.Message) {
+	quote, err := s.shippingRateQuoteRepo.FindByUID(input.QuoteID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingRateQuoteRepo.FindByUID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if quote == nil {
+		return nil, message.ErrRateQuoteNotFound
+	}
+
+	now := time.Now().In(util.Loc)
+	if msg := quote.Validate(channelID, input, now); msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	claimed, err := s.shippingRateQuoteRepo.Claim(quote.UID, input.OrderNo, now)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingRateQuoteRepo.Claim", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if !claimed {
+		return nil, message.ErrRateQuoteUsed
+	}
+
+	return quote, message.SuccessMsg
+}
+
+// nothing has been booked with the quote, it can be used by another order
+func (s *shippingServiceImpl) releaseRateQuote(orderShipping *entity.OrderShipping) {
+	if orderShipping.RateQuoteUID == "" {
+		return
+	}
+
+	if err := s.shippingRateQuoteRepo.Release(orderShipping.RateQuoteUID, orderShipping.OrderNo); err != nil {
+		_ = level.Error(s.logger).Log("s.shippingRateQuoteRepo.Release", err.Error())
+	}
+}
+
+// applyShippingPromotion gives the booked order the discount shown by the shipping rate. An order booked with a quote
+// gets the discount and promotion of the quote, otherwise the best promotion is evaluated on the price of the channel
+// price rules. The order is booked without a discount when the budget of the promotion runs out in the meantime.
+func (s *shippingServiceImpl) applyShippingPromotion(orderShipping *entity.OrderShipping, courierService *entity.CourierService, input *request.CreateDelivery) {
+	logger := log.With(s.logger, "ShippingService", "ApplyShippingPromotion")
+
+	var promotion *entity.ShippingPromotion
+	var discount float64
+	if orderShipping.RateQuote != nil {
+		promotion, discount = s.quotedShippingPromotion(logger, orderShipping.RateQuote)
+	} else {
+		promotion, discount = s.bestShippingPromotion(logger, orderShipping, courierService, input)
+	}
+
+	if promotion == nil {
+		return
+	}
+
+	// the discount is given on the shipping price, it does not cover the insurance of the order
+	discount = math.Min(discount, orderShipping.ShippingCost)
+
+	reserved, err := s.shippingPromotionRepo.ReserveBudget(promotion.ID, discount)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingPromotionRepo.ReserveBudget", err.Error())
+		return
+	}
+
+	if !reserved {
+		_ = level.Warn(logger).Log("promotion", promotion.UID, "order_no", orderShipping.OrderNo, "msg", "budget exhausted")
+		return
+	}
+
+	orderShipping.ApplyShippingPromotion(promotion, discount)
+}
+
+func (s *shippingServiceImpl) quotedShippingPromotion(logger log.Logger, quote *entity.ShippingRateQuote) (*entity.ShippingPromotion, float64) {
+	if quote.ShippingPromotionUID == "" || quote.ShippingDiscount <= 0 {
+		return nil, 0
+	}
 
-	courierService, orderShipping, created, requestPickup, msg := s.populateCreateDelivery(input)
-	if msg != message.SuccessMsg {
-		return &response.CreateDelivery{}, msg
+	promotion, err := s.shippingPromotionRepo.FindByUID(quote.ShippingPromotionUID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingPromotionRepo.FindByUID", err.Error())
+		return nil, 0
 	}
 
-	msg = s.createDelivery(orderShipping, courierService, input)
-	if msg != message.SuccessMsg {
-		return &response.CreateDelivery{}, msg
+	// the promotion may have ended or been disabled since the quote
+	if promotion == nil || !promotion.IsActive(time.Now().In(util.Loc)) {
+		return nil, 0
 	}
 
-	orderShipping.AddHistoryStatus(created, fmt.Sprintf("Booking ID [%s]", orderShipping.BookingID))
+	return promotion, quote.ShippingDiscount
+}
 
-	if orderShipping.Status == shipping_provider.StatusRequestPickup {
-		orderShipping.AddHistoryStatus(requestPickup, fmt.Sprintf("Pickup Code [%s]", *orderShipping.PickupCode))
+func (s *shippingServiceImpl) bestShippingPromotion(logger log.Logger, orderShipping *entity.OrderShipping, courierService *entity.CourierService, input *request.CreateDelivery) (*entity.ShippingPromotion, float64) {
+	at := time.Now().In(util.Loc)
+	promotions, err := s.shippingPromotionRepo.FindActiveByChannelID(orderShipping.ChannelID, at)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingPromotionRepo.FindActiveByChannelID", err.Error())
+		return nil, 0
+	}
+
+	if len(promotions) == 0 {
+		return nil, 0
 	}
 
-	orderShipping, err := s.orderShipping.Upsert(orderShipping)
+	// the promotion is evaluated on the customer price, the same way as the shipping rate
+	price := orderShipping.ShippingCost
+	priceRules, err := s.channelPriceRuleRepo.FindActiveByChannelID(orderShipping.ChannelID)
 	if err != nil {
-		_ = level.Error(logger).Log("", err.Error())
-		return &response.CreateDelivery{}, message.ErrSaveOrderShipping
+		_ = level.Error(logger).Log("s.channelPriceRuleRepo.FindActiveByChannelID", err.Error())
+		return nil, 0
+	}
+
+	price, _ = entity.ApplyPriceRules(priceRules, &entity.PricedShipment{
+		CourierID:        courierService.CourierID,
+		CourierServiceID: courierService.ID,
+		ShippingType:     courierService.ShippingType,
+		PostalCode:       input.Destination.PostalCode,
+		FinalWeight:      orderShipping.TotalFinalWeight,
+	}, price)
+
+	return entity.BestShippingPromotion(promotions, &entity.PromotedShipment{
+		CourierServiceID:  orderShipping.CourierServiceID,
+		TotalProductPrice: input.Package.TotalProductPrice,
+		PostalCode:        input.Destination.PostalCode,
+		VoucherCode:       input.VoucherCode,
+		At:                at,
+	}, price)
+}
+
+func shippingRatePromotionUID(promotion *response.ShippingRatePromotion) string {
+	if promotion == nil {
+		return ""
 	}
+	return promotion.UID
+}
 
-	return &response.CreateDelivery{
-		OrderNoAPI:       input.OrderNo,
-		OrderShippingUID: orderShipping.UID,
-	}, message.SuccessMsg
+// estimateDeliveryDate keeps the delivery window of the booked order in the timezone of the origin
+func (s *shippingServiceImpl) estimateDeliveryDate(orderShipping *entity.OrderShipping, courierService *entity.CourierService, input *request.CreateDelivery) {
+	logger := log.With(s.logger, "ShippingService", "EstimateDeliveryDate")
+
+	at := time.Now().In(util.LoadLocation(input.Origin.Timezone))
+	calendar := s.deliveryCalendar(logger, []uint64{courierService.CourierID}, at)
+
+	estimate := calendar.Estimate(courierService.CourierID, courierService.StartTime, courierService.ETD_Min, courierService.ETD_Max, at)
+	orderShipping.EstimatedDeliveryFrom = &estimate.From
+	orderShipping.EstimatedDeliveryTo = &estimate.To
+}
+
+// the order shipping was not saved, give the reserved budget back to the promotion
+func (s *shippingServiceImpl) releaseShippingPromotion(logger log.Logger, orderShipping *entity.OrderShipping) {
+	if orderShipping.ShippingPromotionID == nil {
+		return
+	}
+
+	if err := s.shippingPromotionRepo.ReleaseBudget(*orderShipping.ShippingPromotionID, orderShipping.ShippingDiscount); err != nil {
+		_ = level.Error(logger).Log("s.shippingPromotionRepo.ReleaseBudget", err.Error())
+	}
 }
 
-func (s *shippingServiceImpl) createDelivery(orderShipping *entity.OrderShipping, courierService *entity.CourierService, input *request.CreateDelivery) message.Message {
+// a booking kept from a previous attempt is reused instead of booking the order again
+func (s *shippingServiceImpl) createDelivery(ctx context.Context, orderShipping *entity.OrderShipping, courierService *entity.CourierService, input *request.CreateDelivery, booking *response.CreateDeliveryThirdPartyData) (*response.CreateDeliveryThirdPartyData, message.Message) {
 	switch courierService.Courier.CourierType {
 	case shipping_provider.ThirPartyCourier, shipping_provider.AggregatorCourier:
-
-		orderData, msg := s.createDeliveryThirdParty(orderShipping.BookingID, courierService, input)
-		if msg != message.SuccessMsg {
-			return msg
+		orderData := booking
+		if orderData == nil {
+			var msg message.Message
+			orderData, msg = s.createDeliveryThirdParty(ctx, orderShipping.ChannelID, orderShipping.BookingID, courierService, input)
+			if msg != message.SuccessMsg {
+				return nil, msg
+			}
 		}
 
 		if orderShipping.ID == 0 {
@@ -578,27 +1621,120 @@ func (s *shippingServiceImpl) createDelivery(orderShipping *entity.OrderShipping
 			orderShipping.TotalShippingCost = orderData.TotalShippingCost
 			orderShipping.ActualShippingCost = orderData.ActualShippingCost
 			orderShipping.BookingID = orderData.BookingID
+			orderShipping.InsuranceMandatory = courierService.InsuranceMandatory == 1
+			if orderData.Insurance {
+				orderShipping.InsuredValue = input.Package.TotalProductPrice
+			}
+			orderShipping.ApplyCodFee(courierService)
 		}
 		orderShipping.PickupCode = &orderData.PickUpCode
 		orderShipping.Airwaybill = orderData.Airwaybill
 		orderShipping.Status = orderData.Status
+		return orderData, message.SuccessMsg
 
-	default:
-		return message.ErrInvalidCourierType
+	case shipping_provider.InternalCourier, shipping_provider.MerchantCourier:
+		if msg := s.createDeliveryInternal(orderShipping, courierService, input); msg != message.SuccessMsg {
+			return nil, msg
+		}
+		return nil, message.SuccessMsg
+	}
+
+	return nil, message.ErrInvalidCourierType
+}
+
+// internal and merchant couriers are booked locally without calling any third party
+func (s *shippingServiceImpl) createDeliveryInternal(orderShipping *entity.OrderShipping, courierService *entity.CourierService, input *request.CreateDelivery) message.Message {
+	shippingCost, msg := s.internalShippingCost(courierService, input)
+	if msg != message.SuccessMsg {
+		return msg
+	}
+
+	var insuranceCost float64
+	insurance := courierService.InsurancePolicy().Calculate(input.Package.TotalProductPrice, input.UseInsurance)
+	if insurance.Applied {
+		insuranceCost = insurance.Premium
 	}
 
+	orderShipping.CreatedBy = input.Username
+	orderShipping.Insurance = insurance.Applied
+	orderShipping.InsuranceCost = insuranceCost
+	orderShipping.InsuranceMandatory = insurance.Mandatory
+	if insurance.Applied {
+		orderShipping.InsuredValue = insurance.InsuredValue
+	}
+	orderShipping.ShippingCost = shippingCost
+	orderShipping.TotalShippingCost = shippingCost + insuranceCost
+	orderShipping.ActualShippingCost = shippingCost + insuranceCost
+	orderShipping.ApplyCodFee(courierService)
+	orderShipping.BookingID = util.GenerateCode("BK")
+	orderShipping.Airwaybill = util.GenerateCode(courierService.Courier.Code)
+	orderShipping.Status = shipping_provider.StatusCreated
 	return message.SuccessMsg
 }
 
-func (s *shippingServiceImpl) createDeliveryThirdParty(bookingID string, courierService *entity.CourierService, input *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message) {
-	switch courierService.Courier.Code {
-	case shipping_provider.ShipperCode:
-		return s.shipper.CreateDelivery(bookingID, courierService, input)
-	case shipping_provider.GrabCode:
-		return s.grab.CreateDelivery(courierService, input)
-	default:
+// internalShippingCost prices the order the same way the shipping rate does
+func (s *shippingServiceImpl) internalShippingCost(courierService *entity.CourierService, input *request.CreateDelivery) (float64, message.Message) {
+	if courierService.Courier.CourierType == shipping_provider.MerchantCourier {
+		return 0, message.SuccessMsg
+	}
+
+	rateCards, err := s.rateCardRepo.FindEffective([]uint64{courierService.ChannelCourierServiceID}, time.Now().In(util.Loc))
+	if err != nil {
+		_ = level.Error(s.logger).Log("s.rateCardRepo.FindEffective", err.Error())
+		return 0, message.ErrDB
+	}
+
+	rateCard, ok := rateCards[courierService.ChannelCourierServiceID]
+	if !ok {
+		return courierService.PriceInternal, message.SuccessMsg
+	}
+
+	shipment := createDeliveryShipment(input)
+	return rateCard.Calculate(shipment.Distance, shipment.FinalWeight, input.Origin.PostalCode, input.Destination.PostalCode).Total, message.SuccessMsg
+}
+
+// shipmentDistance is 0 when any coordinate is missing or invalid
+func shipmentDistance(originLatitude, originLongitude, destinationLatitude, destinationLongitude string) float64 {
+	var coordinates [4]float64
+	for i, v := range []string{originLatitude, originLongitude, destinationLatitude, destinationLongitude} {
+		value, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0
+		}
+		coordinates[i] = value
+	}
+
+	return util.CalculateDistanceInKm(coordinates[0], coordinates[1], coordinates[2], coordinates[3])
+}
+
+// createDeliveryShipment is checked against the courier service limits when booking,
+// the operating hours in the timezone of the origin like the shipping rate
+func createDeliveryShipment(input *request.CreateDelivery) *entity.Shipment {
+	volumeWeight := util.CalculateVolumeWeightKg(input.Package.TotalHeight, input.Package.TotalWidth, input.Package.TotalLength)
+
+	return &entity.Shipment{
+		FinalWeight:  math.Max(input.Package.TotalWeight, volumeWeight),
+		Volume:       util.CalculateVolume(input.Package.TotalHeight, input.Package.TotalWidth, input.Package.TotalLength),
+		Distance:     shipmentDistance(input.Origin.Latitude, input.Origin.Longitude, input.Destination.Latitude, input.Destination.Longitude),
+		Purchase:     input.Package.TotalProductPrice,
+		COD:          input.COD,
+		Prescription: input.Package.ContainPrescription > 0,
+		At:           time.Now().In(util.LoadLocation(input.Origin.Timezone)),
+	}
+}
+
+func (s *shippingServiceImpl) createDeliveryThirdParty(ctx context.Context, channelID uint64, bookingID string, courierService *entity.CourierService, input *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message) {
+	provider, ok := s.shippingProvider.Get(courierService.Courier.Code)
+	if !ok {
 		return nil, message.ErrInvalidCourierCode
 	}
+
+	credential, msg := s.providerCredential(channelID, courierService.CourierID)
+	if msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	return provider.CreateDelivery(shipping_provider.WithCredential(ctx, credential), bookingID, courierService, input)
 }
 
 // swagger:operation GET /shipping/order-tracking/{uid} Shipping OrderShippingTracking
@@ -621,7 +1757,7 @@ func (s *shippingServiceImpl) createDeliveryThirdParty(bookingID string, courier
 //           properties:
 //             record:
 //               $ref: '#/definitions/GetOrderShippingTrackingResponse'
-func (s *shippingServiceImpl) OrderShippingTracking(req *request.GetOrderShippingTracking) ([]response.GetOrderShippingTracking, message.Message) {
+func (s *shippingServiceImpl) OrderShippingTracking(ctx context.Context, req *request.GetOrderShippingTracking) ([]response.GetOrderShippingTracking, message.Message) {
 	logger := log.With(s.logger, "ShippingService", "OrderShippingTracking")
 
 	if len(req.ChannelUID) == 0 {
@@ -646,7 +1782,9 @@ func (s *shippingServiceImpl) OrderShippingTracking(req *request.GetOrderShippin
 	var msg message.Message
 	switch orderShipping.Courier.CourierType {
 	case shipping_provider.ThirPartyCourier, shipping_provider.AggregatorCourier:
-		orderStatus, msg = s.thridPartyTracking(orderShipping)
+		orderStatus, msg = s.thridPartyTracking(ctx, orderShipping)
+	case shipping_provider.InternalCourier, shipping_provider.MerchantCourier:
+		orderStatus, msg = historyTracking(orderShipping), message.SuccessMsg
 	default:
 		return []response.GetOrderShippingTracking{}, message.ErrInvalidCourierType
 	}
@@ -654,15 +1792,39 @@ func (s *shippingServiceImpl) OrderShippingTracking(req *request.GetOrderShippin
 	return response.SortOrderStatusByTimeDesc(orderStatus), msg
 }
 
-func (s *shippingServiceImpl) thridPartyTracking(orderShipping *entity.OrderShipping) ([]response.GetOrderShippingTracking, message.Message) {
-	switch orderShipping.Courier.Code {
-	case shipping_provider.ShipperCode:
-		return s.shipper.GetTracking(orderShipping.BookingID)
-	case shipping_provider.GrabCode:
-		return s.grab.GetTracking(orderShipping.BookingID)
+// internal and merchant couriers have no tracking API, the timeline is built from order shipping history
+func historyTracking(orderShipping *entity.OrderShipping) []response.GetOrderShippingTracking {
+	resp := []response.GetOrderShippingTracking{}
+	for _, v := range orderShipping.OrderShippingHistory {
+		status := v.StatusCode
+		if v.ShippingCourierStatus != nil && v.ShippingCourierStatus.ShippingStatus != nil {
+			status = util.ReplaceEmptyString(v.ShippingCourierStatus.ShippingStatus.StatusName, status)
+		}
+
+		resp = append(resp, response.GetOrderShippingTracking{
+			DateTime: v.CreatedAt,
+			Status:   status,
+			Note:     v.Note,
+			Date:     v.CreatedAt.In(util.Loc).Format(util.LayoutDateOnly),
+			Time:     v.CreatedAt.In(util.Loc).Format(util.LayoutTimeOnly),
+		})
+	}
+
+	return resp
+}
+
+func (s *shippingServiceImpl) thridPartyTracking(ctx context.Context, orderShipping *entity.OrderShipping) ([]response.GetOrderShippingTracking, message.Message) {
+	provider, ok := s.shippingProvider.Get(orderShipping.Courier.Code)
+	if !ok {
+		return nil, message.ErrInvalidCourierCode
+	}
+
+	credential, msg := s.providerCredential(orderShipping.ChannelID, orderShipping.CourierID)
+	if msg != message.SuccessMsg {
+		return nil, msg
 	}
 
-	return nil, message.ErrInvalidCourierCode
+	return provider.GetTracking(shipping_provider.WithCredential(ctx, credential), orderShipping)
 }
 
 // swagger:operation POST /public/webhook/shipper Public WebhookUpdateStatusShipper
@@ -711,7 +1873,7 @@ func (s *shippingServiceImpl) UpdateStatusShipper(req *request.WebhookUpdateStat
 	statusCode := req.ExternalStatus.Code
 	statusDescription := req.ExternalStatus.Description
 
-	shippingStatus, err := s.shippingCourierStatusRepo.FindByCourierStatus(orderShipping.CourierID, fmt.Sprint(statusCode))
+	shippingStatus, err := s.shippingCourierStatusRepo.FindByCourierStatus(orderShipping.ChannelID, orderShipping.CourierID, fmt.Sprint(statusCode))
 
 	if err != nil {
 		_ = level.Error(logger).Log("s.shippingCourierStatusRepo.FindByCourierStatus", err.Error())
@@ -722,7 +1884,20 @@ func (s *shippingServiceImpl) UpdateStatusShipper(req *request.WebhookUpdateStat
 		return nil, message.ShippingStatusNotFoundMsg
 	}
 
-	orderShipping.Status = shippingStatus.StatusCode
+	msg := s.validateStatusTransition(logger, orderShipping, shippingStatus.StatusCode)
+	if msg == message.ErrGetStatusTransition {
+		return nil, msg
+	}
+
+	// out of order event is kept in history without regressing the current status,
+	// a repeated status is kept in history without publishing it again
+	previousStatus := orderShipping.Status
+	isStatusChanged := msg == message.SuccessMsg && previousStatus != shippingStatus.StatusCode
+	if msg == message.SuccessMsg {
+		orderShipping.Status = shippingStatus.StatusCode
+	} else {
+		_ = level.Info(logger).Log(orderShipping.OrderNo, fmt.Sprintf("skip status %s to %s", orderShipping.Status, shippingStatus.StatusCode))
+	}
 	orderShipping.UpdatedBy = "SHIPPER_WEBHOOK"
 
 	if len(req.Awb) > 0 {
@@ -732,35 +1907,20 @@ func (s *shippingServiceImpl) UpdateStatusShipper(req *request.WebhookUpdateStat
 	driverInfo := SplitDriverInfo(req.External.Description)
 	orderShipping.AddHistoryStatus(shippingStatus, statusDescription, driverInfo.Description())
 
+	if isStatusChanged {
+		addUpdateOrderShippingEvent(orderShipping, shippingStatus, request.UpdateOrderShippingBodyDetail{
+			ExternalStatusCode:        fmt.Sprint(req.ExternalStatus.Code),
+			ExternalStatusName:        req.ExternalStatus.Name,
+			ExternalStatusDescription: req.ExternalStatus.Description,
+		}, driverInfo)
+	}
+
 	orderShipping, err = s.orderShipping.Upsert(orderShipping)
 	if err != nil {
 		_ = level.Error(logger).Log("", err.Error())
 		return nil, message.ErrSaveOrderShipping
 	}
 
-	topic := viper.GetString("dapr.topic.update-order-shipping")
-	topic = strings.ReplaceAll(topic, "{channel-code}", strings.ToLower(orderShipping.Channel.ChannelCode))
-	updateOrderRequest := request.UpdateOrderShippingBody{
-		ChannelUID:         orderShipping.Channel.UID,
-		CourierCode:        orderShipping.Courier.Code,
-		CourierServiceUID:  orderShipping.CourierService.UID,
-		OrderNo:            orderShipping.OrderNo,
-		OrderShippingUID:   orderShipping.UID,
-		Airwaybill:         orderShipping.Airwaybill,
-		ShippingStatus:     shippingStatus.StatusCode,
-		ShippingStatusName: shippingStatus.ShippingStatus.StatusName,
-		UpdatedBy:          "shipping_service",
-		Timestamp:          time.Now(),
-		Details: request.UpdateOrderShippingBodyDetail{
-			ExternalStatusCode:        fmt.Sprint(req.ExternalStatus.Code),
-			ExternalStatusName:        req.ExternalStatus.Name,
-			ExternalStatusDescription: req.ExternalStatus.Description,
-		},
-		DriverInfo: driverInfo,
-	}
-
-	_ = level.Info(logger).Log("PUBLISH_QUEUE, TOPIC", topic)
-	s.daprEndpoint.PublishKafka(topic, updateOrderRequest)
 	return orderShipping, message.SuccessMsg
 }
 
@@ -1001,7 +2161,17 @@ func getOrderShippingDetailByUIDResponse(orderShipping *entity.OrderShipping) *r
 	resp.ShippingCost = orderShipping.ShippingCost
 	resp.Insurance = orderShipping.Insurance
 	resp.InsuranceCost = orderShipping.InsuranceCost
+	resp.InsuredValue = orderShipping.InsuredValue
+	resp.InsuranceMandatory = orderShipping.InsuranceMandatory
 	resp.TotalShippingCost = orderShipping.TotalShippingCost
+	resp.Cod = orderShipping.COD
+	resp.CodAmount = orderShipping.CodAmount
+	resp.CodFee = orderShipping.CodFee
+	resp.ShippingDiscount = orderShipping.ShippingDiscount
+	resp.RateQuoteUID = orderShipping.RateQuoteUID
+	resp.QuotedShippingCost = orderShipping.QuotedShippingCost
+	resp.EstimatedDeliveryFrom = orderShipping.EstimatedDeliveryFrom
+	resp.EstimatedDeliveryTo = orderShipping.EstimatedDeliveryTo
 	resp.ShippingNotes = orderShipping.ShippingNotes
 	resp.MerchantUID = orderShipping.MerchantUID
 	resp.MerchantName = orderShipping.MerchantName
@@ -1068,7 +2238,7 @@ func getOrderShippingDetailByUIDResponse(orderShipping *entity.OrderShipping) *r
 //            $ref: '#/definitions/MetaResponse'
 //         data:
 //           type: object
-func (s *shippingServiceImpl) CancelPickup(req *request.CancelPickup) message.Message {
+func (s *shippingServiceImpl) CancelPickup(ctx context.Context, req *request.CancelPickup) message.Message {
 	logger := log.With(s.logger, "ShippingService", "CancelPickup")
 	orderShipping, err := s.orderShipping.FindByUID(req.UID)
 	if err != nil {
@@ -1091,7 +2261,11 @@ func (s *shippingServiceImpl) CancelPickup(req *request.CancelPickup) message.Me
 		return message.ShippingStatusNotFoundMsg
 	}
 
-	msg := s.cancelPickup(orderShipping)
+	if msg := s.validateStatusTransition(logger, orderShipping, shipping_provider.StatusCreated); msg != message.SuccessMsg {
+		return msg
+	}
+
+	msg := s.cancelPickup(ctx, orderShipping)
 
 	if msg != message.SuccessMsg {
 		return msg
@@ -1112,31 +2286,32 @@ func (s *shippingServiceImpl) CancelPickup(req *request.CancelPickup) message.Me
 	return message.SuccessMsg
 }
 
-func (s *shippingServiceImpl) cancelPickup(orderShipping *entity.OrderShipping) message.Message {
+func (s *shippingServiceImpl) cancelPickup(ctx context.Context, orderShipping *entity.OrderShipping) message.Message {
 	switch orderShipping.Courier.CourierType {
 	case shipping_provider.ThirPartyCourier, shipping_provider.AggregatorCourier:
-		return s.cancelPickupThirdParty(orderShipping)
+		return s.cancelPickupThirdParty(ctx, orderShipping)
 	}
 
 	return message.ErrInvalidCourierType
 }
 
-func (s *shippingServiceImpl) cancelPickupThirdParty(orderShipping *entity.OrderShipping) message.Message {
+func (s *shippingServiceImpl) cancelPickupThirdParty(ctx context.Context, orderShipping *entity.OrderShipping) message.Message {
+	provider, ok := s.shippingProvider.Get(orderShipping.Courier.Code)
+	if !ok {
+		return message.ErrInvalidCourierCode
+	}
 
 	// check if order current status is cancelable
-	if !shipping_provider.IsPickUpOrderCancelable(orderShipping.Courier.Code, orderShipping.Status) {
+	if !provider.IsPickUpOrderCancelable(orderShipping.Status) {
 		return message.ErrCantCancelOrderShipping
 	}
 
-	var err error
-	switch orderShipping.Courier.Code {
-	case shipping_provider.ShipperCode:
-		_, err = s.shipper.CancelPickupRequest(*orderShipping.PickupCode)
-	case shipping_provider.GrabCode:
-		err = s.grab.CancelDelivery(orderShipping.BookingID)
+	credential, msg := s.providerCredential(orderShipping.ChannelID, orderShipping.CourierID)
+	if msg != message.SuccessMsg {
+		return msg
 	}
 
-	if err != nil {
+	if err := provider.CancelPickup(shipping_provider.WithCredential(ctx, credential), orderShipping); err != nil {
 		return message.ErrCancelPickup
 	}
 
@@ -1159,7 +2334,7 @@ func (s *shippingServiceImpl) cancelPickupThirdParty(orderShipping *entity.Order
 //            $ref: '#/definitions/MetaResponse'
 //         data:
 //           type: object
-func (s *shippingServiceImpl) CancelOrder(req *request.CancelOrder) message.Message {
+func (s *shippingServiceImpl) CancelOrder(ctx context.Context, req *request.CancelOrder) message.Message {
 	logger := log.With(s.logger, "ShippingService", "CancelOrder")
 
 	orderShipping, err := s.orderShipping.FindByUID(req.UID)
@@ -1182,13 +2357,18 @@ func (s *shippingServiceImpl) CancelOrder(req *request.CancelOrder) message.Mess
 		return message.ShippingStatusNotFoundMsg
 	}
 
-	msg := s.cancelOrder(orderShipping, req)
+	if msg := s.validateStatusTransition(logger, orderShipping, shipping_provider.StatusCancelled); msg != message.SuccessMsg {
+		return msg
+	}
+
+	msg := s.cancelOrder(ctx, orderShipping, req)
 
 	if msg != message.SuccessMsg {
 		return msg
 	}
 
 	orderShipping.Status = shipping_provider.StatusCancelled
+	orderShipping.CancelledByChannel = true
 	orderShipping.UpdatedBy = req.Body.Username
 	orderShipping.AddHistoryStatus(shipperStatus, req.Body.Reason)
 
@@ -1201,36 +2381,32 @@ func (s *shippingServiceImpl) CancelOrder(req *request.CancelOrder) message.Mess
 	return message.SuccessMsg
 }
 
-func (s *shippingServiceImpl) cancelOrder(orderShipping *entity.OrderShipping, req *request.CancelOrder) message.Message {
+func (s *shippingServiceImpl) cancelOrder(ctx context.Context, orderShipping *entity.OrderShipping, req *request.CancelOrder) message.Message {
 	switch orderShipping.Courier.CourierType {
 	case shipping_provider.ThirPartyCourier, shipping_provider.AggregatorCourier:
-		return s.cancelOrderThirdParty(orderShipping, req)
+		return s.cancelOrderThirdParty(ctx, orderShipping, req)
 	}
 
 	return message.ErrInvalidCourierType
 }
 
-func (s *shippingServiceImpl) cancelOrderThirdParty(orderShipping *entity.OrderShipping, req *request.CancelOrder) message.Message {
+func (s *shippingServiceImpl) cancelOrderThirdParty(ctx context.Context, orderShipping *entity.OrderShipping, req *request.CancelOrder) message.Message {
+	provider, ok := s.shippingProvider.Get(orderShipping.Courier.Code)
+	if !ok {
+		return message.ErrInvalidCourierCode
+	}
 
 	// check if order current status is cancelable
-	if !shipping_provider.IsOrderCancelable(orderShipping.Courier.Code, orderShipping.Status) {
+	if !provider.IsOrderCancelable(orderShipping.Status) {
 		return message.ErrCantCancelOrderShipping
 	}
 
-	var err error
-	switch orderShipping.Courier.Code {
-	case shipping_provider.ShipperCode:
-		_, err = s.shipper.CancelOrder(orderShipping.BookingID, req)
-	case shipping_provider.GrabCode:
-		// if request pickup has been cancelled then cancel the order
-		if orderShipping.Status == shipping_provider.StatusCreated {
-			return message.SuccessMsg
-		}
-
-		err = s.grab.CancelDelivery(orderShipping.BookingID)
+	credential, msg := s.providerCredential(orderShipping.ChannelID, orderShipping.CourierID)
+	if msg != message.SuccessMsg {
+		return msg
 	}
 
-	if err != nil {
+	if err := provider.CancelOrder(shipping_provider.WithCredential(ctx, credential), orderShipping, req); err != nil {
 		return message.ErrCancelPickup
 	}
 
@@ -1299,7 +2475,12 @@ func getOrderShippingLabelResponse(orderShipping []entity.OrderShipping, isHideP
 			ShippingCost:         v.ShippingCost,
 			Insurance:            v.Insurance,
 			InsuranceCost:        v.InsuranceCost,
+			InsuredValue:         v.InsuredValue,
+			InsuranceMandatory:   v.InsuranceMandatory,
 			TotalShippingCost:    v.TotalShippingCost,
+			Cod:                  v.COD,
+			CodAmount:            v.CodAmount,
+			CodFee:               v.CodFee,
 			ShippingNotes:        v.ShippingNotes,
 			MerchantUID:          v.MerchantUID,
 			MerchantName:         v.MerchantName,
@@ -1361,7 +2542,7 @@ func getOrderShippingLabelResponse(orderShipping []entity.OrderShipping, isHideP
 //           properties:
 //             record:
 //                 $ref: '#/definitions/RepickupOrderResponse'
-func (s *shippingServiceImpl) RepickupOrder(req *request.RepickupOrderRequest) (*response.RepickupOrderResponse, message.Message) {
+func (s *shippingServiceImpl) RepickupOrder(ctx context.Context, req *request.RepickupOrderRequest) (*response.RepickupOrderResponse, message.Message) {
 	logger := log.With(s.logger, "ShippingService", "RepickupOrder")
 	resp := &response.RepickupOrderResponse{}
 	orderShipping, err := s.orderShipping.FindByUID(req.OrderShippingUID)
@@ -1387,9 +2568,18 @@ func (s *shippingServiceImpl) RepickupOrder(req *request.RepickupOrderRequest) (
 		return resp, message.ShippingStatusNotFoundMsg
 	}
 
+	// the cancelled order is told apart from the other terminal status
+	if orderShipping.Status == shipping_provider.StatusCancelled {
+		return resp, message.OrderHasBeenCancelledMsg
+	}
+
+	if msg := s.validateStatusTransition(logger, orderShipping, shipping_provider.StatusRequestPickup); msg != message.SuccessMsg {
+		return resp, msg
+	}
+
 	orderShipping.UpdatedBy = req.Username
 
-	msg := s.repickupOrder(orderShipping)
+	msg := s.repickupOrder(ctx, orderShipping)
 
 	if msg != message.SuccessMsg {
 		return resp, msg
@@ -1423,7 +2613,7 @@ func (s *shippingServiceImpl) RepickupOrder(req *request.RepickupOrderRequest) (
 	}, message.SuccessMsg
 }
 
-func (s *shippingServiceImpl) repickupOrder(orderShipping *entity.OrderShipping) message.Message {
+func (s *shippingServiceImpl) repickupOrder(ctx context.Context, orderShipping *entity.OrderShipping) message.Message {
 
 	if orderShipping.Status == shipping_provider.StatusCancelled {
 		return message.OrderHasBeenCancelledMsg
@@ -1435,38 +2625,47 @@ func (s *shippingServiceImpl) repickupOrder(orderShipping *entity.OrderShipping)
 
 	switch orderShipping.Courier.CourierType {
 	case shipping_provider.ThirPartyCourier, shipping_provider.AggregatorCourier:
-		return s.repickupThirPartyOrder(orderShipping)
+		return s.repickupThirPartyOrder(ctx, orderShipping)
 	}
 	return message.ErrInvalidCourierType
 }
 
-func (s *shippingServiceImpl) repickupThirPartyOrder(orderShipping *entity.OrderShipping) message.Message {
-	switch orderShipping.Courier.Code {
-	case shipping_provider.ShipperCode:
-		result, msg := s.shipper.CreatePickUpOrderWithTimeSlots(orderShipping.BookingID)
-		if msg != message.SuccessMsg {
-			return msg
-		}
-
-		//update pickupCode
-		orderShipping.PickupCode = &result.Data.OrderActivation[0].PickUpCode
-	case shipping_provider.GrabCode:
-		result, msg := s.grab.ReCreateDelivery(orderShipping)
-		if msg != message.SuccessMsg {
-			return msg
-		}
-		orderShipping.ShippingCost = result.ShippingCost
-		orderShipping.TotalShippingCost = result.TotalShippingCost
-		orderShipping.ActualShippingCost = result.ActualShippingCost
-		orderShipping.Status = result.Status
-		orderShipping.BookingID = result.BookingID
-		orderShipping.Airwaybill = result.Airwaybill
-		orderShipping.PickupCode = &result.PickUpCode
-	default:
+func (s *shippingServiceImpl) repickupThirPartyOrder(ctx context.Context, orderShipping *entity.OrderShipping) message.Message {
+	provider, ok := s.shippingProvider.Get(orderShipping.Courier.Code)
+	if !ok {
 		return message.ErrInvalidCourierCode
 	}
 
-	return message.SuccessMsg
+	credential, msg := s.providerCredential(orderShipping.ChannelID, orderShipping.CourierID)
+	if msg != message.SuccessMsg {
+		return msg
+	}
+
+	return provider.RepickupOrder(shipping_provider.WithCredential(ctx, credential), orderShipping)
+}
+
+// providerCredential is the account of the channel at the provider of the courier,
+// nil when the channel books under the account in the config
+func (s *shippingServiceImpl) providerCredential(channelID, courierID uint64) (*shipping_provider.Credential, message.Message) {
+	logger := log.With(s.logger, "ShippingService", "ProviderCredential")
+
+	credential, err := s.credentialRepo.FindByChannelAndCourier(channelID, courierID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.credentialRepo.FindByChannelAndCourier", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if credential == nil {
+		return nil, message.SuccessMsg
+	}
+
+	secret, err := util.AESDecryption(credential.Secret, credentialEncryptionKey())
+	if err != nil {
+		_ = level.Error(logger).Log("util.AESDecryption", err.Error())
+		return nil, message.ProviderCredentialMsg
+	}
+
+	return &shipping_provider.Credential{ClientID: credential.ClientID, Secret: secret}, message.SuccessMsg
 }
 
 // swagger:operation GET /shipping/tracking/{uid} Shipping ShippingTracking
@@ -1489,8 +2688,8 @@ func (s *shippingServiceImpl) repickupThirPartyOrder(orderShipping *entity.Order
 //           properties:
 //             record:
 //               $ref: '#/definitions/GetOrderShippingTrackingResponse'
-func (s *shippingServiceImpl) ShippingTracking(req *request.GetOrderShippingTracking) ([]response.GetOrderShippingTracking, message.Message) {
-	return s.OrderShippingTracking(req)
+func (s *shippingServiceImpl) ShippingTracking(ctx context.Context, req *request.GetOrderShippingTracking) ([]response.GetOrderShippingTracking, message.Message) {
+	return s.OrderShippingTracking(ctx, req)
 }
 
 func updateStatusTopic(channelCode string) string {
@@ -1547,7 +2746,7 @@ func (s *shippingServiceImpl) UpdateStatusGrab(req *request.WebhookUpdateStatusG
 	statusCode := req.Body.Status
 	statusDescription := req.Body.FailedReason
 
-	shippingStatus, err := s.shippingCourierStatusRepo.FindByCourierStatus(orderShipping.CourierID, fmt.Sprint(statusCode))
+	shippingStatus, err := s.shippingCourierStatusRepo.FindByCourierStatus(orderShipping.ChannelID, orderShipping.CourierID, fmt.Sprint(statusCode))
 
 	if err != nil {
 		_ = level.Error(logger).Log("s.shippingCourierStatusRepo.FindByCourierStatus", err.Error())
@@ -1565,15 +2764,136 @@ func (s *shippingServiceImpl) UpdateStatusGrab(req *request.WebhookUpdateStatusG
 		TrackingURL:  req.Body.TrackURL,
 	}
 
-	orderShipping.Status = shippingStatus.StatusCode
+	msg := s.validateStatusTransition(logger, orderShipping, shippingStatus.StatusCode)
+	if msg == message.ErrGetStatusTransition {
+		return msg
+	}
+
+	// out of order event is kept in history without regressing the current status,
+	// a repeated status is kept in history without publishing it again
+	previousStatus := orderShipping.Status
+	isStatusChanged := msg == message.SuccessMsg && previousStatus != shippingStatus.StatusCode
+	if msg == message.SuccessMsg {
+		orderShipping.Status = shippingStatus.StatusCode
+	} else {
+		_ = level.Info(logger).Log(orderShipping.OrderNo, fmt.Sprintf("skip status %s to %s", orderShipping.Status, shippingStatus.StatusCode))
+	}
 	orderShipping.UpdatedBy = "GRAB_WEBHOOK"
 	orderShipping.AddHistoryStatus(shippingStatus, statusDescription, driverInfo.Description())
 
-	orderShipping, err = s.orderShipping.Upsert(orderShipping)
+	if isStatusChanged {
+		addUpdateOrderShippingEvent(orderShipping, shippingStatus, request.UpdateOrderShippingBodyDetail{
+			ExternalStatusCode:        req.Body.Status,
+			ExternalStatusName:        req.Body.Status,
+			ExternalStatusDescription: req.Body.FailedReason,
+		}, driverInfo)
+	}
+
+	_, err = s.orderShipping.Upsert(orderShipping)
 	if err != nil {
 		_ = level.Error(logger).Log("", err.Error())
 		return message.ErrSaveOrderShipping
 	}
+
+	return message.SuccessMsg
+}
+
+// swagger:operation POST /shipping/update-status/{uid} Shipping UpdateStatusOrderShipping
+// Update Status of Internal and Merchant Courier Order
+//
+// Description :
+// Used by merchant self-delivery and internal dispatcher to move the order through shipping statuses
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//            $ref: '#/definitions/MetaResponse'
+//         data:
+//           type: object
+func (s *shippingServiceImpl) UpdateStatusOrderShipping(req *request.UpdateStatusOrderShipping) message.Message {
+	logger := log.With(s.logger, "ShippingService", "UpdateStatusOrderShipping")
+
+	if len(req.Body.ChannelUID) == 0 {
+		return message.ErrChannelUIDRequired
+	}
+
+	if len(req.Body.StatusCode) == 0 {
+		return message.ErrStatusCodeRequired
+	}
+
+	orderShipping, err := s.orderShipping.FindByUID(req.UID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.orderShipping.FindByUID", err.Error())
+		return message.ErrOrderShippingNotFound
+	}
+
+	if orderShipping == nil {
+		return message.ErrOrderShippingNotFound
+	}
+
+	if orderShipping.Channel.UID != req.Body.ChannelUID {
+		return message.ErrOrderBelongToAnotherChannel
+	}
+
+	// third party courier status is only updated by its webhook
+	if orderShipping.Courier.CourierType != shipping_provider.InternalCourier && orderShipping.Courier.CourierType != shipping_provider.MerchantCourier {
+		return message.ErrInvalidCourierType
+	}
+
+	shippingStatus, _ := s.shippingCourierStatusRepo.FindByCode(orderShipping.ChannelID, orderShipping.CourierID, req.Body.StatusCode)
+	if shippingStatus == nil {
+		return message.ShippingStatusNotFoundMsg
+	}
+
+	if msg := s.validateStatusTransition(logger, orderShipping, shippingStatus.StatusCode); msg != message.SuccessMsg {
+		return msg
+	}
+
+	orderShipping.Status = shippingStatus.StatusCode
+	orderShipping.UpdatedBy = req.Body.Username
+	orderShipping.AddHistoryStatus(shippingStatus, req.Body.Notes, req.Body.DriverInfo.Description())
+	addUpdateOrderShippingEvent(orderShipping, shippingStatus, request.UpdateOrderShippingBodyDetail{
+		ExternalStatusCode:        shippingStatus.StatusCode,
+		ExternalStatusName:        shippingStatus.ShippingStatus.StatusName,
+		ExternalStatusDescription: req.Body.Notes,
+	}, req.Body.DriverInfo)
+
+	_, err = s.orderShipping.Upsert(orderShipping)
+	if err != nil {
+		_ = level.Error(logger).Log(req.UID, err.Error())
+		return message.ErrSaveOrderShipping
+	}
+
+	return message.SuccessMsg
+}
+
+// check the order shipping status move against the transition graph of its channel
+func (s *shippingServiceImpl) validateStatusTransition(logger log.Logger, orderShipping *entity.OrderShipping, statusCode string) message.Message {
+	transitions, err := s.statusTransitionRepo.FindByChannelID(orderShipping.ChannelID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.statusTransitionRepo.FindByChannelID", err.Error())
+		return message.ErrGetStatusTransition
+	}
+
+	if transitions.CanTransition(orderShipping.Status, statusCode) {
+		return message.SuccessMsg
+	}
+
+	if transitions.IsTerminal(orderShipping.Status) {
+		return message.ErrStatusIsTerminal
+	}
+
+	return message.ErrInvalidStatusTransition
+}
+
+// queue the status change for the channel topic, it is saved with the order and published by the outbox relay
+func addUpdateOrderShippingEvent(orderShipping *entity.OrderShipping, shippingStatus *entity.ShippingCourierStatus, details request.UpdateOrderShippingBodyDetail, driverInfo request.UpdateOrderShippingDriverInfo) {
 	topic := updateStatusTopic(orderShipping.Channel.ChannelCode)
 	updateOrderRequest := request.UpdateOrderShippingBody{
 		ChannelUID:         orderShipping.Channel.UID,
@@ -1586,17 +2906,12 @@ func (s *shippingServiceImpl) UpdateStatusGrab(req *request.WebhookUpdateStatusG
 		ShippingStatusName: shippingStatus.ShippingStatus.StatusName,
 		UpdatedBy:          "shipping_service",
 		Timestamp:          time.Now(),
-		Details: request.UpdateOrderShippingBodyDetail{
-			ExternalStatusCode:        req.Body.Status,
-			ExternalStatusName:        req.Body.Status,
-			ExternalStatusDescription: req.Body.FailedReason,
-		},
-		DriverInfo: driverInfo,
+		Details:            details,
+		DriverInfo:         driverInfo,
 	}
 
-	_ = level.Info(logger).Log("PUBLISH_QUEUE, TOPIC", topic)
-	s.daprEndpoint.PublishKafka(topic, updateOrderRequest)
-	return message.SuccessMsg
+	payload, _ := json.Marshal(updateOrderRequest)
+	orderShipping.AddOutbox(topic, payload)
 }
 
 /*
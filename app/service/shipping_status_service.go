@@ -0,0 +1,811 @@
+package service
+
+import (
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/app/repository"
+	"go-klikdokter/helper/http_helper/shipping_provider"
+	"go-klikdokter/helper/message"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+type ShippingStatusService interface {
+	GetChannelShippingStatus(channelUID string) (*response.ChannelShippingStatus, message.Message)
+	CreateShippingStatus(req *request.SaveShippingStatus) (*response.ShippingStatus, message.Message)
+	UpdateShippingStatus(req *request.UpdateShippingStatus) (*response.ShippingStatus, message.Message)
+	DeleteShippingStatus(uid string) message.Message
+	CloneShippingStatus(req *request.CloneShippingStatus) (*response.ChannelShippingStatus, message.Message)
+	CreateShippingCourierStatus(req *request.SaveShippingCourierStatus) (*response.ShippingCourierStatus, message.Message)
+	UpdateShippingCourierStatus(req *request.UpdateShippingCourierStatus) (*response.ShippingCourierStatus, message.Message)
+	DeleteShippingCourierStatus(uid string) message.Message
+	GetChannelStatusTransition(channelUID string) (*response.ChannelStatusTransition, message.Message)
+	CreateStatusTransition(req *request.SaveStatusTransition) (*response.StatusTransition, message.Message)
+	DeleteStatusTransition(uid string) message.Message
+}
+
+type shippingStatusServiceImpl struct {
+	logger                    log.Logger
+	baseRepo                  repository.BaseRepository
+	channelRepo               repository.ChannelRepository
+	courierRepo               repository.CourierRepository
+	shippingStatusRepo        repository.ShippingStatusRepository
+	shippingCourierStatusRepo repository.ShippingCourierStatusRepository
+	statusTransitionRepo      repository.ShippingStatusTransitionRepository
+}
+
+func NewShippingStatusService(
+	l log.Logger,
+	br repository.BaseRepository,
+	chr repository.ChannelRepository,
+	cr repository.CourierRepository,
+	ssr repository.ShippingStatusRepository,
+	scsr repository.ShippingCourierStatusRepository,
+	sstr repository.ShippingStatusTransitionRepository,
+) ShippingStatusService {
+	return &shippingStatusServiceImpl{l, br, chr, cr, ssr, scsr, sstr}
+}
+
+// swagger:operation GET /channel/channel-app/{uid}/shipping-status Channel-Apps GetChannelShippingStatus
+// Get Channel Shipping Status
+//
+// Description :
+// Shipping status of the channel and the required status codes it is still missing
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/ChannelShippingStatusResponse'
+func (s *shippingStatusServiceImpl) GetChannelShippingStatus(channelUID string) (*response.ChannelShippingStatus, message.Message) {
+	logger := log.With(s.logger, "ShippingStatusService", "GetChannelShippingStatus")
+
+	channel, err := s.channelRepo.FindByUid(&channelUID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelRepo.FindByUid", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if channel == nil {
+		return nil, message.ErrChannelNotFound
+	}
+
+	return s.channelShippingStatus(logger, channel)
+}
+
+// swagger:operation POST /channel/shipping-status Channel-Apps SaveShippingStatus
+// Add Shipping Status
+//
+// Description :
+//
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/ShippingStatusResponse'
+func (s *shippingStatusServiceImpl) CreateShippingStatus(req *request.SaveShippingStatus) (*response.ShippingStatus, message.Message) {
+	logger := log.With(s.logger, "ShippingStatusService", "CreateShippingStatus")
+
+	if req.Body.ChannelUID == "" {
+		return nil, message.ErrChannelUIDRequired
+	}
+
+	if req.Body.StatusCode == "" {
+		return nil, message.ErrStatusCodeRequired
+	}
+
+	if req.Body.StatusName == "" {
+		return nil, message.ErrReq
+	}
+
+	channel, err := s.channelRepo.FindByUid(&req.Body.ChannelUID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelRepo.FindByUid", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if channel == nil {
+		return nil, message.ErrChannelNotFound
+	}
+
+	if msg := s.validateStatusCode(logger, channel.ID, 0, req.Body.StatusCode); msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	shippingStatus := &entity.ShippingStatus{
+		ChannelID:   channel.ID,
+		StatusCode:  req.Body.StatusCode,
+		StatusName:  req.Body.StatusName,
+		Description: req.Body.Description,
+	}
+	shippingStatus.CreatedBy = req.ActorName
+
+	if err := s.shippingStatusRepo.Save(shippingStatus); err != nil {
+		_ = level.Error(logger).Log("s.shippingStatusRepo.Save", err.Error())
+		return nil, message.ErrSaveData
+	}
+
+	return response.NewShippingStatus(shippingStatus), message.SuccessMsg
+}
+
+// swagger:operation PUT /channel/shipping-status/{uid} Channel-Apps UpdateShippingStatus
+// Update Shipping Status
+//
+// Description :
+// The status code of created, request_pickup and cancelled can not be changed, a renamed status code is carried over to the courier status mappings, status transitions and order shippings of the channel
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/ShippingStatusResponse'
+func (s *shippingStatusServiceImpl) UpdateShippingStatus(req *request.UpdateShippingStatus) (*response.ShippingStatus, message.Message) {
+	logger := log.With(s.logger, "ShippingStatusService", "UpdateShippingStatus")
+
+	if req.Body.StatusCode == "" {
+		return nil, message.ErrStatusCodeRequired
+	}
+
+	if req.Body.StatusName == "" {
+		return nil, message.ErrReq
+	}
+
+	shippingStatus, err := s.shippingStatusRepo.FindByUID(req.UID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingStatusRepo.FindByUID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if shippingStatus == nil {
+		return nil, message.ShippingStatusNotFoundMsg
+	}
+
+	if shippingStatus.StatusCode != req.Body.StatusCode {
+		if isRequiredShippingStatus(shippingStatus.StatusCode) {
+			return nil, message.ErrRequiredShippingStatus
+		}
+
+		if msg := s.validateStatusCode(logger, shippingStatus.ChannelID, shippingStatus.ID, req.Body.StatusCode); msg != message.SuccessMsg {
+			return nil, msg
+		}
+	}
+
+	shippingStatus.StatusCode = req.Body.StatusCode
+	shippingStatus.StatusName = req.Body.StatusName
+	shippingStatus.Description = req.Body.Description
+	shippingStatus.UpdatedBy = req.ActorName
+
+	if err := s.shippingStatusRepo.Save(shippingStatus); err != nil {
+		_ = level.Error(logger).Log("s.shippingStatusRepo.Save", err.Error())
+		return nil, message.ErrSaveData
+	}
+
+	return response.NewShippingStatus(shippingStatus), message.SuccessMsg
+}
+
+// swagger:operation DELETE /channel/shipping-status/{uid} Channel-Apps DeleteShippingStatus
+// Delete Shipping Status
+//
+// Description :
+// created, request_pickup and cancelled can not be deleted, the courier status mappings have to be deleted first
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           type: object
+func (s *shippingStatusServiceImpl) DeleteShippingStatus(uid string) message.Message {
+	logger := log.With(s.logger, "ShippingStatusService", "DeleteShippingStatus")
+
+	shippingStatus, err := s.shippingStatusRepo.FindByUID(uid)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingStatusRepo.FindByUID", err.Error())
+		return message.ErrDB
+	}
+
+	if shippingStatus == nil {
+		return message.ShippingStatusNotFoundMsg
+	}
+
+	if isRequiredShippingStatus(shippingStatus.StatusCode) {
+		return message.ErrRequiredShippingStatus
+	}
+
+	count, err := s.shippingCourierStatusRepo.CountByShippingStatusID(shippingStatus.ID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingCourierStatusRepo.CountByShippingStatusID", err.Error())
+		return message.ErrDB
+	}
+
+	if count > 0 {
+		return message.ErrShippingStatusHasCourierStatus
+	}
+
+	if err := s.shippingStatusRepo.Delete(shippingStatus); err != nil {
+		_ = level.Error(logger).Log("s.shippingStatusRepo.Delete", err.Error())
+		return message.FailedMsg
+	}
+
+	return message.SuccessMsg
+}
+
+// swagger:operation POST /channel/shipping-status/clone Channel-Apps CloneShippingStatus
+// Clone Shipping Status
+//
+// Description :
+// Copy the shipping status, courier status mappings and status transitions of a channel to a channel without any shipping status
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/ChannelShippingStatusResponse'
+func (s *shippingStatusServiceImpl) CloneShippingStatus(req *request.CloneShippingStatus) (*response.ChannelShippingStatus, message.Message) {
+	logger := log.With(s.logger, "ShippingStatusService", "CloneShippingStatus")
+
+	if req.Body.SourceChannelUID == "" || req.Body.TargetChannelUID == "" {
+		return nil, message.ErrChannelUIDRequired
+	}
+
+	if req.Body.SourceChannelUID == req.Body.TargetChannelUID {
+		return nil, message.ErrCloneSameChannel
+	}
+
+	source, err := s.channelRepo.FindByUid(&req.Body.SourceChannelUID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelRepo.FindByUid", err.Error())
+		return nil, message.ErrDB
+	}
+
+	target, err := s.channelRepo.FindByUid(&req.Body.TargetChannelUID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelRepo.FindByUid", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if source == nil || target == nil {
+		return nil, message.ErrChannelNotFound
+	}
+
+	sourceStatuses, err := s.shippingStatusRepo.FindByChannelID(source.ID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingStatusRepo.FindByChannelID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if len(missingShippingStatusCodes(sourceStatuses)) > 0 {
+		return nil, message.ErrChannelShippingStatusIncomplete
+	}
+
+	targetStatuses, err := s.shippingStatusRepo.FindByChannelID(target.ID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingStatusRepo.FindByChannelID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if len(targetStatuses) > 0 {
+		return nil, message.ErrTargetChannelHasShippingStatus
+	}
+
+	if err := s.shippingStatusRepo.CloneChannel(source.ID, target.ID, req.ActorName); err != nil {
+		_ = level.Error(logger).Log("s.shippingStatusRepo.CloneChannel", err.Error())
+		return nil, message.ErrSaveData
+	}
+
+	return s.channelShippingStatus(logger, target)
+}
+
+// swagger:operation POST /channel/shipping-courier-status Channel-Apps SaveShippingCourierStatus
+// Add Courier Status Mapping
+//
+// Description :
+// Map the status codes sent by a courier to a shipping status of the channel
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/ShippingCourierStatusResponse'
+func (s *shippingStatusServiceImpl) CreateShippingCourierStatus(req *request.SaveShippingCourierStatus) (*response.ShippingCourierStatus, message.Message) {
+	logger := log.With(s.logger, "ShippingStatusService", "CreateShippingCourierStatus")
+
+	if req.Body.ShippingStatusUID == "" {
+		return nil, message.ErrShippingStatusUIDRequired
+	}
+
+	statusCourier := cleanCourierStatusCodes(req.Body.StatusCourier)
+	if req.Body.CourierUID == "" || len(statusCourier) == 0 {
+		return nil, message.ErrReq
+	}
+
+	shippingStatus, err := s.shippingStatusRepo.FindByUID(req.Body.ShippingStatusUID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingStatusRepo.FindByUID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if shippingStatus == nil {
+		return nil, message.ShippingStatusNotFoundMsg
+	}
+
+	courier, err := s.courierRepo.FindByUid(&req.Body.CourierUID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.courierRepo.FindByUid", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if courier == nil {
+		return nil, message.ErrCourierNotFound
+	}
+
+	existing, err := s.shippingCourierStatusRepo.FindByShippingStatusID(courier.ID, shippingStatus.ID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingCourierStatusRepo.FindByShippingStatusID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if existing != nil {
+		return nil, message.ErrShippingCourierStatusExists
+	}
+
+	if msg := s.validateCourierStatus(logger, shippingStatus.ChannelID, courier.ID, 0, statusCourier); msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	courierStatus := &entity.ShippingCourierStatus{
+		ShippingStatusID: shippingStatus.ID,
+		CourierID:        courier.ID,
+		StatusCode:       shippingStatus.StatusCode,
+	}
+	courierStatus.SetCourierStatus(statusCourier)
+	courierStatus.CreatedBy = req.ActorName
+
+	if err := s.shippingCourierStatusRepo.Save(courierStatus); err != nil {
+		_ = level.Error(logger).Log("s.shippingCourierStatusRepo.Save", err.Error())
+		return nil, message.ErrSaveData
+	}
+
+	return response.NewShippingCourierStatus(courierStatus, shippingStatus, courier), message.SuccessMsg
+}
+
+// swagger:operation PUT /channel/shipping-courier-status/{uid} Channel-Apps UpdateShippingCourierStatus
+// Update Courier Status Mapping
+//
+// Description :
+// Replace the status codes sent by the courier that are mapped to the shipping status
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/ShippingCourierStatusResponse'
+func (s *shippingStatusServiceImpl) UpdateShippingCourierStatus(req *request.UpdateShippingCourierStatus) (*response.ShippingCourierStatus, message.Message) {
+	logger := log.With(s.logger, "ShippingStatusService", "UpdateShippingCourierStatus")
+
+	statusCourier := cleanCourierStatusCodes(req.Body.StatusCourier)
+	if len(statusCourier) == 0 {
+		return nil, message.ErrReq
+	}
+
+	courierStatus, err := s.shippingCourierStatusRepo.FindByUID(req.UID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingCourierStatusRepo.FindByUID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if courierStatus == nil || courierStatus.ShippingStatus == nil || courierStatus.Courier == nil {
+		return nil, message.ErrShippingCourierStatusNotFound
+	}
+
+	if msg := s.validateCourierStatus(logger, courierStatus.ShippingStatus.ChannelID, courierStatus.CourierID, courierStatus.ID, statusCourier); msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	courierStatus.SetCourierStatus(statusCourier)
+	courierStatus.UpdatedBy = req.ActorName
+
+	if err := s.shippingCourierStatusRepo.Save(courierStatus); err != nil {
+		_ = level.Error(logger).Log("s.shippingCourierStatusRepo.Save", err.Error())
+		return nil, message.ErrSaveData
+	}
+
+	return response.NewShippingCourierStatus(courierStatus, courierStatus.ShippingStatus, courierStatus.Courier), message.SuccessMsg
+}
+
+// swagger:operation DELETE /channel/shipping-courier-status/{uid} Channel-Apps DeleteShippingCourierStatus
+// Delete Courier Status Mapping
+//
+// Description :
+// The mapping of created, request_pickup and cancelled can not be deleted, update it instead
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           type: object
+func (s *shippingStatusServiceImpl) DeleteShippingCourierStatus(uid string) message.Message {
+	logger := log.With(s.logger, "ShippingStatusService", "DeleteShippingCourierStatus")
+
+	courierStatus, err := s.shippingCourierStatusRepo.FindByUID(uid)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingCourierStatusRepo.FindByUID", err.Error())
+		return message.ErrDB
+	}
+
+	if courierStatus == nil {
+		return message.ErrShippingCourierStatusNotFound
+	}
+
+	if isRequiredShippingStatus(courierStatus.StatusCode) {
+		return message.ErrRequiredShippingCourierStatus
+	}
+
+	if err := s.shippingCourierStatusRepo.Delete(courierStatus); err != nil {
+		_ = level.Error(logger).Log("s.shippingCourierStatusRepo.Delete", err.Error())
+		return message.FailedMsg
+	}
+
+	return message.SuccessMsg
+}
+
+// swagger:operation GET /channel/channel-app/{uid}/status-transition Channel-Apps GetChannelStatusTransition
+// Get Channel Status Transition
+//
+// Description :
+// Allowed moves between the shipping status of the channel, a channel without any transition only keeps the orders from leaving delivered and cancelled
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/ChannelStatusTransitionResponse'
+func (s *shippingStatusServiceImpl) GetChannelStatusTransition(channelUID string) (*response.ChannelStatusTransition, message.Message) {
+	logger := log.With(s.logger, "ShippingStatusService", "GetChannelStatusTransition")
+
+	channel, err := s.channelRepo.FindByUid(&channelUID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelRepo.FindByUid", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if channel == nil {
+		return nil, message.ErrChannelNotFound
+	}
+
+	transitions, err := s.statusTransitionRepo.FindByChannelID(channel.ID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.statusTransitionRepo.FindByChannelID", err.Error())
+		return nil, message.ErrGetStatusTransition
+	}
+
+	result := &response.ChannelStatusTransition{
+		ChannelUID:          channel.UID,
+		StatusTransition:    []response.StatusTransition{},
+		Default:             len(transitions) == 0,
+		TerminalStatusCodes: []string{},
+	}
+
+	if result.Default {
+		result.TerminalStatusCodes = append(result.TerminalStatusCodes, entity.DefaultTerminalStatusCodes...)
+	}
+
+	seen := map[string]bool{}
+	for i := range transitions {
+		result.StatusTransition = append(result.StatusTransition, *response.NewStatusTransition(&transitions[i]))
+
+		if code := transitions[i].ToStatusCode; !seen[code] && transitions.IsTerminal(code) {
+			seen[code] = true
+			result.TerminalStatusCodes = append(result.TerminalStatusCodes, code)
+		}
+	}
+
+	return result, message.SuccessMsg
+}
+
+// swagger:operation POST /channel/status-transition Channel-Apps SaveStatusTransition
+// Add Status Transition
+//
+// Description :
+// Allow the orders of the channel to move from a shipping status to another, once a channel has a transition only the configured moves are allowed
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/StatusTransitionResponse'
+func (s *shippingStatusServiceImpl) CreateStatusTransition(req *request.SaveStatusTransition) (*response.StatusTransition, message.Message) {
+	logger := log.With(s.logger, "ShippingStatusService", "CreateStatusTransition")
+
+	if req.Body.ChannelUID == "" {
+		return nil, message.ErrChannelUIDRequired
+	}
+
+	if req.Body.FromStatusCode == "" || req.Body.ToStatusCode == "" {
+		return nil, message.ErrStatusCodeRequired
+	}
+
+	if req.Body.FromStatusCode == req.Body.ToStatusCode {
+		return nil, message.ErrInvalidStatusTransitionCode
+	}
+
+	channel, err := s.channelRepo.FindByUid(&req.Body.ChannelUID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.channelRepo.FindByUid", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if channel == nil {
+		return nil, message.ErrChannelNotFound
+	}
+
+	for _, code := range []string{req.Body.FromStatusCode, req.Body.ToStatusCode} {
+		shippingStatus, err := s.shippingStatusRepo.FindByCode(channel.ID, code)
+		if err != nil {
+			_ = level.Error(logger).Log("s.shippingStatusRepo.FindByCode", err.Error())
+			return nil, message.ErrDB
+		}
+
+		if shippingStatus == nil {
+			return nil, message.ErrInvalidStatusTransitionCode
+		}
+	}
+
+	existing, err := s.statusTransitionRepo.FindByStatusCodes(channel.ID, req.Body.FromStatusCode, req.Body.ToStatusCode)
+	if err != nil {
+		_ = level.Error(logger).Log("s.statusTransitionRepo.FindByStatusCodes", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if existing != nil {
+		return nil, message.ErrStatusTransitionExists
+	}
+
+	transition := &entity.ShippingStatusTransition{
+		ChannelID:      channel.ID,
+		FromStatusCode: req.Body.FromStatusCode,
+		ToStatusCode:   req.Body.ToStatusCode,
+	}
+	transition.CreatedBy = req.ActorName
+
+	if err := s.statusTransitionRepo.Save(transition); err != nil {
+		_ = level.Error(logger).Log("s.statusTransitionRepo.Save", err.Error())
+		return nil, message.ErrSaveData
+	}
+
+	return response.NewStatusTransition(transition), message.SuccessMsg
+}
+
+// swagger:operation DELETE /channel/status-transition/{uid} Channel-Apps DeleteStatusTransition
+// Delete Status Transition
+//
+// Description :
+// The channel is back to the default transitions when its last transition is deleted
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           type: object
+func (s *shippingStatusServiceImpl) DeleteStatusTransition(uid string) message.Message {
+	logger := log.With(s.logger, "ShippingStatusService", "DeleteStatusTransition")
+
+	transition, err := s.statusTransitionRepo.FindByUID(uid)
+	if err != nil {
+		_ = level.Error(logger).Log("s.statusTransitionRepo.FindByUID", err.Error())
+		return message.ErrDB
+	}
+
+	if transition == nil {
+		return message.ErrStatusTransitionNotFound
+	}
+
+	if err := s.statusTransitionRepo.Delete(transition); err != nil {
+		_ = level.Error(logger).Log("s.statusTransitionRepo.Delete", err.Error())
+		return message.FailedMsg
+	}
+
+	return message.SuccessMsg
+}
+
+func (s *shippingStatusServiceImpl) channelShippingStatus(logger log.Logger, channel *entity.Channel) (*response.ChannelShippingStatus, message.Message) {
+	statuses, err := s.shippingStatusRepo.FindByChannelID(channel.ID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingStatusRepo.FindByChannelID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	result := &response.ChannelShippingStatus{
+		ChannelUID:         channel.UID,
+		ShippingStatus:     []response.ShippingStatus{},
+		MissingStatusCodes: missingShippingStatusCodes(statuses),
+	}
+
+	for i := range statuses {
+		result.ShippingStatus = append(result.ShippingStatus, *response.NewShippingStatus(&statuses[i]))
+	}
+
+	return result, message.SuccessMsg
+}
+
+// validateStatusCode checks the status code is not used by another shipping status of the channel
+func (s *shippingStatusServiceImpl) validateStatusCode(logger log.Logger, channelID, shippingStatusID uint64, statusCode string) message.Message {
+	existing, err := s.shippingStatusRepo.FindByCode(channelID, statusCode)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingStatusRepo.FindByCode", err.Error())
+		return message.ErrDB
+	}
+
+	if existing != nil && existing.ID != shippingStatusID {
+		return message.ErrShippingStatusExists
+	}
+
+	return message.SuccessMsg
+}
+
+// validateCourierStatus checks the courier status codes are not mapped to another shipping status of the channel,
+// a courier status has to resolve to a single shipping status
+func (s *shippingStatusServiceImpl) validateCourierStatus(logger log.Logger, channelID, courierID, courierStatusID uint64, statusCourier []string) message.Message {
+	mappings, err := s.shippingCourierStatusRepo.FindByChannelCourier(channelID, courierID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingCourierStatusRepo.FindByChannelCourier", err.Error())
+		return message.ErrDB
+	}
+
+	for i := range mappings {
+		if mappings[i].ID == courierStatusID {
+			continue
+		}
+
+		for _, code := range mappings[i].CourierStatusCodes() {
+			for _, v := range statusCourier {
+				if code == v {
+					return message.ErrCourierStatusMappedToOtherStatus
+				}
+			}
+		}
+	}
+
+	return message.SuccessMsg
+}
+
+func isRequiredShippingStatus(statusCode string) bool {
+	for _, v := range shipping_provider.RequiredShippingStatusCodes {
+		if v == statusCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+func missingShippingStatusCodes(statuses []entity.ShippingStatus) []string {
+	missing := []string{}
+	for _, code := range shipping_provider.RequiredShippingStatusCodes {
+		found := false
+		for _, v := range statuses {
+			if v.StatusCode == code {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			missing = append(missing, code)
+		}
+	}
+
+	return missing
+}
+
+func cleanCourierStatusCodes(statusCourier []string) []string {
+	var result []string
+	seen := map[string]bool{}
+	for _, v := range statusCourier {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+
+		seen[v] = true
+		result = append(result, v)
+	}
+
+	return result
+}
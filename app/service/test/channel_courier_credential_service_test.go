@@ -0,0 +1,102 @@
+package test
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/repository/repository_mock"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/http_helper/shipping_provider"
+	"go-klikdokter/helper/message"
+	"go-klikdokter/pkg/util"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var credentialEncryptionKey = "0123456789abcdef0123456789abcdef"
+
+func credentialChannelCourier(courierCode, courierType string) *entity.ChannelCourier {
+	return &entity.ChannelCourier{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: "channel-courier"},
+		Courier:     &entity.Courier{BaseIDModel: base.BaseIDModel{ID: 2}, Code: courierCode, CourierType: courierType},
+	}
+}
+
+func TestSaveChannelCourierCredential(t *testing.T) {
+	viper.Set("setting.credential-encryption-key", credentialEncryptionKey)
+	defer viper.Set("setting.credential-encryption-key", nil)
+
+	var channelCourierRepo = &repository_mock.ChannelCourierRepositoryMock{Mock: mock.Mock{}}
+	var credentialRepo = &repository_mock.ChannelCourierCredentialRepositoryMock{Mock: mock.Mock{}}
+	var credentialService = service.NewChannelCourierCredentialService(logger, baseRepository, channelCourierRepo, credentialRepo)
+
+	credential := &entity.ChannelCourierCredential{ChannelCourierID: 1, ClientID: "old-client", Secret: "old-secret"}
+	channelCourierRepo.Mock.On("GetChannelCourierByUID", "channel-courier").
+		Return(credentialChannelCourier(shipping_provider.GrabCode, shipping_provider.ThirPartyCourier))
+	credentialRepo.Mock.On("FindByChannelCourierID").Return(credential).Once()
+	credentialRepo.Mock.On("Save").Return(nil).Once()
+
+	req := &request.SaveChannelCourierCredential{UID: "channel-courier"}
+	req.Body.ClientID = " channel-client "
+	req.Body.Secret = "channel-secret"
+	result, msg := credentialService.SaveChannelCourierCredential(req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "channel-client", result.ClientID)
+	assert.Equal(t, shipping_provider.GrabCode, result.CourierCode)
+
+	// the secret is stored encrypted
+	assert.NotEqual(t, "channel-secret", credential.Secret)
+	secret, err := util.AESDecryption(credential.Secret, credentialEncryptionKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "channel-secret", secret)
+}
+
+func TestSaveChannelCourierCredential_ClientIDRequired(t *testing.T) {
+	var channelCourierRepo = &repository_mock.ChannelCourierRepositoryMock{Mock: mock.Mock{}}
+	var credentialRepo = &repository_mock.ChannelCourierCredentialRepositoryMock{Mock: mock.Mock{}}
+	var credentialService = service.NewChannelCourierCredentialService(logger, baseRepository, channelCourierRepo, credentialRepo)
+
+	channelCourierRepo.Mock.On("GetChannelCourierByUID", "channel-courier").
+		Return(credentialChannelCourier(shipping_provider.GrabCode, shipping_provider.ThirPartyCourier))
+
+	req := &request.SaveChannelCourierCredential{UID: "channel-courier"}
+	req.Body.Secret = "channel-secret"
+	result, msg := credentialService.SaveChannelCourierCredential(req)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrProviderClientIDRequired, msg)
+}
+
+func TestSaveChannelCourierCredential_NotSupported(t *testing.T) {
+	var channelCourierRepo = &repository_mock.ChannelCourierRepositoryMock{Mock: mock.Mock{}}
+	var credentialRepo = &repository_mock.ChannelCourierCredentialRepositoryMock{Mock: mock.Mock{}}
+	var credentialService = service.NewChannelCourierCredentialService(logger, baseRepository, channelCourierRepo, credentialRepo)
+
+	channelCourierRepo.Mock.On("GetChannelCourierByUID", "channel-courier").
+		Return(credentialChannelCourier("internal", "internal"))
+
+	req := &request.SaveChannelCourierCredential{UID: "channel-courier"}
+	req.Body.Secret = "channel-secret"
+	result, msg := credentialService.SaveChannelCourierCredential(req)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrProviderCredentialNotSupported, msg)
+}
+
+func TestDeleteChannelCourierCredential_NotFound(t *testing.T) {
+	var channelCourierRepo = &repository_mock.ChannelCourierRepositoryMock{Mock: mock.Mock{}}
+	var credentialRepo = &repository_mock.ChannelCourierCredentialRepositoryMock{Mock: mock.Mock{}}
+	var credentialService = service.NewChannelCourierCredentialService(logger, baseRepository, channelCourierRepo, credentialRepo)
+
+	channelCourierRepo.Mock.On("GetChannelCourierByUID", "channel-courier").
+		Return(credentialChannelCourier(shipping_provider.ShipperCode, shipping_provider.AggregatorCourier))
+	credentialRepo.Mock.On("FindByChannelCourierID").Return(nil).Once()
+
+	msg := credentialService.DeleteChannelCourierCredential("channel-courier")
+
+	assert.Equal(t, message.ErrProviderCredentialNotFound, msg)
+}
@@ -0,0 +1,181 @@
+package test
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/repository/repository_mock"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/message"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var priceRuleCourierServiceRepository = &repository_mock.CourierServiceRepositoryMock{Mock: mock.Mock{}}
+
+var channelPriceRuleService = service.NewChannelPriceRuleService(
+	logger,
+	baseRepository,
+	channelRepository,
+	courierRepository,
+	priceRuleCourierServiceRepository,
+	channelPriceRuleRepository,
+)
+
+var (
+	priceRuleChannelUID        = "price-rule-channel"
+	priceRuleCourierUID        = "price-rule-courier"
+	priceRuleCourierServiceUID = "price-rule-courier-service"
+)
+
+func TestListChannelPriceRule(t *testing.T) {
+	channelRepository.Mock.On("FindByUid", &priceRuleChannelUID).Return(entity.Channel{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: priceRuleChannelUID},
+	}).Once()
+	channelPriceRuleRepository.Mock.On("FindByChannelID").Return([]entity.ChannelPriceRule{
+		{BaseIDModel: base.BaseIDModel{UID: "rule"}, Name: "markup", AdjustmentType: entity.PriceAdjustmentMarkupFixed,
+			Courier: &entity.Courier{BaseIDModel: base.BaseIDModel{UID: priceRuleCourierUID}}},
+	}).Once()
+
+	result, msg := channelPriceRuleService.ListChannelPriceRule(priceRuleChannelUID)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Len(t, result, 1)
+	assert.Equal(t, priceRuleChannelUID, result[0].ChannelUID)
+	assert.Equal(t, priceRuleCourierUID, result[0].CourierUID)
+	assert.Equal(t, int32(1), result[0].Status)
+}
+
+func TestCreateChannelPriceRule(t *testing.T) {
+	channelRepository.Mock.On("FindByUid", &priceRuleChannelUID).Return(entity.Channel{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: priceRuleChannelUID},
+	}).Once()
+	courierRepository.Mock.On("FindByUid", &priceRuleCourierUID).Return(entity.Courier{
+		BaseIDModel: base.BaseIDModel{ID: 2, UID: priceRuleCourierUID},
+	}).Once()
+	priceRuleCourierServiceRepository.Mock.On("FindByUid", &priceRuleCourierServiceUID).Return(entity.CourierService{
+		BaseIDModel: base.BaseIDModel{ID: 3, UID: priceRuleCourierServiceUID},
+		CourierID:   2,
+	}).Once()
+	channelPriceRuleRepository.Mock.On("Save").Return(nil).Once()
+
+	req := &request.SaveChannelPriceRule{Body: request.SaveChannelPriceRuleBody{
+		ChannelUID: priceRuleChannelUID,
+		ChannelPriceRuleBody: request.ChannelPriceRuleBody{
+			Name:              "subsidy",
+			CourierUID:        priceRuleCourierUID,
+			CourierServiceUID: priceRuleCourierServiceUID,
+			AdjustmentType:    entity.PriceAdjustmentDiscountPercentage,
+			AdjustmentValue:   10,
+			RoundTo:           500,
+			Rounding:          entity.RoundingUp,
+		},
+	}}
+	result, msg := channelPriceRuleService.CreateChannelPriceRule(req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, priceRuleChannelUID, result.ChannelUID)
+	assert.Equal(t, priceRuleCourierUID, result.CourierUID)
+	assert.Equal(t, priceRuleCourierServiceUID, result.CourierServiceUID)
+}
+
+func TestCreateChannelPriceRuleCourierServiceOfAnotherCourier(t *testing.T) {
+	channelRepository.Mock.On("FindByUid", &priceRuleChannelUID).Return(entity.Channel{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: priceRuleChannelUID},
+	}).Once()
+	courierRepository.Mock.On("FindByUid", &priceRuleCourierUID).Return(entity.Courier{
+		BaseIDModel: base.BaseIDModel{ID: 2, UID: priceRuleCourierUID},
+	}).Once()
+	priceRuleCourierServiceRepository.Mock.On("FindByUid", &priceRuleCourierServiceUID).Return(entity.CourierService{
+		BaseIDModel: base.BaseIDModel{ID: 3, UID: priceRuleCourierServiceUID},
+		CourierID:   9,
+	}).Once()
+
+	req := &request.SaveChannelPriceRule{Body: request.SaveChannelPriceRuleBody{
+		ChannelUID: priceRuleChannelUID,
+		ChannelPriceRuleBody: request.ChannelPriceRuleBody{
+			Name:              "subsidy",
+			CourierUID:        priceRuleCourierUID,
+			CourierServiceUID: priceRuleCourierServiceUID,
+			AdjustmentType:    entity.PriceAdjustmentDiscountFixed,
+		},
+	}}
+	_, msg := channelPriceRuleService.CreateChannelPriceRule(req)
+
+	assert.Equal(t, message.ErrNoDataCourierService, msg)
+}
+
+func TestCreateChannelPriceRuleInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     request.ChannelPriceRuleBody
+		expected message.Message
+	}{
+		{"name", request.ChannelPriceRuleBody{AdjustmentType: entity.PriceAdjustmentMarkupFixed}, message.ErrReq},
+		{"adjustment type", request.ChannelPriceRuleBody{Name: "rule", AdjustmentType: "surcharge"}, message.ErrInvalidPriceAdjustmentType},
+		{"negative value", request.ChannelPriceRuleBody{Name: "rule", AdjustmentType: entity.PriceAdjustmentMarkupFixed, AdjustmentValue: -1}, message.ErrInvalidPriceRule},
+		{"weight band", request.ChannelPriceRuleBody{Name: "rule", AdjustmentType: entity.PriceAdjustmentMarkupFixed, MinWeight: 5, MaxWeight: 2}, message.ErrInvalidPriceRule},
+		{"rounding", request.ChannelPriceRuleBody{Name: "rule", AdjustmentType: entity.PriceAdjustmentMarkupFixed, Rounding: "ceil"}, message.ErrInvalidPriceRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channelRepository.Mock.On("FindByUid", &priceRuleChannelUID).Return(entity.Channel{
+				BaseIDModel: base.BaseIDModel{ID: 1, UID: priceRuleChannelUID},
+			}).Once()
+
+			req := &request.SaveChannelPriceRule{Body: request.SaveChannelPriceRuleBody{
+				ChannelUID:           priceRuleChannelUID,
+				ChannelPriceRuleBody: tt.body,
+			}}
+			_, msg := channelPriceRuleService.CreateChannelPriceRule(req)
+
+			assert.Equal(t, tt.expected, msg)
+		})
+	}
+}
+
+func TestUpdateChannelPriceRule(t *testing.T) {
+	channelPriceRuleRepository.Mock.On("FindByUID").Return(&entity.ChannelPriceRule{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: "rule"},
+		Channel:     &entity.Channel{BaseIDModel: base.BaseIDModel{UID: priceRuleChannelUID}},
+		CourierID:   new(uint64),
+	}).Once()
+	channelPriceRuleRepository.Mock.On("Save").Return(nil).Once()
+
+	status := int32(0)
+	req := &request.UpdateChannelPriceRule{UID: "rule", Body: request.ChannelPriceRuleBody{
+		Name:            "markup",
+		ShippingType:    "instant",
+		AdjustmentType:  entity.PriceAdjustmentMarkupPercentage,
+		AdjustmentValue: 5,
+		Status:          &status,
+	}}
+	result, msg := channelPriceRuleService.UpdateChannelPriceRule(req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, priceRuleChannelUID, result.ChannelUID)
+	assert.Equal(t, "", result.CourierUID)
+	assert.Equal(t, int32(0), result.Status)
+}
+
+func TestUpdateChannelPriceRuleNotFound(t *testing.T) {
+	channelPriceRuleRepository.Mock.On("FindByUID").Return(nil).Once()
+
+	_, msg := channelPriceRuleService.UpdateChannelPriceRule(&request.UpdateChannelPriceRule{UID: "rule"})
+
+	assert.Equal(t, message.ErrPriceRuleNotFound, msg)
+}
+
+func TestDeleteChannelPriceRule(t *testing.T) {
+	channelPriceRuleRepository.Mock.On("FindByUID").Return(&entity.ChannelPriceRule{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: "rule"},
+	}).Once()
+	channelPriceRuleRepository.Mock.On("Delete").Return(nil).Once()
+
+	msg := channelPriceRuleService.DeleteChannelPriceRule("rule")
+
+	assert.Equal(t, message.SuccessMsg, msg)
+}
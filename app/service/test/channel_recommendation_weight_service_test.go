@@ -0,0 +1,101 @@
+package test
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/message"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var channelRecommendationWeightService = service.NewChannelRecommendationWeightService(
+	logger,
+	baseRepository,
+	channelRepository,
+	channelRecommendationWeightRepository,
+)
+
+var recommendationWeightChannelUID = "recommendation-weight-channel"
+
+func TestGetChannelRecommendationWeight_Default(t *testing.T) {
+	channelRepository.Mock.On("FindByUid", &recommendationWeightChannelUID).Return(entity.Channel{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: recommendationWeightChannelUID},
+	}).Once()
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+
+	result, msg := channelRecommendationWeightService.GetChannelRecommendationWeight(recommendationWeightChannelUID)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, recommendationWeightChannelUID, result.ChannelUID)
+	assert.Equal(t, 0.4, result.PriceWeight)
+	assert.False(t, result.Configured)
+}
+
+func TestGetChannelRecommendationWeight_Configured(t *testing.T) {
+	channelRepository.Mock.On("FindByUid", &recommendationWeightChannelUID).Return(entity.Channel{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: recommendationWeightChannelUID},
+	}).Once()
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(&entity.ChannelRecommendationWeight{
+		ChannelID: 1, EtdWeight: 1,
+	}).Once()
+
+	result, msg := channelRecommendationWeightService.GetChannelRecommendationWeight(recommendationWeightChannelUID)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, float64(0), result.PriceWeight)
+	assert.Equal(t, float64(1), result.EtdWeight)
+	assert.True(t, result.Configured)
+}
+
+func TestGetChannelRecommendationWeight_ChannelNotFound(t *testing.T) {
+	channelRepository.Mock.On("FindByUid", &recommendationWeightChannelUID).Return(nil).Once()
+
+	result, msg := channelRecommendationWeightService.GetChannelRecommendationWeight(recommendationWeightChannelUID)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrChannelNotFound, msg)
+}
+
+func TestSaveChannelRecommendationWeight(t *testing.T) {
+	channelRepository.Mock.On("FindByUid", &recommendationWeightChannelUID).Return(entity.Channel{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: recommendationWeightChannelUID},
+	}).Once()
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	channelRecommendationWeightRepository.Mock.On("Save").Return(nil).Once()
+
+	req := &request.SaveChannelRecommendationWeight{
+		UID:  recommendationWeightChannelUID,
+		Body: request.ChannelRecommendationWeightBody{PriceWeight: 2, ReliabilityWeight: 1},
+	}
+	result, msg := channelRecommendationWeightService.SaveChannelRecommendationWeight(req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, float64(2), result.PriceWeight)
+	assert.Equal(t, float64(1), result.ReliabilityWeight)
+	assert.True(t, result.Configured)
+}
+
+func TestSaveChannelRecommendationWeight_Invalid(t *testing.T) {
+	channelRepository.Mock.On("FindByUid", &recommendationWeightChannelUID).Return(entity.Channel{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: recommendationWeightChannelUID},
+	}).Twice()
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(&entity.ChannelRecommendationWeight{
+		ChannelID: 1, PriceWeight: 1,
+	}).Twice()
+
+	// every weight is zero
+	req := &request.SaveChannelRecommendationWeight{UID: recommendationWeightChannelUID}
+	result, msg := channelRecommendationWeightService.SaveChannelRecommendationWeight(req)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrInvalidRecommendationWeight, msg)
+
+	req.Body = request.ChannelRecommendationWeightBody{PriceWeight: 1, EtdWeight: -1}
+	result, msg = channelRecommendationWeightService.SaveChannelRecommendationWeight(req)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrInvalidRecommendationWeight, msg)
+}
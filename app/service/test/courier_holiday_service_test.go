@@ -0,0 +1,83 @@
+package test
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/message"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var courierHolidayService = service.NewCourierHolidayService(
+	logger,
+	baseRepository,
+	courierRepository,
+	courierHolidayRepository,
+)
+
+var holidayCourierUID = "holiday-courier"
+
+func TestListCourierHoliday(t *testing.T) {
+	courierID := uint64(1)
+	courierRepository.Mock.On("FindByUid", &holidayCourierUID).Return(entity.Courier{
+		BaseIDModel: base.BaseIDModel{ID: courierID, UID: holidayCourierUID},
+	}).Once()
+	courierHolidayRepository.Mock.On("FindByCourierID").Return([]entity.CourierHoliday{
+		{BaseIDModel: base.BaseIDModel{UID: "eid"}, CourierID: &courierID, HolidayDate: time.Date(2022, time.May, 2, 0, 0, 0, 0, time.UTC), Name: "Eid"},
+		{BaseIDModel: base.BaseIDModel{UID: "new-year"}, HolidayDate: time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC), Name: "New Year"},
+	}).Once()
+
+	result, msg := courierHolidayService.ListCourierHoliday(&request.ListCourierHoliday{CourierUID: holidayCourierUID, Year: 2022})
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "2022-05-02", result[0].Date)
+}
+
+func TestCreateCourierHoliday(t *testing.T) {
+	courierRepository.Mock.On("FindByUid", &holidayCourierUID).Return(entity.Courier{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: holidayCourierUID},
+	}).Once()
+	courierHolidayRepository.Mock.On("Save").Return(nil).Once()
+
+	req := &request.SaveCourierHoliday{}
+	req.Body.CourierUID = holidayCourierUID
+	req.Body.Date = "2022-05-02"
+	req.Body.Name = " Eid "
+	result, msg := courierHolidayService.CreateCourierHoliday(req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "2022-05-02", result.Date)
+	assert.Equal(t, "Eid", result.Name)
+}
+
+func TestCreateCourierHoliday_InvalidDate(t *testing.T) {
+	req := &request.SaveCourierHoliday{}
+	req.Body.Date = "02-05-2022"
+	req.Body.Name = "Eid"
+	result, msg := courierHolidayService.CreateCourierHoliday(req)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrInvalidHolidayDate, msg)
+}
+
+func TestCreateCourierHoliday_NameRequired(t *testing.T) {
+	req := &request.SaveCourierHoliday{}
+	req.Body.Date = "2022-05-02"
+	result, msg := courierHolidayService.CreateCourierHoliday(req)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrHolidayNameRequired, msg)
+}
+
+func TestDeleteCourierHoliday_NotFound(t *testing.T) {
+	courierHolidayRepository.Mock.On("FindByUID").Return(nil).Once()
+
+	msg := courierHolidayService.DeleteCourierHoliday("holiday")
+
+	assert.Equal(t, message.ErrCourierHolidayNotFound, msg)
+}
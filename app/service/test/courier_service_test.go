@@ -39,7 +39,7 @@ func TestCreateCourierService(t *testing.T) {
 		ETD_Min:             1,
 		Insurance:           1,
 		InsuranceFee:        1,
-		InsuranceFeeType:    "Test",
+		InsuranceFeeType:    entity.FeeTypeFixed,
 		InsuranceMin:        1,
 		Logo:                "Test",
 		MaxDistance:         1,
@@ -182,7 +182,7 @@ func TestCreateCourierServiceFail(t *testing.T) {
 		ETD_Min:             1,
 		Insurance:           1,
 		InsuranceFee:        1,
-		InsuranceFeeType:    "Test",
+		InsuranceFeeType:    entity.FeeTypeFixed,
 		InsuranceMin:        1,
 		Logo:                "Test",
 		MaxDistance:         1,
@@ -218,6 +218,32 @@ func TestCreateCourierServiceFail(t *testing.T) {
 	assert.Equal(t, message.ErrDataCourierServiceExists.Code, err.Code, codeIsNotCorrect)
 }
 
+func TestCreateCourierServiceInvalidCodFeeType(t *testing.T) {
+	req := request.SaveCourierServiceRequest{
+		CourierUId:   "gj2MZ9CBhcHSNVOLpUeqU",
+		ShippingCode: "string",
+		CodAvailable: 1,
+		CodFeeType:   "Test",
+	}
+
+	_, err := svc.CreateCourierService(req)
+
+	assert.Equal(t, message.ErrInvalidFeeType, err, codeIsNotCorrect)
+}
+
+func TestCreateCourierServiceInvalidInsuranceFeeType(t *testing.T) {
+	req := request.SaveCourierServiceRequest{
+		CourierUId:       "gj2MZ9CBhcHSNVOLpUeqU",
+		ShippingCode:     "string",
+		Insurance:        1,
+		InsuranceFeeType: "percent",
+	}
+
+	_, err := svc.CreateCourierService(req)
+
+	assert.Equal(t, message.ErrInvalidFeeType, err, codeIsNotCorrect)
+}
+
 func TestUpdateCourierServiceFail(t *testing.T) {
 	req := request.UpdateCourierServiceRequest{
 		Uid:                 "DYcO8MEsPJcuPIXlq30-T",
@@ -229,7 +255,7 @@ func TestUpdateCourierServiceFail(t *testing.T) {
 		ETD_Min:             1,
 		Insurance:           1,
 		InsuranceFee:        1,
-		InsuranceFeeType:    "Test",
+		InsuranceFeeType:    entity.FeeTypeFixed,
 		InsuranceMin:        1,
 		Logo:                "Test",
 		MaxDistance:         1,
@@ -0,0 +1,122 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/app/repository/repository_mock"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/http_helper/http_helper_mock"
+	"go-klikdokter/helper/message"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var orderShippingOutboxRepository = &repository_mock.OrderShippingOutboxRepositoryMock{Mock: mock.Mock{}}
+var dapr = &http_helper_mock.DaprEndpointMock{Mock: mock.Mock{}}
+var orderShippingOutboxService = service.NewOrderShippingOutboxService(logger, baseRepository, orderShippingOutboxRepository, dapr)
+
+func pendingOutbox(attempts int) []entity.OrderShippingOutbox {
+	return []entity.OrderShippingOutbox{
+		{
+			Topic:    "queueing.shipment.order-shipping-update.ch",
+			Payload:  []byte(`{"order_no":"001"}`),
+			Status:   entity.OutboxStatusPending,
+			Attempts: attempts,
+		},
+	}
+}
+
+func TestRelayOutboxDelivered(t *testing.T) {
+	outbox := pendingOutbox(0)
+	orderShippingOutboxRepository.Mock.On("ClaimPending").Return(outbox).Once()
+	dapr.Mock.On("PublishKafka").Return(nil).Once()
+	orderShippingOutboxRepository.Mock.On("Update").Return(nil).Once()
+
+	count := orderShippingOutboxService.RelayOutbox(context.Background())
+
+	assert.Equal(t, 1, count)
+	assert.Equal(t, entity.OutboxStatusDelivered, outbox[0].Status)
+	assert.Equal(t, 1, outbox[0].Attempts)
+	assert.NotNil(t, outbox[0].DeliveredAt)
+}
+
+func TestRelayOutboxPublishFailed(t *testing.T) {
+	outbox := pendingOutbox(0)
+	orderShippingOutboxRepository.Mock.On("ClaimPending").Return(outbox).Once()
+	dapr.Mock.On("PublishKafka").Return(errors.New("broker unavailable")).Once()
+	orderShippingOutboxRepository.Mock.On("Update").Return(nil).Once()
+
+	count := orderShippingOutboxService.RelayOutbox(context.Background())
+
+	assert.Equal(t, 1, count)
+	assert.Equal(t, entity.OutboxStatusPending, outbox[0].Status)
+	assert.Equal(t, 1, outbox[0].Attempts)
+	assert.Equal(t, "broker unavailable", outbox[0].LastError)
+	assert.True(t, outbox[0].NextAttemptAt.After(time.Now()))
+	assert.Nil(t, outbox[0].DeliveredAt)
+}
+
+func TestRelayOutboxMaxAttempts(t *testing.T) {
+	outbox := pendingOutbox(9)
+	orderShippingOutboxRepository.Mock.On("ClaimPending").Return(outbox).Once()
+	dapr.Mock.On("PublishKafka").Return(errors.New("broker unavailable")).Once()
+	orderShippingOutboxRepository.Mock.On("Update").Return(nil).Once()
+
+	orderShippingOutboxService.RelayOutbox(context.Background())
+
+	assert.Equal(t, entity.OutboxStatusFailed, outbox[0].Status)
+	assert.Equal(t, 10, outbox[0].Attempts)
+}
+
+func TestRelayOutboxClaimError(t *testing.T) {
+	orderShippingOutboxRepository.Mock.On("ClaimPending").Return(nil, errors.New("")).Once()
+
+	count := orderShippingOutboxService.RelayOutbox(context.Background())
+	assert.Equal(t, 0, count)
+}
+
+func TestRunOutboxRelayStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		service.RunOutboxRelay(ctx, orderShippingOutboxService, time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the relay did not stop when its context was done")
+	}
+}
+
+func TestGetOrderShippingOutboxList(t *testing.T) {
+	orderShippingOutboxRepository.Mock.On("FindByParams").Return([]response.GetOrderShippingOutboxList{
+		{Status: entity.OutboxStatusFailed},
+	}, &base.Pagination{}).Once()
+
+	req := &request.GetOrderShippingOutboxList{Filter: `{"status":["failed"]}`}
+	req.GetFilter()
+	result, pagination, msg := orderShippingOutboxService.GetOrderShippingOutboxList(req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.NotNil(t, pagination)
+	assert.Len(t, result, 1)
+	assert.Equal(t, []string{entity.OutboxStatusFailed}, req.Filters.Status)
+}
+
+func TestGetOrderShippingOutboxListError(t *testing.T) {
+	orderShippingOutboxRepository.Mock.On("FindByParams").Return([]response.GetOrderShippingOutboxList{}, &base.Pagination{}, errors.New("")).Once()
+
+	_, _, msg := orderShippingOutboxService.GetOrderShippingOutboxList(&request.GetOrderShippingOutboxList{})
+	assert.Equal(t, message.ErrNoData, msg)
+}
@@ -0,0 +1,270 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/http_helper/shipping_provider"
+	"go-klikdokter/helper/message"
+	"go-klikdokter/pkg/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var rateCardService = service.NewRateCardService(
+	logger,
+	baseRepository,
+	channelCourierServiceRepo,
+	rateCardRepository,
+)
+
+func rateCardChannelCourierService(uid, courierType string) *entity.ChannelCourierService {
+	return &entity.ChannelCourierService{
+		BaseIDModel: base.BaseIDModel{ID: 20, UID: uid},
+		ChannelCourier: &entity.ChannelCourier{
+			Courier: &entity.Courier{CourierType: courierType},
+		},
+	}
+}
+
+func saveRateCardRequest(uid string) *request.SaveRateCard {
+	return &request.SaveRateCard{
+		UID: uid,
+		Body: request.SaveRateCardBody{
+			EffectiveFrom: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			BaseFare:      5000,
+			MinimumCharge: 10000,
+			DistanceTiers: []request.RateCardTier{{UpTo: 5, Rate: 2000}, {UpTo: 0, Rate: 1000}},
+			WeightTiers:   []request.RateCardTier{{UpTo: 1, Rate: 0}, {UpTo: 0, Rate: 3000}},
+			Zones:         []request.RateCardZone{{Code: "JKT", PostalCodes: []string{"10", "11"}}},
+			ZoneSurcharges: []request.RateCardZoneSurcharge{
+				{DestinationZone: "JKT", Amount: 2500},
+			},
+		},
+	}
+}
+
+func TestCreateRateCard_Success(t *testing.T) {
+	uid := "rate-card-internal"
+	channelCourierServiceRepo.Mock.On("GetChannelCourierServiceByUID", uid).
+		Return(rateCardChannelCourierService(uid, shipping_provider.InternalCourier)).Once()
+	rateCardRepository.Mock.On("Create").Return(nil).Once()
+
+	result, msg := rateCardService.CreateRateCard(saveRateCardRequest(uid))
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Len(t, result.DistanceTiers, 2)
+	assert.Equal(t, "JKT", result.ZoneSurcharges[0].DestinationZone)
+}
+
+func TestCreateRateCard_InternalCourierOnly(t *testing.T) {
+	uid := "rate-card-merchant"
+	channelCourierServiceRepo.Mock.On("GetChannelCourierServiceByUID", uid).
+		Return(rateCardChannelCourierService(uid, shipping_provider.MerchantCourier)).Once()
+
+	result, msg := rateCardService.CreateRateCard(saveRateCardRequest(uid))
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrRateCardInternalCourierOnly, msg)
+}
+
+func TestCreateRateCard_InvalidTier(t *testing.T) {
+	uid := "rate-card-invalid-tier"
+	channelCourierServiceRepo.Mock.On("GetChannelCourierServiceByUID", uid).
+		Return(rateCardChannelCourierService(uid, shipping_provider.InternalCourier)).Once()
+
+	req := saveRateCardRequest(uid)
+	req.Body.DistanceTiers = []request.RateCardTier{{UpTo: 0, Rate: 1000}, {UpTo: 5, Rate: 2000}}
+
+	result, msg := rateCardService.CreateRateCard(req)
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrInvalidRateCardTier, msg)
+}
+
+func TestCreateRateCard_UnknownZone(t *testing.T) {
+	uid := "rate-card-unknown-zone"
+	channelCourierServiceRepo.Mock.On("GetChannelCourierServiceByUID", uid).
+		Return(rateCardChannelCourierService(uid, shipping_provider.InternalCourier)).Once()
+
+	req := saveRateCardRequest(uid)
+	req.Body.ZoneSurcharges = []request.RateCardZoneSurcharge{{OriginZone: "BDG", Amount: 1000}}
+
+	result, msg := rateCardService.CreateRateCard(req)
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrInvalidRateCardZone, msg)
+}
+
+func TestCreateRateCard_InvalidDateRange(t *testing.T) {
+	uid := "rate-card-invalid-date"
+	channelCourierServiceRepo.Mock.On("GetChannelCourierServiceByUID", uid).
+		Return(rateCardChannelCourierService(uid, shipping_provider.InternalCourier)).Once()
+
+	req := saveRateCardRequest(uid)
+	effectiveTo := req.Body.EffectiveFrom
+	req.Body.EffectiveTo = &effectiveTo
+
+	result, msg := rateCardService.CreateRateCard(req)
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrInvalidDateRange, msg)
+}
+
+func TestCreateRateCard_ChannelCourierServiceNotFound(t *testing.T) {
+	uid := "rate-card-not-found"
+	channelCourierServiceRepo.Mock.On("GetChannelCourierServiceByUID", uid).Return(nil).Once()
+
+	result, msg := rateCardService.CreateRateCard(saveRateCardRequest(uid))
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrNoData, msg)
+}
+
+func TestListRateCard(t *testing.T) {
+	uid := "rate-card-list"
+	channelCourierServiceRepo.Mock.On("GetChannelCourierServiceByUID", uid).
+		Return(rateCardChannelCourierService(uid, shipping_provider.InternalCourier)).Once()
+	rateCardRepository.Mock.On("FindByChannelCourierServiceID").Return([]entity.RateCard{
+		{BaseIDModel: base.BaseIDModel{UID: "v2"}, Version: 2},
+		{BaseIDModel: base.BaseIDModel{UID: "v1"}, Version: 1},
+	}).Once()
+
+	result, msg := rateCardService.ListRateCard(uid)
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Len(t, result, 2)
+	assert.Equal(t, 2, result[0].Version)
+}
+
+func TestGetShippingRate_InternalRateCard(t *testing.T) {
+	input := request.GetShippingRateRequest{
+		CourierServiceUID: []string{""},
+		TotalWeight:       3,
+		Origin: request.AreaDetailPayload{
+			PostalCode: "40111",
+			Latitude:   "-6.2",
+			Longitude:  "106.8",
+		},
+		Destination: request.AreaDetailPayload{
+			PostalCode: "10110",
+			Latitude:   "-6.3",
+			Longitude:  "106.8",
+		},
+	}
+
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
+
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	shippingRateQuoteRepository.Mock.On("CreateBatch").Return(nil).Once()
+
+	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
+		Return([]entity.ChannelCourierServiceForShippingRate{
+			{CourierID: 1, CourierCode: "aa", CourierTypeCode: "internal", ShippingTypeCode: "instant", ChannelCourierServiceID: 20,
+				Price: 99999, EtdMin: 1, EtdMax: 2.5,
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+		}).Once()
+
+	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"aa": true}).Twice()
+
+	rateCard := &entity.RateCard{
+		BaseIDModel:             base.BaseIDModel{UID: "rate-card"},
+		ChannelCourierServiceID: 20,
+		Version:                 3,
+		BaseFare:                5000,
+		DistanceTiers:           []byte(`[{"up_to":5,"rate":2000},{"up_to":0,"rate":1000}]`),
+		WeightTiers:             []byte(`[{"up_to":1,"rate":0},{"up_to":0,"rate":3000}]`),
+		Zones:                   []byte(`[{"code":"JKT","postal_codes":["10","11"]}]`),
+		ZoneSurcharges:          []byte(`[{"origin_zone":"","destination_zone":"JKT","amount":2500}]`),
+	}
+	rateCardRepository.Mock.On("FindEffective").Return(map[uint64]*entity.RateCard{20: rateCard}).Once()
+
+	distance := util.CalculateDistanceInKm(-6.2, 106.8, -6.3, 106.8)
+	distanceCharge := util.RoundFloat(5*2000+(distance-5)*1000, 2)
+	total := util.RoundFloat(5000+distanceCharge+2*3000+2500, 2)
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
+	assert.Equal(t, message.SuccessMsg, msg, codeIsNotCorrect)
+
+	svc := result[0].Services[0]
+	assert.Equal(t, total, svc.TotalPrice)
+	assert.Equal(t, util.RoundFloat(total/3, 2), svc.UnitPrice)
+	assert.Equal(t, 1, svc.MinDay)
+	assert.Equal(t, 3, svc.MaxDay)
+	assert.Equal(t, 3, svc.PriceBreakdown.RateCardVersion)
+	assert.Equal(t, distanceCharge, svc.PriceBreakdown.DistanceCharge)
+	assert.Equal(t, float64(6000), svc.PriceBreakdown.WeightCharge)
+	assert.Equal(t, float64(2500), svc.PriceBreakdown.ZoneSurcharge)
+}
+
+func TestGetShippingRate_InternalRateCardMinimumCharge(t *testing.T) {
+	input := request.GetShippingRateRequest{
+		CourierServiceUID: []string{""},
+		TotalWeight:       1,
+	}
+
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
+
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	shippingRateQuoteRepository.Mock.On("CreateBatch").Return(nil).Once()
+
+	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
+		Return([]entity.ChannelCourierServiceForShippingRate{
+			{CourierID: 1, CourierCode: "aa", CourierTypeCode: "internal", ShippingTypeCode: "instant", ChannelCourierServiceID: 21,
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+		}).Once()
+
+	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"aa": true}).Twice()
+
+	rateCardRepository.Mock.On("FindEffective").Return(map[uint64]*entity.RateCard{
+		21: {ChannelCourierServiceID: 21, BaseFare: 3000, MinimumCharge: 8000},
+	}).Once()
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
+	assert.Equal(t, message.SuccessMsg, msg, codeIsNotCorrect)
+	assert.Equal(t, float64(8000), result[0].Services[0].TotalPrice)
+	assert.Equal(t, float64(5000), result[0].Services[0].PriceBreakdown.MinimumChargeAdjustment)
+}
+
+func TestGetShippingRate_InternalRateCardError(t *testing.T) {
+	input := request.GetShippingRateRequest{
+		CourierServiceUID: []string{""},
+		TotalWeight:       1,
+	}
+
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
+
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+
+	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
+		Return([]entity.ChannelCourierServiceForShippingRate{
+			{CourierID: 1, CourierCode: "aa", CourierTypeCode: "internal", ShippingTypeCode: "instant", ChannelCourierServiceID: 22,
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+		}).Once()
+
+	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"aa": true}).Twice()
+
+	rateCardRepository.Mock.On("FindEffective").Return(nil, errors.New("error")).Once()
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
+	assert.Equal(t, message.SuccessMsg, msg, codeIsNotCorrect)
+	assert.Equal(t, 400, result[0].Services[0].AvailableCode)
+	assert.Equal(t, message.ErrDB.Message, result[0].Services[0].Error.Message)
+}
@@ -0,0 +1,108 @@
+package test
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/message"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var shippingPromotionService = service.NewShippingPromotionService(
+	logger,
+	baseRepository,
+	channelRepository,
+	priceRuleCourierServiceRepository,
+	shippingPromotionRepository,
+)
+
+var promotionChannelUID = "promotion-channel"
+
+func TestCreateShippingPromotion(t *testing.T) {
+	channelRepository.Mock.On("FindByUid", &promotionChannelUID).Return(entity.Channel{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: promotionChannelUID},
+	}).Once()
+	shippingPromotionRepository.Mock.On("Save").Return(nil).Once()
+
+	req := &request.SaveShippingPromotion{Body: request.SaveShippingPromotionBody{
+		ChannelUID: promotionChannelUID,
+		ShippingPromotionBody: request.ShippingPromotionBody{
+			Name:                 "free ongkir",
+			VoucherCode:          " ongkir100 ",
+			MinTotalProductPrice: 100000,
+			StartTime:            time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			DiscountType:         entity.PromotionDiscountFreeShipping,
+			BudgetCap:            5000000,
+		},
+	}}
+	result, msg := shippingPromotionService.CreateShippingPromotion(req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, promotionChannelUID, result.ChannelUID)
+	assert.Equal(t, "ONGKIR100", result.VoucherCode)
+	assert.Equal(t, int32(1), result.Status)
+}
+
+func TestCreateShippingPromotionInvalid(t *testing.T) {
+	startTime := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := startTime.Add(-time.Hour)
+	tests := []struct {
+		name     string
+		body     request.ShippingPromotionBody
+		expected message.Message
+	}{
+		{"discount type", request.ShippingPromotionBody{Name: "promo", StartTime: startTime, DiscountType: "cashback"}, message.ErrInvalidPromotionDiscountType},
+		{"start time", request.ShippingPromotionBody{Name: "promo", DiscountType: entity.PromotionDiscountFixed}, message.ErrShippingPromotionStartTimeRequired},
+		{"date range", request.ShippingPromotionBody{Name: "promo", StartTime: startTime, EndTime: &endTime, DiscountType: entity.PromotionDiscountFixed}, message.ErrInvalidDateRange},
+		{"budget", request.ShippingPromotionBody{Name: "promo", StartTime: startTime, DiscountType: entity.PromotionDiscountFixed, BudgetCap: -1}, message.ErrInvalidShippingPromotion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channelRepository.Mock.On("FindByUid", &promotionChannelUID).Return(entity.Channel{
+				BaseIDModel: base.BaseIDModel{ID: 1, UID: promotionChannelUID},
+			}).Once()
+
+			req := &request.SaveShippingPromotion{Body: request.SaveShippingPromotionBody{
+				ChannelUID:            promotionChannelUID,
+				ShippingPromotionBody: tt.body,
+			}}
+			_, msg := shippingPromotionService.CreateShippingPromotion(req)
+
+			assert.Equal(t, tt.expected, msg)
+		})
+	}
+}
+
+func TestUpdateShippingPromotionKeepsUsedBudget(t *testing.T) {
+	shippingPromotionRepository.Mock.On("FindByUID").Return(&entity.ShippingPromotion{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: "promo"},
+		Channel:     &entity.Channel{BaseIDModel: base.BaseIDModel{UID: promotionChannelUID}},
+		UsedBudget:  25000,
+	}).Once()
+	shippingPromotionRepository.Mock.On("Save").Return(nil).Once()
+
+	req := &request.UpdateShippingPromotion{UID: "promo", Body: request.ShippingPromotionBody{
+		Name:          "promo",
+		StartTime:     time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		DiscountType:  entity.PromotionDiscountPercentage,
+		DiscountValue: 50,
+	}}
+	result, msg := shippingPromotionService.UpdateShippingPromotion(req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, promotionChannelUID, result.ChannelUID)
+	assert.Equal(t, float64(25000), result.UsedBudget)
+}
+
+func TestDeleteShippingPromotionNotFound(t *testing.T) {
+	shippingPromotionRepository.Mock.On("FindByUID").Return(nil).Once()
+
+	msg := shippingPromotionService.DeleteShippingPromotion("promo")
+
+	assert.Equal(t, message.ErrShippingPromotionNotFound, msg)
+}
@@ -0,0 +1,365 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/http_helper/shipping_provider"
+	"go-klikdokter/helper/http_helper/shipping_provider/shipping_provider_mock"
+	"go-klikdokter/helper/message"
+	"go-klikdokter/pkg/cache"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var shippingProviderService = service.NewShippingProviderService(logger)
+
+func TestCircuitBreakerProvider_OpenCircuit(t *testing.T) {
+	viper.Set("grab.circuit-breaker.request-volume-threshold", 2)
+	viper.Set("grab.circuit-breaker.sleep-window", time.Hour)
+	defer viper.Set("grab.circuit-breaker", nil)
+
+	breakerGrab := &shipping_provider_mock.GrabMock{Mock: mock.Mock{}}
+	provider := shipping_provider.NewCircuitBreakerProvider(shipping_provider.NewGrabProvider(breakerGrab))
+
+	breakerGrab.Mock.On("GetShippingRate").Return(nil, errors.New("bad gateway")).Twice()
+
+	input := &request.GetShippingRateRequest{}
+	for i := 0; i < 2; i++ {
+		_, err := provider.GetShippingRate(context.Background(), nil, input)
+		assert.Error(t, err)
+	}
+
+	// the failures are counted asynchronously
+	assert.Eventually(t, func() bool {
+		for _, v := range circuitBreakerList(t) {
+			if v.CourierCode == shipping_provider.GrabCode && v.Operation == shipping_provider.OperationRate {
+				return v.State == response.CircuitOpen
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+
+	// grab is not called while the circuit is open
+	resp, err := provider.GetShippingRate(context.Background(), nil, input)
+	assert.Error(t, err)
+	assert.Equal(t, message.ProviderUnavailableMsg, resp.CourierMsg[shipping_provider.GrabCode])
+	breakerGrab.Mock.AssertNumberOfCalls(t, "GetShippingRate", 2)
+
+	// the other operations have their own circuit
+	for _, v := range circuitBreakerList(t) {
+		if v.CourierCode == shipping_provider.GrabCode && v.Operation == shipping_provider.OperationCreate {
+			assert.Equal(t, response.CircuitClosed, v.State)
+		}
+	}
+}
+
+func TestCircuitBreakerProvider_HungProviderOpenCircuit(t *testing.T) {
+	viper.Set("shipper.circuit-breaker.request-volume-threshold", 2)
+	viper.Set("shipper.circuit-breaker.sleep-window", time.Hour)
+	defer viper.Set("shipper.circuit-breaker", nil)
+
+	breakerShipper := &shipping_provider_mock.ShipperMock{Mock: mock.Mock{}}
+	provider := shipping_provider.NewCircuitBreakerProvider(shipping_provider.NewShipperProvider(breakerShipper))
+
+	// shipper answers after the deadline of the caller
+	breakerShipper.Mock.On("GetShippingRate").Return(nil, context.DeadlineExceeded).After(20 * time.Millisecond).Twice()
+
+	input := &request.GetShippingRateRequest{}
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		_, err := provider.GetShippingRate(ctx, nil, input)
+		cancel()
+		assert.Error(t, err)
+	}
+
+	assert.Eventually(t, func() bool {
+		for _, v := range circuitBreakerList(t) {
+			if v.CourierCode == shipping_provider.ShipperCode && v.Operation == shipping_provider.OperationRate {
+				return v.State == response.CircuitOpen
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCircuitBreakerProvider_BusinessAnswerIgnored(t *testing.T) {
+	viper.Set("grab.circuit-breaker.request-volume-threshold", 2)
+	viper.Set("grab.circuit-breaker.sleep-window", time.Hour)
+	defer viper.Set("grab.circuit-breaker", nil)
+
+	var deliveryStatus int32 = http.StatusNotFound
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			_, _ = w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+		case "/deliveries/G-001":
+			w.WriteHeader(int(atomic.LoadInt32(&deliveryStatus)))
+			_, _ = w.Write([]byte(`{"message":"delivery not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	setGrabServer(server.URL)
+	defer setGrabServer("")
+	viper.Set("grab.path.delivery-detail", "/deliveries/{deliveryID}")
+	defer viper.Set("grab.path.delivery-detail", nil)
+
+	redisOff, _ := cache.SetupRedisConnection("", "", 0, "", false, 0)
+	provider := shipping_provider.NewCircuitBreakerProvider(shipping_provider.NewGrabProvider(shipping_provider.NewGrab(logger, redisOff)))
+	orderShipping := &entity.OrderShipping{BookingID: "G-001"}
+
+	trackCircuit := func() string {
+		for _, v := range circuitBreakerList(t) {
+			if v.CourierCode == shipping_provider.GrabCode && v.Operation == shipping_provider.OperationTrack {
+				return v.State
+			}
+		}
+		return ""
+	}
+
+	// grab answers that the delivery is not found, it is not a failure of grab
+	for i := 0; i < 3; i++ {
+		_, msg := provider.GetTracking(context.Background(), orderShipping)
+		assert.Equal(t, message.ErrGetOrderDetail, msg)
+	}
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, response.CircuitClosed, trackCircuit())
+
+	// grab is unavailable
+	atomic.StoreInt32(&deliveryStatus, http.StatusServiceUnavailable)
+	for i := 0; i < 2; i++ {
+		_, msg := provider.GetTracking(context.Background(), orderShipping)
+		assert.Equal(t, message.ErrGetOrderDetail, msg)
+	}
+	assert.Eventually(t, func() bool {
+		return trackCircuit() == response.CircuitOpen
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestGrabToken_CachedAndRefreshed(t *testing.T) {
+	var (
+		authCalls     int32
+		rejectedToken atomic.Value
+	)
+	rejectedToken.Store("")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			n := atomic.AddInt32(&authCalls, 1)
+			_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, n)
+		case "/quotes":
+			if r.Header.Get("Authorization") == rejectedToken.Load().(string) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"quotes":[{"service":{"type":"INSTANT"},"amount":10000}],"packages":[{}]}`))
+		}
+	}))
+	defer server.Close()
+
+	setGrabServer(server.URL)
+	defer setGrabServer("")
+
+	// the token is kept in memory when redis is not active
+	redisOff, _ := cache.SetupRedisConnection("", "", 0, "", false, 0)
+	grabClient := shipping_provider.NewGrab(logger, redisOff)
+
+	input := &request.GetShippingRateRequest{
+		Origin:      request.AreaDetailPayload{Latitude: "1", Longitude: "2"},
+		Destination: request.AreaDetailPayload{Latitude: "1", Longitude: "2"},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := grabClient.GetShippingRate(context.Background(), input)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&authCalls))
+
+	// the rejected token is refreshed and the request is sent again
+	rejectedToken.Store("Bearer token-1")
+	resp, err := grabClient.GetShippingRate(context.Background(), input)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.Rate["grab:instant"].AvailableCode)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&authCalls))
+}
+
+func TestGrabToken_Failed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message":"invalid client"}`))
+	}))
+	defer server.Close()
+
+	setGrabServer(server.URL)
+	defer setGrabServer("")
+
+	redisOff, _ := cache.SetupRedisConnection("", "", 0, "", false, 0)
+	grabClient := shipping_provider.NewGrab(logger, redisOff)
+
+	resp, err := grabClient.GetShippingRate(context.Background(), &request.GetShippingRateRequest{
+		Origin:      request.AreaDetailPayload{Latitude: "1", Longitude: "2"},
+		Destination: request.AreaDetailPayload{Latitude: "1", Longitude: "2"},
+	})
+	assert.EqualError(t, err, "grab token: invalid client")
+	assert.Equal(t, "grab token: invalid client", resp.CourierMsg[shipping_provider.GrabCode].Message)
+}
+
+func TestGrabToken_ChannelCredential(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		clientIDs []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			var req request.GrabAuthRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			mu.Lock()
+			clientIDs = append(clientIDs, req.ClientID)
+			mu.Unlock()
+			_, _ = fmt.Fprintf(w, `{"access_token":"token-%s","token_type":"Bearer","expires_in":3600}`, req.ClientID)
+		case "/quotes":
+			_, _ = w.Write([]byte(`{"quotes":[{"service":{"type":"INSTANT"},"amount":10000}],"packages":[{}]}`))
+		}
+	}))
+	defer server.Close()
+
+	setGrabServer(server.URL)
+	defer setGrabServer("")
+	viper.Set("grab.auth.client-id", "service-client")
+	defer viper.Set("grab.auth.client-id", nil)
+
+	redisOff, _ := cache.SetupRedisConnection("", "", 0, "", false, 0)
+	grabClient := shipping_provider.NewGrab(logger, redisOff)
+
+	input := &request.GetShippingRateRequest{
+		Origin:      request.AreaDetailPayload{Latitude: "1", Longitude: "2"},
+		Destination: request.AreaDetailPayload{Latitude: "1", Longitude: "2"},
+	}
+
+	// every account has its own token
+	channelCtx := shipping_provider.WithCredential(context.Background(), &shipping_provider.Credential{ClientID: "channel-client", Secret: "channel-secret"})
+	for _, ctx := range []context.Context{context.Background(), channelCtx, channelCtx} {
+		_, err := grabClient.GetShippingRate(ctx, input)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"service-client", "channel-client"}, clientIDs)
+}
+
+func TestGrabToken_AccountsRefreshedIndependently(t *testing.T) {
+	slowAuth := make(chan struct{})
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			var req request.GrabAuthRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if req.ClientID == "slow-client" {
+				close(slowAuth)
+				<-release
+			}
+			_, _ = fmt.Fprintf(w, `{"access_token":"token-%s","token_type":"Bearer","expires_in":3600}`, req.ClientID)
+		case "/quotes":
+			_, _ = w.Write([]byte(`{"quotes":[{"service":{"type":"INSTANT"},"amount":10000}],"packages":[{}]}`))
+		}
+	}))
+	defer server.Close()
+
+	setGrabServer(server.URL)
+	defer setGrabServer("")
+
+	redisOff, _ := cache.SetupRedisConnection("", "", 0, "", false, 0)
+	grabClient := shipping_provider.NewGrab(logger, redisOff)
+
+	input := &request.GetShippingRateRequest{
+		Origin:      request.AreaDetailPayload{Latitude: "1", Longitude: "2"},
+		Destination: request.AreaDetailPayload{Latitude: "1", Longitude: "2"},
+	}
+
+	slowCtx := shipping_provider.WithCredential(context.Background(), &shipping_provider.Credential{ClientID: "slow-client", Secret: "secret"})
+	slowDone := make(chan error, 1)
+	go func() {
+		_, err := grabClient.GetShippingRate(slowCtx, input)
+		slowDone <- err
+	}()
+	<-slowAuth
+
+	// the token of another account is not held up by the refresh of the slow account
+	fastCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	fastCtx = shipping_provider.WithCredential(fastCtx, &shipping_provider.Credential{ClientID: "fast-client", Secret: "secret"})
+	_, err := grabClient.GetShippingRate(fastCtx, input)
+	assert.NoError(t, err)
+
+	close(release)
+	assert.NoError(t, <-slowDone)
+}
+
+func TestGrabToken_NotLogged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			_, _ = w.Write([]byte(`{"access_token":"secret-token","token_type":"Bearer","expires_in":3600}`))
+		case "/quotes":
+			_, _ = w.Write([]byte(`{"quotes":[{"service":{"type":"INSTANT"},"amount":10000}],"packages":[{}]}`))
+		}
+	}))
+	defer server.Close()
+
+	setGrabServer(server.URL)
+	defer setGrabServer("")
+
+	var logs bytes.Buffer
+	redisOff, _ := cache.SetupRedisConnection("", "", 0, "", false, 0)
+	grabClient := shipping_provider.NewGrab(log.NewLogfmtLogger(&logs), redisOff)
+
+	input := &request.GetShippingRateRequest{
+		Origin:      request.AreaDetailPayload{Latitude: "1", Longitude: "2"},
+		Destination: request.AreaDetailPayload{Latitude: "1", Longitude: "2"},
+	}
+	ctx := shipping_provider.WithCredential(context.Background(), &shipping_provider.Credential{ClientID: "channel-client", Secret: "channel-secret"})
+	_, err := grabClient.GetShippingRate(ctx, input)
+	assert.NoError(t, err)
+
+	assert.Contains(t, logs.String(), "/auth")
+	assert.NotContains(t, logs.String(), "channel-secret")
+	assert.NotContains(t, logs.String(), "secret-token")
+}
+
+func setGrabServer(url string) {
+	viper.Set("grab.base", url)
+	viper.Set("grab.path.auth", "/auth")
+	viper.Set("grab.path.get-delivery-quote", "/quotes")
+}
+
+func circuitBreakerList(t *testing.T) []response.CircuitBreaker {
+	result, msg := shippingProviderService.GetCircuitBreakerList()
+	assert.Equal(t, message.SuccessMsg, msg)
+	return result
+}
@@ -1,23 +1,27 @@
 package test
 
 import (
+	"context"
 	"errors"
 	"go-klikdokter/app/model/base"
 	"go-klikdokter/app/model/entity"
 	"go-klikdokter/app/model/request"
 	"go-klikdokter/app/model/response"
+	"go-klikdokter/app/repository"
 	"go-klikdokter/app/repository/repository_mock"
 	"go-klikdokter/app/service"
+	"go-klikdokter/helper/global"
 	"testing"
 	"time"
 
-	"go-klikdokter/helper/http_helper/http_helper_mock"
 	"go-klikdokter/helper/http_helper/shipping_provider"
 	"go-klikdokter/helper/http_helper/shipping_provider/shipping_provider_mock"
 	"go-klikdokter/helper/message"
 	"go-klikdokter/pkg/cache/cache_mock"
 	"go-klikdokter/pkg/util"
+	"go-klikdokter/pkg/util/datatype"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -26,8 +30,16 @@ var shippingService service.ShippingService
 var shipper = &shipping_provider_mock.ShipperMock{Mock: mock.Mock{}}
 var redis = &cache_mock.Redis_Mock{Mock: mock.Mock{}}
 var orderShippingRepository = &repository_mock.OrderShippingRepositoryMock{Mock: mock.Mock{}}
-var dapr = &http_helper_mock.DaprEndpointMock{Mock: mock.Mock{}}
 var grab = &shipping_provider_mock.GrabMock{Mock: mock.Mock{}}
+var shippingStatusTransitionRepository = &repository_mock.ShippingStatusTransitionRepositoryMock{Mock: mock.Mock{}}
+var idempotencyKeyRepository = &repository_mock.IdempotencyKeyRepositoryMock{Mock: mock.Mock{}}
+var rateCardRepository = &repository_mock.RateCardRepositoryMock{Mock: mock.Mock{}}
+var channelPriceRuleRepository = &repository_mock.ChannelPriceRuleRepositoryMock{Mock: mock.Mock{}}
+var shippingPromotionRepository = &repository_mock.ShippingPromotionRepositoryMock{Mock: mock.Mock{}}
+var shippingRateQuoteRepository = &repository_mock.ShippingRateQuoteRepositoryMock{Mock: mock.Mock{}}
+var channelRecommendationWeightRepository = &repository_mock.ChannelRecommendationWeightRepositoryMock{Mock: mock.Mock{}}
+var courierHolidayRepository = &repository_mock.CourierHolidayRepositoryMock{Mock: mock.Mock{}}
+var channelCourierCredentialRepository = &repository_mock.ChannelCourierCredentialRepositoryMock{Mock: mock.Mock{}}
 
 func init() {
 	shippingService = service.NewShippingService(
@@ -36,13 +48,23 @@ func init() {
 		channelRepository,
 		courierServiceRepo,
 		courierCoverageCodeRepository,
-		shipper,
+		shipping_provider.NewShippingProviderRegistry(
+			shipping_provider.NewShipperProvider(shipper),
+			shipping_provider.NewGrabProvider(grab),
+		),
 		redis,
 		orderShippingRepository,
 		courierRepository,
 		shippingCourierStatusRepository,
-		dapr,
-		grab,
+		shippingStatusTransitionRepository,
+		idempotencyKeyRepository,
+		rateCardRepository,
+		channelPriceRuleRepository,
+		shippingPromotionRepository,
+		shippingRateQuoteRepository,
+		channelRecommendationWeightRepository,
+		courierHolidayRepository,
+		channelCourierCredentialRepository,
 	)
 }
 
@@ -54,6 +76,13 @@ func TestGetShippingRate_ShipperSuccess(t *testing.T) {
 	channelRepository.Mock.On("FindByUid", mock.Anything).
 		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
 
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+
 	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
 		Return([]entity.ChannelCourierServiceForShippingRate{
 			{CourierCode: shipping_provider.ShipperCode},
@@ -69,7 +98,7 @@ func TestGetShippingRate_ShipperSuccess(t *testing.T) {
 			Summary: make(map[string]response.ShippingRateSummary),
 		}).Once()
 
-	result, msg := shippingService.GetShippingRate(input)
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
 	assert.NotNil(t, result)
 	assert.Equal(t, msg, message.SuccessMsg, codeIsNotCorrect)
 }
@@ -82,6 +111,13 @@ func TestGetShippingRate_DefaultSuccess(t *testing.T) {
 	channelRepository.Mock.On("FindByUid", mock.Anything).
 		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
 
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+
 	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
 		Return([]entity.ChannelCourierServiceForShippingRate{
 			{CourierCode: "aa"},
@@ -91,7 +127,7 @@ func TestGetShippingRate_DefaultSuccess(t *testing.T) {
 	redis.Mock.On("GetJsonStruct", mock.Anything).
 		Return(nil).Twice()
 
-	result, msg := shippingService.GetShippingRate(input)
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
 	assert.NotNil(t, result)
 	assert.Equal(t, msg, message.SuccessMsg, codeIsNotCorrect)
 }
@@ -105,6 +141,14 @@ func TestGetShippingRate_InternalSuccess(t *testing.T) {
 	channelRepository.Mock.On("FindByUid", mock.Anything).
 		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
 
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	shippingRateQuoteRepository.Mock.On("CreateBatch").Return(nil).Once()
+
 	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
 		Return([]entity.ChannelCourierServiceForShippingRate{
 			{CourierID: 1, CourierCode: "aa", CourierTypeCode: "internal", ShippingTypeCode: "instant",
@@ -115,7 +159,9 @@ func TestGetShippingRate_InternalSuccess(t *testing.T) {
 
 	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"aa": true, "bb": true}).Twice()
 
-	result, msg := shippingService.GetShippingRate(input)
+	rateCardRepository.Mock.On("FindEffective").Return(map[uint64]*entity.RateCard{}).Once()
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
 	assert.NotNil(t, result)
 	assert.Len(t, result[0].Services, 2)
 	assert.Equal(t, message.SuccessMsg.Message, result[0].Services[0].Error.Message)
@@ -139,6 +185,13 @@ func TestGetShippingRate_GrabSuccess(t *testing.T) {
 	channelRepository.Mock.On("FindByUid", mock.Anything).
 		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
 
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+
 	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
 		Return([]entity.ChannelCourierServiceForShippingRate{
 			{CourierCode: shipping_provider.GrabCode},
@@ -154,7 +207,7 @@ func TestGetShippingRate_GrabSuccess(t *testing.T) {
 			Summary: make(map[string]response.ShippingRateSummary),
 		}).Once()
 
-	result, msg := shippingService.GetShippingRate(input)
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
 	assert.NotNil(t, result)
 	assert.Equal(t, msg, message.SuccessMsg, codeIsNotCorrect)
 }
@@ -175,6 +228,13 @@ func TestGetShippingRate_GrabCoordinateRequired_Failed(t *testing.T) {
 	channelRepository.Mock.On("FindByUid", mock.Anything).
 		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
 
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+
 	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
 		Return([]entity.ChannelCourierServiceForShippingRate{
 			{CourierCode: shipping_provider.GrabCode, ShippingTypeCode: "instant"},
@@ -190,7 +250,7 @@ func TestGetShippingRate_GrabCoordinateRequired_Failed(t *testing.T) {
 			Summary: make(map[string]response.ShippingRateSummary),
 		}).Once()
 
-	result, msg := shippingService.GetShippingRate(input)
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
 	assert.NotNil(t, result)
 	assert.Len(t, result, 1)
 	assert.Equal(t, message.SuccessMsg, msg, codeIsNotCorrect)
@@ -205,6 +265,14 @@ func TestGetShippingRate_Internal_CoverageNotExistSuccess(t *testing.T) {
 	channelRepository.Mock.On("FindByUid", mock.Anything).
 		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
 
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	shippingRateQuoteRepository.Mock.On("CreateBatch").Return(nil).Once()
+
 	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
 		Return([]entity.ChannelCourierServiceForShippingRate{
 			{CourierID: 1, CourierCode: "aa", CourierTypeCode: "internal", ShippingTypeCode: "instant",
@@ -215,7 +283,9 @@ func TestGetShippingRate_Internal_CoverageNotExistSuccess(t *testing.T) {
 
 	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"aa": true}).Twice()
 
-	result, msg := shippingService.GetShippingRate(input)
+	rateCardRepository.Mock.On("FindEffective").Return(map[uint64]*entity.RateCard{}).Once()
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.SuccessMsg.Message, result[0].Services[0].Error.Message)
 	assert.Equal(t, message.ErrCourierCoverageCodeUidNotExist.Message, result[0].Services[1].Error.Message)
@@ -231,6 +301,13 @@ func TestGetShippingRate_Internal_WieghtExceedSuccess(t *testing.T) {
 	channelRepository.Mock.On("FindByUid", mock.Anything).
 		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
 
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+
 	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
 		Return([]entity.ChannelCourierServiceForShippingRate{
 			{CourierID: 1, CourierCode: "aa", CourierTypeCode: "internal", MaxWeight: 1, ShippingTypeCode: "instant",
@@ -241,7 +318,9 @@ func TestGetShippingRate_Internal_WieghtExceedSuccess(t *testing.T) {
 
 	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"aa": true}).Twice()
 
-	result, msg := shippingService.GetShippingRate(input)
+	rateCardRepository.Mock.On("FindEffective").Return(map[uint64]*entity.RateCard{}).Once()
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.WeightExceedsMsg.Message, result[0].Services[0].Error.Message)
 	assert.Equal(t, message.ErrCourierCoverageCodeUidNotExist.Message, result[0].Services[1].Error.Message)
@@ -256,6 +335,13 @@ func TestGetShippingRate_ShipperFailed(t *testing.T) {
 	channelRepository.Mock.On("FindByUid", mock.Anything).
 		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
 
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+
 	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
 		Return([]entity.ChannelCourierServiceForShippingRate{{CourierCode: shipping_provider.ShipperCode, ShippingTypeCode: "instant",
 			CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1}}).Once()
@@ -268,7 +354,7 @@ func TestGetShippingRate_ShipperFailed(t *testing.T) {
 			Rate: make(map[string]response.ShippingRateData),
 		}).Once()
 
-	result, msg := shippingService.GetShippingRate(input)
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
 	assert.NotNil(t, result)
 	assert.Equal(t, 400, result[0].Services[0].AvailableCode)
 	assert.Equal(t, message.ErrShippingRateNotFound.Message, result[0].Services[0].Error.Message)
@@ -286,7 +372,7 @@ func TestGetShippingRate_CourierServiceNotFound(t *testing.T) {
 	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
 		Return([]entity.ChannelCourierServiceForShippingRate{}).Once()
 
-	result, msg := shippingService.GetShippingRate(input)
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.CourierServiceNotFoundMsg, msg, codeIsNotCorrect)
 }
@@ -299,17 +385,422 @@ func TestGetShippingRate_ChannelNotFound(t *testing.T) {
 	channelRepository.Mock.On("FindByUid", mock.Anything).
 		Return(nil).Once()
 
-	result, msg := shippingService.GetShippingRate(input)
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.ErrChannelNotFound, msg, codeIsNotCorrect)
 }
 
+func recommendationCourierServices() []entity.ChannelCourierServiceForShippingRate {
+	return []entity.ChannelCourierServiceForShippingRate{
+		{CourierID: 1, CourierCode: "aa", CourierTypeCode: "internal", ShippingTypeCode: "instant", CourierServiceUID: "cheap", CourierServiceID: 1,
+			Price: 10000, EtdMin: 1, EtdMax: 2, PrioritySort: 2,
+			CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+		{CourierID: 2, CourierCode: "bb", CourierTypeCode: "internal", ShippingTypeCode: "instant", CourierServiceUID: "fast", CourierServiceID: 2,
+			Price: 20000, EtdMin: 0, EtdMax: 1, PrioritySort: 1,
+			CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+	}
+}
+
+func TestGetShippingRate_RecommendedByDefaultWeight(t *testing.T) {
+	input := request.GetShippingRateRequest{
+		CourierServiceUID: []string{"cheap", "fast"},
+		TotalWeight:       1,
+		Sort:              request.ShippingRateSortFastest,
+	}
+
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{ID: 1, UID: "1"}}).Once()
+
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	shippingRateQuoteRepository.Mock.On("CreateBatch").Return(nil).Once()
+
+	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
+		Return(recommendationCourierServices()).Once()
+
+	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"aa": true, "bb": true}).Twice()
+
+	rateCardRepository.Mock.On("FindEffective").Return(map[uint64]*entity.RateCard{}).Once()
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
+	assert.Equal(t, message.SuccessMsg, msg, codeIsNotCorrect)
+	assert.Len(t, result[0].Services, 2)
+
+	// sorted by etd, the faster service with the higher channel priority is recommended
+	assert.Equal(t, "fast", result[0].Services[0].CourierServiceUID)
+	assert.Equal(t, 0.6, result[0].Services[0].RecommendationScore)
+	assert.True(t, result[0].Services[0].Recommended)
+	assert.Equal(t, "cheap", result[0].Services[1].CourierServiceUID)
+	assert.Equal(t, 0.5, result[0].Services[1].RecommendationScore)
+	assert.False(t, result[0].Services[1].Recommended)
+}
+
+func TestGetShippingRate_RecommendedByChannelWeight(t *testing.T) {
+	input := request.GetShippingRateRequest{
+		CourierServiceUID: []string{"cheap", "fast"},
+		TotalWeight:       1,
+		Sort:              request.ShippingRateSortRecommended,
+	}
+
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{ID: 1, UID: "1"}}).Once()
+
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(&entity.ChannelRecommendationWeight{
+		ChannelID: 1, PriceWeight: 1, ReliabilityWeight: 1,
+	}).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return([]entity.CourierServiceReliability{
+		{CourierServiceID: 1, Total: 10, Cancelled: 5},
+	}).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	shippingRateQuoteRepository.Mock.On("CreateBatch").Return(nil).Once()
+
+	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
+		Return(recommendationCourierServices()).Once()
+
+	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"aa": true, "bb": true}).Twice()
+
+	rateCardRepository.Mock.On("FindEffective").Return(map[uint64]*entity.RateCard{}).Once()
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
+	assert.Equal(t, message.SuccessMsg, msg, codeIsNotCorrect)
+	assert.Len(t, result[0].Services, 2)
+
+	// half of the orders of the cheap service were cancelled,
+	// the fast service without orders is rated at the mean of the channel
+	assert.Equal(t, "cheap", result[0].Services[0].CourierServiceUID)
+	assert.Equal(t, 0.75, result[0].Services[0].RecommendationScore)
+	assert.True(t, result[0].Services[0].Recommended)
+	assert.Equal(t, "fast", result[0].Services[1].CourierServiceUID)
+	assert.Equal(t, 0.25, result[0].Services[1].RecommendationScore)
+	assert.False(t, result[0].Services[1].Recommended)
+}
+
+func TestGetShippingRate_EstimatedDeliveryAfterHoliday(t *testing.T) {
+	input := request.GetShippingRateRequest{
+		CourierServiceUID: []string{"cheap", "fast"},
+		TotalWeight:       1,
+		Origin:            request.AreaDetailPayload{Timezone: "Asia/Makassar"},
+	}
+
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{ID: 1, UID: "1"}}).Once()
+
+	// the courier of the cheap service is on holiday for the next four days
+	courierID := uint64(1)
+	today := time.Now().In(util.LoadLocation("Asia/Makassar"))
+	var holidays []entity.CourierHoliday
+	for i := 0; i < 4; i++ {
+		date := time.Date(today.Year(), today.Month(), today.Day()+i, 0, 0, 0, 0, time.UTC)
+		holidays = append(holidays, entity.CourierHoliday{CourierID: &courierID, HolidayDate: date, Name: "holiday"})
+	}
+
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(holidays).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	shippingRateQuoteRepository.Mock.On("CreateBatch").Return(nil).Once()
+
+	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
+		Return(recommendationCourierServices()).Once()
+
+	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"aa": true, "bb": true}).Twice()
+
+	rateCardRepository.Mock.On("FindEffective").Return(map[uint64]*entity.RateCard{}).Once()
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
+	assert.Equal(t, message.SuccessMsg, msg, codeIsNotCorrect)
+	assert.Len(t, result[0].Services, 2)
+
+	cheap, fast := result[0].Services[0], result[0].Services[1]
+	assert.Equal(t, "cheap", cheap.CourierServiceUID)
+	assert.NotNil(t, cheap.EstimatedDeliveryFrom)
+	assert.NotNil(t, fast.EstimatedDeliveryFrom)
+
+	// picked up after the holidays and delivered in at least a working day
+	assert.True(t, cheap.EstimatedDeliveryFrom.After(today.AddDate(0, 0, 4)))
+	assert.True(t, fast.EstimatedDeliveryFrom.Before(*cheap.EstimatedDeliveryFrom))
+	assert.Equal(t, "Asia/Makassar", cheap.EstimatedDeliveryTo.Location().String())
+	assert.Equal(t, 23, cheap.EstimatedDeliveryTo.Hour())
+}
+
+func TestGetShippingRate_InvalidSort(t *testing.T) {
+	input := request.GetShippingRateRequest{
+		CourierServiceUID: []string{""},
+		Sort:              "nearest",
+	}
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
+	assert.Empty(t, result)
+	assert.Equal(t, message.ErrInvalidShippingRateSort, msg, codeIsNotCorrect)
+}
+
+func TestGetShippingRate_ProviderTimeout(t *testing.T) {
+	viper.Set("setting.shipping-rate.timeout", 50*time.Millisecond)
+	defer viper.Set("setting.shipping-rate.timeout", nil)
+
+	// the providers of this service are not shared with the other tests, shipper answers too late
+	slowShipper := &shipping_provider_mock.ShipperMock{Mock: mock.Mock{}}
+	fastGrab := &shipping_provider_mock.GrabMock{Mock: mock.Mock{}}
+	rateRedis := &cache_mock.Redis_Mock{Mock: mock.Mock{}}
+	rateService := service.NewShippingService(
+		logger,
+		baseRepository,
+		channelRepository,
+		courierServiceRepo,
+		courierCoverageCodeRepository,
+		shipping_provider.NewShippingProviderRegistry(
+			shipping_provider.NewShipperProvider(slowShipper),
+			shipping_provider.NewGrabProvider(fastGrab),
+		),
+		rateRedis,
+		orderShippingRepository,
+		courierRepository,
+		shippingCourierStatusRepository,
+		shippingStatusTransitionRepository,
+		idempotencyKeyRepository,
+		rateCardRepository,
+		channelPriceRuleRepository,
+		shippingPromotionRepository,
+		shippingRateQuoteRepository,
+		channelRecommendationWeightRepository,
+		courierHolidayRepository,
+		channelCourierCredentialRepository,
+	)
+
+	input := request.GetShippingRateRequest{
+		CourierServiceUID: []string{"shipper-regular", "grab-instant"},
+		TotalWeight:       1,
+	}
+
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{ID: 1, UID: "1"}}).Once()
+
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	shippingRateQuoteRepository.Mock.On("CreateBatch").Return(nil).Once()
+
+	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
+		Return([]entity.ChannelCourierServiceForShippingRate{
+			{CourierID: 1, CourierCode: shipping_provider.ShipperCode, CourierTypeCode: shipping_provider.AggregatorCourier,
+				CourierServiceUID: "shipper-regular", ShippingCode: "regular", ShippingTypeCode: "regular",
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1},
+			{CourierID: 2, CourierCode: shipping_provider.GrabCode, CourierTypeCode: shipping_provider.ThirPartyCourier,
+				CourierServiceUID: "grab-instant", ShippingCode: "instant", ShippingTypeCode: "instant",
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1},
+		}).Once()
+
+	// the reliability of the channel and the prices of both providers are not cached
+	rateRedis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Times(3)
+
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Twice()
+
+	slowShipper.Mock.On("GetShippingRate").
+		After(500 * time.Millisecond).
+		Return(&response.ShippingRateCommonResponse{}).Once()
+
+	fastGrab.Mock.On("GetShippingRate").
+		Return(&response.ShippingRateCommonResponse{
+			Rate: map[string]response.ShippingRateData{
+				global.CourierShippingCodeKey(shipping_provider.GrabCode, "instant"): {AvailableCode: 200, TotalPrice: 20000},
+			},
+			CourierMsg: map[string]message.Message{shipping_provider.GrabCode: message.SuccessMsg},
+		}).Once()
+
+	start := time.Now()
+	result, msg := rateService.GetShippingRate(context.Background(), input)
+	assert.Less(t, int64(time.Since(start)), int64(500*time.Millisecond))
+	assert.Equal(t, message.SuccessMsg, msg, codeIsNotCorrect)
+
+	services := make(map[string]response.GetShippingRateService)
+	for _, rate := range result {
+		for _, v := range rate.Services {
+			services[v.CourierServiceUID] = v
+		}
+	}
+
+	assert.Equal(t, 200, services["grab-instant"].AvailableCode)
+	assert.Equal(t, 400, services["shipper-regular"].AvailableCode)
+	assert.Equal(t, message.ProviderTimeoutMsg.Message, services["shipper-regular"].Error.Message)
+}
+
+func TestGetBatchShippingRate_InternalSuccess(t *testing.T) {
+	input := request.BatchShippingRateRequest{
+		CourierServiceUID: []string{"", ""},
+		Shipments: []request.BatchShippingRateShipment{
+			{ID: "order-1", TotalWeight: 10},
+			{ID: "order-2", TotalWeight: 20},
+		},
+	}
+
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
+
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	shippingRateQuoteRepository.Mock.On("CreateBatch").Return(nil).Twice()
+
+	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
+		Return([]entity.ChannelCourierServiceForShippingRate{
+			{CourierID: 1, CourierCode: "aa", CourierTypeCode: "internal", ShippingTypeCode: "instant",
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+			{CourierID: 2, CourierCode: "bb", CourierTypeCode: "merchant", ShippingTypeCode: "instant",
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+		}).Once()
+
+	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"aa": true, "bb": true}).Times(4)
+
+	rateCardRepository.Mock.On("FindEffective").Return(map[uint64]*entity.RateCard{}).Twice()
+
+	result, msg := shippingService.GetBatchShippingRate(context.Background(), &input)
+	assert.Equal(t, message.SuccessMsg, msg, codeIsNotCorrect)
+	assert.Len(t, result, 2)
+	assert.Len(t, result["order-1"][0].Services, 2)
+	assert.Len(t, result["order-2"][0].Services, 2)
+}
+
+func TestGetBatchShippingRate_Timeout(t *testing.T) {
+	viper.Set("setting.batch-shipping-rate.timeout", 50*time.Millisecond)
+	viper.Set("setting.batch-shipping-rate.concurrency", 1)
+	defer viper.Set("setting.batch-shipping-rate", nil)
+
+	// shipper answers after the timeout of the batch
+	slowShipper := &shipping_provider_mock.ShipperMock{Mock: mock.Mock{}}
+	rateRedis := &cache_mock.Redis_Mock{Mock: mock.Mock{}}
+	rateService := service.NewShippingService(
+		logger,
+		baseRepository,
+		channelRepository,
+		courierServiceRepo,
+		courierCoverageCodeRepository,
+		shipping_provider.NewShippingProviderRegistry(shipping_provider.NewShipperProvider(slowShipper)),
+		rateRedis,
+		orderShippingRepository,
+		courierRepository,
+		shippingCourierStatusRepository,
+		shippingStatusTransitionRepository,
+		idempotencyKeyRepository,
+		rateCardRepository,
+		channelPriceRuleRepository,
+		shippingPromotionRepository,
+		shippingRateQuoteRepository,
+		channelRecommendationWeightRepository,
+		courierHolidayRepository,
+		channelCourierCredentialRepository,
+	)
+
+	input := request.BatchShippingRateRequest{
+		CourierServiceUID: []string{"shipper-regular"},
+		Shipments: []request.BatchShippingRateShipment{
+			{ID: "order-1", TotalWeight: 1},
+			{ID: "order-2", TotalWeight: 1},
+		},
+	}
+
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{ID: 1, UID: "1"}}).Once()
+
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+
+	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
+		Return([]entity.ChannelCourierServiceForShippingRate{
+			{CourierID: 1, CourierCode: shipping_provider.ShipperCode, CourierTypeCode: shipping_provider.AggregatorCourier,
+				CourierServiceUID: "shipper-regular", ShippingCode: "regular", ShippingTypeCode: "regular",
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1},
+		}).Once()
+
+	// the reliability of the channel and the price of the first shipment are not cached
+	rateRedis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Twice()
+
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
+	slowShipper.Mock.On("GetShippingRate").
+		After(500 * time.Millisecond).
+		Return(&response.ShippingRateCommonResponse{}).Once()
+
+	start := time.Now()
+	result, msg := rateService.GetBatchShippingRate(context.Background(), &input)
+	assert.Less(t, int64(time.Since(start)), int64(500*time.Millisecond))
+	assert.Equal(t, message.SuccessMsg, msg, codeIsNotCorrect)
+
+	// the first shipment is rated without the provider, the second is not rated
+	assert.Len(t, result, 1)
+	assert.Equal(t, 400, result["order-1"][0].Services[0].AvailableCode)
+	assert.Equal(t, message.ProviderTimeoutMsg.Message, result["order-1"][0].Services[0].Error.Message)
+	slowShipper.Mock.AssertNumberOfCalls(t, "GetShippingRate", 1)
+}
+
+func TestGetBatchShippingRate_DuplicateShipmentID(t *testing.T) {
+	input := request.BatchShippingRateRequest{
+		CourierServiceUID: []string{""},
+		Shipments: []request.BatchShippingRateShipment{
+			{ID: "order-1"},
+			{ID: "order-1"},
+		},
+	}
+
+	result, msg := shippingService.GetBatchShippingRate(context.Background(), &input)
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrDuplicateShipmentID, msg, codeIsNotCorrect)
+}
+
+func TestGetBatchShippingRate_ShipmentIDRequired(t *testing.T) {
+	input := request.BatchShippingRateRequest{
+		CourierServiceUID: []string{""},
+		Shipments:         []request.BatchShippingRateShipment{{}},
+	}
+
+	result, msg := shippingService.GetBatchShippingRate(context.Background(), &input)
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrShipmentIDRequired, msg, codeIsNotCorrect)
+}
+
+func TestGetBatchShippingRate_TooManyShipments(t *testing.T) {
+	input := request.BatchShippingRateRequest{
+		CourierServiceUID: []string{""},
+		Shipments:         make([]request.BatchShippingRateShipment, 51),
+	}
+
+	result, msg := shippingService.GetBatchShippingRate(context.Background(), &input)
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrTooManyShipments, msg, codeIsNotCorrect)
+}
+
+func TestGetBatchShippingRate_ShipmentsRequired(t *testing.T) {
+	input := request.BatchShippingRateRequest{
+		CourierServiceUID: []string{""},
+	}
+
+	result, msg := shippingService.GetBatchShippingRate(context.Background(), &input)
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrShipmentsRequired, msg, codeIsNotCorrect)
+}
+
 func TestGetShippingRateCourierServiceUID_Required(t *testing.T) {
 	input := request.GetShippingRateRequest{
 		CourierServiceUID: []string{},
 	}
 
-	result, msg := shippingService.GetShippingRate(input)
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.ErrCourierServiceIsRequired, msg, codeIsNotCorrect)
 }
@@ -319,7 +810,7 @@ func TestGetShippingRateByShippingType_ShippingTypeRequired(t *testing.T) {
 		CourierServiceUID: []string{},
 	}
 
-	result, msg := shippingService.GetShippingRateByShippingType(input)
+	result, msg := shippingService.GetShippingRateByShippingType(context.Background(), input)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.ErrShippingTypeRequired, msg, codeIsNotCorrect)
 }
@@ -459,10 +950,14 @@ func TestCreateDeliveryShipperSuccess(t *testing.T) {
 		Status:    shipping_provider.StatusRequestPickup,
 	}
 
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	shipper.Mock.On("CreateDelivery", mock.Anything).Return(order, message.SuccessMsg).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
 	orderShippingRepository.Mock.On("Upsert", mock.Anything).Return(orderShipping).Once()
 
-	result, msg := shippingService.CreateDelivery(createDeliveryRequest)
+	result, msg := shippingService.CreateDelivery(context.Background(), createDeliveryRequest)
 
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
@@ -540,10 +1035,14 @@ func TestCreateDeliveryGrabSuccess(t *testing.T) {
 		Status:    shipping_provider.StatusRequestPickup,
 	}
 
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	grab.Mock.On("CreateDelivery", mock.Anything).Return(order, message.SuccessMsg).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
 	orderShippingRepository.Mock.On("Upsert", mock.Anything).Return(orderShipping).Once()
 
-	result, msg := shippingService.CreateDelivery(createDeliveryRequest)
+	result, msg := shippingService.CreateDelivery(context.Background(), createDeliveryRequest)
 
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
@@ -551,8 +1050,7 @@ func TestCreateDeliveryGrabSuccess(t *testing.T) {
 	assert.Equal(t, message.SuccessMsg, msg)
 }
 
-func TestCreateDeliveryShipperSaveFailed(t *testing.T) {
-
+func createDeliveryInternalTest(t *testing.T, courierType string) {
 	channel := entity.Channel{BaseIDModel: base.BaseIDModel{ID: 1, UID: createDeliveryRequest.ChannelUID}}
 	channelRepository.Mock.On("FindByUid", mock.Anything).
 		Return(channel).Once()
@@ -562,8 +1060,8 @@ func TestCreateDeliveryShipperSaveFailed(t *testing.T) {
 			ID:  3,
 			UID: "cuid",
 		},
-		CourierType: shipping_provider.ThirPartyCourier,
-		Code:        shipping_provider.ShipperCode,
+		CourierType: courierType,
+		Code:        "kurir-kd",
 		Status:      &active,
 	}
 
@@ -572,29 +1070,21 @@ func TestCreateDeliveryShipperSaveFailed(t *testing.T) {
 			ID:  3,
 			UID: createDeliveryRequest.CouirerServiceUID,
 		},
-		CourierID: 2,
-		Courier:   courier,
-		Status:    &active,
+		CourierID:     3,
+		Courier:       courier,
+		Status:        &active,
+		PriceInternal: 10000,
 	}
 
 	courierServiceRepo.Mock.On("FindCourierService", mock.Anything).
 		Return(courierService).Once()
 
-	orderShippingRepository.Mock.On("FindByOrderNo", mock.Anything).
-		Return(nil).Once()
-
-	shippingStatus := &entity.ShippingCourierStatus{
-		BaseIDModel: base.BaseIDModel{
-			ID:  4,
-			UID: "ssuid",
-		},
-		ShippingStatusID: 1,
-		CourierID:        courier.ID,
-		StatusCode:       shipping_provider.StatusRequestPickup,
-		StatusCourier:    []byte(""),
+	if courierType == shipping_provider.InternalCourier {
+		rateCardRepository.Mock.On("FindEffective").Return(map[uint64]*entity.RateCard{}).Once()
 	}
 
-	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(shippingStatus).Once()
+	orderShippingRepository.Mock.On("FindByOrderNo", mock.Anything).
+		Return(nil).Once()
 
 	created := &entity.ShippingCourierStatus{
 		BaseIDModel: base.BaseIDModel{
@@ -606,7 +1096,7 @@ func TestCreateDeliveryShipperSaveFailed(t *testing.T) {
 		StatusCode:       shipping_provider.StatusCreated,
 		StatusCourier:    []byte(""),
 	}
-	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(created).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(created).Twice()
 
 	orderShipping := &entity.OrderShipping{
 		BaseIDModel: base.BaseIDModel{
@@ -615,55 +1105,435 @@ func TestCreateDeliveryShipperSaveFailed(t *testing.T) {
 		},
 		OrderNo: createDeliveryRequest.OrderNo,
 	}
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	orderShippingRepository.Mock.On("Upsert", mock.Anything).Return(orderShipping).Once()
 
-	order := &response.CreateDeliveryThirdPartyData{
-		BookingID: "bookid",
-		Status:    shipping_provider.StatusRequestPickup,
-	}
+	result, msg := shippingService.CreateDelivery(context.Background(), createDeliveryRequest)
 
-	shipper.Mock.On("CreateDelivery", mock.Anything).Return(order, message.SuccessMsg).Once()
-	orderShippingRepository.Mock.On("Upsert", mock.Anything).Return(orderShipping, errors.New("")).Once()
+	assert.NotNil(t, result)
+	assert.Equal(t, createDeliveryRequest.OrderNo, result.OrderNoAPI)
+	assert.Equal(t, orderShipping.UID, result.OrderShippingUID)
+	assert.Equal(t, message.SuccessMsg, msg)
+}
 
-	result, msg := shippingService.CreateDelivery(createDeliveryRequest)
+func TestCreateDeliveryInternalSuccess(t *testing.T) {
+	createDeliveryInternalTest(t, shipping_provider.InternalCourier)
+}
 
-	assert.NotNil(t, result)
-	assert.NotNil(t, msg)
-	assert.Equal(t, message.ErrSaveOrderShipping, msg)
+func TestCreateDeliveryMerchantSuccess(t *testing.T) {
+	createDeliveryInternalTest(t, shipping_provider.MerchantCourier)
 }
 
-func TestCreateDeliveryThridPartyCourierInvalid(t *testing.T) {
+func idempotentCreateDeliveryRequest() *request.CreateDelivery {
+	req := *createDeliveryRequest
+	req.IdempotencyKey = "idempotency-key"
+	return &req
+}
 
-	channel := entity.Channel{BaseIDModel: base.BaseIDModel{ID: 1, UID: createDeliveryRequest.ChannelUID}}
+// mock the lookups of populateCreateDelivery for a shipper courier service
+func mockPopulateCreateDeliveryShipper() {
 	channelRepository.Mock.On("FindByUid", mock.Anything).
-		Return(channel).Once()
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{ID: 1, UID: createDeliveryRequest.ChannelUID}}).Once()
 
-	courier := &entity.Courier{
-		BaseIDModel: base.BaseIDModel{
-			ID:  3,
-			UID: "cuid",
-		},
-		CourierType: shipping_provider.ThirPartyCourier,
-		Code:        "not shipper",
-		Status:      &active,
-	}
+	courierServiceRepo.Mock.On("FindCourierService", mock.Anything).
+		Return(&entity.CourierService{
+			BaseIDModel: base.BaseIDModel{ID: 3, UID: createDeliveryRequest.CouirerServiceUID},
+			CourierID:   3,
+			Courier: &entity.Courier{
+				BaseIDModel: base.BaseIDModel{ID: 3, UID: "cuid"},
+				CourierType: shipping_provider.ThirPartyCourier,
+				Code:        shipping_provider.ShipperCode,
+				Status:      &active,
+			},
+			Status: &active,
+		}).Once()
 
-	courierService := &entity.CourierService{
-		BaseIDModel: base.BaseIDModel{
-			ID:  3,
-			UID: createDeliveryRequest.CouirerServiceUID,
-		},
-		CourierID: 2,
-		Courier:   courier,
-		Status:    &active,
-	}
+	orderShippingRepository.Mock.On("FindByOrderNo", mock.Anything).Return(nil).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(&entity.ShippingCourierStatus{
+		StatusCode: shipping_provider.StatusCreated,
+	}).Twice()
+}
 
-	courierServiceRepo.Mock.On("FindCourierService", mock.Anything).
-		Return(courierService).Once()
+func TestCreateDeliveryIdempotentNewKey(t *testing.T) {
+	req := idempotentCreateDeliveryRequest()
+	idempotencyKeyRepository.Mock.On("FindByKey").Return(nil).Once()
+	idempotencyKeyRepository.Mock.On("Create").Return(nil, nil).Once()
+	mockPopulateCreateDeliveryShipper()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
 
-	orderShippingRepository.Mock.On("FindByOrderNo", mock.Anything).
-		Return(nil).Once()
+	shipper.Mock.On("CreateDelivery", mock.Anything).Return(&response.CreateDeliveryThirdPartyData{
+		BookingID: "bookid",
+		Status:    shipping_provider.StatusCreated,
+	}, message.SuccessMsg).Once()
+	idempotencyKeyRepository.Mock.On("Update").Return(nil).Twice()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	orderShippingRepository.Mock.On("Upsert", mock.Anything).Return(&entity.OrderShipping{
+		BaseIDModel: base.BaseIDModel{UID: "osuid"},
+	}).Once()
 
-	shippingStatus := &entity.ShippingCourierStatus{
+	result, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "osuid", result.OrderShippingUID)
+}
+
+func TestCreateDeliveryIdempotentReplay(t *testing.T) {
+	req := idempotentCreateDeliveryRequest()
+	idempotencyKeyRepository.Mock.On("FindByKey").Return(&entity.IdempotencyKey{
+		Key:          req.IdempotencyKey,
+		RequestHash:  req.Hash(),
+		Status:       entity.IdempotencyStatusCompleted,
+		ResponseData: []byte(`{"order_shipping_uid":"osuid","order_no_api":"` + req.OrderNo + `"}`),
+	}).Once()
+
+	result, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "osuid", result.OrderShippingUID)
+	assert.Equal(t, req.OrderNo, result.OrderNoAPI)
+}
+
+func TestCreateDeliveryIdempotentBookedRetry(t *testing.T) {
+	req := idempotentCreateDeliveryRequest()
+	idempotencyKeyRepository.Mock.On("FindByKey").Return(&entity.IdempotencyKey{
+		Key:         req.IdempotencyKey,
+		RequestHash: req.Hash(),
+		Status:      entity.IdempotencyStatusBooked,
+		BookingData: []byte(`{"BookingID":"bookid","Status":"created"}`),
+	}).Once()
+	idempotencyKeyRepository.Mock.On("TakeOver").Return(true).Once()
+	orderShippingRepository.Mock.On("FindByOrderNo", mock.Anything).Return(nil).Once()
+	mockPopulateCreateDeliveryShipper()
+	idempotencyKeyRepository.Mock.On("Update").Return(nil).Once()
+
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	orderShippingRepository.Mock.On("Upsert", mock.Anything).Return(&entity.OrderShipping{
+		BaseIDModel: base.BaseIDModel{UID: "osuid"},
+	}).Once()
+
+	// the stored booking is reused, shipper is not called again
+	result, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "osuid", result.OrderShippingUID)
+}
+
+func TestCreateDeliveryIdempotentBookedInProgress(t *testing.T) {
+	req := idempotentCreateDeliveryRequest()
+	lockedUntil := time.Now().Add(time.Minute)
+	idempotencyKeyRepository.Mock.On("FindByKey").Return(&entity.IdempotencyKey{
+		Key:         req.IdempotencyKey,
+		RequestHash: req.Hash(),
+		Status:      entity.IdempotencyStatusBooked,
+		LockedUntil: &lockedUntil,
+		BookingData: []byte(`{"BookingID":"bookid","Status":"created"}`),
+	}).Once()
+
+	// the request that booked the order is still saving it
+	_, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.ErrIdempotencyKeyInProgress, msg)
+}
+
+func TestCreateDeliveryIdempotentOrderSaved(t *testing.T) {
+	req := idempotentCreateDeliveryRequest()
+	idempotencyKeyRepository.Mock.On("FindByKey").Return(&entity.IdempotencyKey{
+		Key:         req.IdempotencyKey,
+		RequestHash: req.Hash(),
+		Status:      entity.IdempotencyStatusBooked,
+		BookingData: []byte(`{"BookingID":"bookid","Status":"created"}`),
+	}).Once()
+	idempotencyKeyRepository.Mock.On("TakeOver").Return(true).Once()
+	orderShippingRepository.Mock.On("FindByOrderNo", mock.Anything).Return(&entity.OrderShipping{
+		BaseIDModel: base.BaseIDModel{UID: "osuid"},
+	}).Once()
+	idempotencyKeyRepository.Mock.On("Update").Return(nil).Once()
+
+	// the order was saved before the key was completed, the saved order is the result
+	result, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "osuid", result.OrderShippingUID)
+	assert.Equal(t, req.OrderNo, result.OrderNoAPI)
+}
+
+func TestCreateDeliveryIdempotentSaveFailed(t *testing.T) {
+	req := idempotentCreateDeliveryRequest()
+	idempotencyKeyRepository.Mock.On("FindByKey").Return(nil).Once()
+	idempotencyKeyRepository.Mock.On("Create").Return(nil, nil).Once()
+	mockPopulateCreateDeliveryShipper()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
+	shipper.Mock.On("CreateDelivery", mock.Anything).Return(&response.CreateDeliveryThirdPartyData{
+		BookingID: "bookid",
+		Status:    shipping_provider.StatusCreated,
+	}, message.SuccessMsg).Once()
+	idempotencyKeyRepository.Mock.On("Update").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	orderShippingRepository.Mock.On("Upsert", mock.Anything).Return(nil, errors.New("")).Once()
+
+	_, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.ErrSaveOrderShipping, msg)
+	idempotencyKeyRepository.Mock.AssertNotCalled(t, "Delete")
+}
+
+func TestCreateDeliveryIdempotentProviderFailed(t *testing.T) {
+	req := idempotentCreateDeliveryRequest()
+	idempotencyKeyRepository.Mock.On("FindByKey").Return(nil).Once()
+	idempotencyKeyRepository.Mock.On("Create").Return(nil, nil).Once()
+	mockPopulateCreateDeliveryShipper()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
+	shipper.Mock.On("CreateDelivery", mock.Anything).Return(&response.CreateDeliveryThirdPartyData{}, message.ErrCreateOrder).Once()
+	idempotencyKeyRepository.Mock.On("Delete").Return(nil).Once()
+
+	_, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.ErrCreateOrder, msg)
+}
+
+func TestCreateDeliveryIdempotentConflict(t *testing.T) {
+	req := idempotentCreateDeliveryRequest()
+	idempotencyKeyRepository.Mock.On("FindByKey").Return(&entity.IdempotencyKey{
+		Key:         req.IdempotencyKey,
+		RequestHash: "another-payload",
+		Status:      entity.IdempotencyStatusCompleted,
+	}).Once()
+
+	_, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.ErrIdempotencyKeyConflict, msg)
+}
+
+func TestCreateDeliveryIdempotentInProgress(t *testing.T) {
+	req := idempotentCreateDeliveryRequest()
+	lockedUntil := time.Now().Add(time.Minute)
+	idempotencyKeyRepository.Mock.On("FindByKey").Return(&entity.IdempotencyKey{
+		Key:         req.IdempotencyKey,
+		RequestHash: req.Hash(),
+		Status:      entity.IdempotencyStatusProcessing,
+		LockedUntil: &lockedUntil,
+	}).Once()
+
+	_, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.ErrIdempotencyKeyInProgress, msg)
+}
+
+func TestCreateDeliveryIdempotentLeaseExpired(t *testing.T) {
+	req := idempotentCreateDeliveryRequest()
+	lockedUntil := time.Now().Add(-time.Minute)
+	idempotencyKeyRepository.Mock.On("FindByKey").Return(&entity.IdempotencyKey{
+		Key:         req.IdempotencyKey,
+		RequestHash: req.Hash(),
+		Status:      entity.IdempotencyStatusProcessing,
+		LockedUntil: &lockedUntil,
+	}).Once()
+	idempotencyKeyRepository.Mock.On("TakeOver").Return(true).Once()
+	orderShippingRepository.Mock.On("FindByOrderNo", mock.Anything).Return(nil).Once()
+	mockPopulateCreateDeliveryShipper()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
+	shipper.Mock.On("CreateDelivery", mock.Anything).Return(&response.CreateDeliveryThirdPartyData{
+		BookingID: "bookid",
+		Status:    shipping_provider.StatusCreated,
+	}, message.SuccessMsg).Once()
+	idempotencyKeyRepository.Mock.On("Update").Return(nil).Twice()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	orderShippingRepository.Mock.On("Upsert", mock.Anything).Return(&entity.OrderShipping{
+		BaseIDModel: base.BaseIDModel{UID: "osuid"},
+	}).Once()
+
+	// the request holding the key stopped, the retry books the order
+	result, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "osuid", result.OrderShippingUID)
+}
+
+func TestCreateDeliveryIdempotentLeaseTakenByOtherRetry(t *testing.T) {
+	req := idempotentCreateDeliveryRequest()
+	idempotencyKeyRepository.Mock.On("FindByKey").Return(&entity.IdempotencyKey{
+		Key:         req.IdempotencyKey,
+		RequestHash: req.Hash(),
+		Status:      entity.IdempotencyStatusProcessing,
+	}).Once()
+	idempotencyKeyRepository.Mock.On("TakeOver").Return(false).Once()
+
+	_, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.ErrIdempotencyKeyInProgress, msg)
+}
+
+func TestCreateDeliveryIdempotentCreateKeyFailed(t *testing.T) {
+	req := idempotentCreateDeliveryRequest()
+	idempotencyKeyRepository.Mock.On("FindByKey").Return(nil).Once()
+	idempotencyKeyRepository.Mock.On("Create").Return(nil, errors.New("connection refused")).Once()
+
+	_, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.ErrDB, msg)
+}
+
+func TestCreateDeliveryIdempotentKeyTaken(t *testing.T) {
+	req := idempotentCreateDeliveryRequest()
+	idempotencyKeyRepository.Mock.On("FindByKey").Return(nil).Once()
+	idempotencyKeyRepository.Mock.On("Create").Return(nil, repository.ErrIdempotencyKeyTaken).Once()
+
+	_, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.ErrIdempotencyKeyInProgress, msg)
+}
+
+func TestCreateDeliveryIdempotentBookedUpdateRetried(t *testing.T) {
+	req := idempotentCreateDeliveryRequest()
+	idempotencyKeyRepository.Mock.On("FindByKey").Return(nil).Once()
+	idempotencyKeyRepository.Mock.On("Create").Return(nil, nil).Once()
+	mockPopulateCreateDeliveryShipper()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
+	shipper.Mock.On("CreateDelivery", mock.Anything).Return(&response.CreateDeliveryThirdPartyData{
+		BookingID: "bookid",
+		Status:    shipping_provider.StatusCreated,
+	}, message.SuccessMsg).Once()
+	// the booked key is saved on the second attempt, then completed
+	idempotencyKeyRepository.Mock.On("Update").Return(errors.New("")).Once()
+	idempotencyKeyRepository.Mock.On("Update").Return(nil).Twice()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	orderShippingRepository.Mock.On("Upsert", mock.Anything).Return(&entity.OrderShipping{
+		BaseIDModel: base.BaseIDModel{UID: "osuid"},
+	}).Once()
+
+	result, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "osuid", result.OrderShippingUID)
+}
+
+func TestCreateDeliveryShipperSaveFailed(t *testing.T) {
+
+	channel := entity.Channel{BaseIDModel: base.BaseIDModel{ID: 1, UID: createDeliveryRequest.ChannelUID}}
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(channel).Once()
+
+	courier := &entity.Courier{
+		BaseIDModel: base.BaseIDModel{
+			ID:  3,
+			UID: "cuid",
+		},
+		CourierType: shipping_provider.ThirPartyCourier,
+		Code:        shipping_provider.ShipperCode,
+		Status:      &active,
+	}
+
+	courierService := &entity.CourierService{
+		BaseIDModel: base.BaseIDModel{
+			ID:  3,
+			UID: createDeliveryRequest.CouirerServiceUID,
+		},
+		CourierID: 2,
+		Courier:   courier,
+		Status:    &active,
+	}
+
+	courierServiceRepo.Mock.On("FindCourierService", mock.Anything).
+		Return(courierService).Once()
+
+	orderShippingRepository.Mock.On("FindByOrderNo", mock.Anything).
+		Return(nil).Once()
+
+	shippingStatus := &entity.ShippingCourierStatus{
+		BaseIDModel: base.BaseIDModel{
+			ID:  4,
+			UID: "ssuid",
+		},
+		ShippingStatusID: 1,
+		CourierID:        courier.ID,
+		StatusCode:       shipping_provider.StatusRequestPickup,
+		StatusCourier:    []byte(""),
+	}
+
+	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(shippingStatus).Once()
+
+	created := &entity.ShippingCourierStatus{
+		BaseIDModel: base.BaseIDModel{
+			ID:  4,
+			UID: "ssuid",
+		},
+		ShippingStatusID: 1,
+		CourierID:        courier.ID,
+		StatusCode:       shipping_provider.StatusCreated,
+		StatusCourier:    []byte(""),
+	}
+	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(created).Once()
+
+	orderShipping := &entity.OrderShipping{
+		BaseIDModel: base.BaseIDModel{
+			ID:  5,
+			UID: "osuid",
+		},
+		OrderNo: createDeliveryRequest.OrderNo,
+	}
+
+	order := &response.CreateDeliveryThirdPartyData{
+		BookingID: "bookid",
+		Status:    shipping_provider.StatusRequestPickup,
+	}
+
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
+	shipper.Mock.On("CreateDelivery", mock.Anything).Return(order, message.SuccessMsg).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	orderShippingRepository.Mock.On("Upsert", mock.Anything).Return(orderShipping, errors.New("")).Once()
+
+	result, msg := shippingService.CreateDelivery(context.Background(), createDeliveryRequest)
+
+	assert.NotNil(t, result)
+	assert.NotNil(t, msg)
+	assert.Equal(t, message.ErrSaveOrderShipping, msg)
+}
+
+func TestCreateDeliveryThridPartyCourierInvalid(t *testing.T) {
+
+	channel := entity.Channel{BaseIDModel: base.BaseIDModel{ID: 1, UID: createDeliveryRequest.ChannelUID}}
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(channel).Once()
+
+	courier := &entity.Courier{
+		BaseIDModel: base.BaseIDModel{
+			ID:  3,
+			UID: "cuid",
+		},
+		CourierType: shipping_provider.ThirPartyCourier,
+		Code:        "not shipper",
+		Status:      &active,
+	}
+
+	courierService := &entity.CourierService{
+		BaseIDModel: base.BaseIDModel{
+			ID:  3,
+			UID: createDeliveryRequest.CouirerServiceUID,
+		},
+		CourierID: 2,
+		Courier:   courier,
+		Status:    &active,
+	}
+
+	courierServiceRepo.Mock.On("FindCourierService", mock.Anything).
+		Return(courierService).Once()
+
+	orderShippingRepository.Mock.On("FindByOrderNo", mock.Anything).
+		Return(nil).Once()
+
+	shippingStatus := &entity.ShippingCourierStatus{
 		BaseIDModel: base.BaseIDModel{
 			ID:  4,
 			UID: "ssuid",
@@ -688,7 +1558,7 @@ func TestCreateDeliveryThridPartyCourierInvalid(t *testing.T) {
 	}
 	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(created).Once()
 
-	result, msg := shippingService.CreateDelivery(createDeliveryRequest)
+	result, msg := shippingService.CreateDelivery(context.Background(), createDeliveryRequest)
 
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
@@ -752,7 +1622,7 @@ func TestCreateDeliveryInvalidCourierType(t *testing.T) {
 	}
 	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(created).Once()
 
-	result, msg := shippingService.CreateDelivery(createDeliveryRequest)
+	result, msg := shippingService.CreateDelivery(context.Background(), createDeliveryRequest)
 
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
@@ -821,8 +1691,10 @@ func TestCreateDeliveryShipperFailed(t *testing.T) {
 		Status:    shipping_provider.StatusRequestPickup,
 	}
 
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	shipper.Mock.On("CreateDelivery", mock.Anything).Return(order, message.FailedMsg).Once()
-	result, msg := shippingService.CreateDelivery(createDeliveryRequest)
+	result, msg := shippingService.CreateDelivery(context.Background(), createDeliveryRequest)
 
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
@@ -891,8 +1763,10 @@ func TestCreateDeliveryGrabFailed(t *testing.T) {
 		Status:    shipping_provider.StatusRequestPickup,
 	}
 
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	grab.Mock.On("CreateDelivery", mock.Anything).Return(order, message.FailedMsg).Once()
-	result, msg := shippingService.CreateDelivery(createDeliveryRequest)
+	result, msg := shippingService.CreateDelivery(context.Background(), createDeliveryRequest)
 
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
@@ -943,7 +1817,7 @@ func TestCreateDeliveryShipperShippingStatusNotFound(t *testing.T) {
 	}
 
 	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(shippingStatus, errors.New("")).Once()
-	result, msg := shippingService.CreateDelivery(createDeliveryRequest)
+	result, msg := shippingService.CreateDelivery(context.Background(), createDeliveryRequest)
 
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
@@ -990,7 +1864,7 @@ func TestCreateDeliveryShipperOrderShippingAlreadyExist(t *testing.T) {
 	orderShippingRepository.Mock.On("FindByOrderNo", mock.Anything).
 		Return(orderShipping).Once()
 
-	result, msg := shippingService.CreateDelivery(createDeliveryRequest)
+	result, msg := shippingService.CreateDelivery(context.Background(), createDeliveryRequest)
 
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
@@ -1029,7 +1903,7 @@ func TestCreateDeliveryShipperOrderShippingError(t *testing.T) {
 	orderShippingRepository.Mock.On("FindByOrderNo", mock.Anything).
 		Return(nil, errors.New("")).Once()
 
-	result, msg := shippingService.CreateDelivery(createDeliveryRequest)
+	result, msg := shippingService.CreateDelivery(context.Background(), createDeliveryRequest)
 
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
@@ -1066,7 +1940,7 @@ func TestCreateDeliveryShipperCoureirNotActive(t *testing.T) {
 	courierServiceRepo.Mock.On("FindCourierService", mock.Anything).
 		Return(courierService).Once()
 
-	result, msg := shippingService.CreateDelivery(createDeliveryRequest)
+	result, msg := shippingService.CreateDelivery(context.Background(), createDeliveryRequest)
 
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
@@ -1102,7 +1976,7 @@ func TestCreateDeliveryShipperCoureirServiceNotActive(t *testing.T) {
 	courierServiceRepo.Mock.On("FindCourierService", mock.Anything).
 		Return(courierService).Once()
 
-	result, msg := shippingService.CreateDelivery(createDeliveryRequest)
+	result, msg := shippingService.CreateDelivery(context.Background(), createDeliveryRequest)
 
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
@@ -1142,50 +2016,814 @@ func TestCreateDeliveryShipperCoureirServiceWeightExeeds(t *testing.T) {
 
 	req := *createDeliveryRequest
 	req.Package.ContainPrescription = 1
-	result, msg := shippingService.CreateDelivery(&req)
+	result, msg := shippingService.CreateDelivery(context.Background(), &req)
 
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.WeightExceedsMsg, msg)
 }
 
-func TestCreateDeliveryShipperCoureirServicePrescriptionNotAllowed(t *testing.T) {
+func TestCreateDeliveryShipperCoureirServicePrescriptionNotAllowed(t *testing.T) {
+
+	channel := entity.Channel{BaseIDModel: base.BaseIDModel{ID: 1, UID: createDeliveryRequest.ChannelUID}}
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(channel).Once()
+
+	courier := &entity.Courier{
+		BaseIDModel: base.BaseIDModel{
+			ID:  3,
+			UID: "cuid",
+		},
+		CourierType: shipping_provider.ThirPartyCourier,
+		Code:        shipping_provider.ShipperCode,
+		Status:      &active,
+	}
+
+	courierService := &entity.CourierService{
+		BaseIDModel: base.BaseIDModel{
+			ID:  3,
+			UID: createDeliveryRequest.CouirerServiceUID,
+		},
+		CourierID:           2,
+		Courier:             courier,
+		Status:              &active,
+		PrescriptionAllowed: 0,
+	}
+
+	courierServiceRepo.Mock.On("FindCourierService", mock.Anything).
+		Return(courierService).Once()
+
+	req := *createDeliveryRequest
+	req.Package.ContainPrescription = 1
+	result, msg := shippingService.CreateDelivery(context.Background(), &req)
+
+	assert.NotNil(t, result)
+	assert.NotNil(t, msg)
+	assert.Equal(t, message.PrescriptionNotAllowedMsg, msg)
+}
+
+func TestGetShippingRate_OperatingHoursInOriginTimezone(t *testing.T) {
+	input := request.GetShippingRateRequest{
+		CourierServiceUID: []string{"cheap", "fast"},
+		TotalWeight:       1,
+		Origin:            request.AreaDetailPayload{Timezone: "Asia/Makassar"},
+	}
+
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{ID: 1, UID: "1"}}).Once()
+
+	// open for half an hour around now at the origin, the same clock has passed an hour ago in util.Loc
+	now := time.Now().In(util.LoadLocation("Asia/Makassar"))
+	courierServices := recommendationCourierServices()
+	courierServices[0].StartTime = datatype.Time(now.Add(-15 * time.Minute).Format("15:04:05"))
+	courierServices[0].EndTime = datatype.Time(now.Add(15 * time.Minute).Format("15:04:05"))
+
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	shippingRateQuoteRepository.Mock.On("CreateBatch").Return(nil).Once()
+
+	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
+		Return(courierServices).Once()
+
+	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"aa": true, "bb": true}).Twice()
+
+	rateCardRepository.Mock.On("FindEffective").Return(map[uint64]*entity.RateCard{}).Once()
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
+	assert.Equal(t, message.SuccessMsg, msg, codeIsNotCorrect)
+
+	for _, v := range result[0].Services {
+		if v.CourierServiceUID == "cheap" {
+			assert.Equal(t, 200, v.AvailableCode)
+			assert.NotNil(t, v.EstimatedDeliveryFrom)
+		}
+	}
+}
+
+func TestCreateDeliveryShipperCourierServiceLimits(t *testing.T) {
+	now := time.Now().In(util.Loc)
+	closedFrom := datatype.Time(now.Add(time.Hour).Format("15:04:05-07"))
+	closedUntil := datatype.Time(now.Add(2 * time.Hour).Format("15:04:05-07"))
+
+	tests := []struct {
+		name           string
+		courierService entity.CourierService
+		cod            bool
+		expected       message.Message
+	}{
+		{"volume", entity.CourierService{MaxVolume: 5000}, false, message.VolumeExceedsMsg},
+		{"min purchase", entity.CourierService{MinPurchase: 20000}, false, message.PurchaseBelowMinimumMsg},
+		{"max purchase", entity.CourierService{MaxPurchase: 10000}, false, message.PurchaseExceedsMsg},
+		{"cod", entity.CourierService{CodAvailable: 0}, true, message.CodNotAvailableMsg},
+		{"operating hours", entity.CourierService{StartTime: closedFrom, EndTime: closedUntil}, false, message.OutsideOperatingHoursMsg},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channelRepository.Mock.On("FindByUid", mock.Anything).
+				Return(entity.Channel{BaseIDModel: base.BaseIDModel{ID: 1, UID: createDeliveryRequest.ChannelUID}}).Once()
+
+			courierService := tt.courierService
+			courierService.BaseIDModel = base.BaseIDModel{ID: 3, UID: createDeliveryRequest.CouirerServiceUID}
+			courierService.CourierID = 3
+			courierService.Status = &active
+			courierService.Courier = &entity.Courier{
+				BaseIDModel: base.BaseIDModel{ID: 3, UID: "cuid"},
+				CourierType: shipping_provider.ThirPartyCourier,
+				Code:        shipping_provider.ShipperCode,
+				Status:      &active,
+			}
+
+			courierServiceRepo.Mock.On("FindCourierService", mock.Anything).
+				Return(&courierService).Once()
+
+			req := *createDeliveryRequest
+			req.COD = tt.cod
+			_, msg := shippingService.CreateDelivery(context.Background(), &req)
+
+			assert.Equal(t, tt.expected, msg)
+		})
+	}
+}
+
+func TestGetShippingRate_Internal_LimitsSuccess(t *testing.T) {
+	input := request.GetShippingRateRequest{
+		CourierServiceUID: []string{"", "", ""},
+		TotalWeight:       1,
+		TotalProductPrice: 50000,
+		COD:               true,
+		Origin:            request.AreaDetailPayload{Latitude: "-6.2", Longitude: "106.8"},
+		Destination:       request.AreaDetailPayload{Latitude: "-6.3", Longitude: "106.8"},
+	}
+
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
+
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	shippingRateQuoteRepository.Mock.On("CreateBatch").Return(nil).Once()
+
+	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
+		Return([]entity.ChannelCourierServiceForShippingRate{
+			{CourierID: 2, CourierCode: "bb", CourierTypeCode: "merchant", ShippingTypeCode: "instant", ShippingCode: "distance", MaxDistance: 5, CodAvailable: 1,
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+			{CourierID: 2, CourierCode: "bb", CourierTypeCode: "merchant", ShippingTypeCode: "instant", ShippingCode: "cod",
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+			{CourierID: 2, CourierCode: "bb", CourierTypeCode: "merchant", ShippingTypeCode: "instant", ShippingCode: "eligible", MaxDistance: 50, MaxPurchase: 100000, CodAvailable: 1,
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+		}).Once()
+
+	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"bb": true}).Twice()
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
+	assert.Equal(t, msg, message.SuccessMsg, codeIsNotCorrect)
+	assert.Len(t, result[0].Services, 3)
+	assert.Equal(t, message.DistanceExceedsMsg.Message, result[0].Services[0].Error.Message)
+	assert.Equal(t, message.CodNotAvailableMsg.Message, result[0].Services[1].Error.Message)
+	assert.Equal(t, 200, result[0].Services[2].AvailableCode)
+}
+
+func TestGetShippingRate_Internal_CodFeeSuccess(t *testing.T) {
+	input := request.GetShippingRateRequest{
+		CourierServiceUID: []string{"", ""},
+		TotalWeight:       1,
+		TotalProductPrice: 50000,
+		COD:               true,
+	}
+
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
+
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	shippingRateQuoteRepository.Mock.On("CreateBatch").Return(nil).Once()
+
+	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
+		Return([]entity.ChannelCourierServiceForShippingRate{
+			{CourierID: 2, CourierCode: "bb", CourierTypeCode: "merchant", ShippingTypeCode: "instant", ShippingCode: "percentage", CodAvailable: 1,
+				CodFeeType: entity.FeeTypePercentage, CodFee: 2, CodFeeMin: 5000,
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+			{CourierID: 2, CourierCode: "bb", CourierTypeCode: "merchant", ShippingTypeCode: "instant", ShippingCode: "fixed", CodAvailable: 1,
+				CodFeeType: entity.FeeTypeFixed, CodFee: 3000,
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+		}).Once()
+
+	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"bb": true}).Twice()
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
+	assert.Equal(t, msg, message.SuccessMsg, codeIsNotCorrect)
+	assert.Len(t, result[0].Services, 2)
+	assert.True(t, result[0].Services[0].CodAvailable)
+	assert.Equal(t, float64(5000), result[0].Services[0].CodFee)
+	assert.Equal(t, float64(3000), result[0].Services[1].CodFee)
+}
+
+func TestGetShippingRate_ReliabilityFallback(t *testing.T) {
+	input := request.GetShippingRateRequest{
+		CourierServiceUID: []string{""},
+		TotalWeight:       1,
+	}
+
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
+
+	// the rates are still recommended with the default weights and a neutral reliability
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil, errors.New("")).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil, errors.New("")).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	shippingRateQuoteRepository.Mock.On("CreateBatch").Return(nil).Once()
+
+	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
+		Return([]entity.ChannelCourierServiceForShippingRate{
+			{CourierID: 2, CourierCode: "bb", CourierTypeCode: "merchant", ShippingTypeCode: "instant", ShippingCode: "bb",
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+		}).Once()
+
+	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"bb": true}).Twice()
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Len(t, result[0].Services, 1)
+	assert.True(t, result[0].Services[0].Recommended)
+}
+
+func TestGetShippingRate_Internal_InsuranceSuccess(t *testing.T) {
+	input := request.GetShippingRateRequest{
+		CourierServiceUID: []string{"", "", ""},
+		TotalWeight:       1,
+		TotalProductPrice: 200000,
+	}
+
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
+
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	shippingRateQuoteRepository.Mock.On("CreateBatch").Return(nil).Once()
+
+	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
+		Return([]entity.ChannelCourierServiceForShippingRate{
+			{CourierID: 2, CourierCode: "bb", CourierTypeCode: "merchant", ShippingTypeCode: "instant", ShippingCode: "mandatory",
+				UseInsurance: true, InsuranceMandatory: 1, InsuranceFeeType: entity.FeeTypePercentage, InsuranceFee: 0.5, InsuranceMin: 500,
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+			{CourierID: 2, CourierCode: "bb", CourierTypeCode: "merchant", ShippingTypeCode: "instant", ShippingCode: "optional",
+				UseInsurance: true, InsuranceFeeType: entity.FeeTypeFixed, InsuranceFee: 2500,
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+			{CourierID: 2, CourierCode: "bb", CourierTypeCode: "merchant", ShippingTypeCode: "instant", ShippingCode: "none", InsuranceFee: 2500,
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+		}).Once()
+
+	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"bb": true}).Twice()
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
+	assert.Equal(t, msg, message.SuccessMsg, codeIsNotCorrect)
+	assert.Len(t, result[0].Services, 3)
+
+	mandatory := result[0].Services[0]
+	assert.Equal(t, float64(1000), mandatory.InsuranceFee)
+	assert.Equal(t, float64(200000), mandatory.InsuredValue)
+	assert.True(t, mandatory.MustUseInsurance)
+	assert.True(t, mandatory.InsuranceApplied)
+
+	optional := result[0].Services[1]
+	assert.Equal(t, float64(2500), optional.InsuranceFee)
+	assert.False(t, optional.MustUseInsurance)
+	assert.False(t, optional.InsuranceApplied)
+
+	none := result[0].Services[2]
+	assert.Equal(t, float64(0), none.InsuranceFee)
+	assert.Equal(t, float64(0), none.InsuredValue)
+}
+
+func TestCourierServiceInsurancePremium(t *testing.T) {
+	insurance := &entity.CourierServiceInsurance{Available: true, FeeType: entity.FeeTypePercentage, Fee: 0.5, Min: 500}
+
+	premium := insurance.Calculate(50000, true)
+	assert.Equal(t, float64(500), premium.Premium)
+	assert.True(t, premium.Applied)
+
+	premium = insurance.Calculate(50000, false)
+	assert.False(t, premium.Applied)
+
+	// unknown fee types are charged as a fixed premium
+	insurance = &entity.CourierServiceInsurance{Available: true, FeeType: "flat", Fee: 1500}
+	assert.Equal(t, float64(1500), insurance.Calculate(50000, true).Premium)
+}
+
+func TestGetShippingRate_Internal_PriceRulesSuccess(t *testing.T) {
+	input := request.GetShippingRateRequest{
+		CourierServiceUID: []string{"", ""},
+		TotalWeight:       3,
+		Destination:       request.AreaDetailPayload{PostalCode: "12950"},
+	}
+
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
+
+	courierID := uint64(2)
+	courierServiceID := uint64(7)
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{
+		{BaseIDModel: base.BaseIDModel{UID: "markup"}, Name: "markup", CourierID: &courierID,
+			AdjustmentType: entity.PriceAdjustmentMarkupPercentage, AdjustmentValue: 10, RoundTo: 500, Rounding: entity.RoundingUp},
+		{BaseIDModel: base.BaseIDModel{UID: "subsidy"}, Name: "subsidy", CourierServiceID: &courierServiceID, PostalCodePrefix: "129",
+			AdjustmentType: entity.PriceAdjustmentDiscountFixed, AdjustmentValue: 2000},
+		{BaseIDModel: base.BaseIDModel{UID: "heavy"}, Name: "heavy", MinWeight: 5,
+			AdjustmentType: entity.PriceAdjustmentMarkupFixed, AdjustmentValue: 9000},
+	}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+	shippingRateQuoteRepository.Mock.On("CreateBatch").Return(nil).Once()
+
+	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
+		Return([]entity.ChannelCourierServiceForShippingRate{
+			{CourierID: 2, CourierServiceID: 7, CourierCode: "bb", CourierTypeCode: "merchant", ShippingTypeCode: "instant", ShippingCode: "subsidized",
+				CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+			{CourierID: 3, CourierServiceID: 8, CourierCode: "cc", CourierTypeCode: "internal", ShippingTypeCode: "instant", ShippingCode: "raw",
+				Price: 10000, CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+		}).Once()
+
+	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"bb": true, "cc": true}).Twice()
+	rateCardRepository.Mock.On("FindEffective").Return(map[uint64]*entity.RateCard{}).Once()
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
+	assert.Equal(t, msg, message.SuccessMsg, codeIsNotCorrect)
+	assert.Len(t, result[0].Services, 2)
+
+	// merchant price of 0 is marked up to 0 then the subsidy can not go below 0
+	subsidized := result[0].Services[0]
+	assert.Equal(t, float64(0), subsidized.OriginalPrice)
+	assert.Equal(t, float64(0), subsidized.TotalPrice)
+	assert.Len(t, subsidized.PriceAdjustments, 2)
+
+	raw := result[0].Services[1]
+	assert.Equal(t, float64(10000), raw.OriginalPrice)
+	assert.Equal(t, float64(10000), raw.TotalPrice)
+	assert.Empty(t, raw.PriceAdjustments)
+}
+
+func TestChannelPriceRuleApply(t *testing.T) {
+	markup := &entity.ChannelPriceRule{AdjustmentType: entity.PriceAdjustmentMarkupPercentage, AdjustmentValue: 10, RoundTo: 500, Rounding: entity.RoundingUp}
+	assert.Equal(t, float64(12000), markup.Apply(10600))
+
+	discount := &entity.ChannelPriceRule{AdjustmentType: entity.PriceAdjustmentDiscountPercentage, AdjustmentValue: 15, RoundTo: 100}
+	assert.Equal(t, float64(8500), discount.Apply(10000))
+
+	price, adjustments := entity.ApplyPriceRules([]entity.ChannelPriceRule{*markup, *discount}, &entity.PricedShipment{}, 10600)
+	assert.Equal(t, float64(10200), price)
+	assert.Equal(t, float64(1400), adjustments[0].Amount)
+	assert.Equal(t, float64(-1800), adjustments[1].Amount)
+}
+
+func TestOrderShippingApplyCodFee(t *testing.T) {
+	courierService := &entity.CourierService{CodFeeType: entity.FeeTypePercentage, CodFee: 2, CodFeeMin: 1000}
+
+	orderShipping := &entity.OrderShipping{}
+	orderShipping.FromCreateDeliveryRequest(&request.CreateDelivery{
+		COD:     true,
+		Package: request.CreateDeliveryPackage{TotalProductPrice: 150000},
+	})
+	orderShipping.TotalShippingCost = 10000
+	orderShipping.ActualShippingCost = 10000
+	orderShipping.ApplyCodFee(courierService)
+
+	assert.Equal(t, float64(150000), orderShipping.CodAmount)
+	assert.Equal(t, float64(3000), orderShipping.CodFee)
+	assert.Equal(t, float64(10000), orderShipping.TotalShippingCost)
+	assert.Equal(t, float64(10000), orderShipping.ActualShippingCost)
+
+	orderShipping = &entity.OrderShipping{TotalShippingCost: 10000}
+	orderShipping.ApplyCodFee(courierService)
+
+	assert.Equal(t, float64(0), orderShipping.CodFee)
+	assert.Equal(t, float64(10000), orderShipping.TotalShippingCost)
+}
+
+func TestGetShippingRate_Internal_ShippingPromotionSuccess(t *testing.T) {
+	input := request.GetShippingRateRequest{
+		CourierServiceUID: []string{"", ""},
+		TotalWeight:       1,
+		TotalProductPrice: 150000,
+		VoucherCode:       "ongkir",
+	}
+
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+
+	courierServiceID := uint64(7)
+	startTime := time.Now().Add(-time.Hour)
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{
+		{BaseIDModel: base.BaseIDModel{UID: "free"}, Name: "free ongkir", CourierServiceID: &courierServiceID, MinTotalProductPrice: 100000,
+			StartTime: startTime, DiscountType: entity.PromotionDiscountFreeShipping, MaxDiscount: 8000},
+		{BaseIDModel: base.BaseIDModel{UID: "voucher"}, Name: "voucher", VoucherCode: "ONGKIR",
+			StartTime: startTime, DiscountType: entity.PromotionDiscountFixed, DiscountValue: 3000},
+	}).Once()
+	shippingRateQuoteRepository.Mock.On("CreateBatch").Return(nil).Once()
+
+	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
+		Return([]entity.ChannelCourierServiceForShippingRate{
+			{CourierID: 3, CourierServiceID: 7, CourierCode: "cc", CourierTypeCode: "internal", ShippingTypeCode: "instant", ShippingCode: "free",
+				Price: 10000, CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+			{CourierID: 3, CourierServiceID: 8, CourierCode: "cc", CourierTypeCode: "internal", ShippingTypeCode: "instant", ShippingCode: "voucher",
+				Price: 10000, CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+		}).Once()
+
+	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"cc": true}).Twice()
+	rateCardRepository.Mock.On("FindEffective").Return(map[uint64]*entity.RateCard{}).Once()
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
+	assert.Equal(t, msg, message.SuccessMsg, codeIsNotCorrect)
+	assert.Len(t, result[0].Services, 2)
+
+	free := result[0].Services[0]
+	assert.Equal(t, float64(10000), free.TotalPrice)
+	assert.Equal(t, float64(8000), free.ShippingDiscount)
+	assert.Equal(t, "free", free.Promotion.UID)
+
+	voucher := result[0].Services[1]
+	assert.Equal(t, float64(3000), voucher.ShippingDiscount)
+	assert.Equal(t, "voucher", voucher.Promotion.UID)
+}
+
+func TestShippingPromotionDiscount(t *testing.T) {
+	now := time.Now()
+	endTime := now.Add(time.Hour)
+	promotion := &entity.ShippingPromotion{
+		StartTime:            now.Add(-time.Hour),
+		EndTime:              &endTime,
+		DiscountType:         entity.PromotionDiscountPercentage,
+		DiscountValue:        50,
+		MinTotalProductPrice: 100000,
+		PostalCodePrefix:     "129",
+		BudgetCap:            10000,
+		UsedBudget:           7000,
+	}
+
+	shipment := &entity.PromotedShipment{TotalProductPrice: 100000, PostalCode: "12950", At: now}
+	assert.True(t, promotion.Matches(shipment))
+
+	// the discount is capped by the remaining budget
+	assert.Equal(t, float64(3000), promotion.Discount(20000))
+	assert.Equal(t, float64(2000), promotion.Discount(4000))
+
+	assert.False(t, promotion.Matches(&entity.PromotedShipment{TotalProductPrice: 99000, PostalCode: "12950", At: now}))
+	assert.False(t, promotion.Matches(&entity.PromotedShipment{TotalProductPrice: 100000, PostalCode: "40111", At: now}))
+	assert.False(t, promotion.Matches(&entity.PromotedShipment{TotalProductPrice: 100000, PostalCode: "12950", At: endTime}))
+
+	promotion.VoucherCode = "ONGKIR"
+	assert.False(t, promotion.Matches(shipment))
+	shipment.VoucherCode = "ongkir"
+	assert.True(t, promotion.Matches(shipment))
+
+	best, discount := entity.BestShippingPromotion([]entity.ShippingPromotion{
+		{StartTime: now.Add(-time.Hour), DiscountType: entity.PromotionDiscountFixed, DiscountValue: 5000},
+		{StartTime: now.Add(-time.Hour), DiscountType: entity.PromotionDiscountFreeShipping},
+	}, shipment, 8000)
+	assert.Equal(t, entity.PromotionDiscountFreeShipping, best.DiscountType)
+	assert.Equal(t, float64(8000), discount)
+}
+
+func TestOrderShippingApplyShippingPromotion(t *testing.T) {
+	orderShipping := &entity.OrderShipping{ShippingCost: 10000, TotalShippingCost: 12000, ActualShippingCost: 12000}
+	orderShipping.ApplyShippingPromotion(&entity.ShippingPromotion{BaseIDModel: base.BaseIDModel{ID: 9}}, 10000)
+
+	assert.Equal(t, float64(10000), orderShipping.ShippingDiscount)
+	assert.Equal(t, float64(2000), orderShipping.TotalShippingCost)
+	assert.Equal(t, float64(12000), orderShipping.ActualShippingCost)
+	assert.Equal(t, uint64(9), *orderShipping.ShippingPromotionID)
+	assert.Len(t, orderShipping.ShippingPromotionUsage, 1)
+	assert.Equal(t, float64(10000), orderShipping.ShippingPromotionUsage[0].Amount)
+}
+
+func TestCreateDeliveryShippingPromotionSaveFailed(t *testing.T) {
+	req := idempotentCreateDeliveryRequest()
+	req.IdempotencyKey = "promotion-idempotency-key"
+	idempotencyKeyRepository.Mock.On("FindByKey").Return(nil).Once()
+	idempotencyKeyRepository.Mock.On("Create").Return(nil, nil).Once()
+	mockPopulateCreateDeliveryShipper()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
+	shipper.Mock.On("CreateDelivery", mock.Anything).Return(&response.CreateDeliveryThirdPartyData{
+		BookingID:    "bookid",
+		Status:       shipping_provider.StatusCreated,
+		ShippingCost: 10000,
+	}, message.SuccessMsg).Once()
+	idempotencyKeyRepository.Mock.On("Update").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{
+		{BaseIDModel: base.BaseIDModel{ID: 9, UID: "free"}, StartTime: time.Now().Add(-time.Hour), DiscountType: entity.PromotionDiscountFreeShipping},
+	}).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("ReserveBudget").Return(true).Once()
+	shippingPromotionRepository.Mock.On("ReleaseBudget").Return(nil).Once()
+	orderShippingRepository.Mock.On("Upsert", mock.Anything).Return(nil, errors.New("")).Once()
+
+	_, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	// the budget reserved for the unsaved order is given back
+	assert.Equal(t, message.ErrSaveOrderShipping, msg)
+	shippingPromotionRepository.Mock.AssertNumberOfCalls(t, "ReserveBudget", 1)
+	shippingPromotionRepository.Mock.AssertNumberOfCalls(t, "ReleaseBudget", 1)
+}
+
+func TestGetShippingRate_Internal_RateQuoteSuccess(t *testing.T) {
+	input := request.GetShippingRateRequest{
+		CourierServiceUID: []string{""},
+		TotalWeight:       2,
+	}
+
+	channelRepository.Mock.On("FindByUid", mock.Anything).
+		Return(entity.Channel{BaseIDModel: base.BaseIDModel{UID: "1"}}).Once()
+	channelRecommendationWeightRepository.Mock.On("FindByChannelID").Return(nil).Once()
+	redis.Mock.On("GetJsonStruct", mock.Anything).Return(nil).Once()
+	orderShippingRepository.Mock.On("FindCourierServiceReliability").Return(nil).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	channelPriceRuleRepository.Mock.On("FindActiveByChannelID").Return([]entity.ChannelPriceRule{}).Once()
+	shippingPromotionRepository.Mock.On("FindActiveByChannelID").Return([]entity.ShippingPromotion{}).Once()
+
+	expiredAt := time.Now().Add(15 * time.Minute)
+	shippingRateQuoteRepository.Mock.On("CreateBatch").Return([]entity.ShippingRateQuote{
+		{BaseIDModel: base.BaseIDModel{UID: "quote"}, ExpiredAt: expiredAt},
+	}).Once()
+
+	courierServiceRepo.Mock.On("FindCourierServiceByChannelAndUIDs", mock.Anything).
+		Return([]entity.ChannelCourierServiceForShippingRate{
+			{CourierID: 3, CourierServiceUID: "csuid", CourierCode: "cc", CourierTypeCode: "internal", ShippingTypeCode: "instant", ShippingCode: "quoted",
+				Price: 10000, CourierStatus: 1, CourierServiceStatus: 1, ChannelCourierStatus: 1, ChannelCourierServiceStatus: 1, HidePurpose: 0, PrescriptionAllowed: 1},
+		}).Once()
+
+	courierCoverageCodeRepository.Mock.On("FindInternalAndMerchantCourierCoverage").Return(map[string]bool{"cc": true}).Twice()
+	rateCardRepository.Mock.On("FindEffective").Return(map[uint64]*entity.RateCard{}).Once()
+
+	result, msg := shippingService.GetShippingRate(context.Background(), input)
+	assert.Equal(t, msg, message.SuccessMsg, codeIsNotCorrect)
+	assert.Equal(t, "quote", result[0].Services[0].QuoteID)
+	assert.Equal(t, expiredAt, *result[0].Services[0].QuoteExpiredAt)
+}
+
+func TestShippingRateQuoteValidate(t *testing.T) {
+	now := time.Now()
+	req := &request.CreateDelivery{
+		CouirerServiceUID: "csuid",
+		OrderNo:           "order",
+		Destination:       request.CreateDeiveryArea{PostalCode: "12950"},
+		Package:           request.CreateDeliveryPackage{TotalWeight: 2, TotalProductPrice: 150000},
+	}
+	quote := &entity.ShippingRateQuote{
+		ChannelID:             1,
+		CourierServiceUID:     "csuid",
+		DestinationPostalCode: "12950",
+		TotalWeight:           2,
+		TotalProductPrice:     150000,
+		ExpiredAt:             now.Add(time.Minute),
+	}
+
+	assert.Equal(t, message.SuccessMsg, quote.Validate(1, req, now))
+	assert.Equal(t, message.ErrRateQuoteMismatch, quote.Validate(2, req, now))
+	assert.Equal(t, message.ErrRateQuoteExpired, quote.Validate(1, req, now.Add(time.Minute)))
+
+	quote.OrderNo = "order"
+	assert.Equal(t, message.SuccessMsg, quote.Validate(1, req, now))
+	quote.OrderNo = "another order"
+	assert.Equal(t, message.ErrRateQuoteUsed, quote.Validate(1, req, now))
+
+	quote.OrderNo = ""
+	quote.COD = true
+	assert.Equal(t, message.ErrRateQuoteMismatch, quote.Validate(1, req, now))
+
+	quote.COD = false
+	quote.UseInsurance = true
+	assert.Equal(t, message.ErrRateQuoteMismatch, quote.Validate(1, req, now))
+
+	quote.UseInsurance = false
+	quote.TotalWeight = 3
+	assert.Equal(t, message.ErrRateQuoteMismatch, quote.Validate(1, req, now))
+}
+
+func quoteCreateDeliveryRequest() *request.CreateDelivery {
+	req := *createDeliveryRequest
+	req.QuoteID = "quote"
+	return &req
+}
+
+func mockRateQuote(req *request.CreateDelivery) {
+	shippingRateQuoteRepository.Mock.On("FindByUID").Return(&entity.ShippingRateQuote{
+		BaseIDModel:           base.BaseIDModel{UID: req.QuoteID},
+		ChannelID:             1,
+		CourierServiceUID:     req.CouirerServiceUID,
+		OriginPostalCode:      req.Origin.PostalCode,
+		DestinationPostalCode: req.Destination.PostalCode,
+		TotalWeight:           req.Package.TotalWeight,
+		TotalProductPrice:     req.Package.TotalProductPrice,
+		OriginalPrice:         9000,
+		ExpiredAt:             time.Now().Add(time.Minute),
+	}).Once()
+}
+
+func TestCreateDeliveryRateQuoteSuccess(t *testing.T) {
+	req := quoteCreateDeliveryRequest()
+	mockPopulateCreateDeliveryShipper()
+	mockRateQuote(req)
+	shippingRateQuoteRepository.Mock.On("Claim").Return(true).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
+	shipper.Mock.On("CreateDelivery", mock.Anything).Return(&response.CreateDeliveryThirdPartyData{
+		BookingID:    "bookid",
+		Status:       shipping_provider.StatusCreated,
+		ShippingCost: 10000,
+	}, message.SuccessMsg).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	orderShippingRepository.Mock.On("Upsert", mock.Anything).Return(&entity.OrderShipping{
+		BaseIDModel: base.BaseIDModel{UID: "osuid"},
+	}).Once()
+
+	result, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "osuid", result.OrderShippingUID)
+}
+
+func TestCreateDeliveryRateQuotePromotion(t *testing.T) {
+	req := quoteCreateDeliveryRequest()
+	mockPopulateCreateDeliveryShipper()
+	shippingRateQuoteRepository.Mock.On("FindByUID").Return(&entity.ShippingRateQuote{
+		BaseIDModel:           base.BaseIDModel{UID: req.QuoteID},
+		ChannelID:             1,
+		CourierServiceUID:     req.CouirerServiceUID,
+		OriginPostalCode:      req.Origin.PostalCode,
+		DestinationPostalCode: req.Destination.PostalCode,
+		TotalWeight:           req.Package.TotalWeight,
+		TotalProductPrice:     req.Package.TotalProductPrice,
+		OriginalPrice:         9000,
+		TotalPrice:            9000,
+		ShippingDiscount:      9000,
+		ShippingPromotionUID:  "free",
+		ExpiredAt:             time.Now().Add(time.Minute),
+	}).Once()
+	shippingRateQuoteRepository.Mock.On("Claim").Return(true).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
+	shipper.Mock.On("CreateDelivery", mock.Anything).Return(&response.CreateDeliveryThirdPartyData{
+		BookingID:         "bookid",
+		Status:            shipping_provider.StatusCreated,
+		ShippingCost:      10000,
+		TotalShippingCost: 10000,
+	}, message.SuccessMsg).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	// the promotion of the quote is booked without evaluating the active promotions again
+	shippingPromotionRepository.Mock.On("FindByUID").Return(&entity.ShippingPromotion{
+		BaseIDModel: base.BaseIDModel{ID: 9, UID: "free"},
+	}).Once()
+	shippingPromotionRepository.Mock.On("ReserveBudget").Return(true).Once()
+	orderShippingRepository.Mock.On("Upsert", mock.Anything).Return(&entity.OrderShipping{
+		BaseIDModel: base.BaseIDModel{UID: "osuid"},
+	}).Once()
+
+	result, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "osuid", result.OrderShippingUID)
+}
+
+func TestCreateDeliveryRateQuotePromotionEnded(t *testing.T) {
+	req := quoteCreateDeliveryRequest()
+	mockPopulateCreateDeliveryShipper()
+	shippingRateQuoteRepository.Mock.On("FindByUID").Return(&entity.ShippingRateQuote{
+		BaseIDModel:           base.BaseIDModel{UID: req.QuoteID},
+		ChannelID:             1,
+		CourierServiceUID:     req.CouirerServiceUID,
+		OriginPostalCode:      req.Origin.PostalCode,
+		DestinationPostalCode: req.Destination.PostalCode,
+		TotalWeight:           req.Package.TotalWeight,
+		TotalProductPrice:     req.Package.TotalProductPrice,
+		OriginalPrice:         9000,
+		TotalPrice:            9000,
+		ShippingDiscount:      9000,
+		ShippingPromotionUID:  "free",
+		ExpiredAt:             time.Now().Add(time.Minute),
+	}).Once()
+	shippingRateQuoteRepository.Mock.On("Claim").Return(true).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
+	shipper.Mock.On("CreateDelivery", mock.Anything).Return(&response.CreateDeliveryThirdPartyData{
+		BookingID:         "bookid",
+		Status:            shipping_provider.StatusCreated,
+		ShippingCost:      10000,
+		TotalShippingCost: 10000,
+	}, message.SuccessMsg).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	// the promotion ended after the quote, its budget is not reserved
+	endTime := time.Now().Add(-time.Second)
+	shippingPromotionRepository.Mock.On("FindByUID").Return(&entity.ShippingPromotion{
+		BaseIDModel: base.BaseIDModel{ID: 9, UID: "free"},
+		EndTime:     &endTime,
+	}).Once()
+	orderShippingRepository.Mock.On("Upsert", mock.Anything).Return(&entity.OrderShipping{
+		BaseIDModel: base.BaseIDModel{UID: "osuid"},
+	}).Once()
+
+	result, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "osuid", result.OrderShippingUID)
+}
+
+func TestCreateDeliveryRateQuoteUsed(t *testing.T) {
+	req := quoteCreateDeliveryRequest()
+	mockPopulateCreateDeliveryShipper()
+	mockRateQuote(req)
+	shippingRateQuoteRepository.Mock.On("Claim").Return(false).Once()
+
+	_, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.ErrRateQuoteUsed, msg)
+}
+
+func TestCreateDeliveryRateQuoteNotFound(t *testing.T) {
+	req := quoteCreateDeliveryRequest()
+	mockPopulateCreateDeliveryShipper()
+	shippingRateQuoteRepository.Mock.On("FindByUID").Return(nil).Once()
+
+	_, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	assert.Equal(t, message.ErrRateQuoteNotFound, msg)
+}
+
+func TestCreateDeliveryRateQuoteProviderFailed(t *testing.T) {
+	req := quoteCreateDeliveryRequest()
+	mockPopulateCreateDeliveryShipper()
+	mockRateQuote(req)
+	shippingRateQuoteRepository.Mock.On("Claim").Return(true).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
+	shipper.Mock.On("CreateDelivery", mock.Anything).Return(&response.CreateDeliveryThirdPartyData{}, message.ErrCreateOrder).Once()
+	shippingRateQuoteRepository.Mock.On("Release").Return(nil).Once()
+
+	_, msg := shippingService.CreateDelivery(context.Background(), req)
+
+	// nothing was booked, the quote can be used again
+	assert.Equal(t, message.ErrCreateOrder, msg)
+	shippingRateQuoteRepository.Mock.AssertNumberOfCalls(t, "Release", 1)
+}
+
+func TestCreateDeliveryRateQuoteSaveFailed(t *testing.T) {
+	req := quoteCreateDeliveryRequest()
+	mockPopulateCreateDeliveryShipper()
+	mockRateQuote(req)
+	shippingRateQuoteRepository.Mock.On("Claim").Return(true).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
 
-	channel := entity.Channel{BaseIDModel: base.BaseIDModel{ID: 1, UID: createDeliveryRequest.ChannelUID}}
-	channelRepository.Mock.On("FindByUid", mock.Anything).
-		Return(channel).Once()
+	shipper.Mock.On("CreateDelivery", mock.Anything).Return(&response.CreateDeliveryThirdPartyData{
+		BookingID: "bookid",
+		Status:    shipping_provider.StatusCreated,
+	}, message.SuccessMsg).Once()
+	courierHolidayRepository.Mock.On("FindByCourierIDs").Return(nil).Once()
+	orderShippingRepository.Mock.On("Upsert", mock.Anything).Return(nil, errors.New("")).Once()
+	shippingRateQuoteRepository.Mock.On("Release").Return(nil).Once()
 
-	courier := &entity.Courier{
-		BaseIDModel: base.BaseIDModel{
-			ID:  3,
-			UID: "cuid",
-		},
-		CourierType: shipping_provider.ThirPartyCourier,
-		Code:        shipping_provider.ShipperCode,
-		Status:      &active,
-	}
+	_, msg := shippingService.CreateDelivery(context.Background(), req)
 
-	courierService := &entity.CourierService{
-		BaseIDModel: base.BaseIDModel{
-			ID:  3,
-			UID: createDeliveryRequest.CouirerServiceUID,
-		},
-		CourierID:           2,
-		Courier:             courier,
-		Status:              &active,
-		PrescriptionAllowed: 0,
-	}
+	// the order was not saved, the quote is not kept claimed by it
+	assert.Equal(t, message.ErrSaveOrderShipping, msg)
+	shippingRateQuoteRepository.Mock.AssertNumberOfCalls(t, "Release", 2)
+}
 
-	courierServiceRepo.Mock.On("FindCourierService", mock.Anything).
-		Return(courierService).Once()
+func TestPurgeExpiredRateQuotes(t *testing.T) {
+	shippingRateQuoteRepository.Mock.On("DeleteExpired").Return(int64(3)).Once()
 
-	req := *createDeliveryRequest
-	req.Package.ContainPrescription = 1
-	result, msg := shippingService.CreateDelivery(&req)
+	assert.Equal(t, 3, shippingService.PurgeExpiredRateQuotes())
+}
 
-	assert.NotNil(t, result)
-	assert.NotNil(t, msg)
-	assert.Equal(t, message.PrescriptionNotAllowedMsg, msg)
+func TestPurgeExpiredRateQuotesError(t *testing.T) {
+	shippingRateQuoteRepository.Mock.On("DeleteExpired").Return(int64(0), errors.New("")).Once()
+
+	assert.Equal(t, 0, shippingService.PurgeExpiredRateQuotes())
 }
 
 func TestCreateDeliveryShipperCourierServiceNotFound(t *testing.T) {
@@ -1197,7 +2835,7 @@ func TestCreateDeliveryShipperCourierServiceNotFound(t *testing.T) {
 	courierServiceRepo.Mock.On("FindCourierService", mock.Anything).
 		Return(nil, nil).Once()
 
-	result, msg := shippingService.CreateDelivery(createDeliveryRequest)
+	result, msg := shippingService.CreateDelivery(context.Background(), createDeliveryRequest)
 
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
@@ -1213,7 +2851,7 @@ func TestCreateDeliveryShipperCourierServiceNotFoundError(t *testing.T) {
 	courierServiceRepo.Mock.On("FindCourierService", mock.Anything).
 		Return(nil, errors.New("")).Once()
 
-	result, msg := shippingService.CreateDelivery(createDeliveryRequest)
+	result, msg := shippingService.CreateDelivery(context.Background(), createDeliveryRequest)
 
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
@@ -1224,7 +2862,7 @@ func TestCreateDeliveryShipperChannelNotFound(t *testing.T) {
 	channelRepository.Mock.On("FindByUid", mock.Anything).
 		Return(nil).Once()
 
-	result, msg := shippingService.CreateDelivery(createDeliveryRequest)
+	result, msg := shippingService.CreateDelivery(context.Background(), createDeliveryRequest)
 
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
@@ -1235,7 +2873,7 @@ func TestCreateDeliveryShipperChannelNotFoundError(t *testing.T) {
 	channelRepository.Mock.On("FindByUid", mock.Anything).
 		Return(nil, errors.New("")).Once()
 
-	result, msg := shippingService.CreateDelivery(createDeliveryRequest)
+	result, msg := shippingService.CreateDelivery(context.Background(), createDeliveryRequest)
 
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
@@ -1275,10 +2913,12 @@ func TestOrderShippingTrackingShipperSuccess(t *testing.T) {
 			Channel:   channel,
 		}).Once()
 
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	shipper.Mock.On("GetTracking", mock.Anything).
 		Return([]response.GetOrderShippingTracking{}).Once()
 
-	result, msg := shippingService.OrderShippingTracking(getOrderTrackingRequest)
+	result, msg := shippingService.OrderShippingTracking(context.Background(), getOrderTrackingRequest)
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.SuccessMsg, msg)
@@ -1312,15 +2952,54 @@ func TestOrderShippingTrackingGrabSuccess(t *testing.T) {
 			Channel:   channel,
 		}).Once()
 
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	grab.Mock.On("GetTracking", mock.Anything).
 		Return([]response.GetOrderShippingTracking{}).Once()
 
-	result, msg := shippingService.OrderShippingTracking(getOrderTrackingRequest)
+	result, msg := shippingService.OrderShippingTracking(context.Background(), getOrderTrackingRequest)
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.SuccessMsg, msg)
 }
 
+func TestOrderShippingTrackingGrabCredentialFailed(t *testing.T) {
+	courier := &entity.Courier{
+		BaseIDModel: base.BaseIDModel{
+			ID:  1,
+			UID: "COURIER_UID",
+		},
+		CourierType: shipping_provider.ThirPartyCourier,
+		Code:        shipping_provider.GrabCode,
+	}
+
+	channel := &entity.Channel{
+		BaseIDModel: base.BaseIDModel{
+			ID:  1,
+			UID: getOrderTrackingRequest.ChannelUID,
+		},
+	}
+
+	orderShippingRepository.Mock.On("FindByUID", mock.Anything).
+		Return(&entity.OrderShipping{
+			BaseIDModel: base.BaseIDModel{
+				UID: getOrderTrackingRequest.UID,
+			},
+			CourierID: courier.ID,
+			BookingID: "GRAB_ORDER_ID",
+			Courier:   courier,
+			Channel:   channel,
+		}).Once()
+
+	// the secret was not encrypted with the key of the service, grab is not called
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").
+		Return(&entity.ChannelCourierCredential{ClientID: "channel-client", Secret: "not-encrypted"}).Once()
+
+	result, msg := shippingService.OrderShippingTracking(context.Background(), getOrderTrackingRequest)
+	assert.Nil(t, result)
+	assert.Equal(t, message.ProviderCredentialMsg, msg)
+}
+
 func TestOrderShippingTrackingGrabGetOrderDetailError(t *testing.T) {
 	courier := &entity.Courier{
 		BaseIDModel: base.BaseIDModel{
@@ -1349,10 +3028,12 @@ func TestOrderShippingTrackingGrabGetOrderDetailError(t *testing.T) {
 			Channel:   channel,
 		}).Once()
 
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	grab.Mock.On("GetTracking", mock.Anything).
 		Return(nil, message.ErrGetOrderDetail).Once()
 
-	result, msg := shippingService.OrderShippingTracking(getOrderTrackingRequest)
+	result, msg := shippingService.OrderShippingTracking(context.Background(), getOrderTrackingRequest)
 	assert.Nil(t, result)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrGetOrderDetail, msg)
@@ -1386,10 +3067,12 @@ func TestOrderShippingTrackingShipperGetOrderDetailError(t *testing.T) {
 			Channel:   channel,
 		}).Once()
 
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	shipper.Mock.On("GetTracking", mock.Anything).
 		Return(nil, message.ErrGetOrderDetail).Once()
 
-	result, msg := shippingService.OrderShippingTracking(getOrderTrackingRequest)
+	result, msg := shippingService.OrderShippingTracking(context.Background(), getOrderTrackingRequest)
 	assert.Nil(t, result)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrGetOrderDetail, msg)
@@ -1423,7 +3106,7 @@ func TestOrderShippingTrackingInvalidThirdPartyCourier(t *testing.T) {
 			Channel:   channel,
 		}).Once()
 
-	result, msg := shippingService.OrderShippingTracking(getOrderTrackingRequest)
+	result, msg := shippingService.OrderShippingTracking(context.Background(), getOrderTrackingRequest)
 	assert.Nil(t, result)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrInvalidCourierCode, msg)
@@ -1456,12 +3139,63 @@ func TestOrderShippingTrackingInvalidCourierType(t *testing.T) {
 			Channel:   channel,
 		}).Once()
 
-	result, msg := shippingService.OrderShippingTracking(getOrderTrackingRequest)
+	result, msg := shippingService.OrderShippingTracking(context.Background(), getOrderTrackingRequest)
 	assert.NotNil(t, result)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrInvalidCourierType, msg)
 }
 
+func TestOrderShippingTrackingInternalSuccess(t *testing.T) {
+	courier := &entity.Courier{
+		BaseIDModel: base.BaseIDModel{
+			ID:  1,
+			UID: "COURIER_UID",
+		},
+		CourierType: shipping_provider.InternalCourier,
+		Code:        "kurir-kd",
+	}
+
+	channel := &entity.Channel{
+		BaseIDModel: base.BaseIDModel{
+			ID:  1,
+			UID: getOrderTrackingRequest.ChannelUID,
+		},
+	}
+
+	now := time.Now()
+	orderShippingRepository.Mock.On("FindByUID", mock.Anything).
+		Return(&entity.OrderShipping{
+			BaseIDModel: base.BaseIDModel{
+				UID: getOrderTrackingRequest.UID,
+			},
+			CourierID: courier.ID,
+			Courier:   courier,
+			Channel:   channel,
+			OrderShippingHistory: []entity.OrderShippingHistory{
+				{
+					BaseIDModel: base.BaseIDModel{CreatedAt: now.Add(-time.Hour)},
+					StatusCode:  shipping_provider.StatusCreated,
+					Note:        "Booking ID [BK-1]",
+					ShippingCourierStatus: &entity.ShippingCourierStatus{
+						ShippingStatus: &entity.ShippingStatus{StatusName: "Order Created"},
+					},
+				},
+				{
+					BaseIDModel: base.BaseIDModel{CreatedAt: now},
+					StatusCode:  "delivered",
+					Note:        "delivered to customer",
+				},
+			},
+		}).Once()
+
+	result, msg := shippingService.OrderShippingTracking(context.Background(), getOrderTrackingRequest)
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "delivered", result[0].Status)
+	assert.Equal(t, "Order Created", result[1].Status)
+	assert.Equal(t, "Booking ID [BK-1]", result[1].Note)
+}
+
 func TestOrderShippingTrackingOrderNotBelongToChannel(t *testing.T) {
 	courier := &entity.Courier{
 		BaseIDModel: base.BaseIDModel{
@@ -1489,7 +3223,7 @@ func TestOrderShippingTrackingOrderNotBelongToChannel(t *testing.T) {
 			Channel:   channel,
 		}).Once()
 
-	result, msg := shippingService.OrderShippingTracking(getOrderTrackingRequest)
+	result, msg := shippingService.OrderShippingTracking(context.Background(), getOrderTrackingRequest)
 	assert.Nil(t, result)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrOrderBelongToAnotherChannel, msg)
@@ -1499,7 +3233,7 @@ func TestOrderShippingTrackingGetOrderShippingNotFound(t *testing.T) {
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).
 		Return(nil).Once()
 
-	result, msg := shippingService.OrderShippingTracking(getOrderTrackingRequest)
+	result, msg := shippingService.OrderShippingTracking(context.Background(), getOrderTrackingRequest)
 	assert.Nil(t, result)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrOrderShippingNotFound, msg)
@@ -1509,14 +3243,14 @@ func TestOrderShippingTrackingGetOrderShippingError(t *testing.T) {
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).
 		Return(nil, errors.New("")).Once()
 
-	result, msg := shippingService.OrderShippingTracking(getOrderTrackingRequest)
+	result, msg := shippingService.OrderShippingTracking(context.Background(), getOrderTrackingRequest)
 	assert.Nil(t, result)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrOrderShippingNotFound, msg)
 }
 
 func TestOrderShippingTrackingChannelUIDRequired(t *testing.T) {
-	result, msg := shippingService.OrderShippingTracking(&request.GetOrderShippingTracking{UID: "", ChannelUID: ""})
+	result, msg := shippingService.OrderShippingTracking(context.Background(), &request.GetOrderShippingTracking{UID: "", ChannelUID: ""})
 	assert.Nil(t, result)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrChannelUIDRequired, msg)
@@ -1544,6 +3278,7 @@ func TestUpdateStatusShipper(t *testing.T) {
 	shippingCourierStatusRepository.Mock.On("FindByCourierStatus").Return(&entity.ShippingCourierStatus{
 		ShippingStatus: &entity.ShippingStatus{},
 	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("Upsert").Return(&entity.OrderShipping{
 		Channel:        &entity.Channel{},
 		Courier:        &entity.Courier{},
@@ -1559,11 +3294,16 @@ func TestUpdateStatusShipper(t *testing.T) {
 
 func TestUpdateStatusShipperSaveFailed(t *testing.T) {
 	orderShippingRepository.Mock.On("FindByOrderNo").Return(&entity.OrderShipping{
+		Channel: &entity.Channel{},
 		Courier: &entity.Courier{
 			Code: shipping_provider.ShipperCode,
 		},
+		CourierService: &entity.CourierService{},
 	}).Once()
-	shippingCourierStatusRepository.Mock.On("FindByCourierStatus").Return(&entity.ShippingCourierStatus{}).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCourierStatus").Return(&entity.ShippingCourierStatus{
+		ShippingStatus: &entity.ShippingStatus{},
+	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("Upsert").Return(nil, errors.New("")).Once()
 
 	result, msg := shippingService.UpdateStatusShipper(updateStatusReq)
@@ -1751,12 +3491,15 @@ var orderShipping = entity.OrderShipping{
 func TestCancelPickUpShipperSuccess(t *testing.T) {
 	order := orderShipping
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(&order).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	shipper.Mock.On("CancelPickupRequest", mock.Anything).Return(nil).Once()
 	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
 		ShippingStatus: &entity.ShippingStatus{},
 	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("Upsert").Return(&order).Once()
-	msg := shippingService.CancelPickup(&request.CancelPickup{UID: "uid"})
+	msg := shippingService.CancelPickup(context.Background(), &request.CancelPickup{UID: "uid"})
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.SuccessMsg, msg)
 }
@@ -1764,12 +3507,15 @@ func TestCancelPickUpShipperSuccess(t *testing.T) {
 func TestCancelPickUpShipperFailed(t *testing.T) {
 	order := orderShipping
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(&order).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	shipper.Mock.On("CancelPickupRequest", mock.Anything).Return(nil).Once()
 	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
 		ShippingStatus: &entity.ShippingStatus{},
 	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("Upsert").Return(nil, errors.New("")).Once()
-	msg := shippingService.CancelPickup(&request.CancelPickup{UID: "uid"})
+	msg := shippingService.CancelPickup(context.Background(), &request.CancelPickup{UID: "uid"})
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrSaveOrderShipping, msg)
 }
@@ -1790,12 +3536,15 @@ var orderShippingGrab = entity.OrderShipping{
 func TestCancelPickUpGrabSuccess(t *testing.T) {
 	order := orderShippingGrab
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(&order).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	grab.Mock.On("CancelDelivery", mock.Anything).Return(nil).Once()
 	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
 		ShippingStatus: &entity.ShippingStatus{},
 	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("Upsert").Return(&order).Once()
-	msg := shippingService.CancelPickup(&request.CancelPickup{UID: "uid"})
+	msg := shippingService.CancelPickup(context.Background(), &request.CancelPickup{UID: "uid"})
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.SuccessMsg, msg)
 }
@@ -1804,7 +3553,7 @@ func TestCancelPickUpShippingStatusNotFound(t *testing.T) {
 	order := orderShipping
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(&order).Once()
 	shippingCourierStatusRepository.Mock.On("FindByCode").Return(nil).Once()
-	msg := shippingService.CancelPickup(&request.CancelPickup{UID: "uid"})
+	msg := shippingService.CancelPickup(context.Background(), &request.CancelPickup{UID: "uid"})
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ShippingStatusNotFoundMsg, msg)
 }
@@ -1815,9 +3564,12 @@ func TestCancelPickUpShippingShipperError(t *testing.T) {
 	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
 		ShippingStatus: &entity.ShippingStatus{},
 	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	shipper.Mock.On("CancelPickupRequest", mock.Anything).Return(nil, errors.New("")).Once()
 
-	msg := shippingService.CancelPickup(&request.CancelPickup{UID: "uid"})
+	msg := shippingService.CancelPickup(context.Background(), &request.CancelPickup{UID: "uid"})
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrCancelPickup, msg)
 }
@@ -1828,9 +3580,12 @@ func TestCancelPickUpShippingGrabError(t *testing.T) {
 	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
 		ShippingStatus: &entity.ShippingStatus{},
 	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	grab.Mock.On("CancelDelivery", mock.Anything).Return(errors.New("")).Once()
 
-	msg := shippingService.CancelPickup(&request.CancelPickup{UID: "uid"})
+	msg := shippingService.CancelPickup(context.Background(), &request.CancelPickup{UID: "uid"})
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrCancelPickup, msg)
 }
@@ -1841,9 +3596,10 @@ func TestCancelPickUpShippingThirdPartyOrderNotCancelableError(t *testing.T) {
 	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
 		ShippingStatus: &entity.ShippingStatus{},
 	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(&order).Once()
 
-	msg := shippingService.CancelPickup(&request.CancelPickup{UID: "uid"})
+	msg := shippingService.CancelPickup(context.Background(), &request.CancelPickup{UID: "uid"})
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrCantCancelOrderShipping, msg)
 }
@@ -1855,7 +3611,8 @@ func TestCancelPickUpShippingInvalidCourierTypeError(t *testing.T) {
 	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
 		ShippingStatus: &entity.ShippingStatus{},
 	}).Once()
-	msg := shippingService.CancelPickup(&request.CancelPickup{UID: "uid"})
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
+	msg := shippingService.CancelPickup(context.Background(), &request.CancelPickup{UID: "uid"})
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrInvalidCourierType, msg)
 }
@@ -1865,21 +3622,21 @@ func TestCancelPickUpShippingCourierServiceNotCancelableError(t *testing.T) {
 	order.CourierService.Cancelable = 0
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(&order).Once()
 
-	msg := shippingService.CancelPickup(&request.CancelPickup{UID: "uid"})
+	msg := shippingService.CancelPickup(context.Background(), &request.CancelPickup{UID: "uid"})
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrCantCancelOrderCourierService, msg)
 }
 
 func TestCancelPickUpShippingOrderServiceNotFound(t *testing.T) {
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(nil).Once()
-	msg := shippingService.CancelPickup(&request.CancelPickup{UID: "uid"})
+	msg := shippingService.CancelPickup(context.Background(), &request.CancelPickup{UID: "uid"})
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrOrderShippingNotFound, msg)
 }
 
 func TestCancelPickUpShippingOrderServiceNotFoundError(t *testing.T) {
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(nil, errors.New("")).Once()
-	msg := shippingService.CancelPickup(&request.CancelPickup{UID: "uid"})
+	msg := shippingService.CancelPickup(context.Background(), &request.CancelPickup{UID: "uid"})
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrOrderShippingNotFound, msg)
 }
@@ -1893,12 +3650,15 @@ func TestCancelOrderShipperSuccess(t *testing.T) {
 	order.Courier.Code = shipping_provider.ShipperCode
 
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(&order).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	shipper.Mock.On("CancelOrder", mock.Anything).Return(nil).Once()
 	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
 		ShippingStatus: &entity.ShippingStatus{},
 	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("Upsert").Return(&order).Once()
-	msg := shippingService.CancelOrder(cancelOrderReq)
+	msg := shippingService.CancelOrder(context.Background(), cancelOrderReq)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.SuccessMsg, msg)
 }
@@ -1910,12 +3670,15 @@ func TestCancelOrderGrabSuccess(t *testing.T) {
 	order.Courier.Code = shipping_provider.GrabCode
 
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(&order).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	grab.Mock.On("CancelDelivery", mock.Anything).Return(nil).Once()
 	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
 		ShippingStatus: &entity.ShippingStatus{},
 	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("Upsert").Return(&order).Once()
-	msg := shippingService.CancelOrder(cancelOrderReq)
+	msg := shippingService.CancelOrder(context.Background(), cancelOrderReq)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.SuccessMsg, msg)
 }
@@ -1923,12 +3686,15 @@ func TestCancelOrderGrabSuccess(t *testing.T) {
 func TestCancelOrderFailed(t *testing.T) {
 	order := orderShipping
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(&order).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	shipper.Mock.On("CancelOrder", mock.Anything).Return(nil).Once()
 	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
 		ShippingStatus: &entity.ShippingStatus{},
 	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("Upsert").Return(nil, errors.New("")).Once()
-	msg := shippingService.CancelOrder(cancelOrderReq)
+	msg := shippingService.CancelOrder(context.Background(), cancelOrderReq)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrSaveOrderShipping, msg)
 }
@@ -1937,7 +3703,7 @@ func TestCancelOrderShippingStatusNotFound(t *testing.T) {
 	order := orderShipping
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(&order).Once()
 	shippingCourierStatusRepository.Mock.On("FindByCode").Return(nil).Once()
-	msg := shippingService.CancelOrder(cancelOrderReq)
+	msg := shippingService.CancelOrder(context.Background(), cancelOrderReq)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ShippingStatusNotFoundMsg, msg)
 }
@@ -1948,9 +3714,12 @@ func TestCancelOrderShippingShipperError(t *testing.T) {
 	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
 		ShippingStatus: &entity.ShippingStatus{},
 	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	shipper.Mock.On("CancelOrder", mock.Anything).Return(nil, errors.New("")).Once()
 
-	msg := shippingService.CancelOrder(cancelOrderReq)
+	msg := shippingService.CancelOrder(context.Background(), cancelOrderReq)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrCancelPickup, msg)
 }
@@ -1961,9 +3730,12 @@ func TestCancelOrderShippingGrabError(t *testing.T) {
 	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
 		ShippingStatus: &entity.ShippingStatus{},
 	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	grab.Mock.On("CancelDelivery", mock.Anything).Return(errors.New("")).Once()
 
-	msg := shippingService.CancelOrder(cancelOrderReq)
+	msg := shippingService.CancelOrder(context.Background(), cancelOrderReq)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrCancelPickup, msg)
 }
@@ -1974,9 +3746,10 @@ func TestCancelOrderShippingThirdPartyOrderNotCancelableError(t *testing.T) {
 	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
 		ShippingStatus: &entity.ShippingStatus{},
 	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(&order).Once()
 
-	msg := shippingService.CancelOrder(cancelOrderReq)
+	msg := shippingService.CancelOrder(context.Background(), cancelOrderReq)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrCantCancelOrderShipping, msg)
 }
@@ -1987,9 +3760,10 @@ func TestCancelOrderShippingInvalidCourierTypeError(t *testing.T) {
 	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
 		ShippingStatus: &entity.ShippingStatus{},
 	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(&order).Once()
 
-	msg := shippingService.CancelOrder(cancelOrderReq)
+	msg := shippingService.CancelOrder(context.Background(), cancelOrderReq)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrInvalidCourierType, msg)
 }
@@ -1999,21 +3773,21 @@ func TestCancelOrderShippingCourierServiceNotCancelableError(t *testing.T) {
 	order.CourierService.Cancelable = 0
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(&order).Once()
 
-	msg := shippingService.CancelOrder(cancelOrderReq)
+	msg := shippingService.CancelOrder(context.Background(), cancelOrderReq)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrCantCancelOrderCourierService, msg)
 }
 
 func TestCancelOrderShippingOrderServiceNotFound(t *testing.T) {
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(nil).Once()
-	msg := shippingService.CancelOrder(cancelOrderReq)
+	msg := shippingService.CancelOrder(context.Background(), cancelOrderReq)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrOrderShippingNotFound, msg)
 }
 
 func TestCancelOrderShippingOrderServiceNotFoundError(t *testing.T) {
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(nil, errors.New("")).Once()
-	msg := shippingService.CancelOrder(cancelOrderReq)
+	msg := shippingService.CancelOrder(context.Background(), cancelOrderReq)
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrOrderShippingNotFound, msg)
 }
@@ -2116,6 +3890,8 @@ func TestRepickupShipperSuccess(t *testing.T) {
 		PickupCode:     new(string),
 	}
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(ordershipping).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	shipper.Mock.On("CreatePickUpOrderWithTimeSlots", mock.Anything).
 		Return(&response.CreatePickUpOrderShipperResponse{
 			Data: response.CreatePickUpOrderShipper{
@@ -2126,8 +3902,9 @@ func TestRepickupShipperSuccess(t *testing.T) {
 		}).Once()
 
 	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(&entity.ShippingCourierStatus{}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("Upsert").Return(ordershipping).Once()
-	result, msg := shippingService.RepickupOrder(&req)
+	result, msg := shippingService.RepickupOrder(context.Background(), &req)
 	assert.NotNil(t, msg)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.SuccessMsg, msg)
@@ -2159,11 +3936,14 @@ func TestRepickupGrabSuccess(t *testing.T) {
 		Status:    shipping_provider.StatusRequestPickup,
 	}
 
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	grab.Mock.On("ReCreateDelivery", mock.Anything).Return(order).Once()
 
 	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(&entity.ShippingCourierStatus{}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("Upsert").Return(ordershipping).Once()
-	result, msg := shippingService.RepickupOrder(&req)
+	result, msg := shippingService.RepickupOrder(context.Background(), &req)
 	assert.NotNil(t, msg)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.SuccessMsg, msg)
@@ -2190,6 +3970,8 @@ func TestRepickupSaveFailed(t *testing.T) {
 		PickupCode:     new(string),
 	}
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(ordershipping).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	shipper.Mock.On("CreatePickUpOrderWithTimeSlots", mock.Anything).
 		Return(&response.CreatePickUpOrderShipperResponse{
 			Data: response.CreatePickUpOrderShipper{
@@ -2200,8 +3982,9 @@ func TestRepickupSaveFailed(t *testing.T) {
 		}).Once()
 
 	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(&entity.ShippingCourierStatus{}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("Upsert").Return(ordershipping, errors.New("")).Once()
-	result, msg := shippingService.RepickupOrder(&req)
+	result, msg := shippingService.RepickupOrder(context.Background(), &req)
 	assert.NotNil(t, msg)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.ErrSaveOrderShipping, msg)
@@ -2228,11 +4011,14 @@ func TestRepickupShippingErrorRequestShipperFailed(t *testing.T) {
 		PickupCode:     new(string),
 	}
 	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(&entity.ShippingCourierStatus{}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(ordershipping).Once()
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	shipper.Mock.On("CreatePickUpOrderWithTimeSlots", mock.Anything).
 		Return(nil, message.ErrCreatePickUpOrder).Once()
 
-	result, msg := shippingService.RepickupOrder(&req)
+	result, msg := shippingService.RepickupOrder(context.Background(), &req)
 	assert.NotNil(t, msg)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.ErrCreatePickUpOrder, msg)
@@ -2264,10 +4050,13 @@ func TestRepickupShippingErrorRequestGrabFailed(t *testing.T) {
 		Status:    shipping_provider.StatusRequestPickup,
 	}
 
+	channelCourierCredentialRepository.Mock.On("FindByChannelAndCourier").Return(nil).Once()
+
 	grab.Mock.On("ReCreateDelivery", mock.Anything).Return(order, message.FailedMsg).Once()
 
 	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(&entity.ShippingCourierStatus{}).Once()
-	result, msg := shippingService.RepickupOrder(&req)
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
+	result, msg := shippingService.RepickupOrder(context.Background(), &req)
 	assert.NotNil(t, msg)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.FailedMsg, msg)
@@ -2294,9 +4083,10 @@ func TestRepickupShippingInvalidCourierFailed(t *testing.T) {
 		PickupCode:     new(string),
 	}
 	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(&entity.ShippingCourierStatus{}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(ordershipping).Once()
 
-	result, msg := shippingService.RepickupOrder(&req)
+	result, msg := shippingService.RepickupOrder(context.Background(), &req)
 	assert.NotNil(t, msg)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.ErrInvalidCourierCode, msg)
@@ -2323,9 +4113,10 @@ func TestRepickupShippingInvalidCourierTypeFailed(t *testing.T) {
 		PickupCode:     new(string),
 	}
 	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(&entity.ShippingCourierStatus{}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(ordershipping).Once()
 
-	result, msg := shippingService.RepickupOrder(&req)
+	result, msg := shippingService.RepickupOrder(context.Background(), &req)
 	assert.NotNil(t, msg)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.ErrInvalidCourierType, msg)
@@ -2354,7 +4145,7 @@ func TestRepickupShippingOrderHasBeenCancelledFailed(t *testing.T) {
 	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(&entity.ShippingCourierStatus{}).Once()
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(ordershipping).Once()
 
-	result, msg := shippingService.RepickupOrder(&req)
+	result, msg := shippingService.RepickupOrder(context.Background(), &req)
 	assert.NotNil(t, msg)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.OrderHasBeenCancelledMsg, msg)
@@ -2381,9 +4172,10 @@ func TestRepickupShippingRequestPickupHasBeenMadeFailed(t *testing.T) {
 		PickupCode:     new(string),
 	}
 	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(&entity.ShippingCourierStatus{}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(ordershipping).Once()
 
-	result, msg := shippingService.RepickupOrder(&req)
+	result, msg := shippingService.RepickupOrder(context.Background(), &req)
 	assert.NotNil(t, msg)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.RequestPickupHasBeenMadeMsg, msg)
@@ -2411,7 +4203,7 @@ func TestRepickupShippingStatusNotFoundFailed(t *testing.T) {
 	}
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(ordershipping).Once()
 	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(nil).Once()
-	result, msg := shippingService.RepickupOrder(&req)
+	result, msg := shippingService.RepickupOrder(context.Background(), &req)
 	assert.NotNil(t, msg)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.ShippingStatusNotFoundMsg, msg)
@@ -2442,7 +4234,7 @@ func TestRepickupShippingOrderBelongsToAnotherChannelFailed(t *testing.T) {
 
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(ordershipping).Once()
 
-	result, msg := shippingService.RepickupOrder(&req)
+	result, msg := shippingService.RepickupOrder(context.Background(), &req)
 	assert.NotNil(t, msg)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.ErrOrderBelongToAnotherChannel, msg)
@@ -2457,7 +4249,7 @@ func TestRepickupShippingOrderNotFoundFailed(t *testing.T) {
 
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(nil).Once()
 
-	result, msg := shippingService.RepickupOrder(&req)
+	result, msg := shippingService.RepickupOrder(context.Background(), &req)
 	assert.NotNil(t, msg)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.ErrOrderShippingNotFound, msg)
@@ -2472,7 +4264,7 @@ func TestRepickupShippingErrorgetShippingOrderFailed(t *testing.T) {
 
 	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(nil, errors.New("")).Once()
 
-	result, msg := shippingService.RepickupOrder(&req)
+	result, msg := shippingService.RepickupOrder(context.Background(), &req)
 	assert.NotNil(t, msg)
 	assert.NotNil(t, result)
 	assert.Equal(t, message.ErrOrderShippingNotFound, msg)
@@ -2491,6 +4283,7 @@ func TestUpdateStatusGrab(t *testing.T) {
 	shippingCourierStatusRepository.Mock.On("FindByCourierStatus").Return(&entity.ShippingCourierStatus{
 		ShippingStatus: &entity.ShippingStatus{},
 	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("Upsert").Return(&entity.OrderShipping{
 		Channel:        &entity.Channel{},
 		Courier:        &entity.Courier{},
@@ -2514,6 +4307,7 @@ func TestUpdateStatusGrabSaveFailed(t *testing.T) {
 	shippingCourierStatusRepository.Mock.On("FindByCourierStatus").Return(&entity.ShippingCourierStatus{
 		ShippingStatus: &entity.ShippingStatus{},
 	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
 	orderShippingRepository.Mock.On("Upsert").Return(nil, errors.New("")).Once()
 
 	msg := shippingService.UpdateStatusGrab(updateStatusGrabReq)
@@ -2567,6 +4361,112 @@ func TestUpdateStatusGrabGetOrderError(t *testing.T) {
 	assert.Equal(t, message.ErrOrderShippingNotFound, msg)
 }
 
+var updateStatusOrderShippingReq = &request.UpdateStatusOrderShipping{
+	UID: "osuid",
+	Body: request.UpdateStatusOrderShippingBody{
+		ChannelUID: "chuid",
+		StatusCode: "delivered",
+		Notes:      "delivered to customer",
+		DriverInfo: request.UpdateOrderShippingDriverInfo{Name: "driver"},
+		Username:   "dispatcher",
+	},
+}
+
+func internalOrderShipping(courierType string) *entity.OrderShipping {
+	return &entity.OrderShipping{
+		Channel: &entity.Channel{BaseIDModel: base.BaseIDModel{UID: "chuid"}},
+		Courier: &entity.Courier{
+			Code:        "kurir-kd",
+			CourierType: courierType,
+		},
+		CourierService:       &entity.CourierService{},
+		OrderShippingHistory: []entity.OrderShippingHistory{},
+		Status:               shipping_provider.StatusCreated,
+	}
+}
+
+func TestUpdateStatusOrderShippingInternal(t *testing.T) {
+	order := internalOrderShipping(shipping_provider.InternalCourier)
+	orderShippingRepository.Mock.On("FindByUID").Return(order).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
+		StatusCode:     "delivered",
+		ShippingStatus: &entity.ShippingStatus{},
+	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
+	orderShippingRepository.Mock.On("Upsert").Return(order).Once()
+
+	msg := shippingService.UpdateStatusOrderShipping(updateStatusOrderShippingReq)
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Len(t, order.OrderShippingOutbox, 1)
+	assert.Equal(t, entity.OutboxStatusPending, order.OrderShippingOutbox[0].Status)
+	assert.Contains(t, string(order.OrderShippingOutbox[0].Payload), `"shipping_status":"delivered"`)
+}
+
+func TestUpdateStatusOrderShippingMerchant(t *testing.T) {
+	orderShippingRepository.Mock.On("FindByUID").Return(internalOrderShipping(shipping_provider.MerchantCourier)).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
+		StatusCode:     "delivered",
+		ShippingStatus: &entity.ShippingStatus{},
+	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
+	orderShippingRepository.Mock.On("Upsert").Return(internalOrderShipping(shipping_provider.MerchantCourier)).Once()
+
+	msg := shippingService.UpdateStatusOrderShipping(updateStatusOrderShippingReq)
+	assert.Equal(t, message.SuccessMsg, msg)
+}
+
+func TestUpdateStatusOrderShippingSaveFailed(t *testing.T) {
+	orderShippingRepository.Mock.On("FindByUID").Return(internalOrderShipping(shipping_provider.InternalCourier)).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
+		StatusCode:     "delivered",
+		ShippingStatus: &entity.ShippingStatus{},
+	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
+	orderShippingRepository.Mock.On("Upsert").Return(nil, errors.New("")).Once()
+
+	msg := shippingService.UpdateStatusOrderShipping(updateStatusOrderShippingReq)
+	assert.Equal(t, message.ErrSaveOrderShipping, msg)
+}
+
+func TestUpdateStatusOrderShippingStatusNotFound(t *testing.T) {
+	orderShippingRepository.Mock.On("FindByUID").Return(internalOrderShipping(shipping_provider.InternalCourier)).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCode").Return(nil).Once()
+
+	msg := shippingService.UpdateStatusOrderShipping(updateStatusOrderShippingReq)
+	assert.Equal(t, message.ShippingStatusNotFoundMsg, msg)
+}
+
+func TestUpdateStatusOrderShippingThirdPartyCourier(t *testing.T) {
+	orderShippingRepository.Mock.On("FindByUID").Return(internalOrderShipping(shipping_provider.ThirPartyCourier)).Once()
+
+	msg := shippingService.UpdateStatusOrderShipping(updateStatusOrderShippingReq)
+	assert.Equal(t, message.ErrInvalidCourierType, msg)
+}
+
+func TestUpdateStatusOrderShippingAnotherChannel(t *testing.T) {
+	order := internalOrderShipping(shipping_provider.InternalCourier)
+	order.Channel.UID = "another"
+	orderShippingRepository.Mock.On("FindByUID").Return(order).Once()
+
+	msg := shippingService.UpdateStatusOrderShipping(updateStatusOrderShippingReq)
+	assert.Equal(t, message.ErrOrderBelongToAnotherChannel, msg)
+}
+
+func TestUpdateStatusOrderShippingNotFound(t *testing.T) {
+	orderShippingRepository.Mock.On("FindByUID").Return(nil).Once()
+
+	msg := shippingService.UpdateStatusOrderShipping(updateStatusOrderShippingReq)
+	assert.Equal(t, message.ErrOrderShippingNotFound, msg)
+}
+
+func TestUpdateStatusOrderShippingStatusCodeRequired(t *testing.T) {
+	req := *updateStatusOrderShippingReq
+	req.Body.StatusCode = ""
+
+	msg := shippingService.UpdateStatusOrderShipping(&req)
+	assert.Equal(t, message.ErrStatusCodeRequired, msg)
+}
+
 var downloadOrderShippingReq = &request.DownloadOrderShipping{
 	Filter:  "",
 	Filters: request.DownloadOrderShippingFilter{},
@@ -2684,3 +4584,158 @@ func TestOrderShippingDownload_EmptyEnd_StartDateExceedsMaxRange(t *testing.T) {
 	assert.NotNil(t, msg)
 	assert.Equal(t, message.ErrDateRangeGreaterThanAllowed, msg)
 }
+
+var statusTransitions = entity.ShippingStatusTransitions{
+	{FromStatusCode: shipping_provider.StatusCreated, ToStatusCode: shipping_provider.StatusRequestPickup},
+	{FromStatusCode: shipping_provider.StatusCreated, ToStatusCode: shipping_provider.StatusCancelled},
+	{FromStatusCode: shipping_provider.StatusRequestPickup, ToStatusCode: shipping_provider.StatusCreated},
+	{FromStatusCode: shipping_provider.StatusRequestPickup, ToStatusCode: "delivered"},
+}
+
+func deliveredOrderShipping(courierCode string) *entity.OrderShipping {
+	return &entity.OrderShipping{
+		Channel: &entity.Channel{},
+		Courier: &entity.Courier{
+			Code:        courierCode,
+			CourierType: shipping_provider.ThirPartyCourier,
+		},
+		CourierService:       &entity.CourierService{Cancelable: 1},
+		OrderShippingHistory: []entity.OrderShippingHistory{},
+		Status:               "delivered",
+		PickupCode:           new(string),
+	}
+}
+
+func TestUpdateStatusShipperOutOfOrder(t *testing.T) {
+	order := deliveredOrderShipping(shipping_provider.ShipperCode)
+	orderShippingRepository.Mock.On("FindByOrderNo").Return(order).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCourierStatus").Return(&entity.ShippingCourierStatus{
+		StatusCode:     shipping_provider.StatusRequestPickup,
+		ShippingStatus: &entity.ShippingStatus{},
+	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(statusTransitions).Once()
+	orderShippingRepository.Mock.On("Upsert").Return(order).Once()
+
+	result, msg := shippingService.UpdateStatusShipper(updateStatusReq)
+
+	assert.NotNil(t, result)
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "delivered", order.Status)
+	assert.Len(t, order.OrderShippingHistory, 1)
+	assert.Equal(t, shipping_provider.StatusRequestPickup, order.OrderShippingHistory[0].StatusCode)
+	assert.Empty(t, order.OrderShippingOutbox)
+}
+
+func TestUpdateStatusShipperOutOfOrderDefaultTransitions(t *testing.T) {
+	order := deliveredOrderShipping(shipping_provider.ShipperCode)
+	orderShippingRepository.Mock.On("FindByOrderNo").Return(order).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCourierStatus").Return(&entity.ShippingCourierStatus{
+		StatusCode:     shipping_provider.StatusRequestPickup,
+		ShippingStatus: &entity.ShippingStatus{},
+	}).Once()
+	// the channel has no transition, delivered is still final
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
+	orderShippingRepository.Mock.On("Upsert").Return(order).Once()
+
+	result, msg := shippingService.UpdateStatusShipper(updateStatusReq)
+
+	assert.NotNil(t, result)
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "delivered", order.Status)
+	assert.Empty(t, order.OrderShippingOutbox)
+}
+
+func TestUpdateStatusShipperRepeatedStatus(t *testing.T) {
+	order := deliveredOrderShipping(shipping_provider.ShipperCode)
+	order.Status = shipping_provider.StatusRequestPickup
+	orderShippingRepository.Mock.On("FindByOrderNo").Return(order).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCourierStatus").Return(&entity.ShippingCourierStatus{
+		StatusCode:     shipping_provider.StatusRequestPickup,
+		ShippingStatus: &entity.ShippingStatus{},
+	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(statusTransitions).Once()
+	orderShippingRepository.Mock.On("Upsert").Return(order).Once()
+
+	result, msg := shippingService.UpdateStatusShipper(updateStatusReq)
+
+	// the repeated status is kept in history and not published again
+	assert.NotNil(t, result)
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, shipping_provider.StatusRequestPickup, order.Status)
+	assert.Len(t, order.OrderShippingHistory, 1)
+	assert.Empty(t, order.OrderShippingOutbox)
+}
+
+func TestUpdateStatusShipperGetStatusTransitionError(t *testing.T) {
+	orderShippingRepository.Mock.On("FindByOrderNo").Return(deliveredOrderShipping(shipping_provider.ShipperCode)).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCourierStatus").Return(&entity.ShippingCourierStatus{
+		ShippingStatus: &entity.ShippingStatus{},
+	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(nil, errors.New("")).Once()
+
+	result, msg := shippingService.UpdateStatusShipper(updateStatusReq)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrGetStatusTransition, msg)
+}
+
+func TestUpdateStatusGrabOutOfOrder(t *testing.T) {
+	order := deliveredOrderShipping(shipping_provider.GrabCode)
+	orderShippingRepository.Mock.On("FindByOrderNo").Return(order).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCourierStatus").Return(&entity.ShippingCourierStatus{
+		StatusCode:     shipping_provider.StatusRequestPickup,
+		ShippingStatus: &entity.ShippingStatus{},
+	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(statusTransitions).Once()
+	orderShippingRepository.Mock.On("Upsert").Return(order).Once()
+
+	msg := shippingService.UpdateStatusGrab(updateStatusGrabReq)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "delivered", order.Status)
+	assert.Len(t, order.OrderShippingHistory, 1)
+}
+
+func TestUpdateStatusOrderShippingInvalidTransition(t *testing.T) {
+	orderShippingRepository.Mock.On("FindByUID").Return(internalOrderShipping(shipping_provider.InternalCourier)).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
+		StatusCode:     "delivered",
+		ShippingStatus: &entity.ShippingStatus{},
+	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(statusTransitions).Once()
+
+	msg := shippingService.UpdateStatusOrderShipping(updateStatusOrderShippingReq)
+	assert.Equal(t, message.ErrInvalidStatusTransition, msg)
+}
+
+func TestCancelOrderStatusIsTerminal(t *testing.T) {
+	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(deliveredOrderShipping(shipping_provider.ShipperCode)).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
+		ShippingStatus: &entity.ShippingStatus{},
+	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(statusTransitions).Once()
+
+	msg := shippingService.CancelOrder(context.Background(), cancelOrderReq)
+	assert.Equal(t, message.ErrStatusIsTerminal, msg)
+}
+
+func TestCancelPickUpStatusIsTerminal(t *testing.T) {
+	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(deliveredOrderShipping(shipping_provider.ShipperCode)).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingCourierStatus{
+		ShippingStatus: &entity.ShippingStatus{},
+	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(statusTransitions).Once()
+
+	msg := shippingService.CancelPickup(context.Background(), &request.CancelPickup{UID: "uid"})
+	assert.Equal(t, message.ErrStatusIsTerminal, msg)
+}
+
+func TestRepickupStatusIsTerminal(t *testing.T) {
+	orderShippingRepository.Mock.On("FindByUID", mock.Anything).Return(deliveredOrderShipping(shipping_provider.ShipperCode)).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCode", mock.Anything).Return(&entity.ShippingCourierStatus{}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(statusTransitions).Once()
+
+	result, msg := shippingService.RepickupOrder(context.Background(), &request.RepickupOrderRequest{})
+	assert.NotNil(t, result)
+	assert.Equal(t, message.ErrStatusIsTerminal, msg)
+}
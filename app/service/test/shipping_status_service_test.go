@@ -0,0 +1,399 @@
+package test
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/http_helper/shipping_provider"
+	"go-klikdokter/helper/message"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var shippingStatusService = service.NewShippingStatusService(
+	logger,
+	baseRepository,
+	channelRepository,
+	courierRepository,
+	shippingStatusRepository,
+	shippingCourierStatusRepository,
+	shippingStatusTransitionRepository,
+)
+
+var (
+	statusSourceChannelUID = "status-source-channel"
+	statusTargetChannelUID = "status-target-channel"
+	statusCourierUID       = "status-courier"
+)
+
+func requiredShippingStatus() []entity.ShippingStatus {
+	return []entity.ShippingStatus{
+		{BaseIDModel: base.BaseIDModel{ID: 1, UID: "created"}, StatusCode: shipping_provider.StatusCreated},
+		{BaseIDModel: base.BaseIDModel{ID: 2, UID: "request-pickup"}, StatusCode: shipping_provider.StatusRequestPickup},
+		{BaseIDModel: base.BaseIDModel{ID: 3, UID: "cancelled"}, StatusCode: shipping_provider.StatusCancelled},
+	}
+}
+
+func TestGetChannelShippingStatus(t *testing.T) {
+	channelRepository.Mock.On("FindByUid", &statusSourceChannelUID).Return(entity.Channel{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: statusSourceChannelUID},
+	}).Once()
+	shippingStatusRepository.Mock.On("FindByChannelID").Return(requiredShippingStatus()[:1]).Once()
+
+	result, msg := shippingStatusService.GetChannelShippingStatus(statusSourceChannelUID)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Len(t, result.ShippingStatus, 1)
+	assert.Equal(t, []string{shipping_provider.StatusRequestPickup, shipping_provider.StatusCancelled}, result.MissingStatusCodes)
+}
+
+func TestCreateShippingStatus(t *testing.T) {
+	channelRepository.Mock.On("FindByUid", &statusSourceChannelUID).Return(entity.Channel{
+		BaseIDModel: base.BaseIDModel{ID: 1},
+	}).Once()
+	shippingStatusRepository.Mock.On("FindByCode").Return(nil).Once()
+	shippingStatusRepository.Mock.On("Save").Return(nil).Once()
+
+	req := &request.SaveShippingStatus{Body: request.SaveShippingStatusBody{
+		ChannelUID: statusSourceChannelUID,
+		StatusCode: "delivered",
+		StatusName: "Delivered",
+	}}
+	result, msg := shippingStatusService.CreateShippingStatus(req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "delivered", result.StatusCode)
+}
+
+func TestCreateShippingStatusExists(t *testing.T) {
+	channelRepository.Mock.On("FindByUid", &statusSourceChannelUID).Return(entity.Channel{
+		BaseIDModel: base.BaseIDModel{ID: 1},
+	}).Once()
+	shippingStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingStatus{
+		BaseIDModel: base.BaseIDModel{ID: 5},
+	}).Once()
+
+	req := &request.SaveShippingStatus{Body: request.SaveShippingStatusBody{
+		ChannelUID: statusSourceChannelUID,
+		StatusCode: "delivered",
+		StatusName: "Delivered",
+	}}
+	result, msg := shippingStatusService.CreateShippingStatus(req)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrShippingStatusExists, msg)
+}
+
+func TestCreateShippingStatusRequiredField(t *testing.T) {
+	result, msg := shippingStatusService.CreateShippingStatus(&request.SaveShippingStatus{})
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrChannelUIDRequired, msg)
+}
+
+func TestUpdateShippingStatus(t *testing.T) {
+	shippingStatusRepository.Mock.On("FindByUID").Return(&entity.ShippingStatus{
+		BaseIDModel: base.BaseIDModel{ID: 5},
+		StatusCode:  "delivered",
+	}).Once()
+	shippingStatusRepository.Mock.On("FindByCode").Return(nil).Once()
+	shippingStatusRepository.Mock.On("Save").Return(nil).Once()
+
+	req := &request.UpdateShippingStatus{UID: "delivered", Body: request.UpdateShippingStatusBody{
+		StatusCode: "received",
+		StatusName: "Received",
+	}}
+	result, msg := shippingStatusService.UpdateShippingStatus(req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "received", result.StatusCode)
+}
+
+func TestUpdateShippingStatusRequiredCode(t *testing.T) {
+	shippingStatusRepository.Mock.On("FindByUID").Return(&requiredShippingStatus()[0]).Once()
+
+	req := &request.UpdateShippingStatus{UID: "created", Body: request.UpdateShippingStatusBody{
+		StatusCode: "new",
+		StatusName: "New",
+	}}
+	result, msg := shippingStatusService.UpdateShippingStatus(req)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrRequiredShippingStatus, msg)
+}
+
+func TestUpdateShippingStatusKeepRequiredCode(t *testing.T) {
+	shippingStatusRepository.Mock.On("FindByUID").Return(&requiredShippingStatus()[0]).Once()
+	shippingStatusRepository.Mock.On("Save").Return(nil).Once()
+
+	req := &request.UpdateShippingStatus{UID: "created", Body: request.UpdateShippingStatusBody{
+		StatusCode: shipping_provider.StatusCreated,
+		StatusName: "Order Created",
+	}}
+	result, msg := shippingStatusService.UpdateShippingStatus(req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "Order Created", result.StatusName)
+}
+
+func TestDeleteShippingStatus(t *testing.T) {
+	shippingStatusRepository.Mock.On("FindByUID").Return(&entity.ShippingStatus{StatusCode: "delivered"}).Once()
+	shippingCourierStatusRepository.Mock.On("CountByShippingStatusID").Return(int64(0)).Once()
+	shippingStatusRepository.Mock.On("Delete").Return(nil).Once()
+
+	msg := shippingStatusService.DeleteShippingStatus("delivered")
+
+	assert.Equal(t, message.SuccessMsg, msg)
+}
+
+func TestDeleteShippingStatusRequired(t *testing.T) {
+	shippingStatusRepository.Mock.On("FindByUID").Return(&requiredShippingStatus()[2]).Once()
+
+	msg := shippingStatusService.DeleteShippingStatus("cancelled")
+
+	assert.Equal(t, message.ErrRequiredShippingStatus, msg)
+}
+
+func TestDeleteShippingStatusHasCourierStatus(t *testing.T) {
+	shippingStatusRepository.Mock.On("FindByUID").Return(&entity.ShippingStatus{StatusCode: "delivered"}).Once()
+	shippingCourierStatusRepository.Mock.On("CountByShippingStatusID").Return(int64(2)).Once()
+
+	msg := shippingStatusService.DeleteShippingStatus("delivered")
+
+	assert.Equal(t, message.ErrShippingStatusHasCourierStatus, msg)
+}
+
+func mockCloneChannels() {
+	channelRepository.Mock.On("FindByUid", &statusSourceChannelUID).Return(entity.Channel{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: statusSourceChannelUID},
+	}).Once()
+	channelRepository.Mock.On("FindByUid", &statusTargetChannelUID).Return(entity.Channel{
+		BaseIDModel: base.BaseIDModel{ID: 2, UID: statusTargetChannelUID},
+	}).Once()
+}
+
+var cloneShippingStatusReq = &request.CloneShippingStatus{Body: request.CloneShippingStatusBody{
+	SourceChannelUID: statusSourceChannelUID,
+	TargetChannelUID: statusTargetChannelUID,
+}}
+
+func TestCloneShippingStatus(t *testing.T) {
+	mockCloneChannels()
+	shippingStatusRepository.Mock.On("FindByChannelID").Return(requiredShippingStatus()).Once()
+	shippingStatusRepository.Mock.On("FindByChannelID").Return([]entity.ShippingStatus{}).Once()
+	shippingStatusRepository.Mock.On("CloneChannel").Return(nil).Once()
+	shippingStatusRepository.Mock.On("FindByChannelID").Return(requiredShippingStatus()).Once()
+
+	result, msg := shippingStatusService.CloneShippingStatus(cloneShippingStatusReq)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, statusTargetChannelUID, result.ChannelUID)
+	assert.Len(t, result.ShippingStatus, 3)
+	assert.Empty(t, result.MissingStatusCodes)
+}
+
+func TestCloneShippingStatusSourceIncomplete(t *testing.T) {
+	mockCloneChannels()
+	shippingStatusRepository.Mock.On("FindByChannelID").Return(requiredShippingStatus()[1:]).Once()
+
+	result, msg := shippingStatusService.CloneShippingStatus(cloneShippingStatusReq)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrChannelShippingStatusIncomplete, msg)
+}
+
+func TestCloneShippingStatusTargetNotEmpty(t *testing.T) {
+	mockCloneChannels()
+	shippingStatusRepository.Mock.On("FindByChannelID").Return(requiredShippingStatus()).Once()
+	shippingStatusRepository.Mock.On("FindByChannelID").Return(requiredShippingStatus()[:1]).Once()
+
+	result, msg := shippingStatusService.CloneShippingStatus(cloneShippingStatusReq)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrTargetChannelHasShippingStatus, msg)
+}
+
+func TestCreateShippingCourierStatus(t *testing.T) {
+	shippingStatusRepository.Mock.On("FindByUID").Return(&requiredShippingStatus()[0]).Once()
+	courierRepository.Mock.On("FindByUid", &statusCourierUID).Return(entity.Courier{
+		BaseIDModel: base.BaseIDModel{ID: 7, UID: statusCourierUID},
+	}).Once()
+	shippingCourierStatusRepository.Mock.On("FindByShippingStatusID").Return(nil).Once()
+	shippingCourierStatusRepository.Mock.On("FindByChannelCourier").Return([]entity.ShippingCourierStatus{
+		{BaseIDModel: base.BaseIDModel{ID: 9}, StatusCourier: []byte(`{"status":["2000"]}`)},
+	}).Once()
+	shippingCourierStatusRepository.Mock.On("Save").Return(nil).Once()
+
+	req := &request.SaveShippingCourierStatus{Body: request.SaveShippingCourierStatusBody{
+		ShippingStatusUID: "created",
+		CourierUID:        statusCourierUID,
+		StatusCourier:     []string{"1000", " 1000", "", "1010"},
+	}}
+	result, msg := shippingStatusService.CreateShippingCourierStatus(req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, shipping_provider.StatusCreated, result.StatusCode)
+	assert.Equal(t, statusCourierUID, result.CourierUID)
+	assert.JSONEq(t, `{"status":["1000","1010"]}`, string(result.StatusCourier))
+}
+
+func TestCreateShippingCourierStatusMappedToOtherStatus(t *testing.T) {
+	shippingStatusRepository.Mock.On("FindByUID").Return(&requiredShippingStatus()[0]).Once()
+	courierRepository.Mock.On("FindByUid", &statusCourierUID).Return(entity.Courier{
+		BaseIDModel: base.BaseIDModel{ID: 7, UID: statusCourierUID},
+	}).Once()
+	shippingCourierStatusRepository.Mock.On("FindByShippingStatusID").Return(nil).Once()
+	shippingCourierStatusRepository.Mock.On("FindByChannelCourier").Return([]entity.ShippingCourierStatus{
+		{BaseIDModel: base.BaseIDModel{ID: 9}, StatusCourier: []byte(`{"status":["1000"]}`)},
+	}).Once()
+
+	req := &request.SaveShippingCourierStatus{Body: request.SaveShippingCourierStatusBody{
+		ShippingStatusUID: "created",
+		CourierUID:        statusCourierUID,
+		StatusCourier:     []string{"1000"},
+	}}
+	result, msg := shippingStatusService.CreateShippingCourierStatus(req)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrCourierStatusMappedToOtherStatus, msg)
+}
+
+func TestCreateShippingCourierStatusExists(t *testing.T) {
+	shippingStatusRepository.Mock.On("FindByUID").Return(&requiredShippingStatus()[0]).Once()
+	courierRepository.Mock.On("FindByUid", &statusCourierUID).Return(entity.Courier{
+		BaseIDModel: base.BaseIDModel{ID: 7, UID: statusCourierUID},
+	}).Once()
+	shippingCourierStatusRepository.Mock.On("FindByShippingStatusID").Return(&entity.ShippingCourierStatus{}).Once()
+
+	req := &request.SaveShippingCourierStatus{Body: request.SaveShippingCourierStatusBody{
+		ShippingStatusUID: "created",
+		CourierUID:        statusCourierUID,
+		StatusCourier:     []string{"1000"},
+	}}
+	result, msg := shippingStatusService.CreateShippingCourierStatus(req)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrShippingCourierStatusExists, msg)
+}
+
+func TestUpdateShippingCourierStatus(t *testing.T) {
+	shippingCourierStatusRepository.Mock.On("FindByUID").Return(&entity.ShippingCourierStatus{
+		BaseIDModel:    base.BaseIDModel{ID: 9},
+		StatusCourier:  []byte(`{"status":["1000"]}`),
+		ShippingStatus: &requiredShippingStatus()[0],
+		Courier:        &entity.Courier{},
+	}).Once()
+	// the mapping being updated keeps its own codes
+	shippingCourierStatusRepository.Mock.On("FindByChannelCourier").Return([]entity.ShippingCourierStatus{
+		{BaseIDModel: base.BaseIDModel{ID: 9}, StatusCourier: []byte(`{"status":["1000"]}`)},
+	}).Once()
+	shippingCourierStatusRepository.Mock.On("Save").Return(nil).Once()
+
+	req := &request.UpdateShippingCourierStatus{UID: "mapping", Body: request.UpdateShippingCourierStatusBody{
+		StatusCourier: []string{"1000", "1001"},
+	}}
+	result, msg := shippingStatusService.UpdateShippingCourierStatus(req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.JSONEq(t, `{"status":["1000","1001"]}`, string(result.StatusCourier))
+}
+
+func TestDeleteShippingCourierStatusNotFound(t *testing.T) {
+	shippingCourierStatusRepository.Mock.On("FindByUID").Return(nil).Once()
+
+	msg := shippingStatusService.DeleteShippingCourierStatus("mapping")
+
+	assert.Equal(t, message.ErrShippingCourierStatusNotFound, msg)
+}
+
+func TestDeleteShippingCourierStatus(t *testing.T) {
+	shippingCourierStatusRepository.Mock.On("FindByUID").Return(&entity.ShippingCourierStatus{StatusCode: "delivered"}).Once()
+	shippingCourierStatusRepository.Mock.On("Delete").Return(nil).Once()
+
+	msg := shippingStatusService.DeleteShippingCourierStatus("mapping")
+
+	assert.Equal(t, message.SuccessMsg, msg)
+}
+
+func TestDeleteShippingCourierStatusRequired(t *testing.T) {
+	shippingCourierStatusRepository.Mock.On("FindByUID").Return(&entity.ShippingCourierStatus{StatusCode: "request_pickup"}).Once()
+
+	msg := shippingStatusService.DeleteShippingCourierStatus("mapping")
+
+	assert.Equal(t, message.ErrRequiredShippingCourierStatus, msg)
+}
+
+func TestGetChannelStatusTransition_Default(t *testing.T) {
+	channelRepository.Mock.On("FindByUid", &statusSourceChannelUID).Return(entity.Channel{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: statusSourceChannelUID},
+	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(nil).Once()
+
+	result, msg := shippingStatusService.GetChannelStatusTransition(statusSourceChannelUID)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.True(t, result.Default)
+	assert.Empty(t, result.StatusTransition)
+	assert.Equal(t, entity.DefaultTerminalStatusCodes, result.TerminalStatusCodes)
+}
+
+func TestCreateStatusTransition(t *testing.T) {
+	channelRepository.Mock.On("FindByUid", &statusSourceChannelUID).Return(entity.Channel{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: statusSourceChannelUID},
+	}).Once()
+	shippingStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingStatus{}).Twice()
+	shippingStatusTransitionRepository.Mock.On("FindByStatusCodes").Return(nil).Once()
+	shippingStatusTransitionRepository.Mock.On("Save").Return(nil).Once()
+
+	req := &request.SaveStatusTransition{}
+	req.Body.ChannelUID = statusSourceChannelUID
+	req.Body.FromStatusCode = shipping_provider.StatusRequestPickup
+	req.Body.ToStatusCode = "delivered"
+	result, msg := shippingStatusService.CreateStatusTransition(req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, "delivered", result.ToStatusCode)
+}
+
+func TestCreateStatusTransition_Exists(t *testing.T) {
+	channelRepository.Mock.On("FindByUid", &statusSourceChannelUID).Return(entity.Channel{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: statusSourceChannelUID},
+	}).Once()
+	shippingStatusRepository.Mock.On("FindByCode").Return(&entity.ShippingStatus{}).Twice()
+	shippingStatusTransitionRepository.Mock.On("FindByStatusCodes").Return(&entity.ShippingStatusTransition{}).Once()
+
+	req := &request.SaveStatusTransition{}
+	req.Body.ChannelUID = statusSourceChannelUID
+	req.Body.FromStatusCode = shipping_provider.StatusRequestPickup
+	req.Body.ToStatusCode = "delivered"
+	result, msg := shippingStatusService.CreateStatusTransition(req)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrStatusTransitionExists, msg)
+}
+
+func TestCreateStatusTransition_UnknownStatusCode(t *testing.T) {
+	channelRepository.Mock.On("FindByUid", &statusSourceChannelUID).Return(entity.Channel{
+		BaseIDModel: base.BaseIDModel{ID: 1, UID: statusSourceChannelUID},
+	}).Once()
+	shippingStatusRepository.Mock.On("FindByCode").Return(nil).Once()
+
+	req := &request.SaveStatusTransition{}
+	req.Body.ChannelUID = statusSourceChannelUID
+	req.Body.FromStatusCode = "lost"
+	req.Body.ToStatusCode = "delivered"
+	result, msg := shippingStatusService.CreateStatusTransition(req)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrInvalidStatusTransitionCode, msg)
+}
+
+func TestDeleteStatusTransition_NotFound(t *testing.T) {
+	shippingStatusTransitionRepository.Mock.On("FindByUID").Return(nil).Once()
+
+	msg := shippingStatusService.DeleteStatusTransition("transition")
+
+	assert.Equal(t, message.ErrStatusTransitionNotFound, msg)
+}
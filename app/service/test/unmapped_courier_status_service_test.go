@@ -0,0 +1,212 @@
+package test
+
+import (
+	"errors"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/app/repository/repository_mock"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/http_helper/shipping_provider"
+	"go-klikdokter/helper/message"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var shippingStatusRepository = &repository_mock.ShippingStatusRepositoryMock{Mock: mock.Mock{}}
+var unmappedCourierStatusService service.UnmappedCourierStatusService
+
+func init() {
+	// test files are initialized by name, so the webhook service from webhook_service_test.go is not built yet
+	ws := service.NewWebhookService(
+		logger,
+		baseRepository,
+		webhookLogRepository,
+		unmappedCourierStatusRepository,
+		courierRepository,
+		shippingService,
+	)
+	unmappedCourierStatusService = service.NewUnmappedCourierStatusService(
+		logger,
+		baseRepository,
+		unmappedCourierStatusRepository,
+		shippingStatusRepository,
+		shippingCourierStatusRepository,
+		webhookLogRepository,
+		ws,
+	)
+}
+
+func openUnmappedCourierStatus() *entity.UnmappedCourierStatus {
+	return &entity.UnmappedCourierStatus{
+		BaseIDModel: base.BaseIDModel{UID: "unmapped"},
+		CourierID:   1,
+		StatusCode:  "1000",
+		Status:      entity.UnmappedStatusOpen,
+		Courier:     &entity.Courier{Code: shipping_provider.ShipperCode},
+	}
+}
+
+func heldWebhookLog() *entity.WebhookLog {
+	return &entity.WebhookLog{
+		Provider:   shipping_provider.ShipperCode,
+		Authorized: true,
+		Status:     entity.WebhookStatusFailed,
+		Body:       string(shipperWebhookBody("***")),
+	}
+}
+
+var mapUnmappedCourierStatusReq = &request.MapUnmappedCourierStatus{
+	UID:  "unmapped",
+	Body: request.MapUnmappedCourierStatusBody{ShippingStatusUID: "ssuid"},
+}
+
+func TestMapUnmappedCourierStatus(t *testing.T) {
+	unmapped := openUnmappedCourierStatus()
+	courierStatus := &entity.ShippingCourierStatus{
+		BaseIDModel:   base.BaseIDModel{ID: 3},
+		StatusCourier: []byte(`{"status":["1010"]}`),
+	}
+	unmappedCourierStatusRepository.Mock.On("FindByUID").Return(unmapped).Once()
+	shippingStatusRepository.Mock.On("FindByUID").Return(&entity.ShippingStatus{
+		BaseIDModel: base.BaseIDModel{ID: 2},
+		StatusCode:  shipping_provider.StatusRequestPickup,
+	}).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCourierStatus").Return(nil).Once()
+	shippingCourierStatusRepository.Mock.On("FindByShippingStatusID").Return(courierStatus).Once()
+	shippingCourierStatusRepository.Mock.On("Save").Return(nil).Once()
+	unmappedCourierStatusRepository.Mock.On("Save").Return(nil).Once()
+
+	// one held webhook is applied, the other one still fails
+	webhookLogRepository.Mock.On("FindHeldByStatusCode").Return([]entity.WebhookLog{{}, {}}).Once()
+	webhookLogRepository.Mock.On("FindByUID").Return(heldWebhookLog()).Once()
+	mockUpdateStatusShipperSuccess()
+	webhookLogRepository.Mock.On("FindByUID").Return(heldWebhookLog()).Once()
+	orderShippingRepository.Mock.On("FindByOrderNo").Return(nil).Once()
+	webhookLogRepository.Mock.On("Save").Return(nil).Twice()
+	webhookLogRepository.Mock.On("CountHeldByStatusCode").Return(int64(0)).Once()
+
+	result, msg := unmappedCourierStatusService.MapUnmappedCourierStatus(mapUnmappedCourierStatusReq)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, 1, result.Reapplied)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, shipping_provider.StatusRequestPickup, result.ShippingStatusCode)
+	assert.Equal(t, entity.UnmappedStatusMapped, unmapped.Status)
+	assert.Equal(t, uint64(3), *unmapped.ShippingCourierStatusID)
+	assert.NotNil(t, unmapped.MappedAt)
+	assert.JSONEq(t, `{"status":["1010","1000"]}`, string(courierStatus.StatusCourier))
+}
+
+func TestMapUnmappedCourierStatusNewCourierStatus(t *testing.T) {
+	unmapped := openUnmappedCourierStatus()
+	unmappedCourierStatusRepository.Mock.On("FindByUID").Return(unmapped).Once()
+	shippingStatusRepository.Mock.On("FindByUID").Return(&entity.ShippingStatus{
+		BaseIDModel: base.BaseIDModel{ID: 2},
+		StatusCode:  shipping_provider.StatusRequestPickup,
+	}).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCourierStatus").Return(nil).Once()
+	shippingCourierStatusRepository.Mock.On("FindByShippingStatusID").Return(nil).Once()
+	shippingCourierStatusRepository.Mock.On("Save").Return(nil).Once()
+	unmappedCourierStatusRepository.Mock.On("Save").Return(nil).Once()
+	webhookLogRepository.Mock.On("FindHeldByStatusCode").Return([]entity.WebhookLog{}).Once()
+	webhookLogRepository.Mock.On("CountHeldByStatusCode").Return(int64(0)).Once()
+
+	result, msg := unmappedCourierStatusService.MapUnmappedCourierStatus(mapUnmappedCourierStatusReq)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, 0, result.Reapplied)
+	assert.Equal(t, entity.UnmappedStatusMapped, unmapped.Status)
+}
+
+func TestMapUnmappedCourierStatusHeldInOtherChannel(t *testing.T) {
+	unmapped := openUnmappedCourierStatus()
+	unmappedCourierStatusRepository.Mock.On("FindByUID").Return(unmapped).Once()
+	shippingStatusRepository.Mock.On("FindByUID").Return(&entity.ShippingStatus{
+		BaseIDModel: base.BaseIDModel{ID: 2},
+		ChannelID:   1,
+		StatusCode:  shipping_provider.StatusRequestPickup,
+	}).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCourierStatus").Return(nil).Once()
+	shippingCourierStatusRepository.Mock.On("FindByShippingStatusID").Return(nil).Once()
+	shippingCourierStatusRepository.Mock.On("Save").Return(nil).Once()
+	unmappedCourierStatusRepository.Mock.On("Save").Return(nil).Once()
+	webhookLogRepository.Mock.On("FindHeldByStatusCode").Return([]entity.WebhookLog{}).Once()
+	// the webhooks of the other channel are still held
+	webhookLogRepository.Mock.On("CountHeldByStatusCode").Return(int64(2)).Once()
+
+	result, msg := unmappedCourierStatusService.MapUnmappedCourierStatus(mapUnmappedCourierStatusReq)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, entity.UnmappedStatusOpen, result.Status)
+	assert.Equal(t, entity.UnmappedStatusOpen, unmapped.Status)
+	assert.NotNil(t, unmapped.MappedAt)
+}
+
+func TestMapUnmappedCourierStatusAlreadyMapped(t *testing.T) {
+	unmapped := openUnmappedCourierStatus()
+	unmapped.Status = entity.UnmappedStatusMapped
+	unmappedCourierStatusRepository.Mock.On("FindByUID").Return(unmapped).Once()
+
+	result, msg := unmappedCourierStatusService.MapUnmappedCourierStatus(mapUnmappedCourierStatusReq)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrCourierStatusAlreadyMapped, msg)
+}
+
+func TestMapUnmappedCourierStatusShippingStatusNotFound(t *testing.T) {
+	unmappedCourierStatusRepository.Mock.On("FindByUID").Return(openUnmappedCourierStatus()).Once()
+	shippingStatusRepository.Mock.On("FindByUID").Return(nil).Once()
+
+	result, msg := unmappedCourierStatusService.MapUnmappedCourierStatus(mapUnmappedCourierStatusReq)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ShippingStatusNotFoundMsg, msg)
+}
+
+func TestMapUnmappedCourierStatusNotFound(t *testing.T) {
+	unmappedCourierStatusRepository.Mock.On("FindByUID").Return(nil).Once()
+
+	result, msg := unmappedCourierStatusService.MapUnmappedCourierStatus(mapUnmappedCourierStatusReq)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrUnmappedCourierStatusNotFound, msg)
+}
+
+func TestMapUnmappedCourierStatusShippingStatusRequired(t *testing.T) {
+	result, msg := unmappedCourierStatusService.MapUnmappedCourierStatus(&request.MapUnmappedCourierStatus{UID: "unmapped"})
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrShippingStatusUIDRequired, msg)
+}
+
+func TestMapUnmappedCourierStatusSaveFailed(t *testing.T) {
+	unmappedCourierStatusRepository.Mock.On("FindByUID").Return(openUnmappedCourierStatus()).Once()
+	shippingStatusRepository.Mock.On("FindByUID").Return(&entity.ShippingStatus{}).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCourierStatus").Return(nil).Once()
+	shippingCourierStatusRepository.Mock.On("FindByShippingStatusID").Return(nil).Once()
+	shippingCourierStatusRepository.Mock.On("Save").Return(errors.New("")).Once()
+
+	result, msg := unmappedCourierStatusService.MapUnmappedCourierStatus(mapUnmappedCourierStatusReq)
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrSaveData, msg)
+}
+
+func TestGetUnmappedCourierStatusList(t *testing.T) {
+	unmappedCourierStatusRepository.Mock.On("FindByParams").Return([]response.GetUnmappedCourierStatusList{
+		{StatusCode: "1000", OccurrenceCount: 3},
+	}, &base.Pagination{}).Once()
+
+	req := &request.GetUnmappedCourierStatusList{Filter: `{"status":["open"]}`}
+	req.GetFilter()
+	result, pagination, msg := unmappedCourierStatusService.GetUnmappedCourierStatusList(req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.NotNil(t, pagination)
+	assert.Len(t, result, 1)
+	assert.Equal(t, []string{entity.UnmappedStatusOpen}, req.Filters.Status)
+}
@@ -0,0 +1,328 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/app/repository"
+	"go-klikdokter/app/repository/repository_mock"
+	"go-klikdokter/app/service"
+	"go-klikdokter/helper/http_helper/shipping_provider"
+	"go-klikdokter/helper/message"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var webhookLogRepository = &repository_mock.WebhookLogRepositoryMock{Mock: mock.Mock{}}
+var unmappedCourierStatusRepository = &repository_mock.UnmappedCourierStatusRepositoryMock{Mock: mock.Mock{}}
+var webhookService service.WebhookService
+
+func init() {
+	// runs after the shipping service is built in shipping_service_test.go
+	webhookService = service.NewWebhookService(
+		logger,
+		baseRepository,
+		webhookLogRepository,
+		unmappedCourierStatusRepository,
+		courierRepository,
+		shippingService,
+	)
+}
+
+func shipperWebhookBody(auth string) []byte {
+	body, _ := json.Marshal(request.WebhookUpdateStatusShipper{
+		Auth:       auth,
+		OrderID:    "SHP-001",
+		ExternalID: "ORDER-001",
+		ExternalStatus: request.ShipperStatus{
+			Code: 1000,
+			Name: "Paket sedang dalam proses pengiriman",
+		},
+	})
+	return body
+}
+
+func mockUpdateStatusShipperSuccess() {
+	orderShippingRepository.Mock.On("FindByOrderNo").Return(&entity.OrderShipping{
+		Channel: &entity.Channel{},
+		Courier: &entity.Courier{
+			Code: shipping_provider.ShipperCode,
+		},
+		CourierService: &entity.CourierService{},
+	}).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCourierStatus").Return(&entity.ShippingCourierStatus{
+		ShippingStatus: &entity.ShippingStatus{},
+	}).Once()
+	shippingStatusTransitionRepository.Mock.On("FindByChannelID").Return(entity.ShippingStatusTransitions{}).Once()
+	orderShippingRepository.Mock.On("Upsert").Return(&entity.OrderShipping{}).Once()
+}
+
+func mockShipperStatusNotMapped() {
+	orderShippingRepository.Mock.On("FindByOrderNo").Return(&entity.OrderShipping{
+		Channel: &entity.Channel{},
+		Courier: &entity.Courier{
+			Code: shipping_provider.ShipperCode,
+		},
+		CourierService: &entity.CourierService{},
+	}).Once()
+	shippingCourierStatusRepository.Mock.On("FindByCourierStatus").Return(nil).Once()
+}
+
+func TestReceiveWebhookShipper(t *testing.T) {
+	webhookLogRepository.Mock.On("FindByEventID").Return(nil).Once()
+	webhookLogRepository.Mock.On("Create").Return(nil).Once()
+	webhookLogRepository.Mock.On("Save").Return(nil).Once()
+	mockUpdateStatusShipperSuccess()
+
+	msg := webhookService.ReceiveWebhook(&request.ReceiveWebhook{
+		Provider: shipping_provider.ShipperCode,
+		Body:     shipperWebhookBody(shipping_provider.ShipperWebhookAuth()),
+	})
+
+	assert.Equal(t, message.SuccessMsg, msg)
+}
+
+func TestReceiveWebhookShipperUnauthorized(t *testing.T) {
+	webhookLogRepository.Mock.On("Save").Return(nil).Twice()
+
+	msg := webhookService.ReceiveWebhook(&request.ReceiveWebhook{
+		Provider: shipping_provider.ShipperCode,
+		Body:     shipperWebhookBody("invalid"),
+	})
+
+	assert.Equal(t, message.ErrUnAuth, msg)
+}
+
+func TestReceiveWebhookDuplicate(t *testing.T) {
+	existing := &entity.WebhookLog{
+		Provider:   shipping_provider.ShipperCode,
+		Authorized: true,
+		Status:     entity.WebhookStatusProcessed,
+		Attempts:   1,
+	}
+	webhookLogRepository.Mock.On("FindByEventID").Return(existing).Once()
+	webhookLogRepository.Mock.On("Save").Return(nil).Once()
+
+	msg := webhookService.ReceiveWebhook(&request.ReceiveWebhook{
+		Provider: shipping_provider.ShipperCode,
+		Body:     shipperWebhookBody(shipping_provider.ShipperWebhookAuth()),
+	})
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, 1, existing.DuplicateCount)
+	assert.Equal(t, 1, existing.Attempts)
+}
+
+func TestReceiveWebhookConcurrentDuplicate(t *testing.T) {
+	webhookLogRepository.Mock.On("FindByEventID").Return(nil).Once()
+	// a concurrent request has stored the event in the meantime, it is not processed again
+	webhookLogRepository.Mock.On("Create").Return(repository.ErrWebhookEventTaken).Once()
+
+	msg := webhookService.ReceiveWebhook(&request.ReceiveWebhook{
+		Provider: shipping_provider.ShipperCode,
+		Body:     shipperWebhookBody(shipping_provider.ShipperWebhookAuth()),
+	})
+
+	assert.Equal(t, message.SuccessMsg, msg)
+}
+
+func TestReceiveWebhookRetryFailedEvent(t *testing.T) {
+	existing := &entity.WebhookLog{
+		Provider:   shipping_provider.ShipperCode,
+		Authorized: true,
+		Status:     entity.WebhookStatusFailed,
+		ErrorCode:  message.ShippingStatusNotFoundMsg.Code,
+		Attempts:   1,
+	}
+	webhookLogRepository.Mock.On("FindByEventID").Return(existing).Once()
+	webhookLogRepository.Mock.On("Save").Return(nil).Twice()
+	mockUpdateStatusShipperSuccess()
+
+	msg := webhookService.ReceiveWebhook(&request.ReceiveWebhook{
+		Provider: shipping_provider.ShipperCode,
+		Header:   http.Header{"Authorization": []string{"secret"}, "Content-Type": []string{"application/json"}},
+		Body:     shipperWebhookBody(shipping_provider.ShipperWebhookAuth()),
+	})
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.Equal(t, entity.WebhookStatusProcessed, existing.Status)
+	assert.Equal(t, 0, existing.ErrorCode)
+	assert.Equal(t, 2, existing.Attempts)
+	assert.Equal(t, "ORDER-001", existing.OrderNo)
+	assert.NotNil(t, existing.ProcessedAt)
+	assert.Contains(t, existing.Body, `"auth":"***"`)
+	assert.NotContains(t, string(existing.Headers), "secret")
+}
+
+func TestReceiveWebhookProcessFailed(t *testing.T) {
+	webhookLogRepository.Mock.On("FindByEventID").Return(nil).Once()
+	webhookLogRepository.Mock.On("Create").Return(nil).Once()
+	webhookLogRepository.Mock.On("Save").Return(nil).Once()
+	orderShippingRepository.Mock.On("FindByOrderNo").Return(nil).Once()
+
+	msg := webhookService.ReceiveWebhook(&request.ReceiveWebhook{
+		Provider: shipping_provider.ShipperCode,
+		Body:     shipperWebhookBody(shipping_provider.ShipperWebhookAuth()),
+	})
+
+	assert.Equal(t, message.ErrOrderShippingNotFound, msg)
+}
+
+func TestReceiveWebhookCaptureUnmappedStatus(t *testing.T) {
+	webhookLogRepository.Mock.On("FindByEventID").Return(nil).Once()
+	webhookLogRepository.Mock.On("Create").Return(nil).Once()
+	webhookLogRepository.Mock.On("Save").Return(nil).Once()
+	mockShipperStatusNotMapped()
+	courierRepository.Mock.On("FindByCode", shipping_provider.ShipperCode).Return(entity.Courier{BaseIDModel: base.BaseIDModel{ID: 1}}).Once()
+	unmappedCourierStatusRepository.Mock.On("FindByCourierStatus").Return(nil).Once()
+	unmappedCourierStatusRepository.Mock.On("Save").Return(nil).Once()
+
+	msg := webhookService.ReceiveWebhook(&request.ReceiveWebhook{
+		Provider: shipping_provider.ShipperCode,
+		Body:     shipperWebhookBody(shipping_provider.ShipperWebhookAuth()),
+	})
+
+	assert.Equal(t, message.ShippingStatusNotFoundMsg, msg)
+}
+
+func TestReceiveWebhookCaptureUnmappedStatusAgain(t *testing.T) {
+	unmapped := &entity.UnmappedCourierStatus{
+		CourierID:       1,
+		StatusCode:      "1000",
+		Status:          entity.UnmappedStatusOpen,
+		OccurrenceCount: 1,
+	}
+	webhookLogRepository.Mock.On("FindByEventID").Return(nil).Once()
+	webhookLogRepository.Mock.On("Create").Return(nil).Once()
+	webhookLogRepository.Mock.On("Save").Return(nil).Once()
+	mockShipperStatusNotMapped()
+	courierRepository.Mock.On("FindByCode", shipping_provider.ShipperCode).Return(entity.Courier{BaseIDModel: base.BaseIDModel{ID: 1}}).Once()
+	unmappedCourierStatusRepository.Mock.On("FindByCourierStatus").Return(unmapped).Once()
+	unmappedCourierStatusRepository.Mock.On("Save").Return(nil).Once()
+
+	msg := webhookService.ReceiveWebhook(&request.ReceiveWebhook{
+		Provider: shipping_provider.ShipperCode,
+		Body:     shipperWebhookBody(shipping_provider.ShipperWebhookAuth()),
+	})
+
+	assert.Equal(t, message.ShippingStatusNotFoundMsg, msg)
+	assert.Equal(t, 2, unmapped.OccurrenceCount)
+	assert.Equal(t, "Paket sedang dalam proses pengiriman", unmapped.StatusName)
+	assert.Contains(t, string(unmapped.SamplePayloads), `"auth":"***"`)
+	assert.False(t, unmapped.LastSeenAt.IsZero())
+}
+
+func TestReceiveWebhookInvalidBody(t *testing.T) {
+	webhookLogRepository.Mock.On("Save").Return(nil).Once()
+
+	msg := webhookService.ReceiveWebhook(&request.ReceiveWebhook{
+		Provider: shipping_provider.ShipperCode,
+		Body:     []byte("{invalid"),
+	})
+
+	assert.Equal(t, message.ErrReqParam, msg)
+}
+
+func TestReceiveWebhookGrabUnauthorized(t *testing.T) {
+	webhookLogRepository.Mock.On("Save").Return(nil).Twice()
+
+	msg := webhookService.ReceiveWebhook(&request.ReceiveWebhook{
+		Provider: shipping_provider.GrabCode,
+		Header:   http.Header{"Authorization": []string{"invalid"}},
+		Body:     []byte(`{"deliveryID":"G-001","merchantOrderID":"ORDER-001","status":"COMPLETED"}`),
+	})
+
+	assert.Equal(t, message.ErrUnAuth, msg)
+}
+
+func TestRerunWebhookLog(t *testing.T) {
+	webhookLog := &entity.WebhookLog{
+		Provider:   shipping_provider.ShipperCode,
+		Authorized: true,
+		Status:     entity.WebhookStatusFailed,
+		ErrorCode:  message.ShippingStatusNotFoundMsg.Code,
+		Held:       true,
+		Body:       string(shipperWebhookBody("***")),
+		Attempts:   1,
+	}
+	webhookLogRepository.Mock.On("FindByUID").Return(webhookLog).Once()
+	mockUpdateStatusShipperSuccess()
+	webhookLogRepository.Mock.On("Save").Return(nil).Once()
+
+	req := &request.RerunWebhookLog{UID: "uid"}
+	req.ActorName = "admin"
+	result, msg := webhookService.RerunWebhookLog(req)
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.NotNil(t, result)
+	assert.Equal(t, entity.WebhookStatusProcessed, result.Status)
+	assert.Equal(t, 2, result.Attempts)
+	assert.Equal(t, "admin", result.UpdatedBy)
+	assert.False(t, webhookLog.Held)
+}
+
+func TestRerunWebhookLogFailedAgain(t *testing.T) {
+	webhookLog := &entity.WebhookLog{
+		Provider:   shipping_provider.ShipperCode,
+		Authorized: true,
+		Status:     entity.WebhookStatusFailed,
+		Body:       string(shipperWebhookBody("***")),
+	}
+	webhookLogRepository.Mock.On("FindByUID").Return(webhookLog).Once()
+	orderShippingRepository.Mock.On("FindByOrderNo").Return(nil).Once()
+	webhookLogRepository.Mock.On("Save").Return(nil).Once()
+
+	result, msg := webhookService.RerunWebhookLog(&request.RerunWebhookLog{UID: "uid"})
+
+	assert.Equal(t, message.ErrOrderShippingNotFound, msg)
+	assert.Equal(t, entity.WebhookStatusFailed, result.Status)
+	assert.Equal(t, message.ErrOrderShippingNotFound.Message, result.ErrorMessage)
+}
+
+func TestRerunWebhookLogNotRerunnable(t *testing.T) {
+	webhookLogRepository.Mock.On("FindByUID").Return(&entity.WebhookLog{
+		Provider: shipping_provider.ShipperCode,
+		Status:   entity.WebhookStatusFailed,
+	}).Once()
+
+	result, msg := webhookService.RerunWebhookLog(&request.RerunWebhookLog{UID: "uid"})
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrWebhookLogNotRerunnable, msg)
+}
+
+func TestRerunWebhookLogNotFound(t *testing.T) {
+	webhookLogRepository.Mock.On("FindByUID").Return(nil).Once()
+
+	result, msg := webhookService.RerunWebhookLog(&request.RerunWebhookLog{UID: "uid"})
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrWebhookLogNotFound, msg)
+}
+
+func TestRerunWebhookLogError(t *testing.T) {
+	webhookLogRepository.Mock.On("FindByUID").Return(nil, errors.New("")).Once()
+
+	result, msg := webhookService.RerunWebhookLog(&request.RerunWebhookLog{UID: "uid"})
+
+	assert.Nil(t, result)
+	assert.Equal(t, message.ErrDB, msg)
+}
+
+func TestGetWebhookLogList(t *testing.T) {
+	webhookLogRepository.Mock.On("FindByParams").Return([]response.WebhookLog{
+		{Status: entity.WebhookStatusFailed},
+	}, &base.Pagination{}).Once()
+
+	result, pagination, msg := webhookService.GetWebhookLogList(&request.GetWebhookLogList{})
+
+	assert.Equal(t, message.SuccessMsg, msg)
+	assert.NotNil(t, pagination)
+	assert.Len(t, result, 1)
+}
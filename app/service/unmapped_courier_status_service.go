@@ -0,0 +1,236 @@
+package service
+
+import (
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/app/repository"
+	"go-klikdokter/helper/global"
+	"go-klikdokter/helper/message"
+	"go-klikdokter/pkg/util"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+type UnmappedCourierStatusService interface {
+	GetUnmappedCourierStatusList(req *request.GetUnmappedCourierStatusList) ([]response.GetUnmappedCourierStatusList, *base.Pagination, message.Message)
+	MapUnmappedCourierStatus(req *request.MapUnmappedCourierStatus) (*response.MapUnmappedCourierStatus, message.Message)
+}
+
+type unmappedCourierStatusServiceImpl struct {
+	logger                    log.Logger
+	baseRepo                  repository.BaseRepository
+	unmappedStatusRepo        repository.UnmappedCourierStatusRepository
+	shippingStatusRepo        repository.ShippingStatusRepository
+	shippingCourierStatusRepo repository.ShippingCourierStatusRepository
+	webhookLogRepo            repository.WebhookLogRepository
+	webhookService            WebhookService
+}
+
+func NewUnmappedCourierStatusService(
+	l log.Logger,
+	br repository.BaseRepository,
+	ucsr repository.UnmappedCourierStatusRepository,
+	ssr repository.ShippingStatusRepository,
+	scsr repository.ShippingCourierStatusRepository,
+	wlr repository.WebhookLogRepository,
+	ws WebhookService,
+) UnmappedCourierStatusService {
+	return &unmappedCourierStatusServiceImpl{l, br, ucsr, ssr, scsr, wlr, ws}
+}
+
+// swagger:operation GET /shipping/unmapped-courier-status Shipping GetUnmappedCourierStatusList
+// Get Unmapped Courier Status
+//
+// Description :
+// Courier status codes received by webhook without a shipping status mapping, with the latest sample payloads
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaPaginationResponse'
+//         data:
+//           properties:
+//             records:
+//               type: array
+//               items:
+//                 $ref: '#/definitions/GetUnmappedCourierStatusListResponse'
+func (s *unmappedCourierStatusServiceImpl) GetUnmappedCourierStatusList(req *request.GetUnmappedCourierStatusList) ([]response.GetUnmappedCourierStatusList, *base.Pagination, message.Message) {
+	logger := log.With(s.logger, "UnmappedCourierStatusService", "GetUnmappedCourierStatusList")
+
+	filter := make(map[string]interface{})
+	filter["courier_code"] = req.Filters.CourierCode
+	filter["status_code"] = req.Filters.StatusCode
+	filter["status"] = req.Filters.Status
+
+	result, pagination, err := s.unmappedStatusRepo.FindByParams(req.Limit, req.Page, req.Sort, filter)
+	if err != nil {
+		_ = level.Error(logger).Log("s.unmappedStatusRepo.FindByParams", err.Error())
+		return result, pagination, message.ErrNoData
+	}
+
+	return result, pagination, message.SuccessMsg
+}
+
+// swagger:operation POST /shipping/unmapped-courier-status/{uid}/map Shipping MapUnmappedCourierStatus
+// Map Unmapped Courier Status
+//
+// Description :
+// Map the courier status code to a shipping status, then re-apply the webhooks of the channel that were held because of the missing mapping.
+// The record stays open while webhooks of the other channels are held
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/MapUnmappedCourierStatusResponse'
+func (s *unmappedCourierStatusServiceImpl) MapUnmappedCourierStatus(req *request.MapUnmappedCourierStatus) (*response.MapUnmappedCourierStatus, message.Message) {
+	logger := log.With(s.logger, "UnmappedCourierStatusService", "MapUnmappedCourierStatus")
+
+	if req.Body.ShippingStatusUID == "" {
+		return nil, message.ErrShippingStatusUIDRequired
+	}
+
+	unmapped, err := s.unmappedStatusRepo.FindByUID(req.UID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.unmappedStatusRepo.FindByUID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if unmapped == nil {
+		return nil, message.ErrUnmappedCourierStatusNotFound
+	}
+
+	if unmapped.Status == entity.UnmappedStatusMapped {
+		return nil, message.ErrCourierStatusAlreadyMapped
+	}
+
+	shippingStatus, err := s.shippingStatusRepo.FindByUID(req.Body.ShippingStatusUID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingStatusRepo.FindByUID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if shippingStatus == nil {
+		return nil, message.ShippingStatusNotFoundMsg
+	}
+
+	courierStatus, msg := s.addCourierStatus(logger, unmapped, shippingStatus, req.ActorName)
+	if msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	result := &response.MapUnmappedCourierStatus{
+		UID:                unmapped.UID,
+		StatusCode:         unmapped.StatusCode,
+		ShippingStatusCode: shippingStatus.StatusCode,
+	}
+
+	// the mapping only applies to the channel of the shipping status,
+	// the record stays open while webhooks of the other channels are held
+	unmapped.Status = entity.UnmappedStatusMapped
+	if unmapped.Courier != nil {
+		result.CourierCode = unmapped.Courier.Code
+		s.rerunHeldWebhooks(logger, unmapped, shippingStatus.ChannelID, req.JWTInfo, result)
+
+		held, err := s.webhookLogRepo.CountHeldByStatusCode(unmapped.Courier.Code, unmapped.StatusCode)
+		if err != nil {
+			_ = level.Error(logger).Log("s.webhookLogRepo.CountHeldByStatusCode", err.Error())
+		} else if held > 0 {
+			unmapped.Status = entity.UnmappedStatusOpen
+		}
+	}
+
+	now := time.Now().In(util.Loc)
+	unmapped.ShippingCourierStatusID = &courierStatus.ID
+	unmapped.MappedAt = &now
+	unmapped.UpdatedBy = req.ActorName
+
+	if err := s.unmappedStatusRepo.Save(unmapped); err != nil {
+		_ = level.Error(logger).Log("s.unmappedStatusRepo.Save", err.Error())
+		return nil, message.ErrSaveData
+	}
+
+	result.Status = unmapped.Status
+	return result, message.SuccessMsg
+}
+
+// rerunHeldWebhooks re-runs the held webhooks of the orders of the channel
+func (s *unmappedCourierStatusServiceImpl) rerunHeldWebhooks(logger log.Logger, unmapped *entity.UnmappedCourierStatus, channelID uint64, jwtInfo global.JWTInfo, result *response.MapUnmappedCourierStatus) {
+	held, err := s.webhookLogRepo.FindHeldByStatusCode(unmapped.Courier.Code, unmapped.StatusCode, channelID)
+	if err != nil {
+		// the mapping is saved, the held webhooks can still be re-run one by one
+		_ = level.Error(logger).Log("s.webhookLogRepo.FindHeldByStatusCode", err.Error())
+		return
+	}
+
+	for _, v := range held {
+		_, msg := s.webhookService.RerunWebhookLog(&request.RerunWebhookLog{
+			UID:     v.UID,
+			JWTInfo: jwtInfo,
+		})
+
+		if msg == message.SuccessMsg {
+			result.Reapplied++
+		} else {
+			result.Failed++
+		}
+	}
+}
+
+// addCourierStatus adds the code to the courier mapping of the shipping status,
+// a code that has been mapped in the channel of the shipping status in the meantime keeps its mapping
+func (s *unmappedCourierStatusServiceImpl) addCourierStatus(logger log.Logger, unmapped *entity.UnmappedCourierStatus, shippingStatus *entity.ShippingStatus, actor string) (*entity.ShippingCourierStatus, message.Message) {
+	courierStatus, err := s.shippingCourierStatusRepo.FindByCourierStatus(shippingStatus.ChannelID, unmapped.CourierID, unmapped.StatusCode)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingCourierStatusRepo.FindByCourierStatus", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if courierStatus != nil && courierStatus.ID > 0 {
+		return courierStatus, message.SuccessMsg
+	}
+
+	courierStatus, err = s.shippingCourierStatusRepo.FindByShippingStatusID(unmapped.CourierID, shippingStatus.ID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.shippingCourierStatusRepo.FindByShippingStatusID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if courierStatus == nil {
+		courierStatus = &entity.ShippingCourierStatus{
+			ShippingStatusID: shippingStatus.ID,
+			CourierID:        unmapped.CourierID,
+			StatusCode:       shippingStatus.StatusCode,
+		}
+		courierStatus.CreatedBy = actor
+	}
+
+	courierStatus.AddCourierStatus(unmapped.StatusCode)
+	courierStatus.UpdatedBy = actor
+
+	if err := s.shippingCourierStatusRepo.Save(courierStatus); err != nil {
+		_ = level.Error(logger).Log("s.shippingCourierStatusRepo.Save", err.Error())
+		return nil, message.ErrSaveData
+	}
+
+	return courierStatus, message.SuccessMsg
+}
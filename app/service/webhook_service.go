@@ -0,0 +1,436 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-klikdokter/app/model/base"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/app/repository"
+	"go-klikdokter/helper/http_helper/shipping_provider"
+	"go-klikdokter/helper/message"
+	"go-klikdokter/pkg/util"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+const (
+	redactedValue = "***"
+	// maxRejectedWebhookBody is the part of the body of a rejected webhook that is stored
+	maxRejectedWebhookBody = 1024
+)
+
+type WebhookService interface {
+	ReceiveWebhook(req *request.ReceiveWebhook) message.Message
+	GetWebhookLogList(req *request.GetWebhookLogList) ([]response.WebhookLog, *base.Pagination, message.Message)
+	RerunWebhookLog(req *request.RerunWebhookLog) (*response.WebhookLog, message.Message)
+}
+
+type webhookServiceImpl struct {
+	logger             log.Logger
+	baseRepo           repository.BaseRepository
+	webhookLogRepo     repository.WebhookLogRepository
+	unmappedStatusRepo repository.UnmappedCourierStatusRepository
+	courierRepo        repository.CourierRepository
+	shippingService    ShippingService
+}
+
+func NewWebhookService(
+	l log.Logger,
+	br repository.BaseRepository,
+	wlr repository.WebhookLogRepository,
+	ucsr repository.UnmappedCourierStatusRepository,
+	cr repository.CourierRepository,
+	ss ShippingService,
+) WebhookService {
+	return &webhookServiceImpl{l, br, wlr, ucsr, cr, ss}
+}
+
+// webhookEvent is an inbound webhook parsed into the request of the shipping service
+type webhookEvent struct {
+	eventID    string
+	orderNo    string
+	statusCode string
+	statusName string
+	authorized bool
+	// body without the credentials, it is the one that is stored
+	body    []byte
+	process func() message.Message
+}
+
+// ReceiveWebhook stores the webhook, runs it through the shipping service and records the outcome.
+// An authorized event that has been processed before is only counted as duplicate.
+func (s *webhookServiceImpl) ReceiveWebhook(req *request.ReceiveWebhook) message.Message {
+	logger := log.With(s.logger, "WebhookService", "ReceiveWebhook")
+
+	webhookLog := &entity.WebhookLog{
+		Provider: req.Provider,
+		Status:   entity.WebhookStatusReceived,
+	}
+	webhookLog.Headers, _ = json.Marshal(redactWebhookHeader(req.Header))
+	webhookLog.CreatedBy = req.Provider
+
+	event, msg := s.parseWebhook(req.Provider, req.Header, req.Body, false)
+	if msg != message.SuccessMsg {
+		webhookLog.Body = rejectedWebhookBody(req.Body)
+		webhookLog.Attempts = 1
+		s.saveOutcome(logger, webhookLog, msg)
+		return msg
+	}
+
+	if event.authorized {
+		existing, err := s.webhookLogRepo.FindByEventID(req.Provider, event.eventID)
+		if err != nil {
+			_ = level.Error(logger).Log("s.webhookLogRepo.FindByEventID", err.Error())
+		}
+
+		if existing != nil && existing.Status == entity.WebhookStatusProcessed {
+			existing.DuplicateCount++
+			if err := s.webhookLogRepo.Save(existing); err != nil {
+				_ = level.Error(logger).Log("s.webhookLogRepo.Save", err.Error())
+			}
+			return message.SuccessMsg
+		}
+
+		if existing != nil {
+			existing.Headers = webhookLog.Headers
+			webhookLog = existing
+		}
+	}
+	// an authorized event that is not in the log yet, a concurrent request may store it first
+	isNewEvent := event.authorized && webhookLog.Attempts == 0
+
+	webhookLog.EventID = event.eventID
+	webhookLog.OrderNo = event.orderNo
+	webhookLog.StatusCode = event.statusCode
+	webhookLog.Authorized = event.authorized
+	webhookLog.Body = string(event.body)
+	if !event.authorized {
+		webhookLog.Body = rejectedWebhookBody(event.body)
+	}
+	webhookLog.Status = entity.WebhookStatusReceived
+	webhookLog.Attempts++
+
+	// keep the webhook even when processing does not return
+	if isNewEvent {
+		err := s.webhookLogRepo.Create(webhookLog)
+		if errors.Is(err, repository.ErrWebhookEventTaken) {
+			// the same event is being processed by a concurrent request
+			_ = level.Info(logger).Log("duplicate", event.eventID)
+			return message.SuccessMsg
+		}
+
+		if err != nil {
+			_ = level.Error(logger).Log("s.webhookLogRepo.Create", err.Error())
+		}
+	} else if err := s.webhookLogRepo.Save(webhookLog); err != nil {
+		_ = level.Error(logger).Log("s.webhookLogRepo.Save", err.Error())
+	}
+
+	msg = event.process()
+	s.saveOutcome(logger, webhookLog, msg)
+
+	if event.authorized && msg == message.ShippingStatusNotFoundMsg {
+		s.captureUnmappedStatus(logger, req.Provider, event)
+	}
+	return msg
+}
+
+// captureUnmappedStatus queues the courier status code for review,
+// the webhook stays failed in the log until the code is mapped
+func (s *webhookServiceImpl) captureUnmappedStatus(logger log.Logger, provider string, event *webhookEvent) {
+	courier, err := s.courierRepo.FindByCode(provider)
+	if err != nil {
+		_ = level.Error(logger).Log("s.courierRepo.FindByCode", err.Error())
+		return
+	}
+
+	if courier == nil {
+		return
+	}
+
+	unmapped, err := s.unmappedStatusRepo.FindByCourierStatus(courier.ID, event.statusCode)
+	if err != nil {
+		_ = level.Error(logger).Log("s.unmappedStatusRepo.FindByCourierStatus", err.Error())
+		return
+	}
+
+	if unmapped == nil {
+		unmapped = &entity.UnmappedCourierStatus{
+			CourierID:  courier.ID,
+			StatusCode: event.statusCode,
+		}
+		unmapped.CreatedBy = provider
+	}
+
+	// the mapping has been removed since, the code needs a review again
+	unmapped.Status = entity.UnmappedStatusOpen
+	unmapped.ShippingCourierStatusID = nil
+	unmapped.MappedAt = nil
+	unmapped.StatusName = util.ReplaceEmptyString(event.statusName, unmapped.StatusName)
+	unmapped.AddOccurrence(event.body, time.Now().In(util.Loc))
+
+	if err := s.unmappedStatusRepo.Save(unmapped); err != nil {
+		_ = level.Error(logger).Log("s.unmappedStatusRepo.Save", err.Error())
+	}
+}
+
+// swagger:operation GET /shipping/webhook-log Shipping GetWebhookLogList
+// Get Inbound Webhook Log
+//
+// Description :
+// List of inbound courier webhooks, filter by status failed to find the webhooks that need to be re-run
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaPaginationResponse'
+//         data:
+//           properties:
+//             records:
+//               type: array
+//               items:
+//                 $ref: '#/definitions/WebhookLogResponse'
+func (s *webhookServiceImpl) GetWebhookLogList(req *request.GetWebhookLogList) ([]response.WebhookLog, *base.Pagination, message.Message) {
+	logger := log.With(s.logger, "WebhookService", "GetWebhookLogList")
+
+	filter := make(map[string]interface{})
+	filter["provider"] = req.Filters.Provider
+	filter["status"] = req.Filters.Status
+	filter["order_no"] = req.Filters.OrderNo
+	filter["event_id"] = req.Filters.EventID
+
+	result, pagination, err := s.webhookLogRepo.FindByParams(req.Limit, req.Page, req.Sort, filter)
+	if err != nil {
+		_ = level.Error(logger).Log("s.webhookLogRepo.FindByParams", err.Error())
+		return result, pagination, message.ErrNoData
+	}
+
+	return result, pagination, message.SuccessMsg
+}
+
+// swagger:operation POST /shipping/webhook-log/{uid}/rerun Shipping RerunWebhookLog
+// Re-run Inbound Webhook
+//
+// Description :
+// Run a failed webhook again through the same status update, e.g. after the courier status mapping is fixed.
+// Only authorized webhooks can be re-run.
+// ---
+// security:
+// - Bearer: []
+//
+// responses:
+//   '200':
+//     description: Success Response.
+//     schema:
+//       properties:
+//         meta:
+//           $ref: '#/definitions/MetaResponse'
+//         data:
+//           properties:
+//             record:
+//               $ref: '#/definitions/WebhookLogResponse'
+func (s *webhookServiceImpl) RerunWebhookLog(req *request.RerunWebhookLog) (*response.WebhookLog, message.Message) {
+	logger := log.With(s.logger, "WebhookService", "RerunWebhookLog")
+
+	webhookLog, err := s.webhookLogRepo.FindByUID(req.UID)
+	if err != nil {
+		_ = level.Error(logger).Log("s.webhookLogRepo.FindByUID", err.Error())
+		return nil, message.ErrDB
+	}
+
+	if webhookLog == nil {
+		return nil, message.ErrWebhookLogNotFound
+	}
+
+	if !webhookLog.Authorized || webhookLog.Status != entity.WebhookStatusFailed {
+		return nil, message.ErrWebhookLogNotRerunnable
+	}
+
+	// the credentials are not stored, the webhook was authorized when it was received
+	event, msg := s.parseWebhook(webhookLog.Provider, nil, []byte(webhookLog.Body), true)
+	if msg != message.SuccessMsg {
+		return nil, msg
+	}
+
+	webhookLog.Attempts++
+	webhookLog.UpdatedBy = req.ActorName
+
+	msg = event.process()
+	s.saveOutcome(logger, webhookLog, msg)
+	return toWebhookLogResponse(webhookLog), msg
+}
+
+func (s *webhookServiceImpl) saveOutcome(logger log.Logger, webhookLog *entity.WebhookLog, msg message.Message) {
+	now := time.Now().In(util.Loc)
+	webhookLog.ProcessedAt = &now
+	webhookLog.Held = webhookLog.Authorized && msg == message.ShippingStatusNotFoundMsg
+
+	if msg == message.SuccessMsg {
+		webhookLog.Status = entity.WebhookStatusProcessed
+		webhookLog.ErrorCode = 0
+		webhookLog.ErrorMessage = ""
+	} else {
+		webhookLog.Status = entity.WebhookStatusFailed
+		webhookLog.ErrorCode = msg.Code
+		webhookLog.ErrorMessage = msg.Message
+	}
+
+	if err := s.webhookLogRepo.Save(webhookLog); err != nil {
+		_ = level.Error(logger).Log("s.webhookLogRepo.Save", err.Error())
+	}
+}
+
+// parseWebhook builds the shipping service request of the provider.
+// A trusted webhook is a stored one, its credentials are replaced with the configured ones.
+func (s *webhookServiceImpl) parseWebhook(provider string, header http.Header, body []byte, trusted bool) (*webhookEvent, message.Message) {
+	switch provider {
+	case shipping_provider.ShipperCode:
+		return s.parseShipperWebhook(body, trusted)
+	case shipping_provider.GrabCode:
+		return s.parseGrabWebhook(header, body, trusted)
+	}
+
+	return nil, message.ErrUnknownWebhookProvider
+}
+
+func (s *webhookServiceImpl) parseShipperWebhook(body []byte, trusted bool) (*webhookEvent, message.Message) {
+	req := &request.WebhookUpdateStatusShipper{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, message.ErrReqParam
+	}
+
+	redacted, err := redactWebhookBody(body, "auth")
+	if err != nil {
+		return nil, message.ErrReqParam
+	}
+
+	if trusted {
+		req.Auth = shipping_provider.ShipperWebhookAuth()
+	}
+
+	return &webhookEvent{
+		eventID:    req.EventID(),
+		orderNo:    req.ExternalID,
+		statusCode: fmt.Sprint(req.ExternalStatus.Code),
+		statusName: req.ExternalStatus.Name,
+		authorized: req.Auth == shipping_provider.ShipperWebhookAuth(),
+		body:       redacted,
+		process: func() message.Message {
+			_, msg := s.shippingService.UpdateStatusShipper(req)
+			return msg
+		},
+	}, message.SuccessMsg
+}
+
+func (s *webhookServiceImpl) parseGrabWebhook(header http.Header, body []byte, trusted bool) (*webhookEvent, message.Message) {
+	req := &request.WebhookUpdateStatusGrabRequest{}
+	if err := json.Unmarshal(body, &req.Body); err != nil {
+		return nil, message.ErrReqParam
+	}
+
+	if trusted {
+		auth := shipping_provider.GrabWebhookHeader()
+		req.AuthorizationID = auth.AuthorizationID
+		req.Authorization = auth.Authorization
+	} else {
+		req.AuthorizationID = header.Get("Authorization-Id")
+		req.Authorization = header.Get("Authorization")
+	}
+
+	return &webhookEvent{
+		eventID:    req.Body.EventID(),
+		orderNo:    req.Body.MerchantOrderID,
+		statusCode: req.Body.Status,
+		statusName: req.Body.Status,
+		authorized: shipping_provider.GrabWebhookAuth(&request.WebhookUpdateStatusGrabHeader{
+			AuthorizationID: req.AuthorizationID,
+			Authorization:   req.Authorization,
+		}),
+		body: body,
+		process: func() message.Message {
+			return s.shippingService.UpdateStatusGrab(req)
+		},
+	}, message.SuccessMsg
+}
+
+func redactWebhookHeader(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+	for k, v := range header {
+		if strings.EqualFold(k, "Authorization") || strings.EqualFold(k, "Cookie") {
+			result[k] = redactedValue
+			continue
+		}
+		result[k] = strings.Join(v, ", ")
+	}
+	return result
+}
+
+func redactWebhookBody(body []byte, keys ...string) ([]byte, error) {
+	var payload map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	if payload == nil {
+		return nil, errors.New("webhook body is empty")
+	}
+
+	for _, k := range keys {
+		if _, ok := payload[k]; ok {
+			payload[k] = redactedValue
+		}
+	}
+
+	return json.Marshal(payload)
+}
+
+// rejectedWebhookBody keeps the start of the body of a rejected webhook without the credentials,
+// a body that can not be redacted is not stored
+func rejectedWebhookBody(body []byte) string {
+	redacted, err := redactWebhookBody(body, "auth")
+	if err != nil {
+		return ""
+	}
+
+	if len(redacted) > maxRejectedWebhookBody {
+		redacted = redacted[:maxRejectedWebhookBody]
+	}
+
+	return string(redacted)
+}
+
+func toWebhookLogResponse(webhookLog *entity.WebhookLog) *response.WebhookLog {
+	return &response.WebhookLog{
+		UID:            webhookLog.UID,
+		Provider:       webhookLog.Provider,
+		EventID:        webhookLog.EventID,
+		OrderNo:        webhookLog.OrderNo,
+		Headers:        json.RawMessage(webhookLog.Headers),
+		Body:           webhookLog.Body,
+		Authorized:     webhookLog.Authorized,
+		Status:         webhookLog.Status,
+		ErrorCode:      webhookLog.ErrorCode,
+		ErrorMessage:   webhookLog.ErrorMessage,
+		Attempts:       webhookLog.Attempts,
+		DuplicateCount: webhookLog.DuplicateCount,
+		ProcessedAt:    webhookLog.ProcessedAt,
+		CreatedAt:      webhookLog.CreatedAt,
+		UpdatedBy:      webhookLog.UpdatedBy,
+	}
+}
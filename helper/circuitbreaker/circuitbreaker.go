@@ -2,6 +2,10 @@ package circuitbreaker
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/afex/hystrix-go/hystrix"
 	"github.com/go-kit/kit/endpoint"
@@ -30,3 +34,105 @@ func Hystrix(commandName string, fallbackMesg string, logger log.Logger) endpoin
 		}
 	}
 }
+
+// ErrOpen is returned without making the call while the circuit of the command is open
+var ErrOpen = errors.New("circuit open")
+
+// Outcome of a call made through the breaker
+type Outcome int
+
+const (
+	Success Outcome = iota
+	Failure
+
+	// Ignored calls do not count against the circuit, e.g. the caller gave up on the call
+	Ignored
+)
+
+// Config of the breaker of a command, the circuit opens when the error percentage of the rolling
+// 10 seconds reaches the threshold and lets a single call through after the sleep window to test recovery
+type Config struct {
+	RequestVolumeThreshold int
+	ErrorPercentThreshold  int
+	SleepWindow            time.Duration
+}
+
+// State of the circuit of a command
+type State struct {
+	Command string
+	Open    bool
+}
+
+var (
+	commandsMutex sync.RWMutex
+	commands      = make(map[string]bool)
+)
+
+// Configure registers the breaker of the command, the call itself is bounded by the caller and not by the breaker,
+// Run does not take a ticket of the pool of hystrix so its concurrency is not limited either
+func Configure(commandName string, config Config) {
+	hystrix.ConfigureCommand(commandName, hystrix.CommandConfig{
+		Timeout:                int(time.Hour / time.Millisecond),
+		MaxConcurrentRequests:  hystrix.DefaultMaxConcurrent,
+		RequestVolumeThreshold: config.RequestVolumeThreshold,
+		ErrorPercentThreshold:  config.ErrorPercentThreshold,
+		SleepWindow:            int(config.SleepWindow / time.Millisecond),
+	})
+
+	commandsMutex.Lock()
+	defer commandsMutex.Unlock()
+	commands[commandName] = true
+}
+
+// Run makes the call unless the circuit of the command is open and reports the outcome of the call to the circuit
+func Run(commandName string, call func() Outcome) error {
+	circuit, _, err := hystrix.GetCircuit(commandName)
+	if err != nil {
+		call()
+		return nil
+	}
+
+	start := time.Now()
+	if !circuit.AllowRequest() {
+		_ = circuit.ReportEvent([]string{"short-circuit"}, start, 0)
+		return ErrOpen
+	}
+
+	switch call() {
+	case Success:
+		_ = circuit.ReportEvent([]string{"success"}, start, time.Since(start))
+	case Failure:
+		_ = circuit.ReportEvent([]string{"failure"}, start, time.Since(start))
+	}
+
+	return nil
+}
+
+// IsOpen tells whether the calls of the command are currently rejected
+func IsOpen(commandName string) bool {
+	circuit, _, err := hystrix.GetCircuit(commandName)
+	if err != nil {
+		return false
+	}
+
+	return circuit.IsOpen()
+}
+
+// States of the configured commands ordered by name
+func States() []State {
+	commandsMutex.RLock()
+	names := make([]string, 0, len(commands))
+	for k := range commands {
+		names = append(names, k)
+	}
+	commandsMutex.RUnlock()
+
+	sort.Strings(names)
+
+	result := make([]State, 0, len(names))
+	for _, v := range names {
+		result = append(result, State{Command: v, Open: IsOpen(v)})
+	}
+
+	return result
+}
@@ -34,14 +34,42 @@ var (
 	PathCourierService    = "courier-services"
 	PathCourierServiceUID = "courier-services/{uid}"
 
+	PathCourierHoliday    = "courier-holiday"
+	PathCourierHolidayUID = "courier-holiday/{uid}"
+
 	PathShipmentPredefined    = "shipment-predefined"
 	PathShipmentPredefinedUID = "shipment-predefined/{uid}"
 
 	PathChannelCourierStatus = "channel-status-courier-status"
 	PathUIDCourierList       = "{uid}/courier-list"
 
+	PathChannelShippingStatus    = "channel-app/{uid}/shipping-status"
+	PathShippingStatus           = "shipping-status"
+	PathShippingStatusUID        = "shipping-status/{uid}"
+	PathShippingStatusClone      = "shipping-status/clone"
+	PathShippingCourierStatus    = "shipping-courier-status"
+	PathShippingCourierStatusUID = "shipping-courier-status/{uid}"
+	PathChannelStatusTransition  = "channel-app/{uid}/status-transition"
+	PathStatusTransition         = "status-transition"
+	PathStatusTransitionUID      = "status-transition/{uid}"
+
+	PathRateCard = "{uid}/rate-card"
+
+	PathChannelCourierCredential = "{uid}/credential"
+
+	PathChannelPriceRule = "channel-app/{uid}/price-rule"
+	PathPriceRule        = "price-rule"
+	PathPriceRuleUID     = "price-rule/{uid}"
+
+	PathChannelShippingPromotion = "channel-app/{uid}/shipping-promotion"
+	PathShippingPromotion        = "shipping-promotion"
+	PathShippingPromotionUID     = "shipping-promotion/{uid}"
+
+	PathChannelRecommendationWeight = "channel-app/{uid}/recommendation-weight"
+
 	PathShippingRate             = "shipping-rate"
 	PathShippingRateShippingType = "shipping-rate/{shipping-type}"
+	PathShippingRateBatch        = "shipping-rate/batch"
 	PathOrderShipping            = "order-shipping"
 	PathOrderShippingDownload    = "order-shipping/download"
 	PathOrderTracking            = "order-tracking/{uid}"
@@ -53,6 +81,13 @@ var (
 	PathOrderShippingLabel       = "order-shipping-label/{channel-uid}"
 	PathRepickup                 = "repickup"
 	PathShippingTracking         = "tracking/{uid}"
+	PathUpdateStatusUID          = "update-status/{uid}"
+	PathOrderShippingOutbox      = "outbox"
+	PathCircuitBreaker           = "circuit-breaker"
+	PathWebhookLog               = "webhook-log"
+	PathWebhookLogRerun          = "webhook-log/{uid}/rerun"
+	PathUnmappedCourierStatus    = "unmapped-courier-status"
+	PathUnmappedCourierStatusMap = "unmapped-courier-status/{uid}/map"
 
 	ServerPort = "server.port"
 )
@@ -1,7 +1,14 @@
 package http_helper
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-kit/log/level"
 
@@ -9,26 +16,58 @@ import (
 	"github.com/spf13/viper"
 )
 
+const defaultDaprTimeout = 10 * time.Second
+
 type DaprEndpoint interface {
-	PublishKafka(topicName string, req interface{})
+	PublishKafka(ctx context.Context, topicName string, req interface{}) error
 }
 
 type dapr struct {
-	Logger log.Logger
+	Logger     log.Logger
+	httpClient *http.Client
 }
 
+// NewDaprEndpoint bounds every publish by dapr.http.timeout so a sidecar that does not answer can not hang the caller
 func NewDaprEndpoint(log log.Logger) DaprEndpoint {
-	return &dapr{log}
+	timeout := viper.GetDuration("dapr.http.timeout")
+	if timeout <= 0 {
+		timeout = defaultDaprTimeout
+	}
+
+	return &dapr{log, &http.Client{Timeout: timeout}}
 }
 
-func (d *dapr) PublishKafka(topicName string, req interface{}) {
+func (d *dapr) PublishKafka(ctx context.Context, topicName string, req interface{}) error {
 	logger := log.With(d.Logger, "Webhook", "PublishKafka")
 	url := viper.GetString("dapr.endpoint.publish-kafka")
 	url = strings.ReplaceAll(url, "{topic-name}", topicName)
-	header := map[string]string{"Content-Type": "application/json"}
 
-	if _, err := Post(url, header, req, d.Logger); err != nil {
+	jsonReq, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonReq))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	response, err := d.httpClient.Do(httpReq)
+	if err != nil {
+		_ = level.Error(logger).Log("PublishKafka", err.Error())
+		return err
+	}
+
+	defer response.Body.Close()
+
+	// dapr answers 204 when the message is accepted by the pubsub
+	if response.StatusCode >= http.StatusMultipleChoices {
+		body, _ := ioutil.ReadAll(response.Body)
+		err = fmt.Errorf("publish to %s failed with status %d: %s", topicName, response.StatusCode, string(body))
 		_ = level.Error(logger).Log("PublishKafka", err.Error())
-		return
+		return err
 	}
+
+	return nil
 }
@@ -2,103 +2,147 @@ package http_helper
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
+	"math/rand"
 	"net/http"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	neturl "net/url"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/spf13/viper"
 )
 
-func Post(url string, header map[string]string, request interface{}, log log.Logger) ([]byte, error) {
-	var (
-		//curl      string
-		jsonReq   []byte
-		bodyBytes []byte
-		err       error
-	)
+const (
+	defaultTimeout        = 30 * time.Second
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 2 * time.Second
+)
 
-	defer func() {
-		_ = level.Info(log).Log("url", url)
-		_ = level.Info(log).Log("request", string(jsonReq))
-		_ = level.Info(log).Log("response", string(bodyBytes))
-		//_ = level.Info(log).Log("curl", curl)
-	}()
+// logged instead of the body of a request that carries credentials
+const redactedBody = "[redacted]"
 
-	jsonReq, err = json.Marshal(request)
-	if err != nil {
-		return nil, err
-	}
+// ErrUnauthorized is returned with the body of the response when the provider rejects the credentials of the request
+var ErrUnauthorized = errors.New("the provider rejected the credentials of the request")
 
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonReq))
-	if err != nil {
-		return nil, err
-	}
+type callOutcomeKey struct{}
 
-	for k, h := range header {
-		req.Header.Add(k, h)
-	}
+// CallOutcome records whether the provider failed to answer the requests sent with the context, i.e. it could
+// not be reached, did not answer in time or answered 429 or 5xx. A provider that answers a 4xx has not failed.
+type CallOutcome struct {
+	failed int32
+}
 
-	//curl, _ = GetCurl(req)
-	client := http.Client{}
-	response, err := client.Do(req)
+// WithCallOutcome returns the context that records the outcome of the requests sent with it
+func WithCallOutcome(ctx context.Context) (context.Context, *CallOutcome) {
+	outcome := &CallOutcome{}
+	return context.WithValue(ctx, callOutcomeKey{}, outcome), outcome
+}
 
-	if err != nil {
-		return nil, err
+func (o *CallOutcome) Failed() bool {
+	return atomic.LoadInt32(&o.failed) == 1
+}
+
+func recordCallOutcome(ctx context.Context, statusCode int, err error) {
+	outcome, ok := ctx.Value(callOutcomeKey{}).(*CallOutcome)
+	if !ok {
+		return
 	}
 
-	defer response.Body.Close()
-	bodyBytes, err = ioutil.ReadAll(response.Body)
-	return bodyBytes, err
+	if err != nil || statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError {
+		atomic.StoreInt32(&outcome.failed, 1)
+	}
 }
 
-func Get(url string, header map[string]string, queryString map[string]string, log log.Logger) ([]byte, error) {
-	var (
-		jsonReq   []byte
-		bodyBytes []byte
-		err       error
-	)
+// every provider client shares the connection pool of the transport
+var providerTransport = http.DefaultTransport.(*http.Transport).Clone()
+
+// Client sends the requests of a shipping provider. Every attempt is bounded by the timeout of the provider
+// and the request is given up as soon as the context is done. Idempotent requests are retried with
+// jittered backoff when the provider can not be reached or answers 429 or 5xx.
+type Client struct {
+	httpClient     *http.Client
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
 
-	defer func() {
-		_ = level.Info(log).Log("url", url)
-		_ = level.Info(log).Log("request", string(jsonReq))
-		_ = level.Info(log).Log("response", string(bodyBytes))
-	}()
+// NewClient reads the timeout and retry of the provider from the config block of the provider, e.g.
+// shipper.http.timeout, shipper.http.retry.max-attempts, shipper.http.retry.initial-backoff and shipper.http.retry.max-backoff
+func NewClient(provider string) *Client {
+	client := &Client{
+		httpClient: &http.Client{
+			Transport: providerTransport,
+			Timeout:   viper.GetDuration(provider + ".http.timeout"),
+		},
+		maxAttempts:    viper.GetInt(provider + ".http.retry.max-attempts"),
+		initialBackoff: viper.GetDuration(provider + ".http.retry.initial-backoff"),
+		maxBackoff:     viper.GetDuration(provider + ".http.retry.max-backoff"),
+	}
 
-	q := neturl.Values{}
-	for k, v := range queryString {
-		q.Add(k, v)
+	if client.httpClient.Timeout <= 0 {
+		client.httpClient.Timeout = defaultTimeout
 	}
 
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s?%s", url, q.Encode()), nil)
+	if client.maxAttempts <= 0 {
+		client.maxAttempts = defaultMaxAttempts
+	}
 
-	if err != nil {
-		return nil, err
+	if client.initialBackoff <= 0 {
+		client.initialBackoff = defaultInitialBackoff
 	}
 
-	for k, h := range header {
-		req.Header.Add(k, h)
+	if client.maxBackoff < client.initialBackoff {
+		client.maxBackoff = defaultMaxBackoff
 	}
 
-	client := http.Client{}
-	response, err := client.Do(req)
+	return client
+}
 
-	if err != nil {
-		return nil, err
+// Post is not retried, the provider may have processed a request whose response was lost
+func (c *Client) Post(ctx context.Context, url string, header map[string]string, request interface{}, log log.Logger) ([]byte, error) {
+	return c.send(ctx, http.MethodPost, url, header, request, false, false, log)
+}
+
+// PostIdempotent is retried, for the endpoints that only read like the rates and the access token
+func (c *Client) PostIdempotent(ctx context.Context, url string, header map[string]string, request interface{}, log log.Logger) ([]byte, error) {
+	return c.send(ctx, http.MethodPost, url, header, request, true, false, log)
+}
+
+// PostCredential is retried like PostIdempotent, the request and response carry the credentials and the token
+// of the account and are not logged
+func (c *Client) PostCredential(ctx context.Context, url string, header map[string]string, request interface{}, log log.Logger) ([]byte, error) {
+	return c.send(ctx, http.MethodPost, url, header, request, true, true, log)
+}
+
+func (c *Client) Get(ctx context.Context, url string, header map[string]string, queryString map[string]string, log log.Logger) ([]byte, error) {
+	q := neturl.Values{}
+	for k, v := range queryString {
+		q.Add(k, v)
 	}
 
-	defer response.Body.Close()
-	bodyBytes, err = ioutil.ReadAll(response.Body)
+	return c.send(ctx, http.MethodGet, fmt.Sprintf("%s?%s", url, q.Encode()), header, nil, true, false, log)
+}
+
+func (c *Client) Patch(ctx context.Context, url string, header map[string]string, request interface{}, log log.Logger) ([]byte, error) {
+	return c.send(ctx, http.MethodPatch, url, header, request, false, false, log)
+}
 
-	return bodyBytes, err
+func (c *Client) Delete(ctx context.Context, url string, header map[string]string, request interface{}, log log.Logger) ([]byte, error) {
+	return c.send(ctx, http.MethodDelete, url, header, request, true, false, log)
 }
 
-func Patch(url string, header map[string]string, request interface{}, log log.Logger) ([]byte, error) {
+func (c *Client) send(ctx context.Context, method, url string, header map[string]string, request interface{}, idempotent, redact bool, log log.Logger) ([]byte, error) {
 	var (
 		jsonReq   []byte
 		bodyBytes []byte
@@ -107,79 +151,98 @@ func Patch(url string, header map[string]string, request interface{}, log log.Lo
 
 	defer func() {
 		_ = level.Info(log).Log("url", url)
+		if redact {
+			_ = level.Info(log).Log("request", redactedBody, "response", redactedBody)
+			return
+		}
 		_ = level.Info(log).Log("request", string(jsonReq))
 		_ = level.Info(log).Log("response", string(bodyBytes))
 	}()
 
-	jsonReq, err = json.Marshal(request)
+	if request != nil {
+		jsonReq, err = json.Marshal(request)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	if err != nil {
-		return nil, err
+	maxAttempts := 1
+	if idempotent {
+		maxAttempts = c.maxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		var statusCode int
+		statusCode, bodyBytes, err = c.do(ctx, method, url, header, jsonReq)
+		if err == nil && statusCode == http.StatusUnauthorized {
+			return bodyBytes, ErrUnauthorized
+		}
+
+		if !retryable(ctx, statusCode, err) || attempt >= maxAttempts {
+			recordCallOutcome(ctx, statusCode, err)
+			return bodyBytes, err
+		}
+
+		delay := c.backoff(attempt)
+		_ = level.Warn(log).Log("url", url, "attempt", attempt, "status", statusCode, "err", err, "retry-in", delay.String())
+
+		select {
+		case <-ctx.Done():
+			recordCallOutcome(ctx, statusCode, ctx.Err())
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
 	}
+}
 
-	req, err := http.NewRequest(http.MethodPatch, url, bytes.NewBuffer(jsonReq))
+func (c *Client) do(ctx context.Context, method, url string, header map[string]string, body []byte) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
 
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
 	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
 
 	for k, h := range header {
 		req.Header.Add(k, h)
 	}
 
-	client := http.Client{}
-	response, err := client.Do(req)
-
+	response, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
 
 	defer response.Body.Close()
-	bodyBytes, err = ioutil.ReadAll(response.Body)
+	bodyBytes, err := ioutil.ReadAll(response.Body)
 
-	return bodyBytes, err
+	return response.StatusCode, bodyBytes, err
 }
 
-func Delete(url string, header map[string]string, request interface{}, log log.Logger) ([]byte, error) {
-	var (
-		jsonReq   []byte
-		bodyBytes []byte
-		err       error
-	)
-
-	defer func() {
-		_ = level.Info(log).Log("url", url)
-		_ = level.Info(log).Log("request", string(jsonReq))
-		_ = level.Info(log).Log("response", string(bodyBytes))
-	}()
-
-	jsonReq, err = json.Marshal(request)
-
-	if err != nil {
-		return nil, err
+// the request is not retried once the context is done, the caller is no longer waiting for it
+func retryable(ctx context.Context, statusCode int, err error) bool {
+	if ctx.Err() != nil {
+		return false
 	}
 
-	req, err := http.NewRequest(http.MethodDelete, url, bytes.NewBuffer(jsonReq))
-
 	if err != nil {
-		return nil, err
-	}
-
-	for k, h := range header {
-		req.Header.Add(k, h)
+		return true
 	}
 
-	client := http.Client{}
-	response, err := client.Do(req)
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
 
-	if err != nil {
-		return nil, err
+// backoff is a random delay up to the exponential backoff of the attempt, so the retries of
+// concurrent requests do not hit the provider at the same time
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := time.Duration(float64(c.initialBackoff) * math.Pow(2, float64(attempt-1)))
+	if delay <= 0 || delay > c.maxBackoff {
+		delay = c.maxBackoff
 	}
 
-	defer response.Body.Close()
-	bodyBytes, err = ioutil.ReadAll(response.Body)
-
-	return bodyBytes, err
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
 }
 
 func GetCurl(req *http.Request) (string, error) {
@@ -1,6 +1,8 @@
 package http_helper_mock
 
 import (
+	"context"
+
 	"github.com/stretchr/testify/mock"
 )
 
@@ -8,6 +10,12 @@ type DaprEndpointMock struct {
 	Mock mock.Mock
 }
 
-func (d *DaprEndpointMock) PublishKafka(topicName string, req interface{}) {
-	//implemented
+func (d *DaprEndpointMock) PublishKafka(ctx context.Context, topicName string, req interface{}) error {
+	arguments := d.Mock.Called()
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return nil
 }
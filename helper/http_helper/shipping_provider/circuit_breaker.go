@@ -0,0 +1,250 @@
+package shipping_provider
+
+import (
+	"context"
+	"errors"
+	"go-klikdokter/app/model/entity"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/helper/circuitbreaker"
+	"go-klikdokter/helper/http_helper"
+	"go-klikdokter/helper/message"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// operations of a provider with their own circuit breaker
+const (
+	OperationRate   = "rate"
+	OperationCreate = "create"
+	OperationTrack  = "track"
+	OperationCancel = "cancel"
+)
+
+var circuitBreakerOperations = []string{OperationRate, OperationCreate, OperationTrack, OperationCancel}
+
+const (
+	defaultBreakerRequestVolumeThreshold = 20
+	defaultBreakerErrorPercentThreshold  = 50
+	defaultBreakerSleepWindow            = 30 * time.Second
+)
+
+// CircuitBreakerCommand is the name of the breaker of the operation of the provider, e.g. shipper.rate
+func CircuitBreakerCommand(courierCode, operation string) string {
+	return courierCode + "." + operation
+}
+
+// ParseCircuitBreakerCommand splits the name of the breaker into the courier code and the operation
+func ParseCircuitBreakerCommand(command string) (courierCode, operation string) {
+	i := strings.LastIndex(command, ".")
+	if i < 0 {
+		return command, ""
+	}
+
+	return command[:i], command[i+1:]
+}
+
+// validation messages of the providers, they are answered before the provider is called and are not a failure of the provider
+var providerValidationMsg = []message.Message{
+	message.OriginNotFoundMsg,
+	message.DestinationNotFoundMsg,
+	message.CoordinateRequiredMsg,
+	message.InvalidCoordinateMsg,
+}
+
+type circuitBreakerProvider struct {
+	ShippingProvider
+}
+
+// NewCircuitBreakerProvider stops calling the provider while an operation keeps failing,
+// the thresholds are read from <courier code>.circuit-breaker in the config
+func NewCircuitBreakerProvider(provider ShippingProvider) ShippingProvider {
+	key := provider.Code() + ".circuit-breaker."
+	config := circuitbreaker.Config{
+		RequestVolumeThreshold: viper.GetInt(key + "request-volume-threshold"),
+		ErrorPercentThreshold:  viper.GetInt(key + "error-percent-threshold"),
+		SleepWindow:            viper.GetDuration(key + "sleep-window"),
+	}
+
+	if config.RequestVolumeThreshold <= 0 {
+		config.RequestVolumeThreshold = defaultBreakerRequestVolumeThreshold
+	}
+
+	if config.ErrorPercentThreshold <= 0 {
+		config.ErrorPercentThreshold = defaultBreakerErrorPercentThreshold
+	}
+
+	if config.SleepWindow <= 0 {
+		config.SleepWindow = defaultBreakerSleepWindow
+	}
+
+	for _, v := range circuitBreakerOperations {
+		circuitbreaker.Configure(CircuitBreakerCommand(provider.Code(), v), config)
+	}
+
+	return &circuitBreakerProvider{provider}
+}
+
+func (p *circuitBreakerProvider) command(operation string) string {
+	return CircuitBreakerCommand(p.Code(), operation)
+}
+
+func (p *circuitBreakerProvider) GetShippingRate(ctx context.Context, courierID *uint64, input *request.GetShippingRateRequest) (*response.ShippingRateCommonResponse, error) {
+	var (
+		resp *response.ShippingRateCommonResponse
+		err  error
+	)
+
+	open := circuitbreaker.Run(p.command(OperationRate), func() circuitbreaker.Outcome {
+		resp, err = p.ShippingProvider.GetShippingRate(ctx, courierID, input)
+		if err == nil {
+			return circuitbreaker.Success
+		}
+
+		if resp != nil && isProviderValidationMsg(resp.CourierMsg[p.Code()]) {
+			return circuitbreaker.Ignored
+		}
+
+		return providerFailure(ctx)
+	})
+
+	// the services of the courier are unavailable without waiting for the provider
+	if open != nil {
+		return &response.ShippingRateCommonResponse{
+			Rate:       make(map[string]response.ShippingRateData),
+			CourierMsg: map[string]message.Message{p.Code(): message.ProviderUnavailableMsg},
+		}, open
+	}
+
+	return resp, err
+}
+
+func (p *circuitBreakerProvider) CreateDelivery(ctx context.Context, bookingID string, courierService *entity.CourierService, req *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message) {
+	var (
+		resp *response.CreateDeliveryThirdPartyData
+		msg  message.Message
+	)
+
+	open := circuitbreaker.Run(p.command(OperationCreate), func() circuitbreaker.Outcome {
+		callCtx, outcome := http_helper.WithCallOutcome(ctx)
+		resp, msg = p.ShippingProvider.CreateDelivery(callCtx, bookingID, courierService, req)
+		if msg == message.SuccessMsg {
+			return circuitbreaker.Success
+		}
+
+		return providerAnswerFailure(ctx, outcome)
+	})
+
+	if open != nil {
+		return nil, message.ProviderUnavailableMsg
+	}
+
+	return resp, msg
+}
+
+func (p *circuitBreakerProvider) GetTracking(ctx context.Context, orderShipping *entity.OrderShipping) ([]response.GetOrderShippingTracking, message.Message) {
+	var (
+		resp []response.GetOrderShippingTracking
+		msg  message.Message
+	)
+
+	open := circuitbreaker.Run(p.command(OperationTrack), func() circuitbreaker.Outcome {
+		callCtx, outcome := http_helper.WithCallOutcome(ctx)
+		resp, msg = p.ShippingProvider.GetTracking(callCtx, orderShipping)
+		if msg == message.SuccessMsg {
+			return circuitbreaker.Success
+		}
+
+		return providerAnswerFailure(ctx, outcome)
+	})
+
+	if open != nil {
+		return nil, message.ProviderUnavailableMsg
+	}
+
+	return resp, msg
+}
+
+func (p *circuitBreakerProvider) CancelPickup(ctx context.Context, orderShipping *entity.OrderShipping) error {
+	return p.cancel(ctx, func() error {
+		return p.ShippingProvider.CancelPickup(ctx, orderShipping)
+	})
+}
+
+func (p *circuitBreakerProvider) CancelOrder(ctx context.Context, orderShipping *entity.OrderShipping, req *request.CancelOrder) error {
+	return p.cancel(ctx, func() error {
+		return p.ShippingProvider.CancelOrder(ctx, orderShipping, req)
+	})
+}
+
+func (p *circuitBreakerProvider) cancel(ctx context.Context, call func() error) error {
+	var err error
+
+	open := circuitbreaker.Run(p.command(OperationCancel), func() circuitbreaker.Outcome {
+		err = call()
+		if err == nil {
+			return circuitbreaker.Success
+		}
+
+		return providerFailure(ctx)
+	})
+
+	if open != nil {
+		return open
+	}
+
+	return err
+}
+
+// the repickup books the pickup again
+func (p *circuitBreakerProvider) RepickupOrder(ctx context.Context, orderShipping *entity.OrderShipping) message.Message {
+	var msg message.Message
+
+	open := circuitbreaker.Run(p.command(OperationCreate), func() circuitbreaker.Outcome {
+		callCtx, outcome := http_helper.WithCallOutcome(ctx)
+		msg = p.ShippingProvider.RepickupOrder(callCtx, orderShipping)
+		if msg == message.SuccessMsg {
+			return circuitbreaker.Success
+		}
+
+		return providerAnswerFailure(ctx, outcome)
+	})
+
+	if open != nil {
+		return message.ProviderUnavailableMsg
+	}
+
+	return msg
+}
+
+// the provider is not blamed when the caller gave up on the call, a provider that did not answer before the deadline
+// is a failure so a hung provider opens the circuit
+func providerFailure(ctx context.Context) circuitbreaker.Outcome {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return circuitbreaker.Ignored
+	}
+
+	return circuitbreaker.Failure
+}
+
+// providerAnswerFailure is for the operations whose error is a message, a message the provider answered with,
+// e.g. the order is not found or the area is not served, is not a failure of the provider
+func providerAnswerFailure(ctx context.Context, outcome *http_helper.CallOutcome) circuitbreaker.Outcome {
+	if outcome.Failed() || ctx.Err() != nil {
+		return providerFailure(ctx)
+	}
+
+	return circuitbreaker.Ignored
+}
+
+func isProviderValidationMsg(msg message.Message) bool {
+	for _, v := range providerValidationMsg {
+		if v == msg {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,38 @@
+package shipping_provider
+
+import (
+	"context"
+	"go-klikdokter/pkg/util"
+)
+
+// Credential is the account of a channel at the provider, the provider books under the account in the config
+// when the channel has none. Shipper only needs the secret as its api key, grab needs the client id and secret.
+type Credential struct {
+	ClientID string
+	Secret   string
+}
+
+// Account tells the accounts apart without revealing the secret, e.g. in cache keys
+func (c *Credential) Account() string {
+	if c == nil {
+		return ""
+	}
+
+	return util.MD5Hash(c.ClientID + ":" + c.Secret)
+}
+
+type credentialKey struct{}
+
+// WithCredential sends the calls made with the context under the account of the channel
+func WithCredential(ctx context.Context, credential *Credential) context.Context {
+	if credential == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, credentialKey{}, credential)
+}
+
+func credentialFrom(ctx context.Context) *Credential {
+	credential, _ := ctx.Value(credentialKey{}).(*Credential)
+	return credential
+}
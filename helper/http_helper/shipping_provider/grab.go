@@ -1,6 +1,7 @@
 package shipping_provider
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"go-klikdokter/app/model/response"
 	"go-klikdokter/helper/http_helper"
 	"go-klikdokter/helper/message"
+	"go-klikdokter/pkg/cache"
 	"go-klikdokter/pkg/util"
 	"math"
 	"strconv"
@@ -21,57 +23,34 @@ import (
 )
 
 type Grab interface {
-	GetShippingRate(input *request.GetShippingRateRequest) (*response.ShippingRateCommonResponse, error)
-	CreateDelivery(courierService *entity.CourierService, req *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message)
-	GetTracking(orderID string) ([]response.GetOrderShippingTracking, message.Message)
-	CancelDelivery(deliveryID string) error
-	ReCreateDelivery(req *entity.OrderShipping) (*response.CreateDeliveryThirdPartyData, message.Message)
+	GetShippingRate(ctx context.Context, input *request.GetShippingRateRequest) (*response.ShippingRateCommonResponse, error)
+	CreateDelivery(ctx context.Context, courierService *entity.CourierService, req *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message)
+	GetTracking(ctx context.Context, orderID string) ([]response.GetOrderShippingTracking, message.Message)
+	CancelDelivery(ctx context.Context, deliveryID string) error
+	ReCreateDelivery(ctx context.Context, req *entity.OrderShipping) (*response.CreateDeliveryThirdPartyData, message.Message)
 }
 
 type grab struct {
 	Logger log.Logger
+	client *http_helper.Client
+	token  *grabTokenSource
 }
 
-func NewGrab(log log.Logger) Grab {
+func NewGrab(log log.Logger, redis cache.RedisCache) Grab {
+	client := http_helper.NewClient("grab")
 	return &grab{
 		Logger: log,
+		client: client,
+		token:  newGrabTokenSource(log, client, redis),
 	}
 }
 
-func (g *grab) GetToken() string {
-	req := &request.GrabAuthRequest{
-		ClientID:     viper.GetString("grab.auth.client-id"),
-		ClientSecret: viper.GetString("grab.auth.client-secret"),
-		GrantType:    viper.GetString("grab.auth.grant-type"),
-		Scope:        viper.GetString("grab.auth.scope"),
-	}
-
-	url := grabUrl(viper.GetString("grab.path.auth"))
-	headers := map[string]string{
-		"Cache-Control": "no-cache",
-		"Content-Type":  "application/json",
-	}
-
-	respByte, err := http_helper.Post(url, headers, req, g.Logger)
-	if err != nil {
-		return ""
-	}
-
-	resp := map[string]interface{}{}
-	err = json.Unmarshal(respByte, &resp)
-	if err != nil {
-		return ""
-	}
-
-	token, ok := resp["access_token"]
-	if !ok {
-		return ""
-	}
-
-	return fmt.Sprint("Bearer ", token)
+// GetToken returns the cached access token, a new token is only requested when the cached one is about to expire
+func (g *grab) GetToken(ctx context.Context) (string, error) {
+	return g.token.Authorization(ctx)
 }
 
-func (g *grab) GetShippingRate(input *request.GetShippingRateRequest) (*response.ShippingRateCommonResponse, error) {
+func (g *grab) GetShippingRate(ctx context.Context, input *request.GetShippingRateRequest) (*response.ShippingRateCommonResponse, error) {
 
 	if checkCoordinate, msg := input.CheckCoordinate(); !checkCoordinate {
 		return &response.ShippingRateCommonResponse{
@@ -122,7 +101,7 @@ func (g *grab) GetShippingRate(input *request.GetShippingRateRequest) (*response
 		},
 	}
 
-	resp, err := g.GetDeliveryQuote(req)
+	resp, err := g.GetDeliveryQuote(ctx, req)
 	if err != nil {
 		msg := message.ShippingProviderMsg
 		msg.Message = err.Error()
@@ -135,14 +114,11 @@ func (g *grab) GetShippingRate(input *request.GetShippingRateRequest) (*response
 	return resp.ToShippingRate(), nil
 }
 
-func (g *grab) GetDeliveryQuote(req *request.GrabDeliveryQuotes) (*response.GrabDeliveryQuotes, error) {
+func (g *grab) GetDeliveryQuote(ctx context.Context, req *request.GrabDeliveryQuotes) (*response.GrabDeliveryQuotes, error) {
 	url := grabUrl(viper.GetString("grab.path.get-delivery-quote"))
-	headers, err := g.setRequestHeader()
-	if err != nil {
-		return nil, err
-	}
-
-	respByte, err := http_helper.Post(url, headers, req, g.Logger)
+	respByte, err := g.authorized(ctx, func(headers map[string]string) ([]byte, error) {
+		return g.client.PostIdempotent(ctx, url, headers, req, g.Logger)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -166,7 +142,7 @@ func (g *grab) GetDeliveryQuote(req *request.GrabDeliveryQuotes) (*response.Grab
 	return nil, errors.New(errResp.GetReason())
 }
 
-func (g *grab) CreateDelivery(courierService *entity.CourierService, req *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message) {
+func (g *grab) CreateDelivery(ctx context.Context, courierService *entity.CourierService, req *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message) {
 	if ok, msg := req.CheckCoordinate(); !ok {
 		return nil, msg
 	}
@@ -223,6 +199,10 @@ func (g *grab) CreateDelivery(courierService *entity.CourierService, req *reques
 		},
 	}
 
+	if req.COD {
+		grabReq.CashOnDelivery = &request.CashOnDelivery{Amount: int(req.CodAmount())}
+	}
+
 	for _, v := range req.Package.Product {
 		grabReq.Packages = append(grabReq.Packages, request.Package{
 			Name:        v.Name,
@@ -238,7 +218,7 @@ func (g *grab) CreateDelivery(courierService *entity.CourierService, req *reques
 		})
 	}
 
-	order, err := g.CreateOrder(grabReq)
+	order, err := g.CreateOrder(ctx, grabReq)
 	if err != nil {
 		msg := message.ShippingProviderMsg
 		msg.Message = err.Error()
@@ -260,7 +240,7 @@ func (g *grab) CreateDelivery(courierService *entity.CourierService, req *reques
 
 }
 
-func (g *grab) ReCreateDelivery(req *entity.OrderShipping) (*response.CreateDeliveryThirdPartyData, message.Message) {
+func (g *grab) ReCreateDelivery(ctx context.Context, req *entity.OrderShipping) (*response.CreateDeliveryThirdPartyData, message.Message) {
 
 	now := time.Now().Add(5 * time.Second)
 	grabReq := &request.CreateDeliveryGrab{
@@ -310,6 +290,10 @@ func (g *grab) ReCreateDelivery(req *entity.OrderShipping) (*response.CreateDeli
 		},
 	}
 
+	if req.COD {
+		grabReq.CashOnDelivery = &request.CashOnDelivery{Amount: int(req.CodAmount)}
+	}
+
 	for _, v := range req.OrderShippingItem {
 		grabReq.Packages = append(grabReq.Packages, request.Package{
 			Name:        v.ItemName,
@@ -325,7 +309,7 @@ func (g *grab) ReCreateDelivery(req *entity.OrderShipping) (*response.CreateDeli
 		})
 	}
 
-	order, err := g.CreateOrder(grabReq)
+	order, err := g.CreateOrder(ctx, grabReq)
 	if err != nil {
 		msg := message.ShippingProviderMsg
 		msg.Message = err.Error()
@@ -347,14 +331,12 @@ func (g *grab) ReCreateDelivery(req *entity.OrderShipping) (*response.CreateDeli
 
 }
 
-func (g *grab) CreateOrder(req *request.CreateDeliveryGrab) (*response.CreateDeliveryGrab, error) {
+func (g *grab) CreateOrder(ctx context.Context, req *request.CreateDeliveryGrab) (*response.CreateDeliveryGrab, error) {
 	url := grabUrl(viper.GetString("grab.path.create-delivery"))
-	headers, err := g.setRequestHeader()
-	if err != nil {
-		return nil, err
-	}
-
-	respByte, err := http_helper.Post(url, headers, req, g.Logger)
+	// grab does not create the delivery when it rejects the token, so the request is safe to send again
+	respByte, err := g.authorized(ctx, func(headers map[string]string) ([]byte, error) {
+		return g.client.Post(ctx, url, headers, req, g.Logger)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -378,15 +360,12 @@ func (g *grab) CreateOrder(req *request.CreateDeliveryGrab) (*response.CreateDel
 	return nil, errors.New(errResp.GetReason())
 }
 
-func (g *grab) GetOrderDetail(deliveryID string) (*response.GrabDeliveryDetail, error) {
+func (g *grab) GetOrderDetail(ctx context.Context, deliveryID string) (*response.GrabDeliveryDetail, error) {
 	url := grabUrl(viper.GetString("grab.path.delivery-detail"))
 	url = strings.ReplaceAll(url, "{deliveryID}", deliveryID)
-	headers, err := g.setRequestHeader()
-	if err != nil {
-		return nil, err
-	}
-
-	respByte, err := http_helper.Get(url, headers, map[string]string{}, g.Logger)
+	respByte, err := g.authorized(ctx, func(headers map[string]string) ([]byte, error) {
+		return g.client.Get(ctx, url, headers, map[string]string{}, g.Logger)
+	})
 
 	if err != nil {
 		return nil, err
@@ -412,10 +391,10 @@ func (g *grab) GetOrderDetail(deliveryID string) (*response.GrabDeliveryDetail,
 	return nil, errors.New(errResp.GetReason())
 }
 
-func (g *grab) GetTracking(orderID string) ([]response.GetOrderShippingTracking, message.Message) {
+func (g *grab) GetTracking(ctx context.Context, orderID string) ([]response.GetOrderShippingTracking, message.Message) {
 	logger := log.With(g.Logger, "Grab", "GetTracking")
 
-	orderDetail, err := g.GetOrderDetail(orderID)
+	orderDetail, err := g.GetOrderDetail(ctx, orderID)
 	if err != nil {
 		_ = level.Error(logger).Log("g.GetOrderDetail", err.Error())
 		return nil, message.ErrGetOrderDetail
@@ -424,10 +403,10 @@ func (g *grab) GetTracking(orderID string) ([]response.GetOrderShippingTracking,
 	return orderDetail.ToOrderShippingTracking(), message.SuccessMsg
 }
 
-func (g *grab) setRequestHeader() (map[string]string, error) {
-	auth := g.GetToken()
-	if len(auth) == 0 {
-		return make(map[string]string), errors.New("grab unauthorized")
+func (g *grab) setRequestHeader(ctx context.Context) (map[string]string, error) {
+	auth, err := g.GetToken(ctx)
+	if err != nil {
+		return make(map[string]string), err
 	}
 
 	return map[string]string{
@@ -436,20 +415,39 @@ func (g *grab) setRequestHeader() (map[string]string, error) {
 	}, nil
 }
 
+// authorized sends the request with the access token, when grab rejects the token
+// it is refreshed and the request is sent once more
+func (g *grab) authorized(ctx context.Context, send func(headers map[string]string) ([]byte, error)) ([]byte, error) {
+	headers, err := g.setRequestHeader(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	respByte, err := send(headers)
+	if !errors.Is(err, http_helper.ErrUnauthorized) {
+		return respByte, err
+	}
+
+	g.token.Invalidate(ctx, headers["Authorization"])
+	headers, err = g.setRequestHeader(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return send(headers)
+}
+
 func grabUrl(path string) string {
 	base := viper.GetString("grab.base")
 	return base + path
 }
 
-func (g *grab) CancelDelivery(deliveryID string) error {
+func (g *grab) CancelDelivery(ctx context.Context, deliveryID string) error {
 	url := grabUrl(viper.GetString("grab.path.delivery-detail"))
 	url = strings.ReplaceAll(url, "{deliveryID}", deliveryID)
-	headers, err := g.setRequestHeader()
-	if err != nil {
-		return err
-	}
-
-	respByte, err := http_helper.Delete(url, headers, map[string]string{}, g.Logger)
+	respByte, err := g.authorized(ctx, func(headers map[string]string) ([]byte, error) {
+		return g.client.Delete(ctx, url, headers, map[string]string{}, g.Logger)
+	})
 
 	if err != nil {
 		return err
@@ -470,3 +468,74 @@ func (g *grab) CancelDelivery(deliveryID string) error {
 
 	return errResp.Error()
 }
+
+var grabPickupOrderCancelableStatus = []string{
+	StatusRequestPickup,
+}
+
+var grabOrderCancelableStatus = []string{
+	StatusCreated,
+	StatusRequestPickup,
+}
+
+type grabProvider struct {
+	grab Grab
+}
+
+// NewGrabProvider wraps the Grab Express API client as a ShippingProvider
+func NewGrabProvider(gr Grab) ShippingProvider {
+	return &grabProvider{grab: gr}
+}
+
+func (p *grabProvider) Code() string {
+	return GrabCode
+}
+
+func (p *grabProvider) GetShippingRate(ctx context.Context, courierID *uint64, input *request.GetShippingRateRequest) (*response.ShippingRateCommonResponse, error) {
+	return p.grab.GetShippingRate(ctx, input)
+}
+
+func (p *grabProvider) CreateDelivery(ctx context.Context, bookingID string, courierService *entity.CourierService, req *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message) {
+	return p.grab.CreateDelivery(ctx, courierService, req)
+}
+
+func (p *grabProvider) GetTracking(ctx context.Context, orderShipping *entity.OrderShipping) ([]response.GetOrderShippingTracking, message.Message) {
+	return p.grab.GetTracking(ctx, orderShipping.BookingID)
+}
+
+func (p *grabProvider) CancelPickup(ctx context.Context, orderShipping *entity.OrderShipping) error {
+	return p.grab.CancelDelivery(ctx, orderShipping.BookingID)
+}
+
+func (p *grabProvider) CancelOrder(ctx context.Context, orderShipping *entity.OrderShipping, req *request.CancelOrder) error {
+	// if request pickup has been cancelled then cancel the order
+	if orderShipping.Status == StatusCreated {
+		return nil
+	}
+
+	return p.grab.CancelDelivery(ctx, orderShipping.BookingID)
+}
+
+func (p *grabProvider) RepickupOrder(ctx context.Context, orderShipping *entity.OrderShipping) message.Message {
+	result, msg := p.grab.ReCreateDelivery(ctx, orderShipping)
+	if msg != message.SuccessMsg {
+		return msg
+	}
+
+	orderShipping.ShippingCost = result.ShippingCost
+	orderShipping.TotalShippingCost = result.TotalShippingCost
+	orderShipping.ActualShippingCost = result.ActualShippingCost
+	orderShipping.Status = result.Status
+	orderShipping.BookingID = result.BookingID
+	orderShipping.Airwaybill = result.Airwaybill
+	orderShipping.PickupCode = &result.PickUpCode
+	return message.SuccessMsg
+}
+
+func (p *grabProvider) IsPickUpOrderCancelable(status string) bool {
+	return isStatusIn(grabPickupOrderCancelableStatus, status)
+}
+
+func (p *grabProvider) IsOrderCancelable(status string) bool {
+	return isStatusIn(grabOrderCancelableStatus, status)
+}
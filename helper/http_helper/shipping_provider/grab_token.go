@@ -0,0 +1,208 @@
+package shipping_provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/helper/http_helper"
+	"go-klikdokter/pkg/cache"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/spf13/viper"
+)
+
+// used when grab.auth.token-expiry-margin is not configured
+const defaultGrabTokenExpiryMargin = time.Minute
+
+type grabToken struct {
+	AccessToken string    `json:"access_token"`
+	ExpiredAt   time.Time `json:"expired_at"`
+}
+
+func (t *grabToken) valid() bool {
+	return t != nil && len(t.AccessToken) > 0 && time.Now().Before(t.ExpiredAt)
+}
+
+func (t *grabToken) authorization() string {
+	return fmt.Sprint("Bearer ", t.AccessToken)
+}
+
+// grabTokenSource keeps the access token of every grab account until shortly before it expires, in redis so the instances
+// share it and in memory so it is kept when redis is not active. Only one request at a time asks grab for a new token
+// of an account, the accounts are refreshed independently.
+type grabTokenSource struct {
+	logger log.Logger
+	client *http_helper.Client
+	redis  cache.RedisCache
+
+	// key: client id
+	mu        sync.RWMutex
+	tokens    map[string]*grabToken
+	refreshes map[string]chan struct{}
+}
+
+func newGrabTokenSource(logger log.Logger, client *http_helper.Client, redis cache.RedisCache) *grabTokenSource {
+	return &grabTokenSource{
+		logger:    logger,
+		client:    client,
+		redis:     redis,
+		tokens:    make(map[string]*grabToken),
+		refreshes: make(map[string]chan struct{}),
+	}
+}
+
+// refresh is held by the request that asks grab for a new token of the account
+func (s *grabTokenSource) refresh(clientID string) chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	refresh, ok := s.refreshes[clientID]
+	if !ok {
+		refresh = make(chan struct{}, 1)
+		s.refreshes[clientID] = refresh
+	}
+	return refresh
+}
+
+// account is the grab account of the channel the context carries or the account in the config
+func (s *grabTokenSource) account(ctx context.Context) *Credential {
+	if credential := credentialFrom(ctx); credential != nil {
+		return credential
+	}
+
+	return &Credential{
+		ClientID: viper.GetString("grab.auth.client-id"),
+		Secret:   viper.GetString("grab.auth.client-secret"),
+	}
+}
+
+func (s *grabTokenSource) key(clientID string) string {
+	return fmt.Sprintf("%s:grab-token:%s", viper.GetString("cache.redis.base-key"), clientID)
+}
+
+// Authorization is the value of the authorization header of the requests to grab
+func (s *grabTokenSource) Authorization(ctx context.Context) (string, error) {
+	account := s.account(ctx)
+	if token := s.cached(account.ClientID); token != nil {
+		return token.authorization(), nil
+	}
+
+	refresh := s.refresh(account.ClientID)
+	select {
+	case refresh <- struct{}{}:
+		defer func() { <-refresh }()
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+
+	// the token may have been refreshed while waiting
+	if token := s.cached(account.ClientID); token != nil {
+		return token.authorization(), nil
+	}
+
+	token, err := s.fetch(ctx, account)
+	if err != nil {
+		return "", err
+	}
+
+	s.store(account.ClientID, token)
+	return token.authorization(), nil
+}
+
+// Invalidate drops the token grab rejected, unless it has already been replaced
+func (s *grabTokenSource) Invalidate(ctx context.Context, authorization string) {
+	clientID := s.account(ctx).ClientID
+
+	s.mu.Lock()
+	if token := s.tokens[clientID]; token != nil && token.authorization() == authorization {
+		delete(s.tokens, clientID)
+	}
+	s.mu.Unlock()
+
+	var token *grabToken
+	_ = s.redis.GetJsonStruct(s.key(clientID), &token)
+	if token != nil && token.authorization() == authorization {
+		s.redis.Delete(s.key(clientID))
+	}
+}
+
+func (s *grabTokenSource) cached(clientID string) *grabToken {
+	s.mu.RLock()
+	token := s.tokens[clientID]
+	s.mu.RUnlock()
+
+	if token.valid() {
+		return token
+	}
+
+	token = nil
+	_ = s.redis.GetJsonStruct(s.key(clientID), &token)
+	if !token.valid() {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.tokens[clientID] = token
+	s.mu.Unlock()
+
+	return token
+}
+
+func (s *grabTokenSource) store(clientID string, token *grabToken) {
+	s.mu.Lock()
+	s.tokens[clientID] = token
+	s.mu.Unlock()
+
+	// redis expires in minutes, a token that expires sooner is only kept in memory
+	if minutes := int(time.Until(token.ExpiredAt) / time.Minute); minutes > 0 {
+		s.redis.SetJsonStruct(s.key(clientID), token, minutes)
+	}
+}
+
+func (s *grabTokenSource) fetch(ctx context.Context, account *Credential) (*grabToken, error) {
+	req := &request.GrabAuthRequest{
+		ClientID:     account.ClientID,
+		ClientSecret: account.Secret,
+		GrantType:    viper.GetString("grab.auth.grant-type"),
+		Scope:        viper.GetString("grab.auth.scope"),
+	}
+
+	url := grabUrl(viper.GetString("grab.path.auth"))
+	headers := map[string]string{
+		"Cache-Control": "no-cache",
+		"Content-Type":  "application/json",
+	}
+
+	respByte, err := s.client.PostCredential(ctx, url, headers, req, s.logger)
+	if err != nil {
+		return nil, fmt.Errorf("grab token: %w", err)
+	}
+
+	resp := &response.GrabAuth{}
+	if err := json.Unmarshal(respByte, resp); err != nil {
+		return nil, fmt.Errorf("grab token: %w", err)
+	}
+
+	if len(resp.AccessToken) == 0 {
+		errResp := &response.GrabError{}
+		_ = json.Unmarshal(respByte, errResp)
+		if reason := errResp.GetReason(); len(reason) > 0 {
+			return nil, fmt.Errorf("grab token: %s", reason)
+		}
+		return nil, errors.New("grab token: access token is empty")
+	}
+
+	margin := viper.GetDuration("grab.auth.token-expiry-margin")
+	if margin <= 0 {
+		margin = defaultGrabTokenExpiryMargin
+	}
+
+	// a token that expires within the margin is still used for the request that asked for it
+	expiredAt := time.Now().Add(time.Duration(resp.ExpiresIn)*time.Second - margin)
+	return &grabToken{AccessToken: resp.AccessToken, ExpiredAt: expiredAt}, nil
+}
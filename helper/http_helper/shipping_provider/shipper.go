@@ -1,6 +1,7 @@
 package shipping_provider
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"go-klikdokter/app/model/entity"
@@ -19,21 +20,22 @@ import (
 )
 
 type Shipper interface {
-	GetShippingRate(courierID *uint64, input *request.GetShippingRateRequest) (*response.ShippingRateCommonResponse, error)
-	GetPricingDomestic(req *request.GetPricingDomestic) (*response.GetPricingDomestic, error)
-	CreateOrder(req *request.CreateOrderShipper) (*response.CreateOrderShipperResponse, error)
-	CreatePickUpOrderWithTimeSlots(orderID ...string) (*response.CreatePickUpOrderShipperResponse, message.Message)
-	CreateDelivery(ShipperOrderID string, courierService *entity.CourierService, req *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message)
-	GetOrderDetail(orderID string) (*response.GetOrderDetailResponse, error)
-	GetTracking(orderID string) ([]response.GetOrderShippingTracking, message.Message)
-	CancelPickupRequest(pickupCode string) (*response.MetadataResponse, error)
-	CancelOrder(orderID string, req *request.CancelOrder) (*response.MetadataResponse, error)
+	GetShippingRate(ctx context.Context, courierID *uint64, input *request.GetShippingRateRequest) (*response.ShippingRateCommonResponse, error)
+	GetPricingDomestic(ctx context.Context, req *request.GetPricingDomestic) (*response.GetPricingDomestic, error)
+	CreateOrder(ctx context.Context, req *request.CreateOrderShipper) (*response.CreateOrderShipperResponse, error)
+	CreatePickUpOrderWithTimeSlots(ctx context.Context, orderID ...string) (*response.CreatePickUpOrderShipperResponse, message.Message)
+	CreateDelivery(ctx context.Context, ShipperOrderID string, courierService *entity.CourierService, req *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message)
+	GetOrderDetail(ctx context.Context, orderID string) (*response.GetOrderDetailResponse, error)
+	GetTracking(ctx context.Context, orderID string) ([]response.GetOrderShippingTracking, message.Message)
+	CancelPickupRequest(ctx context.Context, pickupCode string) (*response.MetadataResponse, error)
+	CancelOrder(ctx context.Context, orderID string, req *request.CancelOrder) (*response.MetadataResponse, error)
 }
 type shipper struct {
 	courierCoverage repository.CourierCoverageCodeRepository
 	Logger          log.Logger
 	Header          map[string]string
 	Base            string
+	client          *http_helper.Client
 }
 
 func NewShipper(ccr repository.CourierCoverageCodeRepository, log log.Logger) Shipper {
@@ -45,16 +47,33 @@ func NewShipper(ccr repository.CourierCoverageCodeRepository, log log.Logger) Sh
 		Base:            viper.GetString("shipper.base"),
 		courierCoverage: ccr,
 		Logger:          log,
+		client:          http_helper.NewClient("shipper"),
 	}
 }
 
-func (h *shipper) GetPricingDomestic(req *request.GetPricingDomestic) (*response.GetPricingDomestic, error) {
+// header is sent with the api key of the channel when the channel has its own shipper account
+func (h *shipper) header(ctx context.Context) map[string]string {
+	credential := credentialFrom(ctx)
+	if credential == nil {
+		return h.Header
+	}
+
+	header := make(map[string]string, len(h.Header))
+	for k, v := range h.Header {
+		header[k] = v
+	}
+	header[viper.GetString("shipper.auth.key")] = credential.Secret
+
+	return header
+}
+
+func (h *shipper) GetPricingDomestic(ctx context.Context, req *request.GetPricingDomestic) (*response.GetPricingDomestic, error) {
 
 	response := response.GetPricingDomestic{}
 	path := viper.GetString("shipper.path.get-pricing-domestic")
 	url := h.Base + path
 
-	respByte, err := http_helper.Post(url, h.Header, req, h.Logger)
+	respByte, err := h.client.PostIdempotent(ctx, url, h.header(ctx), req, h.Logger)
 
 	if err != nil {
 		return nil, err
@@ -111,7 +130,7 @@ func (h *shipper) GetOriginAndDestination(courierID *uint64, input *request.GetS
 	return originAreaID, destinationAreaID, message.SuccessMsg
 }
 
-func (h *shipper) GetShippingRate(courierID *uint64, input *request.GetShippingRateRequest) (*response.ShippingRateCommonResponse, error) {
+func (h *shipper) GetShippingRate(ctx context.Context, courierID *uint64, input *request.GetShippingRateRequest) (*response.ShippingRateCommonResponse, error) {
 
 	origin, destination, msg := h.GetOriginAndDestination(courierID, input)
 
@@ -125,7 +144,7 @@ func (h *shipper) GetShippingRate(courierID *uint64, input *request.GetShippingR
 
 	payload := request.NewGetPricingDomesticRequest(origin, destination, input)
 
-	shipperResponse, err := h.GetPricingDomestic(payload)
+	shipperResponse, err := h.GetPricingDomestic(ctx, payload)
 
 	//if failed to get pricing from shipper api
 	if err != nil {
@@ -142,13 +161,13 @@ func (h *shipper) GetShippingRate(courierID *uint64, input *request.GetShippingR
 	return resp, nil
 }
 
-func (h *shipper) CreateOrder(req *request.CreateOrderShipper) (*response.CreateOrderShipperResponse, error) {
+func (h *shipper) CreateOrder(ctx context.Context, req *request.CreateOrderShipper) (*response.CreateOrderShipperResponse, error) {
 
 	response := response.CreateOrderShipperResponse{}
 	path := viper.GetString("shipper.path.order")
 	url := h.Base + path
 
-	respByte, err := http_helper.Post(url, h.Header, req, h.Logger)
+	respByte, err := h.client.Post(ctx, url, h.header(ctx), req, h.Logger)
 
 	if err != nil {
 		return nil, err
@@ -166,7 +185,7 @@ func (h *shipper) CreateOrder(req *request.CreateOrderShipper) (*response.Create
 	return &response, nil
 }
 
-func (h *shipper) GetTimeslot(req *request.GetPickUpTimeslot) (*response.GetPickUpTimeslotResponse, error) {
+func (h *shipper) GetTimeslot(ctx context.Context, req *request.GetPickUpTimeslot) (*response.GetPickUpTimeslotResponse, error) {
 	response := response.GetPickUpTimeslotResponse{}
 	path := viper.GetString("shipper.path.pick-up-timeslot")
 	url := h.Base + path
@@ -175,7 +194,7 @@ func (h *shipper) GetTimeslot(req *request.GetPickUpTimeslot) (*response.GetPick
 		"time_zone": req.TimeZone,
 	}
 
-	respByte, err := http_helper.Get(url, h.Header, params, h.Logger)
+	respByte, err := h.client.Get(ctx, url, h.header(ctx), params, h.Logger)
 
 	if err != nil {
 		return nil, err
@@ -194,13 +213,13 @@ func (h *shipper) GetTimeslot(req *request.GetPickUpTimeslot) (*response.GetPick
 	return &response, nil
 }
 
-func (h *shipper) CreatePickUpOrder(req *request.CreatePickUpOrderShipper) (*response.CreatePickUpOrderShipperResponse, error) {
+func (h *shipper) CreatePickUpOrder(ctx context.Context, req *request.CreatePickUpOrderShipper) (*response.CreatePickUpOrderShipperResponse, error) {
 
 	response := response.CreatePickUpOrderShipperResponse{}
 	path := viper.GetString("shipper.path.pick-up-timeslot")
 	url := h.Base + path
 
-	respByte, err := http_helper.Post(url, h.Header, req, h.Logger)
+	respByte, err := h.client.Post(ctx, url, h.header(ctx), req, h.Logger)
 
 	if err != nil {
 		return nil, err
@@ -219,9 +238,9 @@ func (h *shipper) CreatePickUpOrder(req *request.CreatePickUpOrderShipper) (*res
 	return &response, nil
 }
 
-func (h *shipper) CreatePickUpOrderWithTimeSlots(orderID ...string) (*response.CreatePickUpOrderShipperResponse, message.Message) {
+func (h *shipper) CreatePickUpOrderWithTimeSlots(ctx context.Context, orderID ...string) (*response.CreatePickUpOrderShipperResponse, message.Message) {
 	logger := log.With(h.Logger, "Shipper", "CreatePickUpOrderWithTimeSlots")
-	timeslots, err := h.GetTimeslot(&request.GetPickUpTimeslot{TimeZone: "Asia/Jakarta"})
+	timeslots, err := h.GetTimeslot(ctx, &request.GetPickUpTimeslot{TimeZone: "Asia/Jakarta"})
 
 	if err != nil {
 		_ = level.Error(logger).Log("h.CreateOrder", err.Error())
@@ -239,7 +258,7 @@ func (h *shipper) CreatePickUpOrderWithTimeSlots(orderID ...string) (*response.C
 		},
 	}
 
-	pickup, err := h.CreatePickUpOrder(req)
+	pickup, err := h.CreatePickUpOrder(ctx, req)
 	if err != nil {
 		_ = level.Error(logger).Log("h.CreatePickUpOrder", err.Error())
 		return nil, message.ErrCreatePickUpOrder
@@ -248,7 +267,7 @@ func (h *shipper) CreatePickUpOrderWithTimeSlots(orderID ...string) (*response.C
 	return pickup, message.SuccessMsg
 }
 
-func (h *shipper) CreateDelivery(shipperOrderID string, courierService *entity.CourierService, req *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message) {
+func (h *shipper) CreateDelivery(ctx context.Context, shipperOrderID string, courierService *entity.CourierService, req *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message) {
 	logger := log.With(h.Logger, "Shipper", "CreateDelivery")
 	resp := &response.CreateDeliveryThirdPartyData{}
 	order := &response.CreateOrderShipperResponse{Data: response.CreateOrderShipper{OrderID: shipperOrderID}}
@@ -277,7 +296,7 @@ func (h *shipper) CreateDelivery(shipperOrderID string, courierService *entity.C
 		orderRequet.Destination.AreaID = uint64(destination)
 		orderRequet.Courier.RateID, _ = strconv.Atoi(courierService.ShippingCode)
 		orderRequet.Package.PackageType = viper.GetInt("shipper.setting.package-type")
-		order, err = h.CreateOrder(orderRequet)
+		order, err = h.CreateOrder(ctx, orderRequet)
 
 		if err != nil {
 			_ = level.Error(logger).Log("h.CreateOrder", err.Error())
@@ -295,7 +314,7 @@ func (h *shipper) CreateDelivery(shipperOrderID string, courierService *entity.C
 		resp.Status = StatusCreated
 	}
 
-	pickup, msg := h.CreatePickUpOrderWithTimeSlots(order.Data.OrderID)
+	pickup, msg := h.CreatePickUpOrderWithTimeSlots(ctx, order.Data.OrderID)
 
 	if msg == message.SuccessMsg {
 		resp.Status = StatusRequestPickup
@@ -306,12 +325,12 @@ func (h *shipper) CreateDelivery(shipperOrderID string, courierService *entity.C
 	return resp, message.SuccessMsg
 }
 
-func (h *shipper) GetOrderDetail(orderID string) (*response.GetOrderDetailResponse, error) {
+func (h *shipper) GetOrderDetail(ctx context.Context, orderID string) (*response.GetOrderDetailResponse, error) {
 	response := response.GetOrderDetailResponse{}
 	path := viper.GetString("shipper.path.order-detail")
 	path = strings.ReplaceAll(path, "{orderID}", orderID)
 	url := h.Base + path
-	respByte, err := http_helper.Get(url, h.Header, map[string]string{}, h.Logger)
+	respByte, err := h.client.Get(ctx, url, h.header(ctx), map[string]string{}, h.Logger)
 
 	if err != nil {
 		return nil, err
@@ -330,9 +349,9 @@ func (h *shipper) GetOrderDetail(orderID string) (*response.GetOrderDetailRespon
 	return &response, nil
 }
 
-func (h *shipper) GetTracking(orderID string) ([]response.GetOrderShippingTracking, message.Message) {
+func (h *shipper) GetTracking(ctx context.Context, orderID string) ([]response.GetOrderShippingTracking, message.Message) {
 	logger := log.With(h.Logger, "Shipper", "GetTracking")
-	orderDetail, err := h.GetOrderDetail(orderID)
+	orderDetail, err := h.GetOrderDetail(ctx, orderID)
 	if err != nil {
 		_ = level.Error(logger).Log("h.GetOrderDetail", err.Error())
 		return nil, message.ErrGetOrderDetail
@@ -341,12 +360,12 @@ func (h *shipper) GetTracking(orderID string) ([]response.GetOrderShippingTracki
 	return orderDetail.Data.ToOrderShippingTracking(), message.SuccessMsg
 }
 
-func (h *shipper) CancelPickupRequest(pickupCode string) (*response.MetadataResponse, error) {
+func (h *shipper) CancelPickupRequest(ctx context.Context, pickupCode string) (*response.MetadataResponse, error) {
 	response := response.MetadataResponse{}
 	path := viper.GetString("shipper.path.cancel-pickup")
 	url := h.Base + path
 
-	respByte, err := http_helper.Patch(url, h.Header, map[string]string{"pickup_Code": pickupCode}, h.Logger)
+	respByte, err := h.client.Patch(ctx, url, h.header(ctx), map[string]string{"pickup_Code": pickupCode}, h.Logger)
 
 	if err != nil {
 		return nil, err
@@ -365,13 +384,13 @@ func (h *shipper) CancelPickupRequest(pickupCode string) (*response.MetadataResp
 	return &response, nil
 }
 
-func (h *shipper) CancelOrder(orderID string, req *request.CancelOrder) (*response.MetadataResponse, error) {
+func (h *shipper) CancelOrder(ctx context.Context, orderID string, req *request.CancelOrder) (*response.MetadataResponse, error) {
 	response := response.MetadataResponse{}
 	path := viper.GetString("shipper.path.order-detail")
 	path = strings.ReplaceAll(path, "{orderID}", orderID)
 	url := h.Base + path
 
-	respByte, err := http_helper.Delete(url, h.Header, request.CancelOrderShipperRequest{Reason: req.Body.Reason}, h.Logger)
+	respByte, err := h.client.Delete(ctx, url, h.header(ctx), request.CancelOrderShipperRequest{Reason: req.Body.Reason}, h.Logger)
 
 	if err != nil {
 		return nil, err
@@ -389,3 +408,66 @@ func (h *shipper) CancelOrder(orderID string, req *request.CancelOrder) (*respon
 
 	return &response, nil
 }
+
+var shipperPickupOrderCancelableStatus = []string{
+	StatusRequestPickup,
+}
+
+var shipperOrderCancelableStatus = []string{
+	StatusCreated,
+	StatusRequestPickup,
+}
+
+type shipperProvider struct {
+	shipper Shipper
+}
+
+// NewShipperProvider wraps the Shipper API client as a ShippingProvider
+func NewShipperProvider(sh Shipper) ShippingProvider {
+	return &shipperProvider{shipper: sh}
+}
+
+func (p *shipperProvider) Code() string {
+	return ShipperCode
+}
+
+func (p *shipperProvider) GetShippingRate(ctx context.Context, courierID *uint64, input *request.GetShippingRateRequest) (*response.ShippingRateCommonResponse, error) {
+	return p.shipper.GetShippingRate(ctx, courierID, input)
+}
+
+func (p *shipperProvider) CreateDelivery(ctx context.Context, bookingID string, courierService *entity.CourierService, req *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message) {
+	return p.shipper.CreateDelivery(ctx, bookingID, courierService, req)
+}
+
+func (p *shipperProvider) GetTracking(ctx context.Context, orderShipping *entity.OrderShipping) ([]response.GetOrderShippingTracking, message.Message) {
+	return p.shipper.GetTracking(ctx, orderShipping.BookingID)
+}
+
+func (p *shipperProvider) CancelPickup(ctx context.Context, orderShipping *entity.OrderShipping) error {
+	_, err := p.shipper.CancelPickupRequest(ctx, *orderShipping.PickupCode)
+	return err
+}
+
+func (p *shipperProvider) CancelOrder(ctx context.Context, orderShipping *entity.OrderShipping, req *request.CancelOrder) error {
+	_, err := p.shipper.CancelOrder(ctx, orderShipping.BookingID, req)
+	return err
+}
+
+func (p *shipperProvider) RepickupOrder(ctx context.Context, orderShipping *entity.OrderShipping) message.Message {
+	result, msg := p.shipper.CreatePickUpOrderWithTimeSlots(ctx, orderShipping.BookingID)
+	if msg != message.SuccessMsg {
+		return msg
+	}
+
+	//update pickupCode
+	orderShipping.PickupCode = &result.Data.OrderActivation[0].PickUpCode
+	return message.SuccessMsg
+}
+
+func (p *shipperProvider) IsPickUpOrderCancelable(status string) bool {
+	return isStatusIn(shipperPickupOrderCancelableStatus, status)
+}
+
+func (p *shipperProvider) IsOrderCancelable(status string) bool {
+	return isStatusIn(shipperOrderCancelableStatus, status)
+}
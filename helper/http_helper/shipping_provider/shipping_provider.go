@@ -1,10 +1,15 @@
 package shipping_provider
 
 import (
+	"context"
 	"fmt"
+	"go-klikdokter/app/model/entity"
 	"go-klikdokter/app/model/request"
+	"go-klikdokter/app/model/response"
+	"go-klikdokter/helper/message"
 	"go-klikdokter/pkg/util"
 	"strings"
+	"sync"
 
 	"github.com/spf13/viper"
 )
@@ -22,53 +27,59 @@ const (
 	StatusCancelled     = "cancelled"
 )
 
-var shipperPickupOrderCancelableStatus = []string{
-	StatusRequestPickup,
+// RequiredShippingStatusCodes are the shipping status every channel needs,
+// creating a delivery, re-pickup and cancellation look them up by code
+var RequiredShippingStatusCodes = []string{StatusCreated, StatusRequestPickup, StatusCancelled}
+
+// ShippingProvider is the common contract of every third party courier integration.
+// A courier is bookable as soon as its provider is registered with its courier code.
+// The calls are given up when the context of the request is done and are made under the account of
+// the channel the context carries, see WithCredential.
+type ShippingProvider interface {
+	Code() string
+	GetShippingRate(ctx context.Context, courierID *uint64, input *request.GetShippingRateRequest) (*response.ShippingRateCommonResponse, error)
+	CreateDelivery(ctx context.Context, bookingID string, courierService *entity.CourierService, req *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message)
+	GetTracking(ctx context.Context, orderShipping *entity.OrderShipping) ([]response.GetOrderShippingTracking, message.Message)
+	CancelPickup(ctx context.Context, orderShipping *entity.OrderShipping) error
+	CancelOrder(ctx context.Context, orderShipping *entity.OrderShipping, req *request.CancelOrder) error
+	RepickupOrder(ctx context.Context, orderShipping *entity.OrderShipping) message.Message
+	IsPickUpOrderCancelable(status string) bool
+	IsOrderCancelable(status string) bool
 }
 
-var shipperOrderCancelableStatus = []string{
-	StatusCreated,
-	StatusRequestPickup,
+// ShippingProviderRegistry holds the registered shipping providers keyed by courier code
+type ShippingProviderRegistry interface {
+	Register(provider ShippingProvider)
+	Get(courierCode string) (ShippingProvider, bool)
 }
 
-var grabPickupOrderCancelableStatus = []string{
-	StatusRequestPickup,
+type shippingProviderRegistry struct {
+	mu        sync.RWMutex
+	providers map[string]ShippingProvider
 }
 
-var grabOrderCancelableStatus = []string{
-	StatusCreated,
-	StatusRequestPickup,
-}
-
-func IsPickUpOrderCancelable(courierCode, status string) bool {
-	var statusList []string
-
-	switch courierCode {
-	case ShipperCode:
-		statusList = shipperPickupOrderCancelableStatus
-	case GrabCode:
-		statusList = grabPickupOrderCancelableStatus
-	}
-
-	for _, v := range statusList {
-		if strings.EqualFold(v, status) {
-			return true
-		}
+func NewShippingProviderRegistry(providers ...ShippingProvider) ShippingProviderRegistry {
+	r := &shippingProviderRegistry{providers: make(map[string]ShippingProvider)}
+	for _, v := range providers {
+		r.Register(v)
 	}
-
-	return false
+	return r
 }
 
-func IsOrderCancelable(courierCode, status string) bool {
-	var statusList []string
+func (r *shippingProviderRegistry) Register(provider ShippingProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[provider.Code()] = provider
+}
 
-	switch courierCode {
-	case ShipperCode:
-		statusList = shipperOrderCancelableStatus
-	case GrabCode:
-		statusList = grabOrderCancelableStatus
-	}
+func (r *shippingProviderRegistry) Get(courierCode string) (ShippingProvider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	provider, ok := r.providers[courierCode]
+	return provider, ok
+}
 
+func isStatusIn(statusList []string, status string) bool {
 	for _, v := range statusList {
 		if strings.EqualFold(v, status) {
 			return true
@@ -87,12 +98,19 @@ func ShipperWebhookAuth() string {
 	return util.MD5Hash(apiKey + endpoint + format)
 }
 
+// GrabWebhookHeader is the authorization header grab sends on every webhook
+func GrabWebhookHeader() *request.WebhookUpdateStatusGrabHeader {
+	return &request.WebhookUpdateStatusGrabHeader{
+		AuthorizationID: viper.GetString("grab.auth.webhook-client-id"),
+		Authorization:   viper.GetString("grab.auth.webhook-client-secret"),
+	}
+}
+
 func GrabWebhookAuth(req *request.WebhookUpdateStatusGrabHeader) bool {
-	clientID := viper.GetString("grab.auth.webhook-client-id")
-	clientSecret := viper.GetString("grab.auth.webhook-client-secret")
+	header := GrabWebhookHeader()
 
 	input := fmt.Sprint(req.AuthorizationID, req.Authorization)
-	auth := fmt.Sprint(clientID, clientSecret)
+	auth := fmt.Sprint(header.AuthorizationID, header.Authorization)
 
 	return input == auth
 }
@@ -1,6 +1,7 @@
 package shipping_provider_mock
 
 import (
+	"context"
 	"go-klikdokter/app/model/entity"
 	"go-klikdokter/app/model/request"
 	"go-klikdokter/app/model/response"
@@ -13,7 +14,7 @@ type GrabMock struct {
 	Mock mock.Mock
 }
 
-func (h *GrabMock) GetShippingRate(req *request.GetShippingRateRequest) (*response.ShippingRateCommonResponse, error) {
+func (h *GrabMock) GetShippingRate(ctx context.Context, req *request.GetShippingRateRequest) (*response.ShippingRateCommonResponse, error) {
 	arguments := h.Mock.Called()
 
 	if len(arguments) > 1 {
@@ -29,7 +30,7 @@ func (h *GrabMock) GetShippingRate(req *request.GetShippingRateRequest) (*respon
 	return arguments.Get(0).(*response.ShippingRateCommonResponse), nil
 }
 
-func (h *GrabMock) CreateDelivery(courierService *entity.CourierService, req *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message) {
+func (h *GrabMock) CreateDelivery(ctx context.Context, courierService *entity.CourierService, req *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message) {
 	arguments := h.Mock.Called()
 
 	if len(arguments) > 1 {
@@ -41,7 +42,7 @@ func (h *GrabMock) CreateDelivery(courierService *entity.CourierService, req *re
 	return arguments.Get(0).(*response.CreateDeliveryThirdPartyData), message.SuccessMsg
 }
 
-func (h *GrabMock) ReCreateDelivery(req *entity.OrderShipping) (*response.CreateDeliveryThirdPartyData, message.Message) {
+func (h *GrabMock) ReCreateDelivery(ctx context.Context, req *entity.OrderShipping) (*response.CreateDeliveryThirdPartyData, message.Message) {
 	arguments := h.Mock.Called()
 
 	if len(arguments) > 1 {
@@ -53,7 +54,7 @@ func (h *GrabMock) ReCreateDelivery(req *entity.OrderShipping) (*response.Create
 	return arguments.Get(0).(*response.CreateDeliveryThirdPartyData), message.SuccessMsg
 }
 
-func (h *GrabMock) GetTracking(orderID string) ([]response.GetOrderShippingTracking, message.Message) {
+func (h *GrabMock) GetTracking(ctx context.Context, orderID string) ([]response.GetOrderShippingTracking, message.Message) {
 	arguments := h.Mock.Called()
 
 	if len(arguments) > 1 {
@@ -65,7 +66,7 @@ func (h *GrabMock) GetTracking(orderID string) ([]response.GetOrderShippingTrack
 	return arguments.Get(0).([]response.GetOrderShippingTracking), message.SuccessMsg
 }
 
-func (h *GrabMock) CancelDelivery(deliveryID string) error {
+func (h *GrabMock) CancelDelivery(ctx context.Context, deliveryID string) error {
 	arguments := h.Mock.Called()
 
 	if len(arguments) > 0 {
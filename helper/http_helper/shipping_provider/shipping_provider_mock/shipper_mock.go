@@ -1,6 +1,7 @@
 package shipping_provider_mock
 
 import (
+	"context"
 	"go-klikdokter/app/model/entity"
 	"go-klikdokter/app/model/request"
 	"go-klikdokter/app/model/response"
@@ -13,7 +14,7 @@ type ShipperMock struct {
 	Mock mock.Mock
 }
 
-func (h *ShipperMock) GetPricingDomestic(req *request.GetPricingDomestic) (*response.GetPricingDomestic, error) {
+func (h *ShipperMock) GetPricingDomestic(ctx context.Context, req *request.GetPricingDomestic) (*response.GetPricingDomestic, error) {
 	arguments := h.Mock.Called()
 
 	if len(arguments) > 1 {
@@ -29,7 +30,7 @@ func (h *ShipperMock) GetPricingDomestic(req *request.GetPricingDomestic) (*resp
 	return arguments.Get(0).(*response.GetPricingDomestic), nil
 }
 
-func (h *ShipperMock) GetShippingRate(courierID *uint64, input *request.GetShippingRateRequest) (*response.ShippingRateCommonResponse, error) {
+func (h *ShipperMock) GetShippingRate(ctx context.Context, courierID *uint64, input *request.GetShippingRateRequest) (*response.ShippingRateCommonResponse, error) {
 	arguments := h.Mock.Called()
 
 	if len(arguments) > 1 {
@@ -45,7 +46,7 @@ func (h *ShipperMock) GetShippingRate(courierID *uint64, input *request.GetShipp
 	return arguments.Get(0).(*response.ShippingRateCommonResponse), nil
 }
 
-func (h *ShipperMock) CreateOrder(req *request.CreateOrderShipper) (*response.CreateOrderShipperResponse, error) {
+func (h *ShipperMock) CreateOrder(ctx context.Context, req *request.CreateOrderShipper) (*response.CreateOrderShipperResponse, error) {
 	arguments := h.Mock.Called()
 
 	if len(arguments) > 1 {
@@ -93,7 +94,7 @@ func (h *ShipperMock) CreatePickUpOrder(req *request.CreatePickUpOrderShipper) (
 	return arguments.Get(0).(*response.CreatePickUpOrderShipperResponse), nil
 }
 
-func (h *ShipperMock) CreatePickUpOrderWithTimeSlots(orderID ...string) (*response.CreatePickUpOrderShipperResponse, message.Message) {
+func (h *ShipperMock) CreatePickUpOrderWithTimeSlots(ctx context.Context, orderID ...string) (*response.CreatePickUpOrderShipperResponse, message.Message) {
 	arguments := h.Mock.Called()
 
 	if len(arguments) > 1 {
@@ -105,7 +106,7 @@ func (h *ShipperMock) CreatePickUpOrderWithTimeSlots(orderID ...string) (*respon
 	return arguments.Get(0).(*response.CreatePickUpOrderShipperResponse), message.SuccessMsg
 }
 
-func (h *ShipperMock) CreateDelivery(shipperOrderID string, courierService *entity.CourierService, req *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message) {
+func (h *ShipperMock) CreateDelivery(ctx context.Context, shipperOrderID string, courierService *entity.CourierService, req *request.CreateDelivery) (*response.CreateDeliveryThirdPartyData, message.Message) {
 	arguments := h.Mock.Called()
 
 	if len(arguments) > 1 {
@@ -117,7 +118,7 @@ func (h *ShipperMock) CreateDelivery(shipperOrderID string, courierService *enti
 	return arguments.Get(0).(*response.CreateDeliveryThirdPartyData), message.SuccessMsg
 }
 
-func (h *ShipperMock) GetOrderDetail(orderID string) (*response.GetOrderDetailResponse, error) {
+func (h *ShipperMock) GetOrderDetail(ctx context.Context, orderID string) (*response.GetOrderDetailResponse, error) {
 	arguments := h.Mock.Called()
 
 	if len(arguments) > 1 {
@@ -133,7 +134,7 @@ func (h *ShipperMock) GetOrderDetail(orderID string) (*response.GetOrderDetailRe
 	return arguments.Get(0).(*response.GetOrderDetailResponse), nil
 }
 
-func (h *ShipperMock) GetTracking(orderID string) ([]response.GetOrderShippingTracking, message.Message) {
+func (h *ShipperMock) GetTracking(ctx context.Context, orderID string) ([]response.GetOrderShippingTracking, message.Message) {
 	arguments := h.Mock.Called()
 
 	if len(arguments) > 1 {
@@ -145,7 +146,7 @@ func (h *ShipperMock) GetTracking(orderID string) ([]response.GetOrderShippingTr
 	return arguments.Get(0).([]response.GetOrderShippingTracking), message.SuccessMsg
 }
 
-func (h *ShipperMock) CancelPickupRequest(pickupCode string) (*response.MetadataResponse, error) {
+func (h *ShipperMock) CancelPickupRequest(ctx context.Context, pickupCode string) (*response.MetadataResponse, error) {
 	arguments := h.Mock.Called()
 
 	if len(arguments) > 1 {
@@ -161,7 +162,7 @@ func (h *ShipperMock) CancelPickupRequest(pickupCode string) (*response.Metadata
 	return arguments.Get(0).(*response.MetadataResponse), nil
 }
 
-func (h *ShipperMock) CancelOrder(orderID string, req *request.CancelOrder) (*response.MetadataResponse, error) {
+func (h *ShipperMock) CancelOrder(ctx context.Context, orderID string, req *request.CancelOrder) (*response.MetadataResponse, error) {
 	arguments := h.Mock.Called()
 
 	if len(arguments) > 1 {
@@ -71,6 +71,7 @@ var (
 	ErrChannelHasCourierAssigned      = Message{Code: 209007, Message: "Can not delete Channel that has already assigned to Courier"}
 	ErrChannelHasChildShippingStatus  = Message{Code: 209008, Message: "Can not delete Channel that has one or more Shipping Status"}
 	ErrChannelCourierHasChild         = Message{Code: 209009, Message: "Can not delete Channel Courier that has one or more Channel Courier Service(s)"}
+	ErrShippingStatusHasCourierStatus = Message{Code: 209010, Message: "Can not delete Shipping Status that has one or more Courier Status mapping(s)"}
 )
 
 var ErrShippingRateNotFound = Message{Code: 34602, Message: "shipping rate is not found"}
@@ -91,6 +92,60 @@ var ErrCancelPickup = Message{Code: 34602, Message: "failed when trying to cance
 var ErrCantCancelOrderShipping = Message{Code: 34602, Message: "can't cancel this order"}
 var ErrCantCancelOrderCourierService = Message{Code: 34602, Message: "courier service is not cancelable"}
 var ErrUpdateOrderShipping = Message{Code: 34602, Message: "error update order shipping"}
+var ErrStatusCodeRequired = Message{Code: 34602, Message: "status_code is required"}
+var ErrGetStatusTransition = Message{Code: 34602, Message: "failed when trying to get status transition"}
+var ErrInvalidStatusTransition = Message{Code: 34602, Message: "status transition is not allowed"}
+var ErrStatusIsTerminal = Message{Code: 34602, Message: "order shipping status is already final"}
+var ErrIdempotencyKeyConflict = Message{Code: 34602, Message: "idempotency key is already used for a different request"}
+var ErrIdempotencyKeyInProgress = Message{Code: 34602, Message: "request with the same idempotency key is still in progress"}
+var ErrWebhookLogNotFound = Message{Code: 34602, Message: "webhook log not found"}
+var ErrWebhookLogNotRerunnable = Message{Code: 34602, Message: "only failed authorized webhooks can be re-run"}
+var ErrUnknownWebhookProvider = Message{Code: 34602, Message: "webhook provider is not valid"}
+var ErrUnmappedCourierStatusNotFound = Message{Code: 34602, Message: "unmapped courier status not found"}
+var ErrCourierStatusAlreadyMapped = Message{Code: 34602, Message: "courier status is already mapped"}
+var ErrShippingStatusUIDRequired = Message{Code: 34602, Message: "shipping_status_uid is required"}
+var ErrShippingStatusExists = Message{Code: 34602, Message: "status_code already exists in the channel"}
+var ErrRequiredShippingStatus = Message{Code: 34602, Message: "created, request_pickup and cancelled shipping status can not be removed"}
+var ErrChannelShippingStatusIncomplete = Message{Code: 34602, Message: "source channel must have created, request_pickup and cancelled shipping status"}
+var ErrTargetChannelHasShippingStatus = Message{Code: 34602, Message: "target channel already has shipping status"}
+var ErrCloneSameChannel = Message{Code: 34602, Message: "source and target channel must be different"}
+var ErrShippingCourierStatusNotFound = Message{Code: 34602, Message: "courier status mapping not found"}
+var ErrShippingCourierStatusExists = Message{Code: 34602, Message: "courier already has a status mapping for the shipping status"}
+var ErrCourierStatusMappedToOtherStatus = Message{Code: 34602, Message: "courier status is already mapped to another shipping status of the channel"}
+var ErrRateCardEffectiveFromRequired = Message{Code: 34602, Message: "effective_from is required"}
+var ErrInvalidRateCard = Message{Code: 34602, Message: "base fare and minimum charge can not be negative"}
+var ErrInvalidRateCardTier = Message{Code: 34602, Message: "rate card tiers must be ascending with a non negative rate, only the last tier may be unbounded"}
+var ErrInvalidRateCardZone = Message{Code: 34602, Message: "rate card zone codes must be unique and surcharges must refer to a zone of the card"}
+var ErrRateCardInternalCourierOnly = Message{Code: 34602, Message: "rate card is only available for internal courier"}
+var ErrInvalidFeeType = Message{Code: 34602, Message: "fee type must be percentage or fixed"}
+var ErrPriceRuleNotFound = Message{Code: 34602, Message: "price rule not found"}
+var ErrInvalidPriceAdjustmentType = Message{Code: 34602, Message: "adjustment_type must be markup_percentage, markup_fixed, discount_percentage or discount_fixed"}
+var ErrInvalidPriceRule = Message{Code: 34602, Message: "price rule values can not be negative, max_weight must not be below min_weight and rounding must be nearest, up or down"}
+var ErrShippingPromotionNotFound = Message{Code: 34602, Message: "shipping promotion not found"}
+var ErrInvalidPromotionDiscountType = Message{Code: 34602, Message: "discount_type must be free_shipping, percentage or fixed"}
+var ErrInvalidShippingPromotion = Message{Code: 34602, Message: "shipping promotion values can not be negative"}
+var ErrShippingPromotionStartTimeRequired = Message{Code: 34602, Message: "start_time is required"}
+var ErrRateQuoteNotFound = Message{Code: 34602, Message: "rate quote not found"}
+var ErrRateQuoteExpired = Message{Code: 34602, Message: "rate quote has expired, please get the shipping rate again"}
+var ErrRateQuoteUsed = Message{Code: 34602, Message: "rate quote has been used by another order"}
+var ErrRateQuoteMismatch = Message{Code: 34602, Message: "rate quote does not match the channel, courier service, package, cod or insurance of the order"}
+var ErrShipmentsRequired = Message{Code: 34602, Message: "shipments is required"}
+var ErrTooManyShipments = Message{Code: 34602, Message: "too many shipments in one request"}
+var ErrShipmentIDRequired = Message{Code: 34602, Message: "shipment id is required"}
+var ErrDuplicateShipmentID = Message{Code: 34602, Message: "shipment id must be unique"}
+var ErrInvalidShippingRateSort = Message{Code: 34602, Message: "sort must be cheapest, fastest or recommended"}
+var ErrInvalidRecommendationWeight = Message{Code: 34602, Message: "recommendation weights can not be negative and at least one must be set"}
+var ErrCourierHolidayNotFound = Message{Code: 34602, Message: "courier holiday not found"}
+var ErrInvalidHolidayDate = Message{Code: 34602, Message: "date must be formatted as YYYY-MM-DD"}
+var ErrHolidayNameRequired = Message{Code: 34602, Message: "name is required"}
+var ErrProviderCredentialNotFound = Message{Code: 34602, Message: "channel courier has no provider credential"}
+var ErrProviderCredentialNotSupported = Message{Code: 34602, Message: "provider credential is only used by third party and aggregator couriers"}
+var ErrProviderSecretRequired = Message{Code: 34602, Message: "secret is required"}
+var ErrProviderClientIDRequired = Message{Code: 34602, Message: "client_id is required by the provider of the courier"}
+var ErrStatusTransitionNotFound = Message{Code: 34602, Message: "status transition not found"}
+var ErrStatusTransitionExists = Message{Code: 34602, Message: "status transition already exists in the channel"}
+var ErrInvalidStatusTransitionCode = Message{Code: 34602, Message: "from_status_code and to_status_code must be different shipping status of the channel"}
+var ErrRequiredShippingCourierStatus = Message{Code: 34602, Message: "courier status mapping of created, request_pickup and cancelled can not be removed, update it instead"}
 
 var (
 	ShippingProviderMsg               = Message{Code: 209002, Message: ""}
@@ -115,4 +170,16 @@ var (
 	OrderHasBeenCancelledMsg          = Message{Code: 209002, Message: "order has been cancelled"}
 	CoordinateRequiredMsg             = Message{Code: 209002, Message: "origin and destination coordinates are required"}
 	InvalidCoordinateMsg              = Message{Code: 209002, Message: "origin or destination coordinates are invalid"}
+	ProviderUnavailableMsg            = Message{Code: 209002, Message: "courier is temporarily unavailable, its provider keeps failing"}
+	ProviderTimeoutMsg                = Message{Code: 209002, Message: "courier is temporarily unavailable, its provider did not answer in time"}
+	ProviderCredentialMsg             = Message{Code: 209002, Message: "provider credential of the channel can not be read"}
+)
+
+var (
+	VolumeExceedsMsg         = Message{Code: 209002, Message: "volume exceeds the maximum volume allowed"}
+	DistanceExceedsMsg       = Message{Code: 209002, Message: "distance exceeds the maximum distance allowed"}
+	PurchaseBelowMinimumMsg  = Message{Code: 209002, Message: "total product price is below the minimum purchase"}
+	PurchaseExceedsMsg       = Message{Code: 209002, Message: "total product price exceeds the maximum purchase"}
+	CodNotAvailableMsg       = Message{Code: 209002, Message: "cash on delivery is not available"}
+	OutsideOperatingHoursMsg = Message{Code: 209002, Message: "courier service is outside of its operating hours"}
 )
@@ -0,0 +1,19 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+const codeAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// GenerateCode returns an uppercase reference code made of the prefix, the current date
+// and a random suffix, e.g. KD-20220801-8ZK3Q0FJ
+func GenerateCode(prefix string) string {
+	suffix, _ := gonanoid.Generate(codeAlphabet, 8)
+	date := time.Now().In(Loc).Format("20060102")
+	return strings.ToUpper(fmt.Sprintf("%s-%s-%s", prefix, date, suffix))
+}
@@ -41,3 +41,22 @@ func (Time) GormDBDataType(db *gorm.DB, field *schema.Field) string {
 func (t Time) String() string {
 	return string(t)
 }
+
+var clockFormats = []string{timeFormat, "15:04:05-07:00", "15:04:05", "15:04"}
+
+// SecondOfDay returns the clock of t in seconds since midnight of loc,
+// a value without an offset is read in loc
+func (t Time) SecondOfDay(loc *time.Location) (int, bool) {
+	for _, layout := range clockFormats {
+		clock, err := time.ParseInLocation(layout, string(t), loc)
+		if err != nil {
+			continue
+		}
+
+		// move the offset of the value to loc on a date without daylight saving
+		clock = time.Date(2000, 1, 1, clock.Hour(), clock.Minute(), clock.Second(), 0, clock.Location()).In(loc)
+		return clock.Hour()*3600 + clock.Minute()*60 + clock.Second(), true
+	}
+
+	return 0, false
+}
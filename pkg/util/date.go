@@ -11,6 +11,20 @@ var (
 	Loc, _         = time.LoadLocation("Asia/Jakarta")
 )
 
+// LoadLocation returns the IANA timezone, Loc is used when the name is empty or unknown
+func LoadLocation(name string) *time.Location {
+	if name == "" {
+		return Loc
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return Loc
+	}
+
+	return loc
+}
+
 func DateRangeValidation(dateStart, dateEnd string) (validDate, validRange bool) {
 	validDate = true
 	validRange = false